@@ -5,42 +5,106 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"sync"
+	"time"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/slok/sloth/internal/alert"
+	"github.com/slok/sloth/internal/alertmanager"
 	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/internal/backstage"
 	"github.com/slok/sloth/internal/info"
 	"github.com/slok/sloth/internal/k8sprometheus"
 	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/metrics"
 	"github.com/slok/sloth/internal/prometheus"
+	"github.com/slok/sloth/internal/spec"
 	kubernetesv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
 	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
+	prometheusv2 "github.com/slok/sloth/pkg/prometheus/api/v2"
 )
 
 type generateCommand struct {
-	slosInput         string
-	slosOut           string
-	disableRecordings bool
-	disableAlerts     bool
-	extraLabels       map[string]string
+	slosInput          string
+	slosOut            string
+	disableRecordings  bool
+	disableAlerts      bool
+	kustomize          bool
+	configMap          bool
+	vmRule             bool
+	configMapKeyLayout string
+	extraLabels        map[string]string
+	externalLabels     map[string]string
+	specVars           map[string]string
+	specVarsEnv        bool
+	specVarsTemplate   bool
+	defaultsPath       string
+	backstageOut       string
+	backstageEntity    string
+	alertmanagerOut    string
+	alertmanagerTeam   string
+	slowest            int
+	keepFiringFor      time.Duration
+	metricsPrefix      string
+	sliRulesInterval   time.Duration
+	metaRulesInterval  time.Duration
+	alertRulesInterval time.Duration
+	ruleGroupName      string
+	dedupSLIRecordings bool
+	pageRunbookURL     string
+	ticketRunbookURL   string
+	slothLabelsPrefix  string
+	slothLabelsRename  map[string]string
+	slothLabelsDrop    []string
 }
 
 // NewGenerateCommand returns the generate command.
 func NewGenerateCommand(app *kingpin.Application) Command {
-	c := &generateCommand{extraLabels: map[string]string{}}
+	c := &generateCommand{extraLabels: map[string]string{}, externalLabels: map[string]string{}, specVars: map[string]string{}, slothLabelsRename: map[string]string{}}
 	cmd := app.Command("generate", "Generates Prometheus SLOs.")
 	cmd.Flag("input", "SLO spec input file path.").Short('i').Required().StringVar(&c.slosInput)
 	cmd.Flag("out", "Generated rules output file path. If `-` it will use stdout.").Short('o').Default("-").StringVar(&c.slosOut)
 	cmd.Flag("extra-labels", "Extra labels that will be added to all the generated Prometheus rules ('key=value' form, can be repeated).").Short('l').StringMapVar(&c.extraLabels)
+	cmd.Flag("external-labels", "External labels that identify where the SLOs are (e.g cluster), added to all the generated Prometheus rules and SLO series queries ('key=value' form, can be repeated). The spec ones take precedence.").StringMapVar(&c.externalLabels)
 	cmd.Flag("disable-recordings", "Disables recording rules generation.").BoolVar(&c.disableRecordings)
 	cmd.Flag("disable-alerts", "Disables alert rules generation.").BoolVar(&c.disableAlerts)
+	cmd.Flag("spec-var", "Variables that will be expanded on the spec in ${VAR} or ${VAR:-default} form before loading it ('key=value' form, can be repeated).").Short('v').StringMapVar(&c.specVars)
+	cmd.Flag("spec-vars-env", "Expands the spec ${VAR} variables missing on the spec vars using the environment variables.").BoolVar(&c.specVarsEnv)
+	cmd.Flag("spec-vars-template", "Renders the spec as a Go template using '[[' and ']]' delimiters (e.g '[[ if eq .ENV \"prod\" ]]'), with the spec vars (and the environment variables with --spec-vars-env) as data.").BoolVar(&c.specVarsTemplate)
+	cmd.Flag("defaults", "Defaults document file path that will be merged on the spec and its SLOs before loading it.").StringVar(&c.defaultsPath)
+	cmd.Flag("backstage-out", "Backstage SLO facts (JSON) output file path, these can be used to show the SLOs on the developer portal.").StringVar(&c.backstageOut)
+	cmd.Flag("backstage-entity", "Backstage entity reference of the SLO facts, by default the service as a component (e.g component:default/myservice).").StringVar(&c.backstageEntity)
+	cmd.Flag("alertmanager-out", "Alertmanager routes and receivers (YAML) output file path, these can be merged on the Alertmanager configuration to route the generated SLO alerts.").StringVar(&c.alertmanagerOut)
+	cmd.Flag("alertmanager-team-label", "Alert label used to route the SLO alerts by team on the Alertmanager routes.").Default(alertmanager.DefaultTeamLabel).StringVar(&c.alertmanagerTeam)
+	cmd.Flag("slowest", "Reports the N slowest SLOs to generate on the generation summary.").IntVar(&c.slowest)
+	cmd.Flag("keep-firing-for", "Default time the alerts keep firing after the condition has resolved (e.g 10m), used by the SLOs that don't set it (only Prometheus specs).").DurationVar(&c.keepFiringFor)
+	cmd.Flag("metrics-prefix", "Default prefix of the generated metrics instead of `slo` and `sloth` (e.g myorg), used by the SLOs that don't set it.").StringVar(&c.metricsPrefix)
+	cmd.Flag("sli-rules-interval", "Default evaluation interval of the SLI recording rule groups (e.g 30s), used by the specs that don't set it.").DurationVar(&c.sliRulesInterval)
+	cmd.Flag("meta-rules-interval", "Default evaluation interval of the metadata recording rule groups (e.g 5m), used by the specs that don't set it.").DurationVar(&c.metaRulesInterval)
+	cmd.Flag("alert-rules-interval", "Default evaluation interval of the alert rule groups (e.g 30s), used by the specs that don't set it.").DurationVar(&c.alertRulesInterval)
+	cmd.Flag("rule-group-name", "Default rule group names template (e.g {{ .service }}-{{ .category }}-{{ .slo }}), used by the specs that don't set it.").StringVar(&c.ruleGroupName)
+	cmd.Flag("page-runbook-url", "Default runbook URL template of the page alerts (e.g https://runbooks.myorg.com/[[ .service ]]/[[ .slo ]]), used by the specs that don't set it.").StringVar(&c.pageRunbookURL)
+	cmd.Flag("ticket-runbook-url", "Default runbook URL template of the ticket alerts (e.g https://runbooks.myorg.com/[[ .service ]]/[[ .slo ]]), used by the specs that don't set it.").StringVar(&c.ticketRunbookURL)
+	cmd.Flag("sloth-labels-prefix", "Default prefix of the Sloth labels instead of `sloth` (e.g myorg), used by the specs that don't customize the Sloth labels.").StringVar(&c.slothLabelsPrefix)
+	cmd.Flag("sloth-label-rename", "Default Sloth label rename ('sloth_label=new_label' form, can be repeated), used by the specs that don't customize the Sloth labels.").StringMapVar(&c.slothLabelsRename)
+	cmd.Flag("sloth-label-drop", "Default Sloth label to drop (e.g sloth_slo, can be repeated), used by the specs that don't customize the Sloth labels.").StringsVar(&c.slothLabelsDrop)
+	cmd.Flag("dedup-sli-recordings", "Reuses the SLI recordings of the previous SLOs when the SLOs of the spec record the same SLI query and window.").BoolVar(&c.dedupSLIRecordings)
+	cmd.Flag("configmap", "Stores the Kubernetes generated rules as Prometheus rule files inside a ConfigMap instead of a Prometheus operator PrometheusRule (only Kubernetes specs).").BoolVar(&c.configMap)
+	cmd.Flag("configmap-key-layout", "The ConfigMap rule file keys, a 'single' key for all the rules or a key per 'slo'.").Default(k8sprometheus.ConfigMapKeyLayoutSingle).EnumVar(&c.configMapKeyLayout, k8sprometheus.ConfigMapKeyLayoutSingle, k8sprometheus.ConfigMapKeyLayoutSLO)
+	cmd.Flag("vmrule", "Stores the Kubernetes generated rules as a VictoriaMetrics operator VMRule instead of a Prometheus operator PrometheusRule (only Kubernetes specs).").BoolVar(&c.vmRule)
+	cmd.Flag("kustomize", "Stores the Kubernetes generated rules as a kustomize base using the out path as the directory (only Kubernetes specs).").BoolVar(&c.kustomize)
 
 	return c
 }
 
 func (g generateCommand) Name() string { return "generate" }
 func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
+	if g.slowest < 0 {
+		return fmt.Errorf("slowest SLOs number can't be negative: %d", g.slowest)
+	}
+
 	// Get SLO spec data.
 	// TODO(slok): stdin.
 	f, err := os.Open(g.slosInput)
@@ -54,12 +118,48 @@ func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
 		return fmt.Errorf("could not read SLOs spec file data: %w", err)
 	}
 
+	// Expand spec variables if required.
+	expandVars := len(g.specVars) > 0 || g.specVarsEnv || g.specVarsTemplate
+	expander := spec.VarsExpander{Vars: g.specVars, Env: g.specVarsEnv, Template: g.specVarsTemplate}
+	if expandVars {
+		slxData, err = expander.Expand(slxData)
+		if err != nil {
+			return fmt.Errorf("could not expand SLOs spec variables: %w", err)
+		}
+	}
+
+	// Apply the defaults if required.
+	if g.defaultsPath != "" {
+		defaultsData, err := os.ReadFile(g.defaultsPath)
+		if err != nil {
+			return fmt.Errorf("could not read defaults file: %w", err)
+		}
+
+		if expandVars {
+			defaultsData, err = expander.Expand(defaultsData)
+			if err != nil {
+				return fmt.Errorf("could not expand defaults variables: %w", err)
+			}
+		}
+
+		slxData, err = spec.DefaultsApplier{Defaults: defaultsData}.Apply(slxData)
+		if err != nil {
+			return fmt.Errorf("could not apply defaults on SLOs spec: %w", err)
+		}
+	}
+
 	// Try loading spec with all the generators possible.
 
 	// Raw Prometheus generator.
 	slos, promErr := prometheus.YAMLSpecLoader.LoadSpec(ctx, slxData)
 	if promErr == nil {
-		return g.runPrometheus(ctx, config, *slos)
+		return g.runPrometheus(ctx, config, prometheusv1.Version, *slos)
+	}
+
+	// Raw Prometheus v2 generator.
+	slos, promV2Err := prometheus.YAMLSpecV2Loader.LoadSpec(ctx, slxData)
+	if promV2Err == nil {
+		return g.runPrometheus(ctx, config, prometheusv2.Version, *slos)
 	}
 
 	// Kubernetes Prometheus operator generator.
@@ -70,18 +170,35 @@ func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
 
 	// If we reached here means that we could not use any of the available spec types.
 	config.Logger.Errorf("Tried loading raw prometheus SLOs spec, it couldn't: %s", promErr)
+	config.Logger.Errorf("Tried loading raw prometheus v2 SLOs spec, it couldn't: %s", promV2Err)
 	config.Logger.Errorf("Tried loading Kubernetes prometheus SLOs spec, it couldn't: %s", k8sErr)
 	return fmt.Errorf("invalid spec, could not load with any of the supported spec types")
 }
 
 // runPrometheus generates the SLOs based on a raw regular Prometheus spec format input and
 // outs a Prometheus raw yaml.
-func (g generateCommand) runPrometheus(ctx context.Context, config RootConfig, slos prometheus.SLOGroup) error {
+func (g generateCommand) runPrometheus(ctx context.Context, config RootConfig, specVersion string, slos prometheus.SLOGroup) error {
 	config.Logger.Infof("Generating from Prometheus spec")
+	if g.kustomize {
+		return fmt.Errorf("kustomize output is only supported with Kubernetes specs")
+	}
+
 	info := info.Info{
 		Version: info.Version,
 		Mode:    info.ModeCLIGenPrometheus,
-		Spec:    prometheusv1.Version,
+		Spec:    specVersion,
+	}
+
+	// Set the default keep firing for on the SLOs that don't have one.
+	if g.keepFiringFor != 0 {
+		sloList := make([]prometheus.SLO, 0, len(slos.SLOs))
+		for _, slo := range slos.SLOs {
+			if slo.KeepFiringFor == 0 {
+				slo.KeepFiringFor = g.keepFiringFor
+			}
+			sloList = append(sloList, slo)
+		}
+		slos.SLOs = sloList
 	}
 
 	result, err := g.generate(ctx, config, info, slos)
@@ -89,6 +206,16 @@ func (g generateCommand) runPrometheus(ctx context.Context, config RootConfig, s
 		return err
 	}
 
+	err = g.storeBackstage(ctx, config, result)
+	if err != nil {
+		return err
+	}
+
+	err = g.storeAlertmanager(ctx, config, result)
+	if err != nil {
+		return err
+	}
+
 	// Store.
 	var out io.Writer = config.Stdout
 	if g.slosOut != "-" {
@@ -123,6 +250,10 @@ func (g generateCommand) runPrometheus(ctx context.Context, config RootConfig, s
 func (g generateCommand) runKubernetes(ctx context.Context, config RootConfig, sloGroup k8sprometheus.SLOGroup) error {
 	config.Logger.Infof("Generating from Kubernetes Prometheus spec")
 
+	if g.keepFiringFor != 0 {
+		config.Logger.Warningf("Keep firing for is not supported on Prometheus operator rules, ignoring")
+	}
+
 	info := info.Info{
 		Version: info.Version,
 		Mode:    info.ModeCLIGenKubernetes,
@@ -133,18 +264,47 @@ func (g generateCommand) runKubernetes(ctx context.Context, config RootConfig, s
 		return err
 	}
 
+	err = g.storeBackstage(ctx, config, result)
+	if err != nil {
+		return err
+	}
+
+	err = g.storeAlertmanager(ctx, config, result)
+	if err != nil {
+		return err
+	}
+
 	// Store.
-	var out io.Writer = config.Stdout
-	if g.slosOut != "-" {
-		f, err := os.Create(g.slosOut)
-		if err != nil {
-			return fmt.Errorf("could not create out file: %w", err)
+	var repo kubernetesRepo
+	if g.kustomize {
+		if g.slosOut == "-" {
+			return fmt.Errorf("kustomize output requires an out directory")
+		}
+		if g.configMap || g.vmRule {
+			return fmt.Errorf("kustomize output only supports PrometheusRules")
+		}
+		repo = k8sprometheus.NewKustomizeDirPrometheusOperatorYAMLRepo(g.slosOut, config.Logger)
+	} else {
+		var out io.Writer = config.Stdout
+		if g.slosOut != "-" {
+			f, err := os.Create(g.slosOut)
+			if err != nil {
+				return fmt.Errorf("could not create out file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+		repo = k8sprometheus.NewIOWriterPrometheusOperatorYAMLRepo(out, config.Logger)
+		switch {
+		case g.configMap && g.vmRule:
+			return fmt.Errorf("ConfigMap and VMRule outputs can't be used at the same time")
+		case g.configMap:
+			repo = k8sprometheus.NewIOWriterConfigMapYAMLRepo(out, g.configMapKeyLayout, config.Logger)
+		case g.vmRule:
+			repo = k8sprometheus.NewIOWriterVMRuleYAMLRepo(out, config.Logger)
 		}
-		defer f.Close()
-		out = f
 	}
 
-	repo := k8sprometheus.NewIOWriterPrometheusOperatorYAMLRepo(out, config.Logger)
 	storageSLOs := make([]k8sprometheus.StorageSLO, 0, len(result.PrometheusSLOs))
 	for _, s := range result.PrometheusSLOs {
 		storageSLOs = append(storageSLOs, k8sprometheus.StorageSLO{
@@ -162,9 +322,113 @@ func (g generateCommand) runKubernetes(ctx context.Context, config RootConfig, s
 	return nil
 }
 
+// storeBackstage stores the generated SLOs as Backstage SLO facts if required.
+func (g generateCommand) storeBackstage(ctx context.Context, config RootConfig, result *generate.Response) error {
+	if g.backstageOut == "" {
+		return nil
+	}
+
+	f, err := os.Create(g.backstageOut)
+	if err != nil {
+		return fmt.Errorf("could not create backstage out file: %w", err)
+	}
+	defer f.Close()
+
+	slos := make([]prometheus.SLO, 0, len(result.PrometheusSLOs))
+	for _, s := range result.PrometheusSLOs {
+		slos = append(slos, s.SLO)
+	}
+
+	repo := backstage.NewIOWriterSLOFactsRepo(f, g.backstageEntity, config.Logger)
+	ctx = config.Logger.SetValuesOnCtx(ctx, log.Kv{"out": g.backstageOut})
+	err = repo.StoreSLOs(ctx, slos)
+	if err != nil {
+		return fmt.Errorf("could not store backstage SLO facts: %w", err)
+	}
+
+	return nil
+}
+
+// storeAlertmanager stores the Alertmanager routes of the generated SLO alerts if required.
+func (g generateCommand) storeAlertmanager(ctx context.Context, config RootConfig, result *generate.Response) error {
+	if g.alertmanagerOut == "" {
+		return nil
+	}
+
+	f, err := os.Create(g.alertmanagerOut)
+	if err != nil {
+		return fmt.Errorf("could not create alertmanager out file: %w", err)
+	}
+	defer f.Close()
+
+	slos := make([]prometheus.StorageSLO, 0, len(result.PrometheusSLOs))
+	for _, s := range result.PrometheusSLOs {
+		slos = append(slos, prometheus.StorageSLO{SLO: s.SLO, Rules: s.SLORules})
+	}
+
+	repo := alertmanager.NewIOWriterRoutesYAMLRepo(f, g.alertmanagerTeam, config.Logger)
+	ctx = config.Logger.SetValuesOnCtx(ctx, log.Kv{"out": g.alertmanagerOut})
+	err = repo.StoreSLOs(ctx, slos)
+	if err != nil {
+		return fmt.Errorf("could not store alertmanager routes: %w", err)
+	}
+
+	return nil
+}
+
+// kubernetesRepo knows how to store Kubernetes generated SLOs.
+type kubernetesRepo interface {
+	StoreSLOs(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error
+}
+
 // generate is the main generator logic that all the spec types and storers share. Mainly
 // has the logic of the generate controller.
 func (g generateCommand) generate(ctx context.Context, config RootConfig, info info.Info, slos prometheus.SLOGroup) (*generate.Response, error) {
+	// Set the default metrics prefix, rule group intervals, name, runbook URLs and Sloth labels on the SLOs that don't have them.
+	sloList := make([]prometheus.SLO, 0, len(slos.SLOs))
+	for _, slo := range slos.SLOs {
+		if len(g.externalLabels) > 0 {
+			externalLabels := map[string]string{}
+			for k, v := range g.externalLabels {
+				externalLabels[k] = v
+			}
+			for k, v := range slo.ExternalLabels {
+				externalLabels[k] = v
+			}
+			slo.ExternalLabels = externalLabels
+		}
+		if slo.MetricsPrefix == "" {
+			slo.MetricsPrefix = g.metricsPrefix
+		}
+		if slo.RuleGroupIntervals.SLIErrorRecRules == 0 {
+			slo.RuleGroupIntervals.SLIErrorRecRules = g.sliRulesInterval
+		}
+		if slo.RuleGroupIntervals.MetadataRecRules == 0 {
+			slo.RuleGroupIntervals.MetadataRecRules = g.metaRulesInterval
+		}
+		if slo.RuleGroupIntervals.AlertRules == 0 {
+			slo.RuleGroupIntervals.AlertRules = g.alertRulesInterval
+		}
+		if slo.RuleGroupName == "" {
+			slo.RuleGroupName = g.ruleGroupName
+		}
+		if slo.RunbookURLs.Page == "" {
+			slo.RunbookURLs.Page = g.pageRunbookURL
+		}
+		if slo.RunbookURLs.Ticket == "" {
+			slo.RunbookURLs.Ticket = g.ticketRunbookURL
+		}
+		if slo.SlothLabels.Prefix == "" && len(slo.SlothLabels.Rename) == 0 && len(slo.SlothLabels.Drop) == 0 {
+			slo.SlothLabels = prometheus.SlothLabels{
+				Prefix: g.slothLabelsPrefix,
+				Rename: g.slothLabelsRename,
+				Drop:   g.slothLabelsDrop,
+			}
+		}
+		sloList = append(sloList, slo)
+	}
+	slos.SLOs = sloList
+
 	// Disable recording rules if required.
 	var sliRuleGen generate.SLIRecordingRulesGenerator = generate.NoopSLIRecordingRulesGenerator
 	var metaRuleGen generate.MetadataRecordingRulesGenerator = generate.NoopMetadataRecordingRulesGenerator
@@ -180,11 +444,13 @@ func (g generateCommand) generate(ctx context.Context, config RootConfig, info i
 	}
 
 	// Generate.
+	timings := &sloTimingsRecorder{Recorder: metrics.Noop}
 	controller, err := generate.NewService(generate.ServiceConfig{
 		AlertGenerator:              alert.AlertGenerator,
 		SLIRecordingRulesGenerator:  sliRuleGen,
 		MetaRecordingRulesGenerator: metaRuleGen,
 		SLOAlertRulesGenerator:      alertRuleGen,
+		MetricsRecorder:             timings,
 		Logger:                      config.Logger,
 	})
 	if err != nil {
@@ -192,12 +458,75 @@ func (g generateCommand) generate(ctx context.Context, config RootConfig, info i
 	}
 
 	result, err := controller.Generate(ctx, generate.Request{
-		Info:     info,
-		SLOGroup: slos,
+		Info:               info,
+		ExtraLabels:        g.extraLabels,
+		SLOGroup:           slos,
+		DedupSLIRecordings: g.dedupSLIRecordings,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not generate prometheus rules: %w", err)
 	}
 
+	g.logSummary(config, timings)
+
 	return result, nil
 }
+
+// logSummary logs the generation timing summary, including the slowest SLOs if required.
+func (g generateCommand) logSummary(config RootConfig, timings *sloTimingsRecorder) {
+	slos := timings.sorted()
+
+	var total time.Duration
+	for _, s := range slos {
+		total += s.duration
+	}
+	config.Logger.WithValues(log.Kv{"slos": len(slos), "duration": total}).Infof("SLOs generated")
+
+	for i, s := range slowestSLOTimings(slos, g.slowest) {
+		config.Logger.WithValues(log.Kv{"position": i + 1, "slo": s.slo, "duration": s.duration}).Infof("Slow SLO generation")
+	}
+}
+
+// slowestSLOTimings returns the first n SLO timings, n is clamped to the available timings.
+func slowestSLOTimings(timings []sloTiming, n int) []sloTiming {
+	switch {
+	case n < 0:
+		n = 0
+	case n > len(timings):
+		n = len(timings)
+	}
+
+	return timings[:n]
+}
+
+type sloTiming struct {
+	slo      string
+	duration time.Duration
+}
+
+// sloTimingsRecorder is a metrics recorder that keeps the SLOs generation durations in memory.
+type sloTimingsRecorder struct {
+	metrics.Recorder
+
+	mu      sync.Mutex
+	timings []sloTiming
+}
+
+func (s *sloTimingsRecorder) ObserveSLOGenerationDuration(ctx context.Context, service, slo string, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The SLOs are identified by service, SLOs of different services can have the same name.
+	s.timings = append(s.timings, sloTiming{slo: service + "/" + slo, duration: duration})
+}
+
+// sorted returns the SLO timings sorted from the slowest to the fastest.
+func (s *sloTimingsRecorder) sorted() []sloTiming {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	timings := make([]sloTiming, len(s.timings))
+	copy(timings, s.timings)
+	sort.SliceStable(timings, func(i, j int) bool { return timings[i].duration > timings[j].duration })
+
+	return timings
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowestSLOTimings(t *testing.T) {
+	timings := []sloTiming{
+		{slo: "svc1/slo1", duration: 3 * time.Second},
+		{slo: "svc1/slo2", duration: 2 * time.Second},
+		{slo: "svc2/slo1", duration: 1 * time.Second},
+	}
+
+	tests := map[string]struct {
+		n          int
+		expTimings []sloTiming
+	}{
+		"Zero slowest SLOs should not return timings.": {
+			n:          0,
+			expTimings: []sloTiming{},
+		},
+
+		"Negative slowest SLOs should not return timings.": {
+			n:          -1,
+			expTimings: []sloTiming{},
+		},
+
+		"Less slowest SLOs than timings should return the first timings.": {
+			n:          2,
+			expTimings: timings[:2],
+		},
+
+		"More slowest SLOs than timings should return all the timings.": {
+			n:          10,
+			expTimings: timings,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expTimings, slowestSLOTimings(timings, test.n))
+		})
+	}
+}
+
+func TestSLOTimingsRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	rec := &sloTimingsRecorder{}
+	rec.ObserveSLOGenerationDuration(context.TODO(), "svc1", "slo1", 1*time.Second)
+	rec.ObserveSLOGenerationDuration(context.TODO(), "svc2", "slo1", 2*time.Second)
+
+	// The SLOs with the same name of different services are identified by the service.
+	exp := []sloTiming{
+		{slo: "svc2/slo1", duration: 2 * time.Second},
+		{slo: "svc1/slo1", duration: 1 * time.Second},
+	}
+	assert.Equal(exp, rec.sorted())
+}
@@ -2,71 +2,283 @@ package commands
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/oklog/run"
 	monitoringclientset "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	prometheusclient "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	koopercontroller "github.com/spotahome/kooper/v2/controller"
 	kooperlog "github.com/spotahome/kooper/v2/log"
 	kooperprometheus "github.com/spotahome/kooper/v2/metrics/prometheus"
 	"gopkg.in/alecthomas/kingpin.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Init all available Kube client auth systems.
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/homedir"
 
 	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/app/generate"
 	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/app/kubewebhook"
+	"github.com/slok/sloth/internal/health"
 	"github.com/slok/sloth/internal/k8sprometheus"
 	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/metrics"
 	"github.com/slok/sloth/internal/prometheus"
+	"github.com/slok/sloth/internal/ruler"
 	slothclientset "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned"
+	slothscheme "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/scheme"
 )
 
 type kubeControllerCommand struct {
-	extraLabels       map[string]string
-	workers           int
-	kubeConfig        string
-	kubeContext       string
-	resyncInterval    time.Duration
-	namespace         string
-	development       bool
-	metricsPath       string
-	metricsListenAddr string
+	extraLabels        map[string]string
+	externalLabels     map[string]string
+	ruleSelectorLabels map[string]string
+	ruleAnnotations    map[string]string
+	workers            int
+	processingRetries  int
+	errorBackoff       time.Duration
+	maxErrorBackoff    time.Duration
+	kubeAPIQPS         float32
+	kubeAPIBurst       int
+	kubeAPITimeout     time.Duration
+	kubeConfig         string
+	kubeContext        string
+	resyncInterval     time.Duration
+	namespaces         []string
+	namespaceSelector  string
+	selector           string
+	specSource         string
+	development        bool
+	dryRun             bool
+	metricsPath        string
+	metricsListenAddr  string
+	slosAPITokenFile   string
+	deletionPolicy     string
+	rulesNamespace     string
+	alertCatalog       string
+	rulesOutput        string
+	secondaryOutput    string
+	configMapKeyLayout string
+	rulerURL           string
+	rulesDir           string
+	rulesDirGC         time.Duration
+	rulerAPIPrefix     string
+	rulerTenant        string
+	rulesMaxSize       int
+	deletionGrace      time.Duration
+	orphanRulesGC      time.Duration
+	inventoryInterval  time.Duration
+	adoptRules         bool
+	repairDrift        bool
+	serverSideApply    bool
+	fieldManager       string
+	webhookListenAddr  string
+	webhookTLSCert     string
+	webhookTLSKey      string
+	defaultTimeWindow  string
+	defaultLabels      map[string]string
+	defaultAnnotations map[string]string
+	shardIndex         int
+	totalShards        int
+	remoteClusters     map[string]string
+	remoteClusterLabel string
+	noRulesFinalizer   bool
 }
 
+const (
+	deletionPolicyDelete = "delete"
+	deletionPolicyRetain = "retain"
+
+	rulesOutputPrometheusOperator = "prometheus-operator"
+	rulesOutputConfigMap          = "configmap"
+	rulesOutputVMRule             = "vmrule"
+	rulesOutputRuler              = "ruler"
+	rulesOutputFile               = "file"
+
+	specSourcePrometheusServiceLevel = "prometheus-service-level"
+	specSourceConfigMap              = "configmap"
+)
+
 // NewKubeControllerCommand returns the Kubernetes controller command.
 func NewKubeControllerCommand(app *kingpin.Application) Command {
-	c := &kubeControllerCommand{extraLabels: map[string]string{}}
+	c := &kubeControllerCommand{
+		extraLabels:        map[string]string{},
+		externalLabels:     map[string]string{},
+		ruleSelectorLabels: map[string]string{},
+		ruleAnnotations:    map[string]string{},
+		defaultLabels:      map[string]string{},
+		defaultAnnotations: map[string]string{},
+		remoteClusters:     map[string]string{},
+	}
 	cmd := app.Command("kubernetes-controller", "Runs Sloth in Kubernetes controller/operator mode.")
 	cmd.Alias("controller")
 	cmd.Alias("k8s-controller")
 
 	cmd.Flag("development", "Enable development mode.").BoolVar(&c.development)
 	kubeHome := filepath.Join(homedir.HomeDir(), ".kube", "config")
+	cmd.Flag("dry-run", "Generate the rules without storing them nor the PrometheusServiceLevel status, the rules that would be stored are logged and recorded as events.").BoolVar(&c.dryRun)
 	cmd.Flag("kube-config", "kubernetes configuration path, only used when development mode enabled.").Default(kubeHome).StringVar(&c.kubeConfig)
 	cmd.Flag("kube-context", "kubernetes context, only used when development mode enabled.").StringVar(&c.kubeContext)
 	cmd.Flag("workers", "Concurrent processing workers for each kubernetes controller.").Default("5").IntVar(&c.workers)
 	cmd.Flag("resync-interval", "The duration between all resources resync.").Default("15m").DurationVar(&c.resyncInterval)
-	cmd.Flag("namespace", "Run the controller targeting specific namespace, by default all.").StringVar(&c.namespace)
+	cmd.Flag("processing-retries", "The times a failed handling is retried (rate limited with an exponential backoff per object) before waiting for the next event or resync.").Default("2").IntVar(&c.processingRetries)
+	cmd.Flag("error-backoff", "The time an object that failed all the handling retries will not be handled again unless it changes, doubled on each consecutive failure, by default disabled.").DurationVar(&c.errorBackoff)
+	cmd.Flag("max-error-backoff", "The max error backoff time.").Default("1h").DurationVar(&c.maxErrorBackoff)
+	cmd.Flag("kube-api-qps", "The max queries per second to the Kubernetes API.").Default("100").Float32Var(&c.kubeAPIQPS)
+	cmd.Flag("kube-api-burst", "The max burst of queries to the Kubernetes API.").Default("100").IntVar(&c.kubeAPIBurst)
+	cmd.Flag("kube-api-timeout", "The timeout of the Kubernetes API requests (the watches are restarted when reached), by default disabled.").DurationVar(&c.kubeAPITimeout)
+	cmd.Flag("namespace", "Run the controller targeting specific namespace, by default all (can be repeated).").StringsVar(&c.namespaces)
+	cmd.Flag("selector", "Handle only the PrometheusServiceLevels that match the label selector (e.g 'sloth-instance=a'), by default all. Required with 'configmap' spec source to select the spec ConfigMaps.").StringVar(&c.selector)
+	cmd.Flag("spec-source", "Where the SLO specs are read from, 'prometheus-service-level' CRs or raw Sloth specs on the data keys of 'configmap' ConfigMaps (selected by the label selector) for clusters without the Sloth CRDs.").Default(specSourcePrometheusServiceLevel).EnumVar(&c.specSource, specSourcePrometheusServiceLevel, specSourceConfigMap)
+	cmd.Flag("namespace-label-selector", "Run the controller targeting the namespaces that match the label selector (e.g 'team=a,tier!=dev'), by default all.").StringVar(&c.namespaceSelector)
 	cmd.Flag("metrics-path", "The path for Prometheus metrics.").Default("/metrics").StringVar(&c.metricsPath)
 	cmd.Flag("metrics-listen-addr", "The listen address for Prometheus metrics and pprof.").Default(":8081").StringVar(&c.metricsListenAddr)
+	cmd.Flag("slos-api-token-file", "Serve the managed SLOs JSON on the '/api/v1/slos' path of the metrics listen address, authenticated with the bearer token of this file, by default disabled.").StringVar(&c.slosAPITokenFile)
 	cmd.Flag("extra-labels", "Extra labels that will be added to all the generated Prometheus rules ('key=value' form, can be repeated).").Short('l').StringMapVar(&c.extraLabels)
+	cmd.Flag("external-labels", "External labels that identify where the SLOs are (e.g cluster), added to all the generated Prometheus rules and SLO series queries ('key=value' form, can be repeated). The PrometheusServiceLevel ones take precedence.").StringMapVar(&c.externalLabels)
+	cmd.Flag("rule-selector-labels", "Labels that will be set on all the generated PrometheusRule objects to match the Prometheus ruleSelector, the PrometheusServiceLevel labels and spec ruleSelectorLabels (in this order) take precedence over these ('key=value' form, can be repeated).").StringMapVar(&c.ruleSelectorLabels)
+	cmd.Flag("rule-annotations", "Annotations that will be set on all the generated PrometheusRule objects, the PrometheusServiceLevel ruleAnnotations take precedence ('key=value' form, can be repeated).").StringMapVar(&c.ruleAnnotations)
+	cmd.Flag("deletion-policy", "What to do with the generated Prometheus rules when a PrometheusServiceLevel is deleted, 'delete' them or 'retain' them labeled as pending deletion during the deletion grace period.").Default(deletionPolicyDelete).EnumVar(&c.deletionPolicy, deletionPolicyDelete, deletionPolicyRetain)
+	cmd.Flag("rules-output", "How the generated rules are stored, as Prometheus operator 'prometheus-operator' PrometheusRules, as Prometheus rule files inside 'configmap' ConfigMaps, as VictoriaMetrics operator 'vmrule' VMRules, as rule groups pushed to a Mimir/Cortex 'ruler' API or as Prometheus rule 'file' files inside a directory.").Default(rulesOutputPrometheusOperator).EnumVar(&c.rulesOutput, rulesOutputPrometheusOperator, rulesOutputConfigMap, rulesOutputVMRule, rulesOutputRuler, rulesOutputFile)
+	cmd.Flag("secondary-rules-output", "Also store the generated rules on this rules output (e.g to migrate the rules storage without downtime), its failures are logged and recorded as events but don't fail the handling, by default disabled.").EnumVar(&c.secondaryOutput, rulesOutputPrometheusOperator, rulesOutputConfigMap, rulesOutputVMRule, rulesOutputRuler, rulesOutputFile)
+	cmd.Flag("configmap-key-layout", "The ConfigMap rule file keys, a 'single' key for all the rules or a key per 'slo', only used with 'configmap' rules output.").Default(k8sprometheus.ConfigMapKeyLayoutSingle).EnumVar(&c.configMapKeyLayout, k8sprometheus.ConfigMapKeyLayoutSingle, k8sprometheus.ConfigMapKeyLayoutSLO)
+	cmd.Flag("ruler-url", "The Mimir/Cortex ruler (or gateway) URL the rule groups are pushed to, basic auth credentials can be set on the URL, only used with 'ruler' rules output.").StringVar(&c.rulerURL)
+	cmd.Flag("ruler-api-prefix", "The ruler configuration API path prefix.").Default(ruler.DefaultAPIPrefix).StringVar(&c.rulerAPIPrefix)
+	cmd.Flag("ruler-tenant", "The tenant (X-Scope-OrgID header) the rule groups are pushed to, by default none.").StringVar(&c.rulerTenant)
+	cmd.Flag("rules-dir", "The directory (e.g a volume shared with a Thanos ruler or Prometheus) where the '<namespace>_<name>.yml' rule files are written, only used with 'file' rules output.").StringVar(&c.rulesDir)
+	cmd.Flag("rules-dir-gc-interval", "The interval between the checks that delete the rule files whose SLO spec object doesn't exist anymore, only used with 'file' rules output.").Default("1m").DurationVar(&c.rulesDirGC)
+	cmd.Flag("rules-max-size", "Split the generated rule groups across multiple PrometheusRules ('<name>-part-<n>') when a PrometheusRule would be bigger than this size in bytes (e.g 1000000 to stay under the etcd limits), only used with 'prometheus-operator' rules output, by default disabled.").IntVar(&c.rulesMaxSize)
+	cmd.Flag("rules-namespace", "Store all the generated PrometheusRules on this namespace instead of the PrometheusServiceLevel namespace, the rules are named '<namespace>-<name>' and are retained (deleted by the rules cleanup finalizer or after the deletion grace period), by default disabled.").StringVar(&c.rulesNamespace)
+	cmd.Flag("disable-rules-finalizer", "Don't set the rules cleanup finalizer on the PrometheusServiceLevels whose generated rules can't be owned by them ('ruler' and 'file' rules outputs, or rules namespace), that deletes the rules before the PrometheusServiceLevel is deleted.").BoolVar(&c.noRulesFinalizer)
+	cmd.Flag("alert-windows-catalog", "The '<namespace>/<name>' ConfigMap with the alert windows catalog of each SLO period used by the PrometheusServiceLevels that don't reference one (alertWindowsCatalog), by default disabled.").StringVar(&c.alertCatalog)
+	cmd.Flag("deletion-grace-period", "The time the retained Prometheus rules will wait before being deleted, only used with 'retain' deletion policy.").Default("24h").DurationVar(&c.deletionGrace)
+	cmd.Flag("orphan-rules-gc-interval", "The interval between the checks that delete the generated PrometheusRules whose PrometheusServiceLevel doesn't exist anymore (e.g lost owner reference or restored from a backup), only used with 'prometheus-operator' rules output, by default disabled.").DurationVar(&c.orphanRulesGC)
+	cmd.Flag("inventory-interval", "The interval between the managed SLO spec objects, SLOs and generated rules inventory metrics updates.").Default("1m").DurationVar(&c.inventoryInterval)
+	cmd.Flag("adopt-existing-rules", "Adopt the already existing PrometheusRules not managed by Sloth (e.g hand-written) with the same name as the generated ones, overwriting them and setting the PrometheusServiceLevel as the owner, instead of failing, only used with 'prometheus-operator' rules output.").BoolVar(&c.adoptRules)
+	cmd.Flag("repair-rules-drift", "Watch the generated PrometheusRules and handle again their PrometheusServiceLevel as soon as these are edited or deleted, instead of waiting for the resync, only used with 'prometheus-operator' rules output.").BoolVar(&c.repairDrift)
+	cmd.Flag("server-side-apply", "Store the generated PrometheusRules, ConfigMaps and VMRules using server-side apply, so the fields set by other controllers or admission policies are not overwritten on every handling.").BoolVar(&c.serverSideApply)
+	cmd.Flag("field-manager", "The server-side apply field manager of the generated objects.").Default("sloth").StringVar(&c.fieldManager)
+	cmd.Flag("shard-index", "The shard of this controller replica, in the [0, total-shards) range.").IntVar(&c.shardIndex)
+	cmd.Flag("total-shards", "The number of shards, the PrometheusServiceLevels are spread between the controller replicas using the hash of their namespace and name, by default disabled.").IntVar(&c.totalShards)
+	cmd.Flag("remote-cluster", "Handle also the SLO specs of a remote cluster, using the kubeconfig file (e.g a mounted secret) of the cluster, requires 'ruler' rules output ('name=kubeconfig-path' form, can be repeated).").StringMapVar(&c.remoteClusters)
+	cmd.Flag("remote-cluster-label", "The external label set with the remote cluster name on its SLOs.").Default("cluster").StringVar(&c.remoteClusterLabel)
+	cmd.Flag("webhook-listen-addr", "The listen address for the PrometheusServiceLevel mutating (defaulting) and conversion webhooks, by default disabled.").StringVar(&c.webhookListenAddr)
+	cmd.Flag("webhook-tls-cert-file", "The TLS certificate file of the webhook server.").StringVar(&c.webhookTLSCert)
+	cmd.Flag("webhook-tls-key-file", "The TLS key file of the webhook server.").StringVar(&c.webhookTLSKey)
+	cmd.Flag("default-time-window", "The SLO time window the webhook will set on the SLOs without it (e.g 28d).").StringVar(&c.defaultTimeWindow)
+	cmd.Flag("default-labels", "Labels the webhook will set on the PrometheusServiceLevel spec labels, the spec ones take precedence ('key=value' form, can be repeated).").StringMapVar(&c.defaultLabels)
+	cmd.Flag("default-alert-annotations", "Annotations the webhook will set on the SLOs alerting annotations, the SLO ones take precedence ('key=value' form, can be repeated).").StringMapVar(&c.defaultAnnotations)
 
 	return c
 }
 
 func (k kubeControllerCommand) Name() string { return "kubernetes-controller" }
 func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error {
+	shard := kubecontroller.Shard{Index: k.shardIndex, Total: k.totalShards}
+	err := shard.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid shard: %w", err)
+	}
+	if shard.Total > 1 {
+		config.Logger.Infof("Controller sharding enabled, shard %d of %d", shard.Index, shard.Total)
+	}
+
+	if k.rulesOutput != rulesOutputPrometheusOperator && (k.rulesNamespace != "" || k.deletionPolicy == deletionPolicyRetain) {
+		return fmt.Errorf("%s rules output doesn't support rules namespace nor %q deletion policy", k.rulesOutput, deletionPolicyRetain)
+	}
+
+	if k.orphanRulesGC > 0 && k.rulesOutput != rulesOutputPrometheusOperator {
+		return fmt.Errorf("%s rules output doesn't support orphan rules garbage collection", k.rulesOutput)
+	}
+
+	if k.repairDrift && (k.rulesOutput != rulesOutputPrometheusOperator || k.specSource != specSourcePrometheusServiceLevel) {
+		return fmt.Errorf("rules drift repair requires %s rules output and %s spec source", rulesOutputPrometheusOperator, specSourcePrometheusServiceLevel)
+	}
+
+	if k.serverSideApply && k.fieldManager == "" {
+		return fmt.Errorf("server-side apply requires a field manager")
+	}
+
+	if k.secondaryOutput != "" {
+		if k.secondaryOutput == k.rulesOutput {
+			return fmt.Errorf("secondary rules output can't be the same as the rules output")
+		}
+		if k.specSource == specSourceConfigMap && k.secondaryOutput == rulesOutputConfigMap {
+			return fmt.Errorf("%s spec source doesn't support %s secondary rules output", k.specSource, k.secondaryOutput)
+		}
+		if len(k.remoteClusters) > 0 {
+			return fmt.Errorf("remote clusters don't support secondary rules output")
+		}
+	}
+
+	if (k.rulesOutput == rulesOutputFile || k.secondaryOutput == rulesOutputFile) && k.rulesDir == "" {
+		return fmt.Errorf("%s rules output requires a rules directory", rulesOutputFile)
+	}
+
+	if k.specSource == specSourceConfigMap {
+		if k.selector == "" {
+			return fmt.Errorf("%s spec source requires a label selector", k.specSource)
+		}
+		if k.rulesOutput == rulesOutputConfigMap {
+			return fmt.Errorf("%s spec source doesn't support %s rules output", k.specSource, k.rulesOutput)
+		}
+		if k.rulesNamespace != "" || k.deletionPolicy == deletionPolicyRetain {
+			return fmt.Errorf("%s spec source doesn't support rules namespace nor %q deletion policy", k.specSource, deletionPolicyRetain)
+		}
+	}
+
+	if len(k.remoteClusters) > 0 {
+		if k.rulesOutput != rulesOutputRuler {
+			return fmt.Errorf("remote clusters require %s rules output", rulesOutputRuler)
+		}
+		if k.namespaceSelector != "" {
+			return fmt.Errorf("remote clusters don't support namespace label selector")
+		}
+		for name := range k.remoteClusters {
+			// The cluster name is part of the ruler namespaces and the metrics, so these can't collide.
+			if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+				return fmt.Errorf("invalid %q remote cluster name: %s", name, strings.Join(errs, ", "))
+			}
+			if name == kubecontroller.LocalCluster {
+				return fmt.Errorf("%q remote cluster name is reserved for the local cluster", name)
+			}
+		}
+	}
+
+	// The rules that can't be owned by the PrometheusServiceLevels are deleted by the handler using a finalizer,
+	// unless retained on purpose.
+	rulesFinalizer := !k.noRulesFinalizer &&
+		k.specSource == specSourcePrometheusServiceLevel &&
+		k.deletionPolicy == deletionPolicyDelete &&
+		(k.rulesNamespace != "" || k.rulesOutput == rulesOutputRuler || k.rulesOutput == rulesOutputFile)
+
+	// Cross namespace rules can't be owned by the PrometheusServiceLevels.
+	if k.rulesNamespace != "" && k.deletionPolicy != deletionPolicyRetain {
+		config.Logger.Infof("Rules namespace set, using %q deletion policy", deletionPolicyRetain)
+		k.deletionPolicy = deletionPolicyRetain
+	}
+
 	// Load Kubernetes clients.
 	config.Logger.Infof("Loading Kubernetes configuration...")
 	kcfg, err := k.loadKubernetesConfig()
@@ -74,24 +286,129 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 		return fmt.Errorf("could not load Kubernetes configuration: %w", err)
 	}
 
-	kSlothcli, err := slothclientset.NewForConfig(kcfg)
+	ksvc, kCli, err := newKubernetesService(kcfg, config.Logger)
 	if err != nil {
-		return fmt.Errorf("could not create Kubernetes sloth client: %w", err)
+		return err
 	}
+	eventScheme := runtime.NewScheme()
+	err = slothscheme.AddToScheme(eventScheme)
+	if err != nil {
+		return fmt.Errorf("could not register sloth types on events scheme: %w", err)
+	}
+	err = kubescheme.AddToScheme(eventScheme)
+	if err != nil {
+		return fmt.Errorf("could not register Kubernetes types on events scheme: %w", err)
+	}
+	eventRecorder, stopEvents := newEventRecorder(kCli, eventScheme)
+	defer stopEvents()
 
-	kmonitoringCli, err := monitoringclientset.NewForConfig(kcfg)
+	// A single namespace is targeted directly, multiple namespaces or a namespace selector
+	// target all the namespaces and filter them.
+	namespace := ""
+	if len(k.namespaces) == 1 && k.namespaceSelector == "" {
+		namespace = k.namespaces[0]
+	}
+	nsSelector, err := labels.Parse(k.namespaceSelector)
 	if err != nil {
-		return fmt.Errorf("could not create Kubernetes monitoring (prometheus-operator) client: %w", err)
+		return fmt.Errorf("invalid namespace label selector: %w", err)
+	}
+	nsFilterConfig := kubecontroller.NamespaceFilterConfig{
+		Namespaces:    k.namespaces,
+		LabelSelector: nsSelector,
+	}
+
+	// The local cluster and the remote ones, the rules of all of them are stored by this controller.
+	clusters := []*kubeControllerCluster{newKubeControllerCluster(kubecontroller.LocalCluster, false, ksvc, eventRecorder)}
+	for name, path := range k.remoteClusters {
+		rcfg, err := k.loadRemoteKubernetesConfig(path)
+		if err != nil {
+			return fmt.Errorf("could not load %q remote cluster Kubernetes configuration: %w", name, err)
+		}
+		rksvc, rkCli, err := newKubernetesService(rcfg, config.Logger.WithValues(log.Kv{"cluster": name}))
+		if err != nil {
+			return fmt.Errorf("could not create %q remote cluster clients: %w", name, err)
+		}
+		reventRecorder, stopEvents := newEventRecorder(rkCli, eventScheme)
+		defer stopEvents()
+		clusters = append(clusters, newKubeControllerCluster(name, true, rksvc, reventRecorder))
 	}
-	ksvc := k8sprometheus.NewKubernetesService(kSlothcli, kmonitoringCli, config.Logger)
 
-	// Check we can get Sloth CRs without problem before starting everything. This is a hard
-	// dependency, if we can't then fail.
-	_, err = ksvc.ListPrometheusServiceLevels(ctx, k.namespace, map[string]string{})
+	livenessChecks := map[string]health.Checker{}
+	for _, c := range clusters {
+		checkName := "informer"
+		if c.remote {
+			checkName = "informer-" + c.name
+		}
+		livenessChecks[checkName] = c.informerHealth
+		if k.specSource == specSourceConfigMap {
+			livenessChecks[checkName] = c.cmInformerHealth
+		}
+	}
+	if !nsSelector.Empty() {
+		informerFactory := informers.NewSharedInformerFactory(kCli, k.resyncInterval)
+		nsInformer := informerFactory.Core().V1().Namespaces()
+		nsFilterConfig.NamespaceLister = nsInformer.Lister()
+		livenessChecks["namespace-informer"] = health.CheckerFunc(func(ctx context.Context) error {
+			if !nsInformer.Informer().HasSynced() {
+				return fmt.Errorf("informer cache not synced")
+			}
+			return nil
+		})
+		informerFactory.Start(ctx.Done())
+		informerFactory.WaitForCacheSync(ctx.Done())
+	}
+	nsFilter, err := kubecontroller.NewNamespaceFilter(nsFilterConfig)
+	if err != nil {
+		return fmt.Errorf("could not create namespace filter: %w", err)
+	}
+	selector, err := labels.Parse(k.selector)
 	if err != nil {
-		return fmt.Errorf("check for PrometheusServiceLevel CRD failed: could not list: %w", err)
+		return fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	// Check we can get the specs of all the clusters without problem before starting everything.
+	// This is a hard dependency, if we can't then fail.
+	for _, c := range clusters {
+		logger := config.Logger.WithValues(log.Kv{"cluster": c.name})
+		if k.specSource == specSourceConfigMap {
+			_, err = c.ksvc.ListConfigMaps(ctx, namespace, selector.String())
+			if err != nil {
+				return fmt.Errorf("check for %q cluster spec ConfigMaps failed: could not list: %w", c.name, err)
+			}
+			logger.Debugf("Spec ConfigMaps ready")
+		} else {
+			_, err = c.ksvc.ListPrometheusServiceLevels(ctx, namespace, map[string]string{})
+			if err != nil {
+				return fmt.Errorf("check for %q cluster PrometheusServiceLevel CRD failed: could not list: %w", c.name, err)
+			}
+			logger.Debugf("PrometheusServiceLevel CRD ready")
+		}
+	}
+
+	metricsRecorder := metrics.NewPrometheusRecorder(prometheusclient.DefaultRegisterer)
+
+	// Load the webhook certificate once, so the health check checks the served certificate.
+	var webhookCert tls.Certificate
+	if k.webhookListenAddr != "" {
+		webhookCert, err = tls.LoadX509KeyPair(k.webhookTLSCert, k.webhookTLSKey)
+		if err != nil {
+			return fmt.Errorf("could not load webhook TLS certificate: %w", err)
+		}
+		livenessChecks["webhook-certificate"], err = health.NewTLSCertificateChecker(webhookCert, 0)
+		if err != nil {
+			return fmt.Errorf("could not create webhook certificate health check: %w", err)
+		}
+	}
+
+	// Readiness checks the liveness and the dependencies.
+	readinessChecks := map[string]health.Checker{
+		"kubernetes-api": health.CheckerFunc(func(ctx context.Context) error {
+			return kCli.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Error()
+		}),
+	}
+	for name, c := range livenessChecks {
+		readinessChecks[name] = c
 	}
-	config.Logger.Debugf("PrometheusServiceLevel CRD ready")
 
 	// Prepare our run entrypoints.
 	var g run.Group
@@ -125,6 +442,38 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 		// Metrics.
 		mux.Handle(k.metricsPath, promhttp.Handler())
 
+		// Health checks.
+		livenessHandler, err := health.NewHandler(health.HandlerConfig{Checks: livenessChecks, Logger: config.Logger})
+		if err != nil {
+			return fmt.Errorf("could not create liveness handler: %w", err)
+		}
+		readinessHandler, err := health.NewHandler(health.HandlerConfig{Checks: readinessChecks, Logger: config.Logger})
+		if err != nil {
+			return fmt.Errorf("could not create readiness handler: %w", err)
+		}
+		mux.Handle("/healthz", livenessHandler)
+		mux.Handle("/readyz", readinessHandler)
+
+		// Managed SLOs.
+		if k.slosAPITokenFile != "" {
+			token, err := os.ReadFile(k.slosAPITokenFile)
+			if err != nil {
+				return fmt.Errorf("could not read SLOs API token file: %w", err)
+			}
+			slosHandler, err := kubecontroller.NewSLOsAPIHandler(kubecontroller.SLOsAPIHandlerConfig{
+				Repository:      ksvc,
+				Token:           strings.TrimSpace(string(token)),
+				Namespace:       namespace,
+				NamespaceFilter: nsFilter,
+				Selector:        selector,
+				Logger:          config.Logger,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create SLOs API handler: %w", err)
+			}
+			mux.Handle("/api/v1/slos", slosHandler)
+		}
+
 		// Pprof.
 		mux.HandleFunc("/debug/pprof/", pprof.Index)
 		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
@@ -153,6 +502,185 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 		)
 	}
 
+	// Mutating webhook HTTP server.
+	if k.webhookListenAddr != "" {
+		defaulter, err := kubewebhook.NewDefaulter(kubewebhook.DefaulterConfig{
+			TimeWindow:       k.defaultTimeWindow,
+			Labels:           k.defaultLabels,
+			AlertAnnotations: k.defaultAnnotations,
+			Logger:           config.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create PrometheusServiceLevel defaulter: %w", err)
+		}
+
+		mutatingHandler, err := kubewebhook.NewMutatingHandler(kubewebhook.MutatingHandlerConfig{
+			Mutator: defaulter,
+			Logger:  config.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create mutating webhook handler: %w", err)
+		}
+
+		conversionHandler, err := kubewebhook.NewConversionHandler(kubewebhook.ConversionHandlerConfig{
+			Logger: config.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create conversion webhook handler: %w", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("/webhooks/mutating/prometheusservicelevel", mutatingHandler)
+		mux.Handle("/webhooks/conversion/prometheusservicelevel", conversionHandler)
+		server := &http.Server{
+			Addr:      k.webhookListenAddr,
+			Handler:   mux,
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{webhookCert}},
+		}
+
+		g.Add(
+			func() error {
+				config.Logger.WithValues(log.Kv{"addr": k.webhookListenAddr}).Infof("Webhook https server listening")
+				return server.ListenAndServeTLS("", "")
+			},
+			func(_ error) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				err := server.Shutdown(ctx)
+				if err != nil {
+					config.Logger.Errorf("Error shutting down webhook server: %w", err)
+				}
+			},
+		)
+	}
+
+	if k.dryRun {
+		config.Logger.Warningf("Dry run mode enabled, rules will not be stored")
+	}
+
+	// Retained Prometheus rules deletion.
+	if k.deletionPolicy == deletionPolicyRetain && !k.dryRun {
+		rulesNamespace := namespace
+		if k.rulesNamespace != "" {
+			rulesNamespace = k.rulesNamespace
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		collector, err := kubecontroller.NewPendingDeletionCollector(kubecontroller.PendingDeletionCollectorConfig{
+			Repository:      ksvc,
+			Namespace:       rulesNamespace,
+			NamespaceFilter: nsFilter,
+			Shard:           shard,
+			GracePeriod:     k.deletionGrace,
+			MetricsRecorder: metricsRecorder,
+			Logger:          config.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create pending deletion collector: %w", err)
+		}
+
+		g.Add(
+			func() error {
+				return collector.Run(ctx)
+			},
+			func(_ error) {
+				cancel()
+			},
+		)
+	}
+
+	// Orphan Prometheus rules deletion.
+	if k.orphanRulesGC > 0 && !k.dryRun {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		collector, err := kubecontroller.NewOrphanRulesCollector(kubecontroller.OrphanRulesCollectorConfig{
+			Repository:      ksvc,
+			Namespace:       namespace,
+			NamespaceFilter: nsFilter,
+			Shard:           shard,
+			Interval:        k.orphanRulesGC,
+			Logger:          config.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create orphan rules collector: %w", err)
+		}
+
+		g.Add(
+			func() error {
+				return collector.Run(ctx)
+			},
+			func(_ error) {
+				cancel()
+			},
+		)
+	}
+
+	// The local cluster reconcile metrics, the inventory forgets the deleted objects.
+	reconcileMetrics := kubecontroller.NewReconcileMetrics(kubecontroller.LocalCluster, metricsRecorder)
+
+	// Managed SLOs inventory metrics.
+	{
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		collector, err := kubecontroller.NewInventoryCollector(kubecontroller.InventoryCollectorConfig{
+			Repository:       ksvc,
+			ConfigMapSource:  k.specSource == specSourceConfigMap,
+			Namespace:        namespace,
+			NamespaceFilter:  nsFilter,
+			Selector:         selector,
+			Shard:            shard,
+			Interval:         k.inventoryInterval,
+			ReconcileMetrics: reconcileMetrics,
+			MetricsRecorder:  metricsRecorder,
+			Logger:           config.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create inventory collector: %w", err)
+		}
+
+		g.Add(
+			func() error {
+				return collector.Run(ctx)
+			},
+			func(_ error) {
+				cancel()
+			},
+		)
+	}
+
+	// Rule files of deleted SLO specs deletion.
+	if (k.rulesOutput == rulesOutputFile || k.secondaryOutput == rulesOutputFile) && !k.dryRun {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		collector, err := kubecontroller.NewRuleFilesCollector(kubecontroller.RuleFilesCollectorConfig{
+			KubernetesRepository: ksvc,
+			RuleFilesRepository:  k8sprometheus.NewRuleFileRepo(k.rulesDir, config.Logger),
+			ConfigMapSource:      k.specSource == specSourceConfigMap,
+			Namespace:            namespace,
+			NamespaceFilter:      nsFilter,
+			Shard:                shard,
+			Interval:             k.rulesDirGC,
+			Logger:               config.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create rule files collector: %w", err)
+		}
+
+		g.Add(
+			func() error {
+				return collector.Run(ctx)
+			},
+			func(_ error) {
+				cancel()
+			},
+		)
+	}
+
 	// Main controller.
 	{
 		ctx, cancel := context.WithCancel(ctx)
@@ -164,6 +692,7 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 			SLIRecordingRulesGenerator:  prometheus.SLIRecordingRulesGenerator,
 			MetaRecordingRulesGenerator: prometheus.MetadataRecordingRulesGenerator,
 			SLOAlertRulesGenerator:      prometheus.SLOAlertRulesGenerator,
+			MetricsRecorder:             metricsRecorder,
 			Logger:                      generatorLogger{Logger: config.Logger},
 		})
 		if err != nil {
@@ -171,47 +700,274 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 		}
 
 		// Create handler.
-		config := kubecontroller.HandlerConfig{
-			Generator:        generator,
-			SpecLoader:       k8sprometheus.CRSpecLoader,
-			Repository:       k8sprometheus.NewPrometheusOperatorCRDRepo(ksvc, config.Logger),
-			KubeStatusStorer: ksvc,
-			ExtraLabels:      k.extraLabels,
-			Logger:           config.Logger,
-		}
-		handler, err := kubecontroller.NewHandler(config)
+		repo, err := k.newRepository(k.rulesOutput, ksvc, config.Logger)
 		if err != nil {
-			return fmt.Errorf("could not create controller handler: %w", err)
+			return err
+		}
+		var secondaryRepo kubecontroller.Repository
+		if k.secondaryOutput != "" {
+			secondaryRepo, err = k.newRepository(k.secondaryOutput, ksvc, config.Logger)
+			if err != nil {
+				return fmt.Errorf("could not create secondary rules output: %w", err)
+			}
+		}
+		kooperMetricsRecorder := kooperprometheus.New(kooperprometheus.Config{})
+		catalogRepos := make([]*k8sprometheus.AlertWindowsCatalogRepo, 0, len(clusters))
+		for _, c := range clusters {
+			logger := config.Logger
+			ctrlName := "sloth"
+			clusterRepo := repo
+			externalLabels := k.externalLabels
+			if c.remote {
+				logger = logger.WithValues(log.Kv{"cluster": c.name})
+				ctrlName = "sloth-" + c.name
+				clusterRepo = repo.(k8sprometheus.RulerRepo).WithNamespacePrefix(c.name)
+				externalLabels = map[string]string{}
+				for key, value := range k.externalLabels {
+					externalLabels[key] = value
+				}
+				externalLabels[k.remoteClusterLabel] = c.name
+			}
+			clusterReconcileMetrics := reconcileMetrics
+			if c.remote {
+				clusterReconcileMetrics = kubecontroller.NewReconcileMetrics(c.name, metricsRecorder)
+			}
+			var rulesDeleter kubecontroller.RulesDeleter
+			if rulesFinalizer {
+				rulesDeleter, _ = clusterRepo.(kubecontroller.RulesDeleter)
+			}
+
+			catalogRepo := k8sprometheus.NewAlertWindowsCatalogRepo(c.ksvc, 0, logger)
+			catalogRepos = append(catalogRepos, catalogRepo)
+
+			handler, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator:                     generator,
+				SpecLoader:                    k8sprometheus.CRSpecLoader,
+				Repository:                    clusterRepo,
+				SecondaryRepository:           secondaryRepo,
+				KubeStatusStorer:              c.ksvc,
+				RulesDeleter:                  rulesDeleter,
+				KubeFinalizerStorer:           c.ksvc,
+				EventRecorder:                 c.eventRecorder,
+				MetricsRecorder:               metricsRecorder,
+				ReconcileMetrics:              clusterReconcileMetrics,
+				ExtraLabels:                   k.extraLabels,
+				ExternalLabels:                externalLabels,
+				RuleSelectorLabels:            k.ruleSelectorLabels,
+				RuleAnnotations:               k.ruleAnnotations,
+				RulesNamespace:                k.rulesNamespace,
+				DryRun:                        k.dryRun,
+				Logger:                        logger,
+				AlertWindowsCatalogRepository: catalogRepo,
+				AlertWindowsCatalog:           k.alertCatalog,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create %q cluster controller handler: %w", c.name, err)
+			}
+			if k.errorBackoff > 0 {
+				handler, err = kubecontroller.NewErrorBackoffHandler(kubecontroller.ErrorBackoffHandlerConfig{
+					Handler:    handler,
+					Retries:    k.processingRetries,
+					Backoff:    k.errorBackoff,
+					MaxBackoff: k.maxErrorBackoff,
+					Logger:     logger,
+				})
+				if err != nil {
+					return fmt.Errorf("could not create %q cluster controller error backoff handler: %w", c.name, err)
+				}
+			}
+
+			// Repair the local cluster drifted rules.
+			if !c.remote && k.repairDrift && !k.dryRun {
+				rulesNamespace := namespace
+				if k.rulesNamespace != "" {
+					rulesNamespace = k.rulesNamespace
+				}
+				repairer, err := kubecontroller.NewRulesDriftRepairer(kubecontroller.RulesDriftRepairerConfig{
+					Repository:      ksvc,
+					Handler:         handler,
+					Namespace:       rulesNamespace,
+					NamespaceFilter: nsFilter,
+					Selector:        selector,
+					Shard:           shard,
+					Logger:          logger,
+				})
+				if err != nil {
+					return fmt.Errorf("could not create rules drift repairer: %w", err)
+				}
+
+				g.Add(
+					func() error {
+						return repairer.Run(ctx)
+					},
+					func(_ error) {
+						cancel()
+					},
+				)
+			}
+
+			// Create retriever.
+			ret := kubecontroller.NewPrometheusServiceLevelsRetriver(namespace, nsFilter, selector, shard, c.informerHealth)
+			if k.specSource == specSourceConfigMap {
+				ret = kubecontroller.NewConfigMapSpecsRetriever(namespace, nsFilter, selector, shard, c.cmInformerHealth)
+			}
+
+			ctrl, err := koopercontroller.New(&koopercontroller.Config{
+				Handler:              handler,
+				Retriever:            ret,
+				Logger:               kooperlogger{Logger: logger.WithValues(log.Kv{"lib": "kooper"})},
+				Name:                 ctrlName,
+				ConcurrentWorkers:    k.workers,
+				ProcessingJobRetries: k.processingRetries,
+				ResyncInterval:       k.resyncInterval,
+				MetricsRecorder:      kooperMetricsRecorder,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create %q cluster controller: %w", c.name, err)
+			}
+
+			g.Add(
+				func() error {
+					return ctrl.Run(ctx)
+				},
+				func(_ error) {
+					cancel()
+				},
+			)
 		}
 
-		// Create retriever.
-		ret := kubecontroller.NewPrometheusServiceLevelsRetriver(k.namespace, ksvc)
+		// Reload the alert windows catalogs on SIGHUP, the objects use them on their next handling.
+		{
+			hupC := make(chan os.Signal, 1)
+			exitC := make(chan struct{})
+			signal.Notify(hupC, syscall.SIGHUP)
 
-		ctrl, err := koopercontroller.New(&koopercontroller.Config{
-			Handler:              handler,
-			Retriever:            ret,
-			Logger:               kooperlogger{Logger: config.Logger.WithValues(log.Kv{"lib": "kooper"})},
-			Name:                 "sloth",
-			ConcurrentWorkers:    k.workers,
-			ProcessingJobRetries: 2,
-			ResyncInterval:       k.resyncInterval,
-			MetricsRecorder:      kooperprometheus.New(kooperprometheus.Config{}),
+			g.Add(
+				func() error {
+					for {
+						select {
+						case <-hupC:
+							for _, r := range catalogRepos {
+								r.Invalidate()
+							}
+							config.Logger.Infof("Signal %s received, alert windows catalogs reloaded", syscall.SIGHUP)
+						case <-exitC:
+							return nil
+						}
+					}
+				},
+				func(_ error) {
+					signal.Stop(hupC)
+					close(exitC)
+				},
+			)
+		}
+	}
+
+	return g.Run()
+}
+
+// newRepository returns the repository that stores the generated rules on the rules output.
+func (k kubeControllerCommand) newRepository(output string, ksvc k8sprometheus.KubernetesService, logger log.Logger) (kubecontroller.Repository, error) {
+	if k.serverSideApply {
+		ksvc = ksvc.WithServerSideApply(k.fieldManager)
+	}
+
+	switch output {
+	case rulesOutputConfigMap:
+		return k8sprometheus.NewConfigMapRepo(ksvc, k.configMapKeyLayout, logger), nil
+	case rulesOutputFile:
+		return k8sprometheus.NewRuleFileRepo(k.rulesDir, logger), nil
+	case rulesOutputVMRule:
+		return k8sprometheus.NewVMRuleRepo(ksvc, logger), nil
+	case rulesOutputRuler:
+		rulerCli, err := ruler.NewClient(ruler.ClientConfig{
+			URL:       k.rulerURL,
+			APIPrefix: k.rulerAPIPrefix,
+			Tenant:    k.rulerTenant,
+			Logger:    logger,
 		})
 		if err != nil {
-			return fmt.Errorf("could not create namespace controller: %w", err)
+			return nil, fmt.Errorf("could not create ruler client: %w", err)
 		}
+		return k8sprometheus.NewRulerRepo(rulerCli, logger), nil
+	}
 
-		g.Add(
-			func() error {
-				return ctrl.Run(ctx)
-			},
-			func(_ error) {
-				cancel()
-			},
-		)
+	promOpRepo := k8sprometheus.NewPrometheusOperatorCRDRepo(ksvc, logger)
+	if k.deletionPolicy == deletionPolicyRetain {
+		promOpRepo = k8sprometheus.NewRetainPrometheusOperatorCRDRepo(ksvc, logger)
+	}
+	if k.rulesMaxSize > 0 {
+		promOpRepo = promOpRepo.WithSplit(k.rulesMaxSize, ksvc)
 	}
+	return promOpRepo.WithOwnershipCheck(ksvc, k.adoptRules).WithDeleter(ksvc), nil
+}
 
-	return g.Run()
+// kubeControllerCluster is a cluster whose SLO specs are handled by the controller.
+type kubeControllerCluster struct {
+	name             string
+	remote           bool
+	ksvc             k8sprometheus.KubernetesService
+	eventRecorder    record.EventRecorder
+	informerHealth   *kubecontroller.InformerHealthCheck
+	cmInformerHealth *kubecontroller.ConfigMapInformerHealthCheck
+}
+
+func newKubeControllerCluster(name string, remote bool, ksvc k8sprometheus.KubernetesService, eventRecorder record.EventRecorder) *kubeControllerCluster {
+	return &kubeControllerCluster{
+		name:             name,
+		remote:           remote,
+		ksvc:             ksvc,
+		eventRecorder:    eventRecorder,
+		informerHealth:   kubecontroller.NewInformerHealthCheck(ksvc, 0),
+		cmInformerHealth: kubecontroller.NewConfigMapInformerHealthCheck(ksvc, 0),
+	}
+}
+
+// newKubernetesService creates the Kubernetes clients of a cluster.
+func newKubernetesService(kcfg *rest.Config, logger log.Logger) (k8sprometheus.KubernetesService, kubernetes.Interface, error) {
+	kSlothcli, err := slothclientset.NewForConfig(kcfg)
+	if err != nil {
+		return k8sprometheus.KubernetesService{}, nil, fmt.Errorf("could not create Kubernetes sloth client: %w", err)
+	}
+
+	kmonitoringCli, err := monitoringclientset.NewForConfig(kcfg)
+	if err != nil {
+		return k8sprometheus.KubernetesService{}, nil, fmt.Errorf("could not create Kubernetes monitoring (prometheus-operator) client: %w", err)
+	}
+	kCli, err := kubernetes.NewForConfig(kcfg)
+	if err != nil {
+		return k8sprometheus.KubernetesService{}, nil, fmt.Errorf("could not create Kubernetes core client: %w", err)
+	}
+	kDynamicCli, err := dynamic.NewForConfig(kcfg)
+	if err != nil {
+		return k8sprometheus.KubernetesService{}, nil, fmt.Errorf("could not create Kubernetes dynamic client: %w", err)
+	}
+
+	return k8sprometheus.NewKubernetesService(kCli, kDynamicCli, kSlothcli, kmonitoringCli, logger), kCli, nil
+}
+
+// newEventRecorder returns a Kubernetes events recorder of a cluster and a function to stop it.
+func newEventRecorder(kCli kubernetes.Interface, scheme *runtime.Scheme) (record.EventRecorder, func()) {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kCli.CoreV1().Events("")})
+	return eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "sloth"}), eventBroadcaster.Shutdown
+}
+
+// loadRemoteKubernetesConfig loads a remote cluster kubernetes configuration from a kubeconfig file.
+func (k kubeControllerCommand) loadRemoteKubernetesConfig(path string) (*rest.Config, error) {
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: path},
+		&clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not load configuration: %w", err)
+	}
+
+	cfg.QPS = k.kubeAPIQPS
+	cfg.Burst = k.kubeAPIBurst
+	cfg.Timeout = k.kubeAPITimeout
+
+	return cfg, nil
 }
 
 // loadKubernetesConfig loads kubernetes configuration based on flags.
@@ -241,8 +997,9 @@ func (k kubeControllerCommand) loadKubernetesConfig() (*rest.Config, error) {
 	}
 
 	// Set better cli rate limiter.
-	cfg.QPS = 100
-	cfg.Burst = 100
+	cfg.QPS = k.kubeAPIQPS
+	cfg.Burst = k.kubeAPIBurst
+	cfg.Timeout = k.kubeAPITimeout
 
 	return cfg, nil
 }
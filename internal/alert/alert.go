@@ -3,6 +3,7 @@ package alert
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -55,57 +56,121 @@ type generator bool
 // The generated alerts are generic and don't depend on any specific SLO implementation.
 const AlertGenerator = generator(false)
 
+// MWMBAlertOverride overrides the default window and burn rate settings of an alert,
+// the zero values are ignored.
+type MWMBAlertOverride struct {
+	ShortWindow    time.Duration
+	LongWindow     time.Duration
+	BurnRateFactor float64
+}
+
+// MWMBAlertGroupOverride has the overrides of all the alerts of an SLO.
+type MWMBAlertGroupOverride struct {
+	PageQuick   MWMBAlertOverride
+	PageSlow    MWMBAlertOverride
+	TicketQuick MWMBAlertOverride
+	TicketSlow  MWMBAlertOverride
+}
+
 type SLO struct {
 	ID         string
 	TimeWindow time.Duration
 	Objective  float64
+	Overrides  MWMBAlertGroupOverride
 }
 
 func (g generator) GenerateMWMBAlerts(ctx context.Context, slo SLO) (*MWMBAlertGroup, error) {
-	if slo.TimeWindow != 30*24*time.Hour {
-		return nil, fmt.Errorf("only 30 day SLO time window is supported")
+	if slo.TimeWindow < minTimeWindow {
+		return nil, fmt.Errorf("SLO time window should be at least %s", minTimeWindow)
 	}
 
 	errorBudget := 100 - slo.Objective
+	if errorBudget <= 0 {
+		return nil, fmt.Errorf("SLO objective %g has no error budget to burn", slo.Objective)
+	}
+	ws := getPeriodWindows(slo.TimeWindow)
 
 	group := MWMBAlertGroup{
 		PageQuick: MWMBAlert{
 			ID:             fmt.Sprintf("%s-page-quick", slo.ID),
-			ShortWindow:    windowPageQuickShort,
-			LongWindow:     windowPageQuickLong,
-			BurnRateFactor: speedPageQuick,
+			ShortWindow:    ws.pageQuick.short,
+			LongWindow:     ws.pageQuick.long,
+			BurnRateFactor: ws.pageQuick.speed,
 			ErrorBudget:    errorBudget,
 			Severity:       PageAlertSeverity,
 		},
 		PageSlow: MWMBAlert{
 			ID:             fmt.Sprintf("%s-page-slow", slo.ID),
-			ShortWindow:    windowPageSlowShort,
-			LongWindow:     windowPageSlowLong,
-			BurnRateFactor: speedPageSlow,
+			ShortWindow:    ws.pageSlow.short,
+			LongWindow:     ws.pageSlow.long,
+			BurnRateFactor: ws.pageSlow.speed,
 			ErrorBudget:    errorBudget,
 			Severity:       PageAlertSeverity,
 		},
 		TicketQuick: MWMBAlert{
 			ID:             fmt.Sprintf("%s-ticket-quick", slo.ID),
-			ShortWindow:    windowTicketQuickShort,
-			LongWindow:     windowTicketQuickLong,
-			BurnRateFactor: speedTicketQuick,
+			ShortWindow:    ws.ticketQuick.short,
+			LongWindow:     ws.ticketQuick.long,
+			BurnRateFactor: ws.ticketQuick.speed,
 			ErrorBudget:    errorBudget,
 			Severity:       TicketAlertSeverity,
 		},
 		TicketSlow: MWMBAlert{
 			ID:             fmt.Sprintf("%s-ticket-slow", slo.ID),
-			ShortWindow:    windowTicketSlowShort,
-			LongWindow:     windowTicketSlowLong,
-			BurnRateFactor: speedTicketSlow,
+			ShortWindow:    ws.ticketSlow.short,
+			LongWindow:     ws.ticketSlow.long,
+			BurnRateFactor: ws.ticketSlow.speed,
 			ErrorBudget:    errorBudget,
 			Severity:       TicketAlertSeverity,
 		},
 	}
 
+	// Apply the SLO specific settings.
+	overrides := []struct {
+		alert    *MWMBAlert
+		override MWMBAlertOverride
+	}{
+		{alert: &group.PageQuick, override: slo.Overrides.PageQuick},
+		{alert: &group.PageSlow, override: slo.Overrides.PageSlow},
+		{alert: &group.TicketQuick, override: slo.Overrides.TicketQuick},
+		{alert: &group.TicketSlow, override: slo.Overrides.TicketSlow},
+	}
+	for _, o := range overrides {
+		err := o.override.apply(o.alert)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q alert override: %w", o.alert.ID, err)
+		}
+	}
+
 	return &group, nil
 }
 
+func (o MWMBAlertOverride) apply(a *MWMBAlert) error {
+	if o.ShortWindow < 0 || o.LongWindow < 0 {
+		return fmt.Errorf("windows can't be negative")
+	}
+	if o.BurnRateFactor < 0 {
+		return fmt.Errorf("burn rate factor can't be negative")
+	}
+
+	if o.ShortWindow != 0 {
+		a.ShortWindow = o.ShortWindow
+	}
+	if o.LongWindow != 0 {
+		a.LongWindow = o.LongWindow
+	}
+	if o.BurnRateFactor != 0 {
+		a.BurnRateFactor = o.BurnRateFactor
+	}
+
+	// A single window override can invert the windows.
+	if a.ShortWindow >= a.LongWindow {
+		return fmt.Errorf("short window (%s) should be less than the long window (%s)", a.ShortWindow, a.LongWindow)
+	}
+
+	return nil
+}
+
 // From https://sre.google/workbook/alerting-on-slos/#recommended_parameters_for_an_slo_based_a table.
 const (
 	// Time windows.
@@ -118,24 +183,71 @@ const (
 	windowTicketSlowShort  = 6 * time.Hour
 	windowTicketSlowLong   = 3 * 24 * time.Hour
 
-	// Error budget percents for 30 day time window.
+	// Error budget percents for 30 day time window, the same ones are used with any time window.
 	ErrBudgetPercentPageQuick30D   = 2
 	ErrBudgetPercentPageSlow30D    = 5
 	ErrBudgetPercentTicketQuick30D = 10
 	ErrBudgetPercentTicketSlow30D  = 10
 )
 
-var (
-	// Error budget speeds based on a 30 day window, however once we have the factor (speed)
-	// the value can be used with any time window, that's why we calculate here.
-	// We could hardcode the factors but this way we know how are generated and we use it
-	// as as documention.
-	baseWindow       = 30 * 24 * time.Hour
-	speedPageQuick   = getBurnRateFactor(baseWindow, ErrBudgetPercentPageQuick30D, windowPageQuickLong)     // Speed: 14.4.
-	speedPageSlow    = getBurnRateFactor(baseWindow, ErrBudgetPercentPageSlow30D, windowPageSlowLong)       // Speed: 6.
-	speedTicketQuick = getBurnRateFactor(baseWindow, ErrBudgetPercentTicketQuick30D, windowTicketQuickLong) // Speed: 3.
-	speedTicketSlow  = getBurnRateFactor(baseWindow, ErrBudgetPercentTicketSlow30D, windowTicketSlowLong)   // Speed: 1.
-)
+// baseWindow is the SLO time window the recommended alert windows are based on.
+const baseWindow = 30 * 24 * time.Hour
+
+// minTimeWindow is the minimum SLO time window supported, with smaller ones the
+// derived alert windows would be too small to be useful.
+const minTimeWindow = 24 * time.Hour
+
+type periodWindow struct {
+	short time.Duration
+	long  time.Duration
+	speed float64
+}
+
+type periodWindows struct {
+	pageQuick   periodWindow
+	pageSlow    periodWindow
+	ticketQuick periodWindow
+	ticketSlow  periodWindow
+}
+
+// getPeriodWindows derives the multiwindow multiburn alert windows for any SLO time window.
+// The recommended 30 day windows are scaled to the SLO time window (rounded to minutes), this
+// way every alert consumes the same error budget percent independently of the SLO period
+// (e.g: on a 90 day SLO the page quick alert windows are 15m and 3h).
+// The burn rate factors (speeds) are calculated with the final windows, on a 30 day SLO these
+// are the recommended ones: 14.4, 6, 3 and 1.
+func getPeriodWindows(timeWindow time.Duration) periodWindows {
+	newWindow := func(errBudgetPercent float64, short, long time.Duration) periodWindow {
+		short = scaleWindow(timeWindow, short)
+		long = scaleWindow(timeWindow, long)
+		return periodWindow{
+			short: short,
+			long:  long,
+			speed: math.Round(getBurnRateFactor(timeWindow, errBudgetPercent, long)*1e4) / 1e4, // Avoid float precision noise on the rules.
+		}
+	}
+
+	return periodWindows{
+		pageQuick:   newWindow(ErrBudgetPercentPageQuick30D, windowPageQuickShort, windowPageQuickLong),
+		pageSlow:    newWindow(ErrBudgetPercentPageSlow30D, windowPageSlowShort, windowPageSlowLong),
+		ticketQuick: newWindow(ErrBudgetPercentTicketQuick30D, windowTicketQuickShort, windowTicketQuickLong),
+		ticketSlow:  newWindow(ErrBudgetPercentTicketSlow30D, windowTicketSlowShort, windowTicketSlowLong),
+	}
+}
+
+// scaleWindow scales a window based on the 30 day SLO time window to the SLO time window.
+func scaleWindow(timeWindow, window time.Duration) time.Duration {
+	if timeWindow == baseWindow {
+		return window
+	}
+
+	w := time.Duration(float64(window) * float64(timeWindow) / float64(baseWindow)).Round(time.Minute)
+	if w < time.Minute {
+		w = time.Minute
+	}
+
+	return w
+}
 
 // getBurnRateFactor calculates the burnRateFactor (speed) needed to consume all the error budget available percent
 // in a specific time window taking into account the total time window.
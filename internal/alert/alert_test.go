@@ -16,15 +16,24 @@ func TestGenerateMWMBAlerts(t *testing.T) {
 		expAlerts *alert.MWMBAlertGroup
 		expErr    bool
 	}{
-		"Generating alerts different to 30 day time window should fail.": {
+		"Generating alerts with a time window less than a day should fail.": {
 			slo: alert.SLO{
 				ID:         "test",
-				TimeWindow: 31 * 24 * time.Hour,
+				TimeWindow: 12 * time.Hour,
 				Objective:  99.9,
 			},
 			expErr: true,
 		},
 
+		"Generating alerts with a 100% objective should fail.": {
+			slo: alert.SLO{
+				ID:         "test",
+				TimeWindow: 30 * 24 * time.Hour,
+				Objective:  100,
+			},
+			expErr: true,
+		},
+
 		"Generating a 30 day time window alerts should generate the alerts correctly.": {
 			slo: alert.SLO{
 				ID:         "test",
@@ -67,6 +76,162 @@ func TestGenerateMWMBAlerts(t *testing.T) {
 				},
 			},
 		},
+
+		"Generating a 90 day time window alerts should derive the alert windows from the time window.": {
+			slo: alert.SLO{
+				ID:         "test",
+				TimeWindow: 90 * 24 * time.Hour,
+				Objective:  99.9,
+			},
+			expAlerts: &alert.MWMBAlertGroup{
+				PageQuick: alert.MWMBAlert{
+					ID:             "test-page-quick",
+					ShortWindow:    15 * time.Minute,
+					LongWindow:     3 * time.Hour,
+					BurnRateFactor: 14.4,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.PageAlertSeverity,
+				},
+				PageSlow: alert.MWMBAlert{
+					ID:             "test-page-slow",
+					ShortWindow:    90 * time.Minute,
+					LongWindow:     18 * time.Hour,
+					BurnRateFactor: 6,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.PageAlertSeverity,
+				},
+
+				TicketQuick: alert.MWMBAlert{
+					ID:             "test-ticket-quick",
+					ShortWindow:    6 * time.Hour,
+					LongWindow:     3 * 24 * time.Hour,
+					BurnRateFactor: 3,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.TicketAlertSeverity,
+				},
+				TicketSlow: alert.MWMBAlert{
+					ID:             "test-ticket-slow",
+					ShortWindow:    18 * time.Hour,
+					LongWindow:     9 * 24 * time.Hour,
+					BurnRateFactor: 1,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.TicketAlertSeverity,
+				},
+			},
+		},
+
+		"Generating a 7 day time window alerts should derive the alert windows from the time window.": {
+			slo: alert.SLO{
+				ID:         "test",
+				TimeWindow: 7 * 24 * time.Hour,
+				Objective:  99.9,
+			},
+			expAlerts: &alert.MWMBAlertGroup{
+				PageQuick: alert.MWMBAlert{
+					ID:             "test-page-quick",
+					ShortWindow:    1 * time.Minute,
+					LongWindow:     14 * time.Minute,
+					BurnRateFactor: 14.4,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.PageAlertSeverity,
+				},
+				PageSlow: alert.MWMBAlert{
+					ID:             "test-page-slow",
+					ShortWindow:    7 * time.Minute,
+					LongWindow:     84 * time.Minute,
+					BurnRateFactor: 6,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.PageAlertSeverity,
+				},
+
+				TicketQuick: alert.MWMBAlert{
+					ID:             "test-ticket-quick",
+					ShortWindow:    28 * time.Minute,
+					LongWindow:     336 * time.Minute,
+					BurnRateFactor: 3,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.TicketAlertSeverity,
+				},
+				TicketSlow: alert.MWMBAlert{
+					ID:             "test-ticket-slow",
+					ShortWindow:    84 * time.Minute,
+					LongWindow:     1008 * time.Minute,
+					BurnRateFactor: 1,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.TicketAlertSeverity,
+				},
+			},
+		},
+
+		"Generating alerts with overrides should override the alert settings.": {
+			slo: alert.SLO{
+				ID:         "test",
+				TimeWindow: 30 * 24 * time.Hour,
+				Objective:  99.9,
+				Overrides: alert.MWMBAlertGroupOverride{
+					PageQuick:   alert.MWMBAlertOverride{LongWindow: 2 * time.Hour, BurnRateFactor: 10},
+					TicketQuick: alert.MWMBAlertOverride{LongWindow: 12 * time.Hour},
+				},
+			},
+			expAlerts: &alert.MWMBAlertGroup{
+				PageQuick: alert.MWMBAlert{
+					ID:             "test-page-quick",
+					ShortWindow:    5 * time.Minute,
+					LongWindow:     2 * time.Hour,
+					BurnRateFactor: 10,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.PageAlertSeverity,
+				},
+				PageSlow: alert.MWMBAlert{
+					ID:             "test-page-slow",
+					ShortWindow:    30 * time.Minute,
+					LongWindow:     6 * time.Hour,
+					BurnRateFactor: 6,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.PageAlertSeverity,
+				},
+				TicketQuick: alert.MWMBAlert{
+					ID:             "test-ticket-quick",
+					ShortWindow:    2 * time.Hour,
+					LongWindow:     12 * time.Hour,
+					BurnRateFactor: 3,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.TicketAlertSeverity,
+				},
+				TicketSlow: alert.MWMBAlert{
+					ID:             "test-ticket-slow",
+					ShortWindow:    6 * time.Hour,
+					LongWindow:     3 * 24 * time.Hour,
+					BurnRateFactor: 1,
+					ErrorBudget:    0.09999999999999432,
+					Severity:       alert.TicketAlertSeverity,
+				},
+			},
+		},
+
+		"Generating alerts with a single window override that inverts the alert windows should fail.": {
+			slo: alert.SLO{
+				ID:         "test",
+				TimeWindow: 30 * 24 * time.Hour,
+				Objective:  99.9,
+				Overrides: alert.MWMBAlertGroupOverride{
+					PageQuick: alert.MWMBAlertOverride{ShortWindow: 2 * time.Hour},
+				},
+			},
+			expErr: true,
+		},
+
+		"Generating alerts with negative overrides should fail.": {
+			slo: alert.SLO{
+				ID:         "test",
+				TimeWindow: 30 * 24 * time.Hour,
+				Objective:  99.9,
+				Overrides: alert.MWMBAlertGroupOverride{
+					PageQuick: alert.MWMBAlertOverride{BurnRateFactor: -1},
+				},
+			},
+			expErr: true,
+		},
 	}
 
 	for name, test := range tests {
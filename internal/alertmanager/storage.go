@@ -0,0 +1,217 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/slok/sloth/internal/info"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+const (
+	// DefaultTeamLabel is the label used by default to route the alerts by team.
+	DefaultTeamLabel = "team"
+
+	serviceLabelName  = "sloth_service"
+	severityLabelName = "sloth_severity"
+)
+
+func NewIOWriterRoutesYAMLRepo(writer io.Writer, teamLabel string, logger log.Logger) IOWriterRoutesYAMLRepo {
+	if teamLabel == "" {
+		teamLabel = DefaultTeamLabel
+	}
+
+	return IOWriterRoutesYAMLRepo{
+		writer:    writer,
+		teamLabel: teamLabel,
+		logger:    logger.WithValues(log.Kv{"svc": "storage.IOWriter", "format": "alertmanager-routes"}),
+	}
+}
+
+// IOWriterRoutesYAMLRepo knows to store an Alertmanager configuration fragment (YAML) in an IOWriter
+// with the routes and receivers that match the generated SLO alerts, so the alert routing can
+// be kept in lockstep with the rules generation.
+//
+// The SLO alerts are routed by service and team (if the alerts have the team label), and every
+// service route has a child route for each alert severity. The receivers are placeholders that
+// need to be configured with the notification integrations before merging the fragment.
+type IOWriterRoutesYAMLRepo struct {
+	writer    io.Writer
+	teamLabel string
+	logger    log.Logger
+}
+
+type configYAML struct {
+	Route     routeYAML      `yaml:"route"`
+	Receivers []receiverYAML `yaml:"receivers"`
+}
+
+type routeYAML struct {
+	Receiver string      `yaml:"receiver,omitempty"`
+	Matchers []string    `yaml:"matchers,omitempty"`
+	Routes   []routeYAML `yaml:"routes,omitempty"`
+}
+
+type receiverYAML struct {
+	Name string `yaml:"name"`
+}
+
+// routeGroup are the alerts of a service and team.
+type routeGroup struct {
+	receiver string
+	// serviceMatcher matches the service alerts, empty if the Sloth service label has been dropped.
+	serviceMatcher string
+	teamMatcher    string
+	alerts         []string
+	severities     map[string]struct{}
+	// severityLabel is the name of the severity label on the alerts, empty if dropped.
+	severityLabel string
+}
+
+func (i IOWriterRoutesYAMLRepo) StoreSLOs(ctx context.Context, slos []prometheus.StorageSLO) error {
+	if len(slos) == 0 {
+		return fmt.Errorf("slos required")
+	}
+
+	groups := map[string]*routeGroup{}
+	for _, slo := range slos {
+		for _, rule := range slo.Rules.AlertRules {
+			if rule.Alert == "" {
+				continue
+			}
+
+			team := rule.Labels[i.teamLabel]
+			if team == "" {
+				team = slo.SLO.Labels[i.teamLabel]
+			}
+
+			key := slo.SLO.Service + "/" + team
+			group, ok := groups[key]
+			if !ok {
+				group = i.newRouteGroup(slo.SLO, team)
+				groups[key] = group
+			}
+
+			group.alerts = append(group.alerts, rule.Alert)
+			if group.severityLabel != "" && rule.Labels[group.severityLabel] != "" {
+				group.severities[rule.Labels[group.severityLabel]] = struct{}{}
+			}
+		}
+	}
+
+	if len(groups) == 0 {
+		return fmt.Errorf("0 SLO alerts generated")
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	config := configYAML{}
+	for _, k := range keys {
+		group := groups[k]
+
+		// Without the service label the alerts can only be matched by name.
+		matchers := []string{group.serviceMatcher}
+		if group.serviceMatcher == "" {
+			matchers = []string{alertNamesMatcher(group.alerts)}
+		}
+		if group.teamMatcher != "" {
+			matchers = append(matchers, group.teamMatcher)
+		}
+
+		route := routeYAML{Receiver: group.receiver, Matchers: matchers}
+		config.Receivers = append(config.Receivers, receiverYAML{Name: group.receiver})
+		for _, severity := range sortedKeys(group.severities) {
+			receiver := group.receiver + "-" + severity
+			route.Routes = append(route.Routes, routeYAML{
+				Receiver: receiver,
+				Matchers: []string{fmt.Sprintf("%s=%q", group.severityLabel, severity)},
+			})
+			config.Receivers = append(config.Receivers, receiverYAML{Name: receiver})
+		}
+
+		config.Route.Routes = append(config.Route.Routes, route)
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("could not marshal alertmanager routes: %w", err)
+	}
+
+	_, err = i.writer.Write([]byte(disclaimer + string(data)))
+	if err != nil {
+		return fmt.Errorf("could not write alertmanager routes: %w", err)
+	}
+
+	logger := i.logger.WithCtxValues(ctx)
+	logger.WithValues(log.Kv{"routes": len(config.Route.Routes), "receivers": len(config.Receivers)}).Infof("Alertmanager routes written")
+
+	return nil
+}
+
+// newRouteGroup returns the route group of a service and team.
+func (i IOWriterRoutesYAMLRepo) newRouteGroup(slo prometheus.SLO, team string) *routeGroup {
+	group := &routeGroup{
+		receiver:   slo.Service,
+		severities: map[string]struct{}{},
+	}
+
+	if !isSlothLabelDropped(slo, serviceLabelName) {
+		group.serviceMatcher = fmt.Sprintf("%s=%q", slo.GetLabelName(serviceLabelName), slo.Service)
+	}
+
+	if team != "" {
+		group.receiver = team + "-" + slo.Service
+		group.teamMatcher = fmt.Sprintf("%s=%q", i.teamLabel, team)
+	}
+
+	if !isSlothLabelDropped(slo, severityLabelName) {
+		group.severityLabel = slo.GetLabelName(severityLabelName)
+	}
+
+	return group
+}
+
+func isSlothLabelDropped(slo prometheus.SLO, name string) bool {
+	for _, d := range slo.SlothLabels.Drop {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+func alertNamesMatcher(alerts []string) string {
+	names := map[string]struct{}{}
+	for _, a := range alerts {
+		names[regexp.QuoteMeta(a)] = struct{}{}
+	}
+
+	return fmt.Sprintf("alertname=~%q", strings.Join(sortedKeys(names), "|"))
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var disclaimer = fmt.Sprintf(`
+---
+# Code generated by Sloth (%s): https://github.com/slok/sloth.
+# Set the receivers notification integrations and merge on the Alertmanager configuration.
+
+`, info.Version)
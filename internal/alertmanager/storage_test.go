@@ -0,0 +1,189 @@
+package alertmanager_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/alertmanager"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+func TestIOWriterRoutesYAMLRepo(t *testing.T) {
+	tests := map[string]struct {
+		teamLabel string
+		slos      []prometheus.StorageSLO
+		expYAML   string
+		expErr    bool
+	}{
+		"Having 0 SLOs should fail.": {
+			slos:   []prometheus.StorageSLO{},
+			expErr: true,
+		},
+
+		"Having SLOs without alerts should fail.": {
+			slos: []prometheus.StorageSLO{
+				{SLO: prometheus.SLO{ID: "test1", Service: "test-svc"}},
+			},
+			expErr: true,
+		},
+
+		"Having multiple SLOs should store the routes by service and severity.": {
+			slos: []prometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1", Service: "test-svc"},
+					Rules: prometheus.SLORules{AlertRules: []rulefmt.Rule{
+						{Alert: "testAlert1", Labels: map[string]string{"sloth_severity": "page"}},
+						{Alert: "testAlert1", Labels: map[string]string{"sloth_severity": "ticket"}},
+					}},
+				},
+				{
+					SLO: prometheus.SLO{ID: "test2", Service: "test-svc"},
+					Rules: prometheus.SLORules{AlertRules: []rulefmt.Rule{
+						{Alert: "testAlert2", Labels: map[string]string{"sloth_severity": "page"}},
+					}},
+				},
+				{
+					SLO: prometheus.SLO{ID: "test3", Service: "test-svc2"},
+					Rules: prometheus.SLORules{AlertRules: []rulefmt.Rule{
+						{Alert: "testAlert3", Labels: map[string]string{"sloth_severity": "ticket"}},
+					}},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# Set the receivers notification integrations and merge on the Alertmanager configuration.
+
+route:
+  routes:
+  - receiver: test-svc
+    matchers:
+    - sloth_service="test-svc"
+    routes:
+    - receiver: test-svc-page
+      matchers:
+      - sloth_severity="page"
+    - receiver: test-svc-ticket
+      matchers:
+      - sloth_severity="ticket"
+  - receiver: test-svc2
+    matchers:
+    - sloth_service="test-svc2"
+    routes:
+    - receiver: test-svc2-ticket
+      matchers:
+      - sloth_severity="ticket"
+receivers:
+- name: test-svc
+- name: test-svc-page
+- name: test-svc-ticket
+- name: test-svc2
+- name: test-svc2-ticket
+`,
+		},
+
+		"Having SLOs with teams should store the routes by service, team and severity.": {
+			teamLabel: "owner",
+			slos: []prometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1", Service: "test-svc", Labels: map[string]string{"owner": "team-a"}},
+					Rules: prometheus.SLORules{AlertRules: []rulefmt.Rule{
+						{Alert: "testAlert1", Labels: map[string]string{"sloth_severity": "page"}},
+						{Alert: "testAlert1", Labels: map[string]string{"sloth_severity": "ticket", "owner": "team-b"}},
+					}},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# Set the receivers notification integrations and merge on the Alertmanager configuration.
+
+route:
+  routes:
+  - receiver: team-a-test-svc
+    matchers:
+    - sloth_service="test-svc"
+    - owner="team-a"
+    routes:
+    - receiver: team-a-test-svc-page
+      matchers:
+      - sloth_severity="page"
+  - receiver: team-b-test-svc
+    matchers:
+    - sloth_service="test-svc"
+    - owner="team-b"
+    routes:
+    - receiver: team-b-test-svc-ticket
+      matchers:
+      - sloth_severity="ticket"
+receivers:
+- name: team-a-test-svc
+- name: team-a-test-svc-page
+- name: team-b-test-svc
+- name: team-b-test-svc-ticket
+`,
+		},
+
+		"Having SLOs with customized Sloth labels should store the routes using the customized labels.": {
+			slos: []prometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1", Service: "test-svc", SlothLabels: prometheus.SlothLabels{
+						Prefix: "myorg",
+						Drop:   []string{"sloth_service"},
+					}},
+					Rules: prometheus.SLORules{AlertRules: []rulefmt.Rule{
+						{Alert: "testAlert1", Labels: map[string]string{"myorg_severity": "page"}},
+					}},
+				},
+				{
+					SLO: prometheus.SLO{ID: "test2", Service: "test-svc", SlothLabels: prometheus.SlothLabels{
+						Prefix: "myorg",
+						Drop:   []string{"sloth_service"},
+					}},
+					Rules: prometheus.SLORules{AlertRules: []rulefmt.Rule{
+						{Alert: "test.Alert2", Labels: map[string]string{"myorg_severity": "page"}},
+					}},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# Set the receivers notification integrations and merge on the Alertmanager configuration.
+
+route:
+  routes:
+  - receiver: test-svc
+    matchers:
+    - alertname=~"testAlert1|test\\.Alert2"
+    routes:
+    - receiver: test-svc-page
+      matchers:
+      - myorg_severity="page"
+receivers:
+- name: test-svc
+- name: test-svc-page
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var gotYAML bytes.Buffer
+			repo := alertmanager.NewIOWriterRoutesYAMLRepo(&gotYAML, test.teamLabel, log.Noop)
+			err := repo.StoreSLOs(context.TODO(), test.slos)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expYAML, gotYAML.String())
+			}
+		})
+	}
+}
@@ -3,12 +3,16 @@ package generate
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/prometheus/prometheus/pkg/rulefmt"
 
 	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/info"
 	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/metrics"
 	"github.com/slok/sloth/internal/prometheus"
 )
 
@@ -18,6 +22,7 @@ type ServiceConfig struct {
 	SLIRecordingRulesGenerator  SLIRecordingRulesGenerator
 	MetaRecordingRulesGenerator MetadataRecordingRulesGenerator
 	SLOAlertRulesGenerator      SLOAlertRulesGenerator
+	MetricsRecorder             metrics.Recorder
 	Logger                      log.Logger
 }
 
@@ -38,6 +43,10 @@ func (c *ServiceConfig) defaults() error {
 		c.SLOAlertRulesGenerator = prometheus.SLOAlertRulesGenerator
 	}
 
+	if c.MetricsRecorder == nil {
+		c.MetricsRecorder = metrics.Noop
+	}
+
 	if c.Logger == nil {
 		c.Logger = log.Noop
 	}
@@ -72,6 +81,7 @@ type Service struct {
 	sliRecordRuleGen  SLIRecordingRulesGenerator
 	metaRecordRuleGen MetadataRecordingRulesGenerator
 	alertRuleGen      SLOAlertRulesGenerator
+	metricsRec        metrics.Recorder
 	logger            log.Logger
 }
 
@@ -87,6 +97,7 @@ func NewService(config ServiceConfig) (*Service, error) {
 		sliRecordRuleGen:  config.SLIRecordingRulesGenerator,
 		metaRecordRuleGen: config.MetaRecordingRulesGenerator,
 		alertRuleGen:      config.SLOAlertRulesGenerator,
+		metricsRec:        config.MetricsRecorder,
 		logger:            config.Logger,
 	}, nil
 }
@@ -98,6 +109,9 @@ type Request struct {
 	ExtraLabels map[string]string
 	// SLOGroup are the SLOs group that will be used to generate the SLO results and Prom rules.
 	SLOGroup prometheus.SLOGroup
+	// DedupSLIRecordings will make the SLI recording rules that are identical to the ones of
+	// a previous SLO, reuse the already recorded series instead of evaluating the same query again.
+	DedupSLIRecordings bool
 }
 
 type SLOResult struct {
@@ -110,6 +124,15 @@ type Response struct {
 	PrometheusSLOs []SLOResult
 }
 
+// SLOError is the generation error of a specific SLO.
+type SLOError struct {
+	SLOID string
+	Err   error
+}
+
+func (e SLOError) Error() string { return fmt.Sprintf("could not generate %q slo: %s", e.SLOID, e.Err) }
+func (e SLOError) Unwrap() error { return e.Err }
+
 func (s Service) Generate(ctx context.Context, r Request) (*Response, error) {
 	err := r.SLOGroup.Validate()
 	if err != nil {
@@ -123,14 +146,21 @@ func (s Service) Generate(ctx context.Context, r Request) (*Response, error) {
 		slo.Labels = mergeLabels(slo.Labels, r.ExtraLabels)
 
 		// Generate SLO result.
+		start := time.Now()
 		result, err := s.generateSLO(ctx, r.Info, slo)
 		if err != nil {
-			return nil, fmt.Errorf("could not generate %q slo: %w", slo.ID, err)
+			return nil, SLOError{SLOID: slo.ID, Err: err}
 		}
+		s.metricsRec.ObserveSLOGenerationDuration(ctx, slo.Service, slo.ID, time.Since(start))
 
 		results = append(results, *result)
 	}
 
+	if r.DedupSLIRecordings {
+		deduped := dedupSLIRecordingRules(results)
+		s.logger.WithCtxValues(ctx).WithValues(log.Kv{"rules": deduped}).Infof("Duplicated SLI recording rules reused")
+	}
+
 	return &Response{
 		PrometheusSLOs: results,
 	}, nil
@@ -144,6 +174,7 @@ func (s Service) generateSLO(ctx context.Context, info info.Info, slo prometheus
 		ID:         slo.ID,
 		Objective:  slo.Objective,
 		TimeWindow: slo.TimeWindow,
+		Overrides:  slo.AlertOverrides,
 	}
 	as, err := s.alertGen.GenerateMWMBAlerts(ctx, alertSLO)
 	if err != nil {
@@ -151,22 +182,34 @@ func (s Service) generateSLO(ctx context.Context, info info.Info, slo prometheus
 	}
 	logger.Infof("Multiwindow-multiburn alerts generated")
 
+	// Use the noop generators for the rule categories disabled by the SLO.
+	sliRecordRuleGen, metaRecordRuleGen, alertRuleGen := s.sliRecordRuleGen, s.metaRecordRuleGen, s.alertRuleGen
+	if slo.DisabledRules.SLIErrorRecRules {
+		sliRecordRuleGen = NoopSLIRecordingRulesGenerator
+	}
+	if slo.DisabledRules.MetadataRecRules {
+		metaRecordRuleGen = NoopMetadataRecordingRulesGenerator
+	}
+	if slo.DisabledRules.AlertRules {
+		alertRuleGen = NoopSLOAlertRulesGenerator
+	}
+
 	// Generate SLI recording rules.
-	sliRecordingRules, err := s.sliRecordRuleGen.GenerateSLIRecordingRules(ctx, slo, *as)
+	sliRecordingRules, err := sliRecordRuleGen.GenerateSLIRecordingRules(ctx, slo, *as)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate Prometheus sli recording rules: %w", err)
 	}
 	logger.WithValues(log.Kv{"rules": len(sliRecordingRules)}).Infof("SLI recording rules generated")
 
 	// Generate Metadata recording rules.
-	metaRecordingRules, err := s.metaRecordRuleGen.GenerateMetadataRecordingRules(ctx, info, slo, *as)
+	metaRecordingRules, err := metaRecordRuleGen.GenerateMetadataRecordingRules(ctx, info, slo, *as)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate Prometheus metadata recording rules: %w", err)
 	}
 	logger.WithValues(log.Kv{"rules": len(metaRecordingRules)}).Infof("Metadata recording rules generated")
 
 	// Generate Alert rules.
-	alertRules, err := s.alertRuleGen.GenerateSLOAlertRules(ctx, slo, *as)
+	alertRules, err := alertRuleGen.GenerateSLOAlertRules(ctx, slo, *as)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate Prometheus alert rules: %w", err)
 	}
@@ -183,6 +226,54 @@ func (s Service) generateSLO(ctx context.Context, info info.Info, slo prometheus
 	}, nil
 }
 
+// dedupSLIRecordingRules replaces the SLI recording rules that record the same metric with the same
+// expression of a previous SLO, with a cheap query over the series already recorded by the first one.
+// The first SLO labels are removed and the ones of the deduplicated rule will be set by the rule itself.
+// Returns the number of deduplicated rules.
+func dedupSLIRecordingRules(results []SLOResult) int {
+	type source struct {
+		record string
+		labels map[string]string
+	}
+
+	deduped := 0
+	sources := map[string]source{}
+	for _, result := range results {
+		for i, rule := range result.SLORules.SLIErrorRecRules {
+			if rule.Record == "" {
+				continue
+			}
+
+			key := rule.Record + "\n" + rule.Expr
+			src, ok := sources[key]
+			if !ok {
+				sources[key] = source{record: rule.Record, labels: rule.Labels}
+				continue
+			}
+
+			result.SLORules.SLIErrorRecRules[i].Expr = dedupedSLIRecordingExpr(src.record, src.labels)
+			deduped++
+		}
+	}
+
+	return deduped
+}
+
+func dedupedSLIRecordingExpr(record string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	matchers := make([]string, 0, len(keys))
+	for _, k := range keys {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+
+	return fmt.Sprintf("max without (%s) (%s{%s})\n", strings.Join(keys, ", "), record, strings.Join(matchers, ", "))
+}
+
 func mergeLabels(ms ...map[string]string) map[string]string {
 	res := map[string]string{}
 	for _, m := range ms {
@@ -2,10 +2,12 @@ package generate_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/prometheus/prometheus/pkg/rulefmt"
+	promqlparser "github.com/prometheus/prometheus/promql/parser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -231,12 +233,25 @@ func TestIntegrationAppServiceGenerate(t *testing.T) {
 										"sloth_window": "30d",
 									},
 								},
+								{
+									Record: "slo:sli_total:rate5m",
+									Expr:   "sum(rate(my_metric[5m]))\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "5m",
+									},
+								},
 							},
 							MetadataRecRules: []rulefmt.Rule{
 								// Metadata labels.
 								{
 									Record: "slo:objective:ratio",
-									Expr:   "vector(0.9990000000000001)",
+									Expr:   "vector(0.999)",
 									Labels: map[string]string{
 										"test_label":    "label_1",
 										"extra_k1":      "extra_v1",
@@ -248,7 +263,7 @@ func TestIntegrationAppServiceGenerate(t *testing.T) {
 								},
 								{
 									Record: "slo:error_budget:ratio",
-									Expr:   "vector(1-0.9990000000000001)",
+									Expr:   "vector(1-0.999)",
 									Labels: map[string]string{
 										"test_label":    "label_1",
 										"extra_k1":      "extra_v1",
@@ -312,6 +327,30 @@ slo:error_budget:ratio{sloth_id="test-id", sloth_service="test-svc", sloth_slo="
 										"sloth_id":      "test-id",
 									},
 								},
+								{
+									Record: "slo:period_error_budget_remaining:minutes",
+									Expr:   "slo:period_error_budget_remaining:ratio{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(slo:error_budget:ratio{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * slo:time_period:days{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * 1440)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+									},
+								},
+								{
+									Record: "slo:period_error_budget_remaining:events",
+									Expr:   "slo:period_error_budget_remaining:ratio{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left\nslo:error_budget:ratio{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(avg_over_time(slo:sli_total:rate5m{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d]) * 2592000)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+									},
+								},
 								{
 									Record: "sloth_slo_info",
 									Expr:   `vector(1)`,
@@ -329,19 +368,123 @@ slo:error_budget:ratio{sloth_id="test-id", sloth_service="test-svc", sloth_slo="
 								},
 							},
 							AlertRules: []rulefmt.Rule{
+								{
+									Record: "slo:burn_rate5m",
+									Expr:   "slo:sli_error:ratio_rate5m{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "5m",
+									},
+								},
+								{
+									Record: "slo:burn_rate30m",
+									Expr:   "slo:sli_error:ratio_rate30m{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "30m",
+									},
+								},
+								{
+									Record: "slo:burn_rate1h",
+									Expr:   "slo:sli_error:ratio_rate1h{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "1h",
+									},
+								},
+								{
+									Record: "slo:burn_rate2h",
+									Expr:   "slo:sli_error:ratio_rate2h{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "2h",
+									},
+								},
+								{
+									Record: "slo:burn_rate6h",
+									Expr:   "slo:sli_error:ratio_rate6h{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "6h",
+									},
+								},
+								{
+									Record: "slo:burn_rate1d",
+									Expr:   "slo:sli_error:ratio_rate1d{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "1d",
+									},
+								},
+								{
+									Record: "slo:burn_rate3d",
+									Expr:   "slo:sli_error:ratio_rate3d{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "3d",
+									},
+								},
+								{
+									Record: "slo:burn_rate30d",
+									Expr:   "slo:sli_error:ratio_rate30d{sloth_id=\"test-id\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/ (1 - 0.999)\n",
+									Labels: map[string]string{
+										"test_label":    "label_1",
+										"extra_k1":      "extra_v1",
+										"extra_k2":      "extra_v2",
+										"sloth_service": "test-svc",
+										"sloth_slo":     "test-name",
+										"sloth_id":      "test-id",
+										"sloth_window":  "30d",
+									},
+								},
 
 								{
 									Alert: "p_alert_test_name",
 									Expr: `(
-    (slo:sli_error:ratio_rate5m{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (14.4 * 0.0009999999999999432))
+    (slo:burn_rate5m{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 14.4)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate1h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (14.4 * 0.0009999999999999432))
+    (slo:burn_rate1h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 14.4)
 )
 or ignoring (sloth_window)
 (
-    (slo:sli_error:ratio_rate30m{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (6 * 0.0009999999999999432))
+    (slo:burn_rate30m{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 6)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate6h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (6 * 0.0009999999999999432))
+    (slo:burn_rate6h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 6)
 )
 `,
 									Labels: map[string]string{
@@ -357,15 +500,15 @@ or ignoring (sloth_window)
 								{
 									Alert: "t_alert_test_name",
 									Expr: `(
-    (slo:sli_error:ratio_rate2h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (3 * 0.0009999999999999432))
+    (slo:burn_rate2h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 3)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate1d{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (3 * 0.0009999999999999432))
+    (slo:burn_rate1d{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 3)
 )
 or ignoring (sloth_window)
 (
-    (slo:sli_error:ratio_rate6h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (1 * 0.0009999999999999432))
+    (slo:burn_rate6h{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 1)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate3d{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > (1 * 0.0009999999999999432))
+    (slo:burn_rate3d{sloth_id="test-id", sloth_service="test-svc", sloth_slo="test-name"} > 1)
 )
 `,
 									Labels: map[string]string{
@@ -404,3 +547,225 @@ or ignoring (sloth_window)
 		})
 	}
 }
+
+func TestIntegrationAppServiceGenerateDedupSLIRecordings(t *testing.T) {
+	newSLO := func(id string) prometheus.SLO {
+		return prometheus.SLO{
+			ID:      id,
+			Name:    "test-name",
+			Service: "test-svc",
+			SLI: prometheus.SLI{
+				Raw: &prometheus.SLIRaw{
+					ErrorRatioQuery: `sum(rate(my_metric{error="true"}[{{.window}}])) / sum(rate(my_metric[{{.window}}]))`,
+				},
+			},
+			TimeWindow:       30 * 24 * time.Hour,
+			Objective:        99.9,
+			PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+			WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+		}
+	}
+
+	tests := map[string]struct {
+		dedup   bool
+		expRule rulefmt.Rule
+	}{
+		"Without dedup, identical SLIs should be recorded by each SLO.": {
+			dedup: false,
+			expRule: rulefmt.Rule{
+				Record: "slo:sli_error:ratio_rate5m",
+				Expr:   "(sum(rate(my_metric{error=\"true\"}[5m])) / sum(rate(my_metric[5m])))",
+				Labels: map[string]string{
+					"sloth_id":      "test-id-2",
+					"sloth_service": "test-svc",
+					"sloth_slo":     "test-name",
+					"sloth_window":  "5m",
+				},
+			},
+		},
+
+		"With dedup, identical SLIs should reuse the recordings of the first SLO.": {
+			dedup: true,
+			expRule: rulefmt.Rule{
+				Record: "slo:sli_error:ratio_rate5m",
+				Expr:   "max without (sloth_id, sloth_service, sloth_slo, sloth_window) (slo:sli_error:ratio_rate5m{sloth_id=\"test-id-1\", sloth_service=\"test-svc\", sloth_slo=\"test-name\", sloth_window=\"5m\"})\n",
+				Labels: map[string]string{
+					"sloth_id":      "test-id-2",
+					"sloth_service": "test-svc",
+					"sloth_slo":     "test-name",
+					"sloth_window":  "5m",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			svc, err := generate.NewService(generate.ServiceConfig{})
+			require.NoError(err)
+
+			resp, err := svc.Generate(context.TODO(), generate.Request{
+				Info:               info.Info{Mode: info.ModeTest},
+				SLOGroup:           prometheus.SLOGroup{SLOs: []prometheus.SLO{newSLO("test-id-1"), newSLO("test-id-2")}},
+				DedupSLIRecordings: test.dedup,
+			})
+			require.NoError(err)
+			require.Len(resp.PrometheusSLOs, 2)
+
+			// The first SLO is always the source of the recordings.
+			assert.NotContains(resp.PrometheusSLOs[0].SLORules.SLIErrorRecRules[0].Expr, "max without")
+			assert.Equal(test.expRule, resp.PrometheusSLOs[1].SLORules.SLIErrorRecRules[0])
+		})
+	}
+}
+
+func TestIntegrationAppServiceGenerateDisabledRules(t *testing.T) {
+	tests := map[string]struct {
+		disabledRules   prometheus.DisabledRules
+		expSLIRecRules  int
+		expMetaRecRules int
+		expAlertRules   int
+	}{
+		"Without disabled rules, all the rule categories should be generated.": {
+			disabledRules:   prometheus.DisabledRules{},
+			expSLIRecRules:  9,
+			expMetaRecRules: 9,
+			expAlertRules:   10,
+		},
+
+		"Disabling the SLI recordings should only generate the metadata recordings and alerts.": {
+			disabledRules:   prometheus.DisabledRules{SLIErrorRecRules: true},
+			expSLIRecRules:  0,
+			expMetaRecRules: 9,
+			expAlertRules:   10,
+		},
+
+		"Disabling the SLI recordings and alerts should only generate the metadata recordings.": {
+			disabledRules:   prometheus.DisabledRules{SLIErrorRecRules: true, AlertRules: true},
+			expSLIRecRules:  0,
+			expMetaRecRules: 9,
+			expAlertRules:   0,
+		},
+
+		"Disabling all the rules should not generate any rule.": {
+			disabledRules:   prometheus.DisabledRules{SLIErrorRecRules: true, MetadataRecRules: true, AlertRules: true},
+			expSLIRecRules:  0,
+			expMetaRecRules: 0,
+			expAlertRules:   0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			svc, err := generate.NewService(generate.ServiceConfig{})
+			require.NoError(err)
+
+			resp, err := svc.Generate(context.TODO(), generate.Request{
+				Info: info.Info{Mode: info.ModeTest},
+				SLOGroup: prometheus.SLOGroup{SLOs: []prometheus.SLO{
+					{
+						ID:      "test-id",
+						Name:    "test-name",
+						Service: "test-svc",
+						SLI: prometheus.SLI{
+							Events: &prometheus.SLIEvents{
+								ErrorQuery: `rate(my_metric{error="true"}[{{.window}}])`,
+								TotalQuery: `rate(my_metric[{{.window}}])`,
+							},
+						},
+						TimeWindow:       30 * 24 * time.Hour,
+						Objective:        99.9,
+						PageAlertMeta:    prometheus.AlertMeta{Name: "p_alert_test_name"},
+						WarningAlertMeta: prometheus.AlertMeta{Name: "t_alert_test_name"},
+						DisabledRules:    test.disabledRules,
+					},
+				}},
+			})
+			require.NoError(err)
+			require.Len(resp.PrometheusSLOs, 1)
+
+			rules := resp.PrometheusSLOs[0].SLORules
+			assert.Len(rules.SLIErrorRecRules, test.expSLIRecRules)
+			assert.Len(rules.MetadataRecRules, test.expMetaRecRules)
+			assert.Len(rules.AlertRules, test.expAlertRules)
+			assertAlertBurnRatesRecorded(t, rules)
+		})
+	}
+}
+
+// assertAlertBurnRatesRecorded asserts that the burn rate series used by the alerts are recorded
+// by the generated rules.
+func assertAlertBurnRatesRecorded(t *testing.T, rules prometheus.SLORules) {
+	recorded := map[string]bool{}
+	for _, rs := range [][]rulefmt.Rule{rules.SLIErrorRecRules, rules.MetadataRecRules, rules.AlertRules} {
+		for _, r := range rs {
+			recorded[r.Record] = true
+		}
+	}
+
+	for _, r := range rules.AlertRules {
+		if r.Alert == "" {
+			continue
+		}
+
+		expr, err := promqlparser.ParseExpr(r.Expr)
+		require.NoError(t, err)
+		for _, path := range promqlparser.ExtractSelectors(expr) {
+			for _, m := range path {
+				if m.Name == "__name__" && strings.HasPrefix(m.Value, "slo:burn_rate") {
+					assert.True(t, recorded[m.Value], "%q alert %q series is not recorded", r.Alert, m.Value)
+				}
+			}
+		}
+	}
+}
+
+func TestIntegrationAppServiceGenerateDisabledSLIRecordingsAlerts(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Disabled SLI recordings generator, like `--disable-recordings`.
+	svc, err := generate.NewService(generate.ServiceConfig{
+		SLIRecordingRulesGenerator:  generate.NoopSLIRecordingRulesGenerator,
+		MetaRecordingRulesGenerator: generate.NoopMetadataRecordingRulesGenerator,
+	})
+	require.NoError(err)
+
+	for _, strategy := range []prometheus.AlertStrategy{prometheus.MWMBAlertStrategy, prometheus.SingleWindowAlertStrategy, prometheus.BudgetConsumedAlertStrategy} {
+		resp, err := svc.Generate(context.TODO(), generate.Request{
+			Info: info.Info{Mode: info.ModeTest},
+			SLOGroup: prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:      "test-id",
+					Name:    "test-name",
+					Service: "test-svc",
+					SLI: prometheus.SLI{
+						Events: &prometheus.SLIEvents{
+							ErrorQuery: `rate(my_metric{error="true"}[{{.window}}])`,
+							TotalQuery: `rate(my_metric[{{.window}}])`,
+						},
+					},
+					TimeWindow:        30 * 24 * time.Hour,
+					Objective:         99.9,
+					AlertStrategy:     strategy,
+					PageAlertMeta:     prometheus.AlertMeta{Name: "p_alert_test_name"},
+					WarningAlertMeta:  prometheus.AlertMeta{Name: "t_alert_test_name"},
+					ForecastAlertMeta: &prometheus.ForecastAlertMeta{Name: "f_alert_test_name"},
+				},
+			}},
+		})
+		require.NoError(err)
+		require.Len(resp.PrometheusSLOs, 1)
+
+		rules := resp.PrometheusSLOs[0].SLORules
+		assert.Empty(rules.SLIErrorRecRules)
+		assert.NotEmpty(rules.AlertRules)
+		assertAlertBurnRatesRecorded(t, rules)
+	}
+}
@@ -0,0 +1,151 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spotahome/kooper/v2/controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/slok/sloth/internal/log"
+)
+
+// ErrorBackoffHandlerConfig is the error backoff handler configuration.
+type ErrorBackoffHandlerConfig struct {
+	Handler controller.Handler
+	// Retries are the controller retries of a failed handling, the backoff starts when all
+	// the retries of a handling failed.
+	Retries int
+	// Backoff is the time a failed object will not be handled again, doubled on each consecutive
+	// failed handling.
+	Backoff time.Duration
+	// MaxBackoff is the max backoff time.
+	MaxBackoff time.Duration
+	// TimeNow is used to get the current time (used for testing purposes).
+	TimeNow func() time.Time
+	Logger  log.Logger
+}
+
+func (c *ErrorBackoffHandlerConfig) defaults() error {
+	if c.Handler == nil {
+		return fmt.Errorf("handler is required")
+	}
+
+	if c.Retries < 0 {
+		c.Retries = 0
+	}
+
+	if c.Backoff <= 0 {
+		return fmt.Errorf("backoff is required")
+	}
+
+	if c.MaxBackoff < c.Backoff {
+		c.MaxBackoff = c.Backoff
+	}
+
+	if c.TimeNow == nil {
+		c.TimeNow = time.Now
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.ErrorBackoffHandler"})
+
+	return nil
+}
+
+type objectFailures struct {
+	version  string
+	failures int
+	until    time.Time
+}
+
+type errorBackoffHandler struct {
+	handler    controller.Handler
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	timeNow    func() time.Time
+	logger     log.Logger
+
+	mu      sync.Mutex
+	objects map[string]*objectFailures
+}
+
+// NewErrorBackoffHandler returns a handler that wraps a handler and doesn't handle again the objects that
+// persistently fail until an exponential backoff expires, so on clusters with lots of objects the failing
+// ones don't use the controller (and Kubernetes API) capacity on each resync. The objects that change
+// (new generation or resource version) are handled without waiting.
+func NewErrorBackoffHandler(config ErrorBackoffHandlerConfig) (controller.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &errorBackoffHandler{
+		handler:    config.Handler,
+		retries:    config.Retries,
+		backoff:    config.Backoff,
+		maxBackoff: config.MaxBackoff,
+		timeNow:    config.TimeNow,
+		logger:     config.Logger,
+		objects:    map[string]*objectFailures{},
+	}, nil
+}
+
+func (e *errorBackoffHandler) Handle(ctx context.Context, obj runtime.Object) error {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return e.handler.Handle(ctx, obj)
+	}
+
+	// The status updates change the resource version, so the generation is used when available.
+	key := m.GetNamespace() + "/" + m.GetName()
+	version := m.GetResourceVersion()
+	if m.GetGeneration() != 0 {
+		version = strconv.FormatInt(m.GetGeneration(), 10)
+	}
+
+	e.mu.Lock()
+	f, ok := e.objects[key]
+	if ok && f.version == version && e.timeNow().Before(f.until) {
+		e.mu.Unlock()
+		e.logger.WithValues(log.Kv{"ns": m.GetNamespace(), "name": m.GetName()}).Debugf("Ignoring object due to %q until %s", "error backoff", f.until.UTC().Format(time.RFC3339))
+		return nil
+	}
+	e.mu.Unlock()
+
+	err = e.handler.Handle(ctx, obj)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err == nil {
+		delete(e.objects, key)
+		return nil
+	}
+
+	if !ok || f.version != version {
+		f = &objectFailures{version: version}
+		e.objects[key] = f
+	}
+	f.failures++
+
+	// Start the backoff when all the handling retries failed.
+	if f.failures%(e.retries+1) == 0 {
+		backoff := e.backoff
+		for i := 1; i < f.failures/(e.retries+1) && backoff < e.maxBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > e.maxBackoff {
+			backoff = e.maxBackoff
+		}
+		f.until = e.timeNow().Add(backoff)
+	}
+
+	return err
+}
@@ -0,0 +1,118 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+type handlerFunc func(ctx context.Context, obj runtime.Object) error
+
+func (h handlerFunc) Handle(ctx context.Context, obj runtime.Object) error { return h(ctx, obj) }
+
+func TestErrorBackoffHandler(t *testing.T) {
+	newPSL := func(generation int64) *slothv1.PrometheusServiceLevel {
+		return &slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test", Generation: generation}}
+	}
+
+	type handle struct {
+		after  time.Duration
+		obj    runtime.Object
+		err    error
+		expRun bool
+	}
+
+	tests := map[string]struct {
+		retries    int
+		maxBackoff time.Duration
+		handles    []handle
+	}{
+		"Successful objects should always be handled.": {
+			handles: []handle{
+				{obj: newPSL(1), expRun: true},
+				{obj: newPSL(1), expRun: true},
+			},
+		},
+
+		"A failed object should not be handled until the backoff expires.": {
+			handles: []handle{
+				{obj: newPSL(1), err: fmt.Errorf("something"), expRun: true},
+				{after: 30 * time.Second, obj: newPSL(1), expRun: false},
+				{after: 31 * time.Second, obj: newPSL(1), expRun: true},
+			},
+		},
+
+		"A failed object should be handled again if it changed.": {
+			handles: []handle{
+				{obj: newPSL(1), err: fmt.Errorf("something"), expRun: true},
+				{obj: newPSL(2), expRun: true},
+			},
+		},
+
+		"The retries of a failed handling should not wait for the backoff.": {
+			retries: 1,
+			handles: []handle{
+				{obj: newPSL(1), err: fmt.Errorf("something"), expRun: true},
+				{obj: newPSL(1), err: fmt.Errorf("something"), expRun: true},
+				{obj: newPSL(1), expRun: false},
+			},
+		},
+
+		"Consecutive failures should double the backoff up to the max backoff.": {
+			maxBackoff: 3 * time.Minute,
+			handles: []handle{
+				{obj: newPSL(1), err: fmt.Errorf("something"), expRun: true},
+				{after: 61 * time.Second, obj: newPSL(1), err: fmt.Errorf("something"), expRun: true},
+				{after: 61 * time.Second, obj: newPSL(1), expRun: false},
+				{after: 60 * time.Second, obj: newPSL(1), err: fmt.Errorf("something"), expRun: true},
+				{after: 179 * time.Second, obj: newPSL(1), expRun: false},
+				{after: 2 * time.Second, obj: newPSL(1), expRun: true},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			now := time.Now()
+			var gotRun bool
+			var handleErr error
+			h, err := kubecontroller.NewErrorBackoffHandler(kubecontroller.ErrorBackoffHandlerConfig{
+				Handler: handlerFunc(func(ctx context.Context, obj runtime.Object) error {
+					gotRun = true
+					return handleErr
+				}),
+				Retries:    test.retries,
+				Backoff:    time.Minute,
+				MaxBackoff: test.maxBackoff,
+				TimeNow:    func() time.Time { return now },
+			})
+			require.NoError(err)
+
+			for i, hd := range test.handles {
+				now = now.Add(hd.after)
+				gotRun = false
+				handleErr = hd.err
+
+				err := h.Handle(context.TODO(), hd.obj)
+				assert.Equal(hd.expRun, gotRun, "handle %d", i)
+				if hd.expRun {
+					assert.Equal(hd.err, err, "handle %d", i)
+				} else {
+					assert.NoError(err, "handle %d", i)
+				}
+			}
+		})
+	}
+}
@@ -2,24 +2,59 @@ package kubecontroller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/spotahome/kooper/v2/controller"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/slok/sloth/internal/app/generate"
 	"github.com/slok/sloth/internal/info"
 	"github.com/slok/sloth/internal/k8sprometheus"
 	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/metrics"
+	"github.com/slok/sloth/internal/prometheus"
 	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
 )
 
+// PausedAnnotationName is the annotation that makes the controller skip the handling of an object
+// (when set to `true`) leaving its already stored rules untouched, e.g during incident response or migrations.
+const PausedAnnotationName = "sloth.slok.dev/paused"
+
+// RulesCleanupFinalizerName is the finalizer set on the PrometheusServiceLevels whose generated rules can't be
+// owned by them (e.g stored on a different namespace or outside Kubernetes), so the rules are deleted before the
+// PrometheusServiceLevel is.
+const RulesCleanupFinalizerName = "sloth.slok.dev/rules-cleanup"
+
+type forceHandleKey struct{}
+
+// WithForceHandle returns a context that makes the handler handle the PrometheusServiceLevels already in a
+// correct state without spec changes, e.g to repair the stored rules drift.
+func WithForceHandle(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceHandleKey{}, true)
+}
+
+func isForceHandle(ctx context.Context) bool {
+	force, _ := ctx.Value(forceHandleKey{}).(bool)
+	return force
+}
+
 // SpecLoader Knows how to load a Kubernetes Spec into an app model.
 type SpecLoader interface {
 	LoadSpec(ctx context.Context, spec *slothv1.PrometheusServiceLevel) (*k8sprometheus.SLOGroup, error)
 }
 
+// ConfigMapSpecLoader knows how to load the raw specs of a Kubernetes ConfigMap into an app model.
+type ConfigMapSpecLoader interface {
+	LoadSpec(ctx context.Context, cm *corev1.ConfigMap) (*k8sprometheus.ConfigMapSLOGroup, error)
+}
+
 // Generator Knows how to generate SLO prometheus rules from app SLO model.
 type Generator interface {
 	Generate(ctx context.Context, r generate.Request) (*generate.Response, error)
@@ -30,18 +65,87 @@ type Repository interface {
 	StoreSLOs(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error
 }
 
+// RulesDeleter knows how to delete the stored SLO Prometheus rules of an object.
+type RulesDeleter interface {
+	DeleteSLOs(ctx context.Context, kmeta k8sprometheus.K8sMeta) error
+}
+
+// KubeFinalizerStorer knows how to set and unset the finalizers of Prometheus service levels Kubernetes CRD.
+type KubeFinalizerStorer interface {
+	AddPrometheusServiceLevelFinalizer(ctx context.Context, slo *slothv1.PrometheusServiceLevel, finalizer string) (*slothv1.PrometheusServiceLevel, error)
+	RemovePrometheusServiceLevelFinalizer(ctx context.Context, slo *slothv1.PrometheusServiceLevel, finalizer string) error
+}
+
+// AlertWindowsCatalogRepository knows how to get the SLO period alert windows catalogs.
+type AlertWindowsCatalogRepository interface {
+	GetAlertWindowsCatalog(ctx context.Context, ns, name string) (*k8sprometheus.AlertWindowsCatalog, error)
+}
+
 // KubeStatusStorer knows how to set the status of Prometheus service levels Kubernetes CRD.
 type KubeStatusStorer interface {
-	EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, err error) error
+	EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error
+}
+
+// EventRecorder knows how to record Kubernetes events on the handled objects.
+type EventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
+}
+
+type noopEventRecorder int
+
+func (noopEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
 }
 
 // HandlerConfig is the controller handler configuration.
 type HandlerConfig struct {
-	Generator        Generator
-	SpecLoader       SpecLoader
-	Repository       Repository
-	KubeStatusStorer KubeStatusStorer
+	Generator  Generator
+	SpecLoader SpecLoader
+	// ConfigMapSpecLoader loads the raw specs of the handled ConfigMaps.
+	ConfigMapSpecLoader ConfigMapSpecLoader
+	Repository          Repository
+	// SecondaryRepository also stores the generated rules (e.g while migrating the rules storage), its
+	// failures are reported independently and don't fail the handling, by default disabled.
+	SecondaryRepository Repository
+	KubeStatusStorer    KubeStatusStorer
+	// RulesDeleter deletes the generated rules of the deleted PrometheusServiceLevels, when set the
+	// PrometheusServiceLevels get the RulesCleanupFinalizerName finalizer, for the rules outputs where
+	// the rules can't be owned by them, by default disabled.
+	RulesDeleter RulesDeleter
+	// KubeFinalizerStorer sets and unsets the PrometheusServiceLevels rules cleanup finalizer, the
+	// finalizer of the PrometheusServiceLevels being deleted is removed even without RulesDeleter, so
+	// disabling the rules cleanup doesn't block their deletion.
+	KubeFinalizerStorer KubeFinalizerStorer
+	// EventRecorder records the handling outcome as Kubernetes events on the handled objects.
+	EventRecorder EventRecorder
+	// MetricsRecorder records the handling outcome metrics of each handled object.
+	MetricsRecorder metrics.Recorder
+	// ReconcileMetrics records the reconcile metrics of the handled objects cluster, shared with the
+	// inventory collector so the deleted objects are forgotten, by default one for the local cluster.
+	ReconcileMetrics *ReconcileMetrics
 	ExtraLabels      map[string]string
+	// ExternalLabels are the labels that identify where the SLOs are (e.g cluster), set on all the
+	// SLOs. The PrometheusServiceLevel SLO `externalLabels` take precedence over these.
+	ExternalLabels map[string]string
+	// RuleSelectorLabels are the labels that will be set on all the generated PrometheusRule
+	// objects so they are selected by the Prometheus instance `ruleSelector`. The
+	// PrometheusServiceLevel labels and `ruleSelectorLabels` (merged by the spec loader,
+	// the latter taking precedence) take precedence over these.
+	RuleSelectorLabels map[string]string
+	// RuleAnnotations are the annotations that will be set on all the generated PrometheusRule
+	// objects. The PrometheusServiceLevel `ruleAnnotations` take precedence over these.
+	RuleAnnotations map[string]string
+	// RulesNamespace is the namespace where the PrometheusRule objects will be stored, by default
+	// the PrometheusServiceLevel namespace. The rules stored on a different namespace are named
+	// `<namespace>-<name>` and can't be owned by the PrometheusServiceLevel.
+	RulesNamespace string
+	// AlertWindowsCatalogRepository gets the alert windows catalogs referenced by the handled objects.
+	AlertWindowsCatalogRepository AlertWindowsCatalogRepository
+	// AlertWindowsCatalog is the `<namespace>/<name>` ConfigMap alert windows catalog used by the
+	// objects that don't reference one (PrometheusServiceLevel `alertWindowsCatalog`).
+	AlertWindowsCatalog string
+	// DryRun makes the handler generate the rules without storing them nor the PrometheusServiceLevel
+	// status, the rules that would be stored are logged and recorded as events.
+	DryRun bool
 	// IgnoreHandleBefore makes the handles of objects with a success state and no spec change,
 	// be ignored if the last success is less than this setting.
 	// Be aware that this setting should be less than the controller resync interval.
@@ -58,18 +162,55 @@ func (c *HandlerConfig) defaults() error {
 		c.SpecLoader = k8sprometheus.CRSpecLoader
 	}
 
+	if c.ConfigMapSpecLoader == nil {
+		c.ConfigMapSpecLoader = k8sprometheus.ConfigMapSpecLoader
+	}
+
 	if c.KubeStatusStorer == nil {
 		return fmt.Errorf("kubernetes status storer is required")
 	}
 
+	if c.RulesDeleter != nil && c.KubeFinalizerStorer == nil {
+		return fmt.Errorf("kubernetes finalizer storer is required")
+	}
+
+	if c.EventRecorder == nil {
+		c.EventRecorder = noopEventRecorder(0)
+	}
+
+	if c.MetricsRecorder == nil {
+		c.MetricsRecorder = metrics.Noop
+	}
+
+	if c.ReconcileMetrics == nil {
+		c.ReconcileMetrics = NewReconcileMetrics(LocalCluster, c.MetricsRecorder)
+	}
+
 	if c.ExtraLabels == nil {
 		c.ExtraLabels = map[string]string{}
 	}
 
+	if c.RuleSelectorLabels == nil {
+		c.RuleSelectorLabels = map[string]string{}
+	}
+
+	if c.RuleAnnotations == nil {
+		c.RuleAnnotations = map[string]string{}
+	}
+
 	if c.Repository == nil {
 		return fmt.Errorf("repository is required")
 	}
 
+	if c.AlertWindowsCatalog != "" {
+		if c.AlertWindowsCatalogRepository == nil {
+			return fmt.Errorf("alert windows catalog repository is required")
+		}
+		if parts := strings.Split(c.AlertWindowsCatalog, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid alert windows catalog %q, should be in `<namespace>/<name>` format", c.AlertWindowsCatalog)
+		}
+	}
+
 	if c.IgnoreHandleBefore == 0 {
 		c.IgnoreHandleBefore = 3 * time.Minute
 	}
@@ -84,10 +225,24 @@ func (c *HandlerConfig) defaults() error {
 
 type handler struct {
 	specLoader         SpecLoader
+	cmSpecLoader       ConfigMapSpecLoader
 	generator          Generator
 	repository         Repository
+	secondaryRepo      Repository
 	kubeStatusStorer   KubeStatusStorer
+	rulesDeleter       RulesDeleter
+	finalizerStorer    KubeFinalizerStorer
+	eventRecorder      EventRecorder
+	metricsRecorder    metrics.Recorder
+	reconcileMetrics   *ReconcileMetrics
 	extraLabels        map[string]string
+	externalLabels     map[string]string
+	ruleSelectorLabels map[string]string
+	ruleAnnotations    map[string]string
+	rulesNamespace     string
+	catalogRepo        AlertWindowsCatalogRepository
+	catalog            string
+	dryRun             bool
 	ignoreHandleBefore time.Duration
 	logger             log.Logger
 }
@@ -99,10 +254,24 @@ func NewHandler(config HandlerConfig) (controller.Handler, error) {
 	}
 	return &handler{
 		specLoader:         config.SpecLoader,
+		cmSpecLoader:       config.ConfigMapSpecLoader,
 		generator:          config.Generator,
 		repository:         config.Repository,
+		secondaryRepo:      config.SecondaryRepository,
 		kubeStatusStorer:   config.KubeStatusStorer,
+		rulesDeleter:       config.RulesDeleter,
+		finalizerStorer:    config.KubeFinalizerStorer,
+		eventRecorder:      config.EventRecorder,
+		metricsRecorder:    config.MetricsRecorder,
+		reconcileMetrics:   config.ReconcileMetrics,
 		extraLabels:        config.ExtraLabels,
+		externalLabels:     config.ExternalLabels,
+		ruleSelectorLabels: config.RuleSelectorLabels,
+		ruleAnnotations:    config.RuleAnnotations,
+		rulesNamespace:     config.RulesNamespace,
+		catalogRepo:        config.AlertWindowsCatalogRepository,
+		catalog:            config.AlertWindowsCatalog,
+		dryRun:             config.DryRun,
 		ignoreHandleBefore: config.IgnoreHandleBefore,
 		logger:             config.Logger,
 	}, nil
@@ -112,6 +281,8 @@ func (h handler) Handle(ctx context.Context, obj runtime.Object) error {
 	switch v := obj.(type) {
 	case *slothv1.PrometheusServiceLevel:
 		return h.handlePrometheusServiceLevelV1(ctx, v)
+	case *corev1.ConfigMap:
+		return h.handleConfigMap(ctx, v)
 	default:
 		h.logger.Warningf("Unsuported Kubernetes object type: %s", obj.GetObjectKind())
 	}
@@ -123,19 +294,47 @@ func (h handler) handlePrometheusServiceLevelV1(ctx context.Context, psl *slothv
 	ctx = h.logger.SetValuesOnCtx(ctx, log.Kv{"ns": psl.Namespace, "name": psl.Name})
 	logger := h.logger.WithCtxValues(ctx)
 
+	// The objects being deleted are not reconciled anymore.
+	if !psl.DeletionTimestamp.IsZero() {
+		h.reconcileMetrics.forget(ctx, psl.Namespace, psl.Name)
+	}
+
+	if !psl.DeletionTimestamp.IsZero() && hasFinalizer(psl.Finalizers, RulesCleanupFinalizerName) && h.finalizerStorer != nil && !h.dryRun {
+		return h.cleanupPrometheusServiceLevelV1(ctx, psl)
+	}
+
 	ignoreReason, ignore := h.ignoreHandlePrometheusServiceLevelV1(ctx, psl)
 	if ignore {
 		logger.Debugf("Ignoring object due to %q", ignoreReason)
 		return nil
 	}
 
+	// Set the finalizer before storing any rule, so these are not left behind if the object is deleted.
+	if h.rulesDeleter != nil && !h.dryRun {
+		psl, err = h.finalizerStorer.AddPrometheusServiceLevelFinalizer(ctx, psl, RulesCleanupFinalizerName)
+		if err != nil {
+			return fmt.Errorf("could not add rules cleanup finalizer: %w", err)
+		}
+	}
+
 	// Store the status with the result of the handling process every time we
 	// process a CR.
+	result := k8sprometheus.HandleResult{}
+	start := time.Now()
 	defer func() {
-		storedErr := h.kubeStatusStorer.EnsurePrometheusServiceLevelStatus(ctx, psl, err)
+		result.Err = err
+		h.recordMetrics(ctx, psl.Namespace, psl.Name, result, time.Since(start))
+		if h.dryRun {
+			if err != nil {
+				h.recordEvent(psl, result)
+			}
+			return
+		}
+		storedErr := h.kubeStatusStorer.EnsurePrometheusServiceLevelStatus(ctx, psl, result)
 		if storedErr != nil {
 			logger.Errorf("Could not set PrometheusServiceLevel CRD status: %s", storedErr)
 		}
+		h.recordEvent(psl, result)
 	}()
 
 	// Load From CRD to model.
@@ -143,38 +342,265 @@ func (h handler) handlePrometheusServiceLevelV1(ctx context.Context, psl *slothv
 	if err != nil {
 		return fmt.Errorf("could not load CR spec into model: %w", err)
 	}
+	result.Validated = true
+
+	catalog := h.catalog
+	if psl.Spec.AlertWindowsCatalog != "" {
+		catalog = psl.Namespace + "/" + psl.Spec.AlertWindowsCatalog
+	}
+	err = h.setAlertWindowsCatalog(ctx, catalog, model.SLOGroup.SLOs)
+	if err != nil {
+		return err
+	}
 
 	// Generate rules.
+	h.setDefaultExternalLabels(model.SLOGroup.SLOs)
+	specVersion := fmt.Sprintf("%s/%s", slothv1.SchemeGroupVersion.Group, slothv1.SchemeGroupVersion.Version)
+	storageSLOs, err := h.generate(ctx, specVersion, model.SLOGroup, &result)
+	if err != nil {
+		return err
+	}
+
+	return h.store(ctx, psl, h.rulesK8sMeta(model.K8sMeta), storageSLOs, &result)
+}
+
+// cleanupPrometheusServiceLevelV1 deletes the generated rules of a deleted PrometheusServiceLevel and removes
+// its finalizer, so Kubernetes can finish the deletion.
+func (h handler) cleanupPrometheusServiceLevelV1(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error {
+	logger := h.logger.WithCtxValues(ctx)
+
+	if h.rulesDeleter != nil {
+		kmeta := h.rulesK8sMeta(k8sprometheus.K8sMeta{
+			Kind:       "PrometheusServiceLevel",
+			APIVersion: "sloth.slok.dev/v1",
+			UID:        string(psl.UID),
+			Name:       psl.Name,
+			Namespace:  psl.Namespace,
+		})
+		err := h.rulesDeleter.DeleteSLOs(ctx, kmeta)
+		if err != nil {
+			h.eventRecorder.Eventf(psl, corev1.EventTypeWarning, "CleanupFailed", "Prometheus rules could not be deleted: %s", err)
+			return fmt.Errorf("could not delete SLOs: %w", err)
+		}
+		logger.WithValues(log.Kv{"rules-ns": kmeta.Namespace, "rules-name": kmeta.Name}).Infof("Prometheus rules deleted")
+	}
+
+	err := h.finalizerStorer.RemovePrometheusServiceLevelFinalizer(ctx, psl, RulesCleanupFinalizerName)
+	if err != nil {
+		return fmt.Errorf("could not remove rules cleanup finalizer: %w", err)
+	}
+
+	return nil
+}
+
+func hasFinalizer(finalizers []string, finalizer string) bool {
+	for _, f := range finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleConfigMap handles the ConfigMaps with raw Sloth specs, ConfigMaps don't have status, so only
+// the failures are recorded as events.
+func (h handler) handleConfigMap(ctx context.Context, cm *corev1.ConfigMap) (err error) {
+	ctx = h.logger.SetValuesOnCtx(ctx, log.Kv{"ns": cm.Namespace, "name": cm.Name, "kind": "ConfigMap"})
+	logger := h.logger.WithCtxValues(ctx)
+
+	if !cm.DeletionTimestamp.IsZero() {
+		h.reconcileMetrics.forget(ctx, cm.Namespace, cm.Name)
+		logger.Debugf("Ignoring object due to %q", "deletion in progress")
+		return nil
+	}
+
+	if isPaused(cm.Annotations) {
+		logger.Debugf("Ignoring object due to %q", "paused")
+		return nil
+	}
+
+	result := k8sprometheus.HandleResult{}
+	start := time.Now()
+	defer func() {
+		result.Err = err
+		h.recordMetrics(ctx, cm.Namespace, cm.Name, result, time.Since(start))
+		if err != nil {
+			h.recordFailureEvent(cm, result)
+		}
+	}()
+
+	// Load From ConfigMap to model.
+	model, err := h.cmSpecLoader.LoadSpec(ctx, cm)
+	if err != nil {
+		return fmt.Errorf("could not load ConfigMap specs into model: %w", err)
+	}
+	result.Validated = true
+
+	// Generate the rules of each spec, these are stored together.
+	storageSLOs := []k8sprometheus.StorageSLO{}
+	for _, spec := range model.Specs {
+		err := h.setAlertWindowsCatalog(ctx, h.catalog, spec.SLOGroup.SLOs)
+		if err != nil {
+			return fmt.Errorf("could not set %q key spec alert windows: %w", spec.Key, err)
+		}
+		h.setDefaultExternalLabels(spec.SLOGroup.SLOs)
+		slos, err := h.generate(ctx, spec.Version, spec.SLOGroup, &result)
+		if err != nil {
+			return fmt.Errorf("could not generate %q key spec: %w", spec.Key, err)
+		}
+		storageSLOs = append(storageSLOs, slos...)
+	}
+
+	return h.store(ctx, cm, h.rulesK8sMeta(model.K8sMeta), storageSLOs, &result)
+}
+
+// setAlertWindowsCatalog sets the alert windows of a `<namespace>/<name>` alert windows catalog on the SLOs
+// that don't have them.
+func (h handler) setAlertWindowsCatalog(ctx context.Context, catalog string, slos []prometheus.SLO) error {
+	if catalog == "" {
+		return nil
+	}
+
+	if h.catalogRepo == nil {
+		return fmt.Errorf("alert windows catalogs are not supported")
+	}
+
+	parts := strings.SplitN(catalog, "/", 2)
+	c, err := h.catalogRepo.GetAlertWindowsCatalog(ctx, parts[0], parts[1])
+	if err != nil {
+		return fmt.Errorf("could not get alert windows catalog: %w", err)
+	}
+	c.SetAlertWindows(slos)
+
+	return nil
+}
+
+// setDefaultExternalLabels sets the default external labels on the SLOs, the SLO ones take precedence.
+func (h handler) setDefaultExternalLabels(slos []prometheus.SLO) {
+	if len(h.externalLabels) == 0 {
+		return
+	}
+
+	for i, slo := range slos {
+		externalLabels := map[string]string{}
+		for k, v := range h.externalLabels {
+			externalLabels[k] = v
+		}
+		for k, v := range slo.ExternalLabels {
+			externalLabels[k] = v
+		}
+		slos[i].ExternalLabels = externalLabels
+	}
+}
+
+// rulesK8sMeta returns the Kubernetes metadata of the generated rules object based on the handled object one.
+func (h handler) rulesK8sMeta(kmeta k8sprometheus.K8sMeta) k8sprometheus.K8sMeta {
+	// Set the default rule selector labels on the PrometheusRule object.
+	if len(h.ruleSelectorLabels) > 0 {
+		labels := map[string]string{}
+		for k, v := range h.ruleSelectorLabels {
+			labels[k] = v
+		}
+		for k, v := range kmeta.Labels {
+			labels[k] = v
+		}
+		kmeta.Labels = labels
+	}
+
+	// Set the default annotations on the PrometheusRule object.
+	if len(h.ruleAnnotations) > 0 {
+		annotations := map[string]string{}
+		for k, v := range h.ruleAnnotations {
+			annotations[k] = v
+		}
+		for k, v := range kmeta.Annotations {
+			annotations[k] = v
+		}
+		kmeta.Annotations = annotations
+	}
+
+	// Store the rules on the central rules namespace, referencing the source object so the
+	// retained rules can be cleaned.
+	if h.rulesNamespace != "" && h.rulesNamespace != kmeta.Namespace {
+		annotations := map[string]string{}
+		for k, v := range kmeta.Annotations {
+			annotations[k] = v
+		}
+		annotations[k8sprometheus.SourceNamespaceAnnotationName] = kmeta.Namespace
+		annotations[k8sprometheus.SourceNameAnnotationName] = kmeta.Name
+		kmeta.Annotations = annotations
+		kmeta.Name = fmt.Sprintf("%s-%s", kmeta.Namespace, kmeta.Name)
+		kmeta.Namespace = h.rulesNamespace
+	}
+
+	return kmeta
+}
+
+// generate generates the Prometheus rules of the SLO group.
+func (h handler) generate(ctx context.Context, specVersion string, slos prometheus.SLOGroup, result *k8sprometheus.HandleResult) ([]k8sprometheus.StorageSLO, error) {
 	req := generate.Request{
 		Info: info.Info{
 			Version: info.Version,
 			Mode:    info.ModeControllerGenKubernetes,
-			Spec:    fmt.Sprintf("%s/%s", slothv1.SchemeGroupVersion.Group, slothv1.SchemeGroupVersion.Version),
+			Spec:    specVersion,
 		},
 		ExtraLabels: h.extraLabels,
-		SLOGroup:    model.SLOGroup,
+		SLOGroup:    slos,
 	}
 	resp, err := h.generator.Generate(ctx, req)
 	if err != nil {
-		return fmt.Errorf("could not generate SLOs: %w", err)
+		result.SLOErrors = append(result.SLOErrors, sloErrors(slos, err)...)
+		return nil, fmt.Errorf("could not generate SLOs: %w", err)
 	}
 
-	// Store on k8s as Prometheus operator Rules.
 	storageSLOs := make([]k8sprometheus.StorageSLO, 0, len(resp.PrometheusSLOs))
 	for _, s := range resp.PrometheusSLOs {
 		storageSLOs = append(storageSLOs, k8sprometheus.StorageSLO{
 			SLO:   s.SLO,
 			Rules: s.SLORules,
 		})
+		result.GeneratedRules += len(s.SLORules.SLIErrorRecRules) + len(s.SLORules.MetadataRecRules) + len(s.SLORules.AlertRules)
 	}
-	err = h.repository.StoreSLOs(ctx, model.K8sMeta, storageSLOs)
+
+	return storageSLOs, nil
+}
+
+// store stores the generated rules of the handled object, in dry run mode these are only logged and recorded as events.
+func (h handler) store(ctx context.Context, obj runtime.Object, kmeta k8sprometheus.K8sMeta, storageSLOs []k8sprometheus.StorageSLO, result *k8sprometheus.HandleResult) error {
+	result.RulesGenerated = true
+
+	if h.dryRun {
+		h.logger.WithCtxValues(ctx).WithValues(log.Kv{"rules-ns": kmeta.Namespace, "rules-name": kmeta.Name, "slos": len(storageSLOs), "rules": result.GeneratedRules}).Infof("Dry run, rules not stored")
+		h.eventRecorder.Eventf(obj, corev1.EventTypeNormal, "DryRun", "Dry run: %d Prometheus rules for %d SLOs would be stored on %s/%s", result.GeneratedRules, len(storageSLOs), kmeta.Namespace, kmeta.Name)
+		return nil
+	}
+
+	h.storeSecondary(ctx, obj, kmeta, storageSLOs)
+
+	err := h.repository.StoreSLOs(ctx, kmeta, storageSLOs)
 	if err != nil {
 		return fmt.Errorf("could not store SLOs: %w", err)
 	}
+	result.RulesApplied = true
 
 	return nil
 }
 
+// storeSecondary stores the generated rules on the secondary repository, the failures are only logged and
+// recorded as events so the primary repository rules and the object status are not affected by them.
+func (h handler) storeSecondary(ctx context.Context, obj runtime.Object, kmeta k8sprometheus.K8sMeta, storageSLOs []k8sprometheus.StorageSLO) {
+	if h.secondaryRepo == nil {
+		return
+	}
+
+	err := h.secondaryRepo.StoreSLOs(ctx, kmeta, storageSLOs)
+	if err != nil {
+		h.logger.WithCtxValues(ctx).Errorf("Could not store SLOs on secondary repository: %s", err)
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, "SecondaryApplyFailed", "Prometheus rules could not be stored on the secondary output: %s", err)
+	}
+}
+
 func (h handler) ignoreHandlePrometheusServiceLevelV1(ctx context.Context, psl *slothv1.PrometheusServiceLevel) (reason string, ignore bool) {
 	// If the received object is being deleted, ignore.
 	deleteInProgress := !psl.DeletionTimestamp.IsZero()
@@ -182,6 +608,10 @@ func (h handler) ignoreHandlePrometheusServiceLevelV1(ctx context.Context, psl *
 		return "deletion in progress", true
 	}
 
+	if isPaused(psl.Annotations) {
+		return "paused", true
+	}
+
 	// If we received an update event not because of an spec change but because of an status change
 	// we need to break the loop because if we continue with the handling most likely that will update
 	// the status (and we will end here again on the next controller event).
@@ -191,7 +621,8 @@ func (h handler) ignoreHandlePrometheusServiceLevelV1(ctx context.Context, psl *
 	// - The generation of the status is the same as the one in the metadata: Means the spec didn't change.
 	// - The status is ok: Means is not a retry because of an error.
 	// - The status success TS is less than a duration: Means that if we just updated the success state we break the inmediate loop.
-	if psl.Generation == psl.Status.ObservedGeneration &&
+	if !isForceHandle(ctx) &&
+		psl.Generation == psl.Status.ObservedGeneration &&
 		psl.Status.PromOpRulesGenerated &&
 		time.Since(psl.Status.LastPromOpRulesSuccessfulGenerated.Time) < h.ignoreHandleBefore {
 		return "no spec change in correct state object", true
@@ -199,3 +630,115 @@ func (h handler) ignoreHandlePrometheusServiceLevelV1(ctx context.Context, psl *
 
 	return "", false
 }
+
+func isPaused(annotations map[string]string) bool {
+	return annotations[PausedAnnotationName] == "true"
+}
+
+// recordEvent records the handling result as a Kubernetes event on the object. The successful
+// handlings are only recorded when the rules changed (new spec or recovered from an error), so the
+// periodic resyncs don't fill the object events.
+func (h handler) recordEvent(psl *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) {
+	switch {
+	case result.Err == nil:
+		if psl.Generation == psl.Status.ObservedGeneration && psl.Status.PromOpRulesGenerated {
+			return
+		}
+		h.eventRecorder.Eventf(psl, corev1.EventTypeNormal, "RulesUpdated", "Prometheus rules updated: %d rules for %d SLOs", result.GeneratedRules, len(psl.Spec.SLOs))
+	default:
+		h.recordFailureEvent(psl, result)
+	}
+}
+
+// recordFailureEvent records the handling failure as a Kubernetes warning event on the object.
+func (h handler) recordFailureEvent(obj runtime.Object, result k8sprometheus.HandleResult) {
+	switch {
+	case !result.Validated:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, "InvalidSpec", "Invalid spec: %s", result.Err)
+	case !result.RulesGenerated && isPromQLError(result.Err):
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, "InvalidPromQL", "Invalid PromQL query: %s", result.Err)
+	case !result.RulesGenerated:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, "GenerationFailed", "Prometheus rules generation failed: %s", result.Err)
+	default:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, "ApplyFailed", "Prometheus rules could not be stored: %s", result.Err)
+	}
+}
+
+// recordMetrics records the handling result metrics of the object, the consecutive failures include
+// the controller retries, so the objects that fail persistently can be alerted.
+func (h handler) recordMetrics(ctx context.Context, ns, name string, result k8sprometheus.HandleResult, duration time.Duration) {
+	res := metrics.ReconcileResultSuccess
+	switch {
+	case result.Err == nil:
+	case !result.Validated:
+		res = metrics.ReconcileResultInvalidSpec
+	case !result.RulesGenerated:
+		res = metrics.ReconcileResultGenerationFailed
+	default:
+		res = metrics.ReconcileResultApplyFailed
+	}
+	h.reconcileMetrics.record(ctx, ns, name, res, duration)
+}
+
+var validationSLOIndexRegexp = regexp.MustCompile(`\.SLOs\[(\d+)\]\.(.+)$`)
+
+// sloErrors returns the errors of the specific SLOs of an SLO group generation error, so the failed
+// SLOs and expressions can be known without checking the whole error.
+func sloErrors(slos prometheus.SLOGroup, err error) []k8sprometheus.SLOError {
+	var sloErr generate.SLOError
+	if errors.As(err, &sloErr) {
+		name := sloErr.SLOID
+		for _, slo := range slos.SLOs {
+			if slo.ID == sloErr.SLOID {
+				name = slo.Name
+				break
+			}
+		}
+		return []k8sprometheus.SLOError{{SLO: name, Message: sloErr.Err.Error()}}
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	errs := []k8sprometheus.SLOError{}
+	for _, verr := range verrs {
+		match := validationSLOIndexRegexp.FindStringSubmatch(verr.Namespace())
+		if match == nil {
+			continue
+		}
+		i, _ := strconv.Atoi(match[1])
+		if i >= len(slos.SLOs) {
+			continue
+		}
+
+		sloErr := k8sprometheus.SLOError{
+			SLO:     slos.SLOs[i].Name,
+			Field:   match[2],
+			Message: fmt.Sprintf("invalid %s field, failed on %q validation", match[2], verr.Tag()),
+		}
+		if expr, ok := verr.Value().(string); ok {
+			sloErr.Expression = expr
+		}
+		errs = append(errs, sloErr)
+	}
+
+	return errs
+}
+
+// isPromQLError returns true if the error is a model validation error of a PromQL expression.
+func isPromQLError(err error) bool {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return false
+	}
+
+	for _, verr := range verrs {
+		if strings.HasPrefix(verr.Tag(), "prom_") && strings.HasSuffix(verr.Tag(), "_expr") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,1285 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/slok/sloth/internal/alert"
+	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/metrics"
+	"github.com/slok/sloth/internal/prometheus"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+type generatorFunc func(ctx context.Context, r generate.Request) (*generate.Response, error)
+
+func (g generatorFunc) Generate(ctx context.Context, r generate.Request) (*generate.Response, error) {
+	return g(ctx, r)
+}
+
+type repositoryFunc func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error
+
+func (r repositoryFunc) StoreSLOs(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+	return r(ctx, kmeta, slos)
+}
+
+type statusStorerFunc func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error
+
+func (s statusStorerFunc) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+	return s(ctx, slo, result)
+}
+
+func TestHandlerEvents(t *testing.T) {
+	getPSL := func() *slothv1.PrometheusServiceLevel {
+		return &slothv1.PrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Generation: 2},
+			Spec: slothv1.PrometheusServiceLevelSpec{
+				Service: "test-svc",
+				SLOs: []slothv1.SLO{{
+					Name:      "slo1",
+					Objective: 99,
+					SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+					Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+				}},
+			},
+		}
+	}
+	okGenerator := generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+		return &generate.Response{PrometheusSLOs: []generate.SLOResult{{
+			SLO:      r.SLOGroup.SLOs[0],
+			SLORules: prometheus.SLORules{SLIErrorRecRules: make([]rulefmt.Rule, 3)},
+		}}}, nil
+	})
+	okRepository := repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+		return nil
+	})
+
+	tests := map[string]struct {
+		psl        func() *slothv1.PrometheusServiceLevel
+		generator  kubecontroller.Generator
+		repository kubecontroller.Repository
+		expEvents  []string
+	}{
+		"An invalid spec should record an invalid spec warning.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getPSL()
+				psl.Spec.SLOs[0].Objective = 100
+				return psl
+			},
+			generator:  okGenerator,
+			repository: okRepository,
+			expEvents:  []string{`Warning InvalidSpec Invalid spec: could not load CR spec into model: invalid "slo1" SLO objective`},
+		},
+
+		"An invalid PromQL query should record an invalid PromQL warning.": {
+			psl: getPSL,
+			generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+				r.SLOGroup.SLOs[0].SLI.Raw.ErrorRatioQuery = "sum(" // Make it invalid.
+				return generate.Service{}.Generate(ctx, r)
+			}),
+			repository: okRepository,
+			expEvents:  []string{"Warning InvalidPromQL"},
+		},
+
+		"A generation error should record a generation failed warning.": {
+			psl: getPSL,
+			generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+				return nil, fmt.Errorf("something")
+			}),
+			repository: okRepository,
+			expEvents:  []string{"Warning GenerationFailed Prometheus rules generation failed: could not generate SLOs: something"},
+		},
+
+		"A store error should record an apply failed warning.": {
+			psl:       getPSL,
+			generator: okGenerator,
+			repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+				return fmt.Errorf("something")
+			}),
+			expEvents: []string{"Warning ApplyFailed Prometheus rules could not be stored: could not store SLOs: something"},
+		},
+
+		"A successful handling of a changed spec should record the rules updated.": {
+			psl:        getPSL,
+			generator:  okGenerator,
+			repository: okRepository,
+			expEvents:  []string{"Normal RulesUpdated Prometheus rules updated: 3 rules for 1 SLOs"},
+		},
+
+		"A successful handling of an already handled spec should not record events.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getPSL()
+				psl.Status.ObservedGeneration = 2
+				psl.Status.PromOpRulesGenerated = true
+				psl.Status.LastPromOpRulesSuccessfulGenerated = &metav1.Time{Time: time.Now().Add(-1 * time.Hour)}
+				return psl
+			},
+			generator:  okGenerator,
+			repository: okRepository,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			recorder := record.NewFakeRecorder(10)
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator:  test.generator,
+				Repository: test.repository,
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					return nil
+				}),
+				EventRecorder: recorder,
+			})
+			require.NoError(err)
+
+			_ = h.Handle(context.TODO(), test.psl())
+			close(recorder.Events)
+
+			gotEvents := []string{}
+			for e := range recorder.Events {
+				gotEvents = append(gotEvents, e)
+			}
+			require.Len(gotEvents, len(test.expEvents))
+			for i, exp := range test.expEvents {
+				assert.Contains(gotEvents[i], exp)
+			}
+		})
+	}
+}
+
+func TestHandlerSLOErrors(t *testing.T) {
+	tests := map[string]struct {
+		generator    kubecontroller.Generator
+		expSLOErrors []k8sprometheus.SLOError
+	}{
+		"A successful handling should not have SLO errors.": {
+			generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+				return &generate.Response{}, nil
+			}),
+		},
+
+		"An invalid PromQL query should have the failed SLO and expression.": {
+			generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+				r.SLOGroup.SLOs[1].SLI.Raw.ErrorRatioQuery = "rate(errors[{{.window}}]" // Make it invalid.
+				return generate.Service{}.Generate(ctx, r)
+			}),
+			expSLOErrors: []k8sprometheus.SLOError{
+				{SLO: "slo2", Field: "SLI.Raw.ErrorRatioQuery", Expression: "rate(errors[{{.window}}]", Message: `invalid SLI.Raw.ErrorRatioQuery field, failed on "prom_expr" validation`},
+			},
+		},
+
+		"An SLO generation error should have the failed SLO.": {
+			generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+				return nil, generate.SLOError{SLOID: r.SLOGroup.SLOs[1].ID, Err: fmt.Errorf("something")}
+			}),
+			expSLOErrors: []k8sprometheus.SLOError{
+				{SLO: "slo2", Message: "something"},
+			},
+		},
+
+		"A not SLO specific generation error should not have SLO errors.": {
+			generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+				return nil, fmt.Errorf("something")
+			}),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotResult k8sprometheus.HandleResult
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: test.generator,
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					gotResult = result
+					return nil
+				}),
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service: "test-svc",
+					SLOs: []slothv1.SLO{
+						{
+							Name:      "slo1",
+							Objective: 99,
+							SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "rate(errors[{{.window}}])"}},
+							Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+						},
+						{
+							Name:      "slo2",
+							Objective: 99,
+							SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "rate(errors[{{.window}}])"}},
+							Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+						},
+					},
+				},
+			}
+			_ = h.Handle(context.TODO(), psl)
+
+			assert.Equal(test.expSLOErrors, gotResult.SLOErrors)
+		})
+	}
+}
+
+func TestHandlerRuleAnnotations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var gotKMeta k8sprometheus.K8sMeta
+	h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+		Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+			return &generate.Response{}, nil
+		}),
+		Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+			gotKMeta = kmeta
+			return nil
+		}),
+		KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+			return nil
+		}),
+		RuleSelectorLabels: map[string]string{"prometheus": "k8s", "role": "default"},
+		RuleAnnotations:    map[string]string{"team": "default", "owner": "sre"},
+	})
+	require.NoError(err)
+
+	psl := &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "test-ns",
+			Labels:      map[string]string{"role": "alert-rules"},
+			Annotations: map[string]string{"team": "a"},
+		},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service: "test-svc",
+			SLOs: []slothv1.SLO{{
+				Name:      "slo1",
+				Objective: 99,
+				SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+				Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+			}},
+		},
+	}
+	err = h.Handle(context.TODO(), psl)
+	require.NoError(err)
+
+	assert.Equal(map[string]string{"prometheus": "k8s", "role": "alert-rules"}, gotKMeta.Labels)
+	assert.Equal(map[string]string{"team": "a", "owner": "sre"}, gotKMeta.Annotations)
+}
+
+func TestHandlerRuleSelectorLabels(t *testing.T) {
+	tests := map[string]struct {
+		ruleSelectorLabels map[string]string
+		objLabels          map[string]string
+		specLabels         map[string]string
+		expLabels          map[string]string
+	}{
+		"The default rule selector labels should be set on the rules.": {
+			ruleSelectorLabels: map[string]string{"prometheus": "k8s", "role": "default"},
+			expLabels:          map[string]string{"prometheus": "k8s", "role": "default"},
+		},
+
+		"The object labels should take precedence over the default rule selector labels.": {
+			ruleSelectorLabels: map[string]string{"prometheus": "k8s", "role": "default"},
+			objLabels:          map[string]string{"role": "obj", "team": "a"},
+			expLabels:          map[string]string{"prometheus": "k8s", "role": "obj", "team": "a"},
+		},
+
+		"The spec rule selector labels should take precedence over the object and default rule selector labels.": {
+			ruleSelectorLabels: map[string]string{"prometheus": "k8s", "role": "default", "tier": "default"},
+			objLabels:          map[string]string{"role": "obj", "team": "a"},
+			specLabels:         map[string]string{"role": "spec", "prometheus": "other"},
+			expLabels:          map[string]string{"prometheus": "other", "role": "spec", "team": "a", "tier": "default"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotKMeta k8sprometheus.K8sMeta
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					return &generate.Response{}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotKMeta = kmeta
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					return nil
+				}),
+				RuleSelectorLabels: test.ruleSelectorLabels,
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Labels: test.objLabels},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service:            "test-svc",
+					RuleSelectorLabels: test.specLabels,
+					SLOs: []slothv1.SLO{{
+						Name:      "slo1",
+						Objective: 99,
+						SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+						Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+					}},
+				},
+			}
+			err = h.Handle(context.TODO(), psl)
+			require.NoError(err)
+
+			assert.Equal(test.expLabels, gotKMeta.Labels)
+		})
+	}
+}
+
+func TestHandlerExternalLabels(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var gotReq generate.Request
+	h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+		Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+			gotReq = r
+			return &generate.Response{}, nil
+		}),
+		Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+			return nil
+		}),
+		KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+			return nil
+		}),
+		ExternalLabels: map[string]string{"cluster": "default", "env": "prod"},
+	})
+	require.NoError(err)
+
+	psl := &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service:        "test-svc",
+			ExternalLabels: map[string]string{"cluster": "c1"},
+			SLOs: []slothv1.SLO{
+				{
+					Name:      "slo1",
+					Objective: 99,
+					SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+					Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+				},
+				{
+					Name:      "slo2",
+					Objective: 99.9,
+					SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+					Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+				},
+			},
+		},
+	}
+	err = h.Handle(context.TODO(), psl)
+	require.NoError(err)
+
+	require.Len(gotReq.SLOGroup.SLOs, 2)
+	for _, slo := range gotReq.SLOGroup.SLOs {
+		assert.Equal(map[string]string{"cluster": "c1", "env": "prod"}, slo.ExternalLabels)
+	}
+}
+
+type alertWindowsCatalogRepositoryFunc func(ctx context.Context, ns, name string) (*k8sprometheus.AlertWindowsCatalog, error)
+
+func (a alertWindowsCatalogRepositoryFunc) GetAlertWindowsCatalog(ctx context.Context, ns, name string) (*k8sprometheus.AlertWindowsCatalog, error) {
+	return a(ctx, ns, name)
+}
+
+func TestHandlerAlertWindowsCatalog(t *testing.T) {
+	catalog, err := k8sprometheus.NewAlertWindowsCatalogFromConfigMap(&corev1.ConfigMap{
+		Data: map[string]string{
+			"30d": "pageQuick: {short: 10m, long: 2h}\npageSlow: {short: 1h, long: 12h}",
+		},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		catalog           string
+		pslCatalog        string
+		sloWindows        *slothv1.AlertWindows
+		catalogErr        error
+		expCatalog        string
+		expAlertOverrides alert.MWMBAlertGroupOverride
+		expErr            bool
+	}{
+		"Without catalog the SLO windows should not be set.": {},
+
+		"The default catalog windows should be set on the SLOs.": {
+			catalog:    "monitoring/windows",
+			expCatalog: "monitoring/windows",
+			expAlertOverrides: alert.MWMBAlertGroupOverride{
+				PageQuick: alert.MWMBAlertOverride{ShortWindow: 10 * time.Minute, LongWindow: 2 * time.Hour},
+				PageSlow:  alert.MWMBAlertOverride{ShortWindow: time.Hour, LongWindow: 12 * time.Hour},
+			},
+		},
+
+		"The PrometheusServiceLevel catalog should take precedence over the default catalog.": {
+			catalog:    "monitoring/windows",
+			pslCatalog: "custom-windows",
+			expCatalog: "test-ns/custom-windows",
+			expAlertOverrides: alert.MWMBAlertGroupOverride{
+				PageQuick: alert.MWMBAlertOverride{ShortWindow: 10 * time.Minute, LongWindow: 2 * time.Hour},
+				PageSlow:  alert.MWMBAlertOverride{ShortWindow: time.Hour, LongWindow: 12 * time.Hour},
+			},
+		},
+
+		"The SLO windows should take precedence over the catalog windows.": {
+			catalog:    "monitoring/windows",
+			sloWindows: &slothv1.AlertWindows{PageQuick: slothv1.AlertWindow{Short: "15m", Long: "3h"}},
+			expCatalog: "monitoring/windows",
+			expAlertOverrides: alert.MWMBAlertGroupOverride{
+				PageQuick: alert.MWMBAlertOverride{ShortWindow: 15 * time.Minute, LongWindow: 3 * time.Hour},
+				PageSlow:  alert.MWMBAlertOverride{ShortWindow: time.Hour, LongWindow: 12 * time.Hour},
+			},
+		},
+
+		"A failure getting the catalog should fail the handling.": {
+			catalog:    "monitoring/windows",
+			catalogErr: fmt.Errorf("something"),
+			expCatalog: "monitoring/windows",
+			expErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotReq generate.Request
+			var gotCatalog string
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					gotReq = r
+					return &generate.Response{}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					return nil
+				}),
+				AlertWindowsCatalogRepository: alertWindowsCatalogRepositoryFunc(func(ctx context.Context, ns, name string) (*k8sprometheus.AlertWindowsCatalog, error) {
+					gotCatalog = ns + "/" + name
+					if test.catalogErr != nil {
+						return nil, test.catalogErr
+					}
+					return catalog, nil
+				}),
+				AlertWindowsCatalog: test.catalog,
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service:             "test-svc",
+					AlertWindowsCatalog: test.pslCatalog,
+					SLOs: []slothv1.SLO{
+						{
+							Name:      "slo1",
+							Objective: 99,
+							SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+							Alerting: slothv1.Alerting{
+								Windows:     test.sloWindows,
+								PageAlert:   slothv1.Alert{Disable: true},
+								TicketAlert: slothv1.Alert{Disable: true},
+							},
+						},
+					},
+				},
+			}
+			err = h.Handle(context.TODO(), psl)
+			assert.Equal(test.expCatalog, gotCatalog)
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			require.Len(gotReq.SLOGroup.SLOs, 1)
+			assert.Equal(test.expAlertOverrides, gotReq.SLOGroup.SLOs[0].AlertOverrides)
+		})
+	}
+}
+
+func TestHandlerRulesNamespace(t *testing.T) {
+	tests := map[string]struct {
+		rulesNamespace string
+		expKMeta       k8sprometheus.K8sMeta
+	}{
+		"Without rules namespace the rules should be stored on the PrometheusServiceLevel namespace.": {
+			expKMeta: k8sprometheus.K8sMeta{
+				Kind:        "PrometheusServiceLevel",
+				APIVersion:  "sloth.slok.dev/v1",
+				Name:        "test",
+				Namespace:   "test-ns",
+				Annotations: map[string]string{"a": "b"},
+			},
+		},
+
+		"With the PrometheusServiceLevel namespace as the rules namespace the rules should be stored as usual.": {
+			rulesNamespace: "test-ns",
+			expKMeta: k8sprometheus.K8sMeta{
+				Kind:        "PrometheusServiceLevel",
+				APIVersion:  "sloth.slok.dev/v1",
+				Name:        "test",
+				Namespace:   "test-ns",
+				Annotations: map[string]string{"a": "b"},
+			},
+		},
+
+		"With a rules namespace the rules should be stored on the rules namespace referencing the PrometheusServiceLevel.": {
+			rulesNamespace: "monitoring",
+			expKMeta: k8sprometheus.K8sMeta{
+				Kind:       "PrometheusServiceLevel",
+				APIVersion: "sloth.slok.dev/v1",
+				Name:       "test-ns-test",
+				Namespace:  "monitoring",
+				Annotations: map[string]string{
+					"a": "b",
+					k8sprometheus.SourceNamespaceAnnotationName: "test-ns",
+					k8sprometheus.SourceNameAnnotationName:      "test",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotKMeta k8sprometheus.K8sMeta
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					return &generate.Response{}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotKMeta = kmeta
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					return nil
+				}),
+				RulesNamespace: test.rulesNamespace,
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Annotations: map[string]string{"a": "b"}},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service: "test-svc",
+					SLOs: []slothv1.SLO{{
+						Name:      "slo1",
+						Objective: 99,
+						SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+						Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+					}},
+				},
+			}
+			err = h.Handle(context.TODO(), psl)
+			require.NoError(err)
+
+			assert.Equal(test.expKMeta, gotKMeta)
+			assert.Equal(map[string]string{"a": "b"}, psl.Annotations)
+		})
+	}
+}
+
+func TestHandlerDryRun(t *testing.T) {
+	tests := map[string]struct {
+		objective float64
+		expEvents []string
+		expErr    bool
+	}{
+		"A dry run should generate the rules without storing them.": {
+			objective: 99,
+			expEvents: []string{"Normal DryRun Dry run: 3 Prometheus rules for 1 SLOs would be stored on test-ns/test"},
+		},
+
+		"A dry run with an invalid spec should record the error without storing the status.": {
+			objective: 100,
+			expEvents: []string{"Warning InvalidSpec"},
+			expErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			recorder := record.NewFakeRecorder(10)
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					return &generate.Response{PrometheusSLOs: []generate.SLOResult{{
+						SLO:      r.SLOGroup.SLOs[0],
+						SLORules: prometheus.SLORules{SLIErrorRecRules: make([]rulefmt.Rule, 3)},
+					}}}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					t.Errorf("rules should not be stored")
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					t.Errorf("status should not be stored")
+					return nil
+				}),
+				EventRecorder: recorder,
+				DryRun:        true,
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service: "test-svc",
+					SLOs: []slothv1.SLO{{
+						Name:      "slo1",
+						Objective: test.objective,
+						SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+						Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+					}},
+				},
+			}
+			err = h.Handle(context.TODO(), psl)
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			close(recorder.Events)
+			gotEvents := []string{}
+			for e := range recorder.Events {
+				gotEvents = append(gotEvents, e)
+			}
+			require.Len(gotEvents, len(test.expEvents))
+			for i, exp := range test.expEvents {
+				assert.Contains(gotEvents[i], exp)
+			}
+		})
+	}
+}
+
+func TestHandlerPaused(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		expHandled  bool
+	}{
+		"A not paused object should be handled.": {
+			annotations: map[string]string{kubecontroller.PausedAnnotationName: "false"},
+			expHandled:  true,
+		},
+
+		"A paused object should not be handled.": {
+			annotations: map[string]string{kubecontroller.PausedAnnotationName: "true"},
+			expHandled:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotStore, gotStatus bool
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					return &generate.Response{}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotStore = true
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					gotStatus = true
+					return nil
+				}),
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Annotations: test.annotations},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service: "test-svc",
+					SLOs: []slothv1.SLO{{
+						Name:      "slo1",
+						Objective: 99,
+						SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+						Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+					}},
+				},
+			}
+			err = h.Handle(context.TODO(), psl)
+			require.NoError(err)
+
+			assert.Equal(test.expHandled, gotStore)
+			assert.Equal(test.expHandled, gotStatus)
+		})
+	}
+}
+
+func TestHandlerSecondaryRepository(t *testing.T) {
+	tests := map[string]struct {
+		primaryErr   error
+		secondaryErr error
+		expErr       bool
+		expEvents    []string
+	}{
+		"A secondary store error should not fail the handling.": {
+			secondaryErr: fmt.Errorf("something"),
+			expEvents: []string{
+				"Warning SecondaryApplyFailed Prometheus rules could not be stored on the secondary output: something",
+				"Normal RulesUpdated",
+			},
+		},
+
+		"A primary store error should fail the handling and store on the secondary repository.": {
+			primaryErr: fmt.Errorf("something"),
+			expErr:     true,
+			expEvents:  []string{"Warning ApplyFailed Prometheus rules could not be stored: could not store SLOs: something"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotPrimary, gotSecondary bool
+			var gotResult k8sprometheus.HandleResult
+			recorder := record.NewFakeRecorder(10)
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					return &generate.Response{}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotPrimary = true
+					return test.primaryErr
+				}),
+				SecondaryRepository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotSecondary = true
+					return test.secondaryErr
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					gotResult = result
+					return nil
+				}),
+				EventRecorder: recorder,
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service: "test-svc",
+					SLOs: []slothv1.SLO{{
+						Name:      "slo1",
+						Objective: 99,
+						SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+						Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+					}},
+				},
+			}
+			err = h.Handle(context.TODO(), psl)
+			close(recorder.Events)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.True(gotPrimary)
+			assert.True(gotSecondary)
+			assert.Equal(!test.expErr, gotResult.RulesApplied)
+
+			gotEvents := []string{}
+			for e := range recorder.Events {
+				gotEvents = append(gotEvents, e)
+			}
+			require.Len(gotEvents, len(test.expEvents))
+			for i, exp := range test.expEvents {
+				assert.Contains(gotEvents[i], exp)
+			}
+		})
+	}
+}
+
+func TestHandlerForceHandle(t *testing.T) {
+	tests := map[string]struct {
+		force      bool
+		expHandled bool
+	}{
+		"An already handled object without spec changes should not be handled.": {
+			force:      false,
+			expHandled: false,
+		},
+
+		"An already handled object without spec changes should be handled when forced.": {
+			force:      true,
+			expHandled: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gotStore := false
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					return &generate.Response{}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotStore = true
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					return nil
+				}),
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Generation: 2},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service: "test-svc",
+					SLOs: []slothv1.SLO{{
+						Name:      "slo1",
+						Objective: 99,
+						SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+						Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+					}},
+				},
+				Status: slothv1.PrometheusServiceLevelStatus{
+					ObservedGeneration:                 2,
+					PromOpRulesGenerated:               true,
+					LastPromOpRulesSuccessfulGenerated: &metav1.Time{Time: time.Now()},
+				},
+			}
+			ctx := context.TODO()
+			if test.force {
+				ctx = kubecontroller.WithForceHandle(ctx)
+			}
+			err = h.Handle(ctx, psl)
+			require.NoError(err)
+
+			assert.Equal(test.expHandled, gotStore)
+		})
+	}
+}
+
+type rulesDeleterFunc func(ctx context.Context, kmeta k8sprometheus.K8sMeta) error
+
+func (r rulesDeleterFunc) DeleteSLOs(ctx context.Context, kmeta k8sprometheus.K8sMeta) error {
+	return r(ctx, kmeta)
+}
+
+type testFinalizerStorer struct {
+	added   []string
+	removed []string
+}
+
+func (t *testFinalizerStorer) AddPrometheusServiceLevelFinalizer(ctx context.Context, slo *slothv1.PrometheusServiceLevel, finalizer string) (*slothv1.PrometheusServiceLevel, error) {
+	t.added = append(t.added, slo.Name+" "+finalizer)
+	slo = slo.DeepCopy()
+	slo.Finalizers = append(slo.Finalizers, finalizer)
+	return slo, nil
+}
+
+func (t *testFinalizerStorer) RemovePrometheusServiceLevelFinalizer(ctx context.Context, slo *slothv1.PrometheusServiceLevel, finalizer string) error {
+	t.removed = append(t.removed, slo.Name+" "+finalizer)
+	return nil
+}
+
+func TestHandlerRulesCleanupFinalizer(t *testing.T) {
+	tests := map[string]struct {
+		deleting     bool
+		finalizer    bool
+		noDeleter    bool
+		deleteErr    error
+		expErr       bool
+		expStored    bool
+		expDeleted   []string
+		expAdded     []string
+		expRemoved   []string
+		expFinalizer bool
+	}{
+		"A handled object should get the finalizer before storing the rules.": {
+			expStored:    true,
+			expAdded:     []string{"test sloth.slok.dev/rules-cleanup"},
+			expFinalizer: true,
+		},
+
+		"A handled object without rules deleter should not get the finalizer.": {
+			noDeleter: true,
+			expStored: true,
+		},
+
+		"A deleted object with the finalizer should have its rules deleted and the finalizer removed.": {
+			deleting:   true,
+			finalizer:  true,
+			expDeleted: []string{"rules-ns/test-ns-test"},
+			expRemoved: []string{"test sloth.slok.dev/rules-cleanup"},
+		},
+
+		"A deleted object with the finalizer whose rules can't be deleted should fail and keep the finalizer.": {
+			deleting:   true,
+			finalizer:  true,
+			deleteErr:  fmt.Errorf("something"),
+			expErr:     true,
+			expDeleted: []string{"rules-ns/test-ns-test"},
+		},
+
+		"A deleted object with the finalizer and without rules deleter should only have the finalizer removed.": {
+			deleting:   true,
+			finalizer:  true,
+			noDeleter:  true,
+			expRemoved: []string{"test sloth.slok.dev/rules-cleanup"},
+		},
+
+		"A deleted object without the finalizer should be ignored.": {
+			deleting: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gotStored := false
+			var gotDeleted []string
+			gotFinalizer := false
+			finalizerStorer := &testFinalizerStorer{}
+			var deleter kubecontroller.RulesDeleter = rulesDeleterFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta) error {
+				gotDeleted = append(gotDeleted, kmeta.Namespace+"/"+kmeta.Name)
+				return test.deleteErr
+			})
+			if test.noDeleter {
+				deleter = nil
+			}
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					return &generate.Response{}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotStored = true
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					gotFinalizer = len(slo.Finalizers) == 1
+					return nil
+				}),
+				RulesDeleter:        deleter,
+				KubeFinalizerStorer: finalizerStorer,
+				RulesNamespace:      "rules-ns",
+			})
+			require.NoError(err)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					Service: "test-svc",
+					SLOs: []slothv1.SLO{{
+						Name:      "slo1",
+						Objective: 99,
+						SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+						Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+					}},
+				},
+			}
+			if test.deleting {
+				psl.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			if test.finalizer {
+				psl.Finalizers = []string{kubecontroller.RulesCleanupFinalizerName}
+			}
+			err = h.Handle(context.TODO(), psl)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.expStored, gotStored)
+			assert.Equal(test.expFinalizer, gotFinalizer)
+			assert.Equal(test.expDeleted, gotDeleted)
+			assert.Equal(test.expAdded, finalizerStorer.added)
+			assert.Equal(test.expRemoved, finalizerStorer.removed)
+		})
+	}
+}
+
+type reconcileMetricsRecorder struct {
+	metrics.Recorder
+	results  []string
+	failures []int
+	deleted  []string
+}
+
+func (r *reconcileMetricsRecorder) ObservePrometheusServiceLevelReconcile(ctx context.Context, cluster, ns, name, result string, duration time.Duration) {
+	r.results = append(r.results, cluster+"/"+ns+"/"+name+" "+result)
+}
+
+func (r *reconcileMetricsRecorder) SetPrometheusServiceLevelConsecutiveFailures(ctx context.Context, cluster, ns, name string, quantity int) {
+	r.failures = append(r.failures, quantity)
+}
+
+func (r *reconcileMetricsRecorder) DeletePrometheusServiceLevelMetrics(ctx context.Context, cluster, ns, name string) {
+	r.deleted = append(r.deleted, cluster+"/"+ns+"/"+name)
+}
+
+func TestHandlerMetrics(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	storeErrs := []error{fmt.Errorf("something"), fmt.Errorf("something"), nil, fmt.Errorf("something")}
+	rec := &reconcileMetricsRecorder{Recorder: metrics.Noop}
+	h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+		Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+			return &generate.Response{}, nil
+		}),
+		Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+			err := storeErrs[0]
+			storeErrs = storeErrs[1:]
+			return err
+		}),
+		KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+			return nil
+		}),
+		MetricsRecorder: rec,
+	})
+	require.NoError(err)
+
+	psl := &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service: "test-svc",
+			SLOs: []slothv1.SLO{{
+				Name:      "slo1",
+				Objective: 99,
+				SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+				Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+			}},
+		},
+	}
+	for range storeErrs {
+		_ = h.Handle(context.TODO(), psl)
+	}
+	invalidPSL := psl.DeepCopy()
+	invalidPSL.Name = "test-invalid"
+	invalidPSL.Spec.SLOs[0].Objective = 100
+	_ = h.Handle(context.TODO(), invalidPSL)
+
+	expResults := []string{
+		"local/test-ns/test apply_failed",
+		"local/test-ns/test apply_failed",
+		"local/test-ns/test success",
+		"local/test-ns/test apply_failed",
+		"local/test-ns/test-invalid invalid_spec",
+	}
+	assert.Equal(expResults, rec.results)
+	assert.Equal([]int{1, 2, 0, 1, 1}, rec.failures)
+}
+
+func TestHandlerMetricsDeletedObject(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rec := &reconcileMetricsRecorder{Recorder: metrics.Noop}
+	h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+		Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+			return &generate.Response{}, nil
+		}),
+		Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+			return fmt.Errorf("something")
+		}),
+		KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+			return nil
+		}),
+		MetricsRecorder: rec,
+	})
+	require.NoError(err)
+
+	psl := &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service: "test-svc",
+			SLOs: []slothv1.SLO{{
+				Name:      "slo1",
+				Objective: 99,
+				SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+				Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+			}},
+		},
+	}
+	_ = h.Handle(context.TODO(), psl)
+
+	// The deleted object metrics should be deleted once, and its failures start again if recreated.
+	deletedPSL := psl.DeepCopy()
+	deletedPSL.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	_ = h.Handle(context.TODO(), deletedPSL)
+	_ = h.Handle(context.TODO(), deletedPSL)
+	_ = h.Handle(context.TODO(), psl)
+
+	assert.Equal([]string{"local/test-ns/test"}, rec.deleted)
+	assert.Equal([]int{1, 1}, rec.failures)
+}
+
+func TestHandlerConfigMap(t *testing.T) {
+	promSpec := `
+version: "prometheus/v1"
+service: "svc1"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: test_expr
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`
+	k8sSpec := `
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: ignored
+spec:
+  service: "svc2"
+  slos:
+    - name: "slo2"
+      objective: 99
+      sli:
+        raw:
+          errorRatioQuery: test_expr
+      alerting:
+        pageAlert:
+          disable: true
+        ticketAlert:
+          disable: true
+`
+
+	tests := map[string]struct {
+		data      map[string]string
+		expSpecs  []string
+		expKMeta  *k8sprometheus.K8sMeta
+		expSLOIDs []string
+		expEvents []string
+		expErr    bool
+	}{
+		"A ConfigMap with multiple specs should store the rules of all the specs together.": {
+			data:     map[string]string{"b.yaml": k8sSpec, "a.yaml": promSpec},
+			expSpecs: []string{"prometheus/v1", "sloth.slok.dev/v1"},
+			expKMeta: &k8sprometheus.K8sMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+				Name:       "test",
+				Namespace:  "test-ns",
+				UID:        "test-uid",
+				Labels:     map[string]string{"prometheus": "k8s"},
+			},
+			expSLOIDs: []string{"svc1-slo1", "svc2-slo2"},
+		},
+
+		"A ConfigMap with an invalid spec should not store the rules and record an invalid spec warning.": {
+			data:      map[string]string{"a.yaml": promSpec, "b.yaml": "service: test"},
+			expEvents: []string{`Warning InvalidSpec Invalid spec: could not load ConfigMap specs into model: invalid "b.yaml" key spec`},
+			expErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gotSpecs := []string{}
+			var gotKMeta *k8sprometheus.K8sMeta
+			gotSLOIDs := []string{}
+			recorder := record.NewFakeRecorder(10)
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+					gotSpecs = append(gotSpecs, r.Info.Spec)
+					return &generate.Response{PrometheusSLOs: []generate.SLOResult{{SLO: r.SLOGroup.SLOs[0]}}}, nil
+				}),
+				Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+					gotKMeta = &kmeta
+					for _, s := range slos {
+						gotSLOIDs = append(gotSLOIDs, s.SLO.ID)
+					}
+					return nil
+				}),
+				KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+					return fmt.Errorf("ConfigMaps don't have status")
+				}),
+				EventRecorder:      recorder,
+				RuleSelectorLabels: map[string]string{"prometheus": "k8s"},
+			})
+			require.NoError(err)
+
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", UID: "test-uid", Labels: map[string]string{"sloth-spec": "true"}},
+				Data:       test.data,
+			}
+			err = h.Handle(context.TODO(), cm)
+			close(recorder.Events)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			gotEvents := []string{}
+			for e := range recorder.Events {
+				gotEvents = append(gotEvents, e)
+			}
+			require.Len(gotEvents, len(test.expEvents))
+			for i, exp := range test.expEvents {
+				assert.Contains(gotEvents[i], exp)
+			}
+
+			if test.expKMeta != nil {
+				assert.Equal(test.expSpecs, gotSpecs)
+				assert.Equal(test.expKMeta, gotKMeta)
+				assert.Equal(test.expSLOIDs, gotSLOIDs)
+			} else {
+				assert.Nil(gotKMeta)
+			}
+		})
+	}
+}
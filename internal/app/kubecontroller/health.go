@@ -0,0 +1,121 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// InformerHealthCheck tracks the list and watch calls of the PrometheusServiceLevels retriever, so
+// the health of the controller informer can be checked. It wraps the retriever repository.
+//
+// The informer is healthy when the cache has been synced (listed) and the last list or watch didn't
+// fail. The watches are restarted periodically (the API server closes them), so a watch that has not
+// been restarted in more than the max watch age means that the informer is stuck.
+type InformerHealthCheck struct {
+	RetrieverKubernetesRepository
+	*informerHealth
+}
+
+// NewInformerHealthCheck returns a new informer health check that tracks the repository calls.
+func NewInformerHealthCheck(repo RetrieverKubernetesRepository, maxWatchAge time.Duration) *InformerHealthCheck {
+	return &InformerHealthCheck{
+		RetrieverKubernetesRepository: repo,
+		informerHealth:                newInformerHealth(maxWatchAge),
+	}
+}
+
+func (i *InformerHealthCheck) ListPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (*slothv1.PrometheusServiceLevelList, error) {
+	l, err := i.RetrieverKubernetesRepository.ListPrometheusServiceLevels(ctx, ns, labelSelector)
+	i.listed(err)
+	return l, err
+}
+
+func (i *InformerHealthCheck) WatchPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (watch.Interface, error) {
+	w, err := i.RetrieverKubernetesRepository.WatchPrometheusServiceLevels(ctx, ns, labelSelector)
+	i.watched(err)
+	return w, err
+}
+
+// ConfigMapInformerHealthCheck is like InformerHealthCheck but for the ConfigMap specs retriever.
+type ConfigMapInformerHealthCheck struct {
+	ConfigMapRetrieverKubernetesRepository
+	*informerHealth
+}
+
+// NewConfigMapInformerHealthCheck returns a new ConfigMap informer health check that tracks the repository calls.
+func NewConfigMapInformerHealthCheck(repo ConfigMapRetrieverKubernetesRepository, maxWatchAge time.Duration) *ConfigMapInformerHealthCheck {
+	return &ConfigMapInformerHealthCheck{
+		ConfigMapRetrieverKubernetesRepository: repo,
+		informerHealth:                         newInformerHealth(maxWatchAge),
+	}
+}
+
+func (i *ConfigMapInformerHealthCheck) ListConfigMaps(ctx context.Context, ns string, labelSelector string) (*corev1.ConfigMapList, error) {
+	l, err := i.ConfigMapRetrieverKubernetesRepository.ListConfigMaps(ctx, ns, labelSelector)
+	i.listed(err)
+	return l, err
+}
+
+func (i *ConfigMapInformerHealthCheck) WatchConfigMaps(ctx context.Context, ns string, labelSelector string) (watch.Interface, error) {
+	w, err := i.ConfigMapRetrieverKubernetesRepository.WatchConfigMaps(ctx, ns, labelSelector)
+	i.watched(err)
+	return w, err
+}
+
+type informerHealth struct {
+	maxWatchAge time.Duration
+	mu          sync.Mutex
+	synced      bool
+	lastWatch   time.Time
+	err         error
+}
+
+func newInformerHealth(maxWatchAge time.Duration) *informerHealth {
+	if maxWatchAge == 0 {
+		maxWatchAge = 2 * time.Hour
+	}
+
+	return &informerHealth{maxWatchAge: maxWatchAge}
+}
+
+func (i *informerHealth) listed(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.err = err
+	if err == nil {
+		i.synced = true
+	}
+}
+
+func (i *informerHealth) watched(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.err = err
+	if err == nil {
+		i.lastWatch = time.Now()
+	}
+}
+
+// Check returns an error if the informer is not healthy.
+func (i *informerHealth) Check(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	switch {
+	case i.err != nil:
+		return fmt.Errorf("informer list/watch failed: %w", i.err)
+	case !i.synced:
+		return fmt.Errorf("informer cache not synced")
+	case !i.lastWatch.IsZero() && time.Since(i.lastWatch) > i.maxWatchAge:
+		return fmt.Errorf("informer watch not restarted since %s", i.lastWatch.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+type retrieverRepository struct {
+	listErr  error
+	watchErr error
+}
+
+func (r retrieverRepository) ListPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (*slothv1.PrometheusServiceLevelList, error) {
+	if r.listErr != nil {
+		return nil, r.listErr
+	}
+	return &slothv1.PrometheusServiceLevelList{}, nil
+}
+
+func (r retrieverRepository) WatchPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (watch.Interface, error) {
+	if r.watchErr != nil {
+		return nil, r.watchErr
+	}
+	return watch.NewEmptyWatch(), nil
+}
+
+func TestInformerHealthCheck(t *testing.T) {
+	tests := map[string]struct {
+		repo        retrieverRepository
+		maxWatchAge time.Duration
+		calls       func(r kubecontroller.RetrieverKubernetesRepository)
+		expErr      bool
+	}{
+		"Without listing the informer should not be synced.": {
+			calls:  func(r kubecontroller.RetrieverKubernetesRepository) {},
+			expErr: true,
+		},
+
+		"Listing and watching should be healthy.": {
+			calls: func(r kubecontroller.RetrieverKubernetesRepository) {
+				_, _ = r.ListPrometheusServiceLevels(context.TODO(), "", nil)
+				_, _ = r.WatchPrometheusServiceLevels(context.TODO(), "", nil)
+			},
+		},
+
+		"A failed list should be unhealthy.": {
+			repo: retrieverRepository{listErr: fmt.Errorf("something")},
+			calls: func(r kubecontroller.RetrieverKubernetesRepository) {
+				_, _ = r.ListPrometheusServiceLevels(context.TODO(), "", nil)
+			},
+			expErr: true,
+		},
+
+		"A failed watch after a correct list should be unhealthy.": {
+			repo: retrieverRepository{watchErr: fmt.Errorf("something")},
+			calls: func(r kubecontroller.RetrieverKubernetesRepository) {
+				_, _ = r.ListPrometheusServiceLevels(context.TODO(), "", nil)
+				_, _ = r.WatchPrometheusServiceLevels(context.TODO(), "", nil)
+			},
+			expErr: true,
+		},
+
+		"A watch that has not been restarted in more than the max watch age should be unhealthy.": {
+			maxWatchAge: time.Nanosecond,
+			calls: func(r kubecontroller.RetrieverKubernetesRepository) {
+				_, _ = r.ListPrometheusServiceLevels(context.TODO(), "", nil)
+				_, _ = r.WatchPrometheusServiceLevels(context.TODO(), "", nil)
+				time.Sleep(time.Millisecond)
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			check := kubecontroller.NewInformerHealthCheck(test.repo, test.maxWatchAge)
+			test.calls(check)
+
+			err := check.Check(context.TODO())
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/metrics"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// InventoryKubernetesRepository is the service to manage k8s resources by the inventory collector.
+type InventoryKubernetesRepository interface {
+	ListPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (*slothv1.PrometheusServiceLevelList, error)
+	ListConfigMaps(ctx context.Context, ns string, labelSelector string) (*corev1.ConfigMapList, error)
+}
+
+// InventoryCollectorConfig is the inventory collector configuration.
+type InventoryCollectorConfig struct {
+	Repository InventoryKubernetesRepository
+	// ConfigMapSpecLoader loads the raw specs of the ConfigMaps.
+	ConfigMapSpecLoader ConfigMapSpecLoader
+	// ConfigMapSource will take the inventory of the SLO spec ConfigMaps instead of the PrometheusServiceLevels.
+	ConfigMapSource bool
+	// Namespace is the namespace of the SLO spec objects, by default all.
+	Namespace string
+	// NamespaceFilter filters the namespaces of the SLO spec objects, by default all.
+	NamespaceFilter NamespaceFilter
+	// Selector is the label selector of the SLO spec objects, by default all.
+	Selector labels.Selector
+	// Shard is the controller shard, only the SLO spec objects of the shard are taken.
+	Shard Shard
+	// Interval is the interval between the inventories.
+	Interval time.Duration
+	// ReconcileMetrics are the handler reconcile metrics of the local cluster, the objects not listed
+	// anymore are forgotten, by default disabled.
+	ReconcileMetrics *ReconcileMetrics
+	MetricsRecorder  metrics.Recorder
+	Logger           log.Logger
+}
+
+func (c *InventoryCollectorConfig) defaults() error {
+	if c.Repository == nil {
+		return fmt.Errorf("repository is required")
+	}
+
+	if c.ConfigMapSpecLoader == nil {
+		c.ConfigMapSpecLoader = k8sprometheus.ConfigMapSpecLoader
+	}
+
+	if c.NamespaceFilter == nil {
+		c.NamespaceFilter = AllNamespaces
+	}
+
+	if c.Selector == nil {
+		c.Selector = labels.Everything()
+	}
+
+	err := c.Shard.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid shard: %w", err)
+	}
+
+	if c.Interval == 0 {
+		c.Interval = time.Minute
+	}
+
+	if c.MetricsRecorder == nil {
+		c.MetricsRecorder = metrics.Noop
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.InventoryCollector"})
+
+	return nil
+}
+
+// InventoryCollector knows how to measure the SLO spec objects, SLOs and generated rules managed by the
+// controller, and the last time all of them were in sync, e.g for the controller capacity and health
+// dashboards.
+type InventoryCollector struct {
+	repository       InventoryKubernetesRepository
+	cmSpecLoader     ConfigMapSpecLoader
+	configMapSource  bool
+	namespace        string
+	nsFilter         NamespaceFilter
+	selector         labels.Selector
+	shard            Shard
+	interval         time.Duration
+	reconcileMetrics *ReconcileMetrics
+	metricsRecorder  metrics.Recorder
+	logger           log.Logger
+}
+
+// NewInventoryCollector returns a new InventoryCollector.
+func NewInventoryCollector(config InventoryCollectorConfig) (*InventoryCollector, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &InventoryCollector{
+		repository:       config.Repository,
+		cmSpecLoader:     config.ConfigMapSpecLoader,
+		configMapSource:  config.ConfigMapSource,
+		namespace:        config.Namespace,
+		nsFilter:         config.NamespaceFilter,
+		selector:         config.Selector,
+		shard:            config.Shard,
+		interval:         config.Interval,
+		reconcileMetrics: config.ReconcileMetrics,
+		metricsRecorder:  config.MetricsRecorder,
+		logger:           config.Logger,
+	}, nil
+}
+
+// Run will take the inventory in intervals until the context is cancelled.
+func (i *InventoryCollector) Run(ctx context.Context) error {
+	t := time.NewTicker(i.interval)
+	defer t.Stop()
+
+	for {
+		err := i.Collect(ctx)
+		if err != nil {
+			i.logger.Errorf("Could not take managed SLOs inventory: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Collect will take the inventory once.
+func (i *InventoryCollector) Collect(ctx context.Context) error {
+	collect := i.collectPrometheusServiceLevels
+	if i.configMapSource {
+		collect = i.collectConfigMaps
+	}
+
+	// Take the tracked objects before listing, so the objects created after the listing are not forgotten.
+	var tracked map[string]objectKey
+	if i.reconcileMetrics != nil {
+		tracked = i.reconcileMetrics.trackedObjects()
+	}
+
+	inventory := map[string]*metrics.Inventory{}
+	listed := map[string]bool{}
+	inSync, err := collect(ctx, func(ns, name, specVersion string, objects, slos, rules int) {
+		listed[ns+"/"+name] = true
+		key := ns + "/" + specVersion
+		inv, ok := inventory[key]
+		if !ok {
+			inv = &metrics.Inventory{Namespace: ns, SpecVersion: specVersion}
+			inventory[key] = inv
+		}
+		inv.Objects += objects
+		inv.SLOs += slos
+		inv.Rules += rules
+	})
+	if err != nil {
+		return err
+	}
+
+	for k, obj := range tracked {
+		if !listed[k] {
+			i.reconcileMetrics.forget(ctx, obj.namespace, obj.name)
+		}
+	}
+
+	keys := make([]string, 0, len(inventory))
+	for k := range inventory {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	invs := make([]metrics.Inventory, 0, len(keys))
+	for _, k := range keys {
+		invs = append(invs, *inventory[k])
+	}
+
+	i.metricsRecorder.SetManagedInventory(ctx, invs)
+	if inSync {
+		i.metricsRecorder.SetLastFullSync(ctx, time.Now())
+	}
+
+	return nil
+}
+
+type inventoryAddFunc func(ns, name, specVersion string, objects, slos, rules int)
+
+func (i *InventoryCollector) collectPrometheusServiceLevels(ctx context.Context, add inventoryAddFunc) (inSync bool, err error) {
+	l, err := i.repository.ListPrometheusServiceLevels(ctx, i.namespace, map[string]string{})
+	if err != nil {
+		return false, fmt.Errorf("could not list PrometheusServiceLevels: %w", err)
+	}
+
+	specVersion := slothv1.SchemeGroupVersion.String()
+	inSync = true
+	for _, psl := range l.Items {
+		if !i.nsFilter.Allows(psl.Namespace) || !i.selector.Matches(labels.Set(psl.Labels)) || !i.shard.Owns(psl.Namespace, psl.Name) {
+			continue
+		}
+
+		add(psl.Namespace, psl.Name, specVersion, 1, len(psl.Spec.SLOs), psl.Status.GeneratedRules)
+		if isPaused(psl.Annotations) {
+			continue
+		}
+		if psl.Generation != psl.Status.ObservedGeneration || !psl.Status.PromOpRulesGenerated {
+			inSync = false
+		}
+	}
+
+	return inSync, nil
+}
+
+// collectConfigMaps takes the inventory of the SLO spec ConfigMaps, these don't have status, so the
+// generated rules are unknown and these are in sync when all the specs are valid. The invalid specs
+// are counted with the `invalid` spec version.
+func (i *InventoryCollector) collectConfigMaps(ctx context.Context, add inventoryAddFunc) (inSync bool, err error) {
+	l, err := i.repository.ListConfigMaps(ctx, i.namespace, i.selector.String())
+	if err != nil {
+		return false, fmt.Errorf("could not list ConfigMaps: %w", err)
+	}
+
+	inSync = true
+	for _, cm := range l.Items {
+		cm := cm
+		if !i.nsFilter.Allows(cm.Namespace) || !i.shard.Owns(cm.Namespace, cm.Name) {
+			continue
+		}
+
+		model, err := i.cmSpecLoader.LoadSpec(ctx, &cm)
+		if err != nil {
+			add(cm.Namespace, cm.Name, "invalid", 1, 0, 0)
+			inSync = false
+			continue
+		}
+
+		// A ConfigMap with multiple spec versions is counted on each version.
+		slos := map[string]int{}
+		for _, spec := range model.Specs {
+			slos[spec.Version] += len(spec.SLOs)
+		}
+		for version, n := range slos {
+			add(cm.Namespace, cm.Name, version, 1, n, 0)
+		}
+	}
+
+	return inSync, nil
+}
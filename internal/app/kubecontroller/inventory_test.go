@@ -0,0 +1,202 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/metrics"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+type inventoryMetricsRecorder struct {
+	metrics.Recorder
+	inventory    []metrics.Inventory
+	lastFullSync bool
+}
+
+func (r *inventoryMetricsRecorder) SetManagedInventory(ctx context.Context, inventory []metrics.Inventory) {
+	r.inventory = inventory
+}
+
+func (r *inventoryMetricsRecorder) SetLastFullSync(ctx context.Context, t time.Time) {
+	r.lastFullSync = true
+}
+
+func TestInventoryCollectorCollect(t *testing.T) {
+	newPSL := func(ns, name string, slos int, generation int64, status slothv1.PrometheusServiceLevelStatus) *slothv1.PrometheusServiceLevel {
+		return &slothv1.PrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Generation: generation},
+			Spec:       slothv1.PrometheusServiceLevelSpec{SLOs: make([]slothv1.SLO, slos)},
+			Status:     status,
+		}
+	}
+	syncedStatus := slothv1.PrometheusServiceLevelStatus{ObservedGeneration: 1, PromOpRulesGenerated: true, GeneratedRules: 10}
+
+	tests := map[string]struct {
+		configMapSource bool
+		slos            []runtime.Object
+		cms             []runtime.Object
+		expInventory    []metrics.Inventory
+		expFullSync     bool
+	}{
+		"No managed objects should set an empty inventory in sync.": {
+			expInventory: []metrics.Inventory{},
+			expFullSync:  true,
+		},
+
+		"The PrometheusServiceLevels in sync should set the inventory by namespace and the full sync.": {
+			slos: []runtime.Object{
+				newPSL("ns1", "test1", 2, 1, syncedStatus),
+				newPSL("ns1", "test2", 1, 1, syncedStatus),
+				newPSL("ns2", "test1", 3, 1, syncedStatus),
+			},
+			expInventory: []metrics.Inventory{
+				{Namespace: "ns1", SpecVersion: "sloth.slok.dev/v1", Objects: 2, SLOs: 3, Rules: 20},
+				{Namespace: "ns2", SpecVersion: "sloth.slok.dev/v1", Objects: 1, SLOs: 3, Rules: 10},
+			},
+			expFullSync: true,
+		},
+
+		"A PrometheusServiceLevel not in sync should not set the full sync.": {
+			slos: []runtime.Object{
+				newPSL("ns1", "test1", 2, 1, syncedStatus),
+				newPSL("ns1", "test2", 1, 2, syncedStatus),
+			},
+			expInventory: []metrics.Inventory{
+				{Namespace: "ns1", SpecVersion: "sloth.slok.dev/v1", Objects: 2, SLOs: 3, Rules: 20},
+			},
+			expFullSync: false,
+		},
+
+		"The ConfigMaps should set the inventory by spec version.": {
+			configMapSource: true,
+			cms: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "ns1"},
+					Data: map[string]string{"slos.yaml": `
+version: "prometheus/v1"
+service: "svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: "test_expr"
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "test2", Namespace: "ns1"},
+					Data:       map[string]string{"slos.yaml": "something"},
+				},
+			},
+			expInventory: []metrics.Inventory{
+				{Namespace: "ns1", SpecVersion: "invalid", Objects: 1},
+				{Namespace: "ns1", SpecVersion: "prometheus/v1", Objects: 1, SLOs: 1},
+			},
+			expFullSync: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(test.cms...), nil, slothfake.NewSimpleClientset(test.slos...), nil, log.Noop)
+			rec := &inventoryMetricsRecorder{Recorder: metrics.Noop}
+			collector, err := kubecontroller.NewInventoryCollector(kubecontroller.InventoryCollectorConfig{
+				Repository:      ksvc,
+				ConfigMapSource: test.configMapSource,
+				MetricsRecorder: rec,
+			})
+			require.NoError(err)
+
+			err = collector.Collect(context.TODO())
+			require.NoError(err)
+
+			assert.Equal(test.expInventory, rec.inventory)
+			assert.Equal(test.expFullSync, rec.lastFullSync)
+		})
+	}
+}
+
+type forgetMetricsRecorder struct {
+	metrics.Recorder
+	deleted []string
+}
+
+func (r *forgetMetricsRecorder) DeletePrometheusServiceLevelMetrics(ctx context.Context, cluster, ns, name string) {
+	r.deleted = append(r.deleted, cluster+"/"+ns+"/"+name)
+}
+
+func TestInventoryCollectorForgetDeletedObjects(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	newPSL := func(ns, name string) *slothv1.PrometheusServiceLevel {
+		return &slothv1.PrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Spec: slothv1.PrometheusServiceLevelSpec{
+				Service: "test-svc",
+				SLOs: []slothv1.SLO{{
+					Name:      "slo1",
+					Objective: 99,
+					SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+					Alerting:  slothv1.Alerting{PageAlert: slothv1.Alert{Disable: true}, TicketAlert: slothv1.Alert{Disable: true}},
+				}},
+			},
+		}
+	}
+
+	// Handle an existing and a deleted (without finalizer) object.
+	rec := &forgetMetricsRecorder{Recorder: metrics.Noop}
+	reconcileMetrics := kubecontroller.NewReconcileMetrics(kubecontroller.LocalCluster, rec)
+	h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+		Generator: generatorFunc(func(ctx context.Context, r generate.Request) (*generate.Response, error) {
+			return &generate.Response{}, nil
+		}),
+		Repository: repositoryFunc(func(ctx context.Context, kmeta k8sprometheus.K8sMeta, slos []k8sprometheus.StorageSLO) error {
+			return fmt.Errorf("something")
+		}),
+		KubeStatusStorer: statusStorerFunc(func(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result k8sprometheus.HandleResult) error {
+			return nil
+		}),
+		MetricsRecorder:  rec,
+		ReconcileMetrics: reconcileMetrics,
+	})
+	require.NoError(err)
+	_ = h.Handle(context.TODO(), newPSL("ns1", "test1"))
+	_ = h.Handle(context.TODO(), newPSL("ns1", "test2"))
+
+	ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothfake.NewSimpleClientset(newPSL("ns1", "test1")), nil, log.Noop)
+	collector, err := kubecontroller.NewInventoryCollector(kubecontroller.InventoryCollectorConfig{
+		Repository:       ksvc,
+		ReconcileMetrics: reconcileMetrics,
+		MetricsRecorder:  rec,
+	})
+	require.NoError(err)
+
+	// The not listed objects should be forgotten once.
+	require.NoError(collector.Collect(context.TODO()))
+	require.NoError(collector.Collect(context.TODO()))
+	assert.Equal([]string{"local/ns1/test2"}, rec.deleted)
+}
@@ -0,0 +1,94 @@
+package kubecontroller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+// NamespaceFilter knows if the controller should handle the objects of a namespace.
+type NamespaceFilter interface {
+	Allows(ns string) bool
+}
+
+type allNamespaces int
+
+func (allNamespaces) Allows(string) bool { return true }
+
+// AllNamespaces is a NamespaceFilter that allows all the namespaces.
+const AllNamespaces = allNamespaces(0)
+
+// NamespaceFilterConfig is the namespace filter configuration.
+type NamespaceFilterConfig struct {
+	// Namespaces are the allowed namespaces, by default all.
+	Namespaces []string
+	// LabelSelector selects the allowed namespaces by their labels, by default all.
+	LabelSelector labels.Selector
+	// NamespaceLister is used to get the namespace labels, required when the label
+	// selector is set.
+	NamespaceLister corev1listers.NamespaceLister
+}
+
+func (c *NamespaceFilterConfig) defaults() error {
+	if c.LabelSelector == nil {
+		c.LabelSelector = labels.Everything()
+	}
+
+	if !c.LabelSelector.Empty() && c.NamespaceLister == nil {
+		return fmt.Errorf("namespace lister is required with a label selector")
+	}
+
+	return nil
+}
+
+type namespaceFilter struct {
+	namespaces map[string]bool
+	selector   labels.Selector
+	lister     corev1listers.NamespaceLister
+}
+
+// NewNamespaceFilter returns a NamespaceFilter that allows the namespaces that are on the
+// namespace list and match the label selector.
+func NewNamespaceFilter(config NamespaceFilterConfig) (NamespaceFilter, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if len(config.Namespaces) == 0 && config.LabelSelector.Empty() {
+		return AllNamespaces, nil
+	}
+
+	var nss map[string]bool
+	if len(config.Namespaces) > 0 {
+		nss = map[string]bool{}
+		for _, ns := range config.Namespaces {
+			nss[ns] = true
+		}
+	}
+
+	return namespaceFilter{
+		namespaces: nss,
+		selector:   config.LabelSelector,
+		lister:     config.NamespaceLister,
+	}, nil
+}
+
+func (n namespaceFilter) Allows(ns string) bool {
+	if n.namespaces != nil && !n.namespaces[ns] {
+		return false
+	}
+
+	if n.selector.Empty() {
+		return true
+	}
+
+	// Missing namespaces are not allowed.
+	namespace, err := n.lister.Get(ns)
+	if err != nil {
+		return false
+	}
+
+	return n.selector.Matches(labels.Set(namespace.Labels))
+}
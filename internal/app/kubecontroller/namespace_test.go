@@ -0,0 +1,85 @@
+package kubecontroller_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+)
+
+func TestNamespaceFilter(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-a", Labels: map[string]string{"team": "a"}}})
+	_ = indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-b", Labels: map[string]string{"team": "b"}}})
+	_ = indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-c", Labels: map[string]string{"team": "a"}}})
+	lister := corev1listers.NewNamespaceLister(indexer)
+
+	tests := map[string]struct {
+		config     kubecontroller.NamespaceFilterConfig
+		expErr     bool
+		expAllowed map[string]bool
+	}{
+		"Without namespaces nor selector should allow all the namespaces.": {
+			config:     kubecontroller.NamespaceFilterConfig{},
+			expAllowed: map[string]bool{"ns-a": true, "ns-b": true, "ns-c": true, "ns-missing": true},
+		},
+
+		"A selector without lister should fail.": {
+			config: kubecontroller.NamespaceFilterConfig{
+				LabelSelector: labels.SelectorFromSet(labels.Set{"team": "a"}),
+			},
+			expErr: true,
+		},
+
+		"A namespace list should allow only the listed namespaces.": {
+			config: kubecontroller.NamespaceFilterConfig{
+				Namespaces: []string{"ns-a", "ns-b"},
+			},
+			expAllowed: map[string]bool{"ns-a": true, "ns-b": true, "ns-c": false, "ns-missing": false},
+		},
+
+		"A label selector should allow only the matching namespaces.": {
+			config: kubecontroller.NamespaceFilterConfig{
+				LabelSelector:   labels.SelectorFromSet(labels.Set{"team": "a"}),
+				NamespaceLister: lister,
+			},
+			expAllowed: map[string]bool{"ns-a": true, "ns-b": false, "ns-c": true, "ns-missing": false},
+		},
+
+		"A namespace list and a label selector should allow only the listed and matching namespaces.": {
+			config: kubecontroller.NamespaceFilterConfig{
+				Namespaces:      []string{"ns-a", "ns-b"},
+				LabelSelector:   labels.SelectorFromSet(labels.Set{"team": "a"}),
+				NamespaceLister: lister,
+			},
+			expAllowed: map[string]bool{"ns-a": true, "ns-b": false, "ns-c": false, "ns-missing": false},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			f, err := kubecontroller.NewNamespaceFilter(test.config)
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			gotAllowed := map[string]bool{}
+			for ns := range test.expAllowed {
+				gotAllowed[ns] = f.Allows(ns)
+			}
+			assert.Equal(test.expAllowed, gotAllowed)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// OrphanRulesKubernetesRepository is the service to manage k8s resources by the orphan rules collector.
+type OrphanRulesKubernetesRepository interface {
+	GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error)
+	ListPrometheusRules(ctx context.Context, ns string, labelSelector map[string]string) (*monitoringv1.PrometheusRuleList, error)
+	DeletePrometheusRule(ctx context.Context, ns, name string) error
+}
+
+// OrphanRulesCollectorConfig is the orphan rules collector configuration.
+type OrphanRulesCollectorConfig struct {
+	Repository OrphanRulesKubernetesRepository
+	// Namespace is the namespace where the collector will check the generated rules, by default all.
+	Namespace string
+	// NamespaceFilter filters the namespaces where the collector will check the generated rules, by default all.
+	NamespaceFilter NamespaceFilter
+	// Shard is the controller shard, only the generated rules of the shard will be checked.
+	Shard Shard
+	// Interval is the interval between the generated rules checks.
+	Interval time.Duration
+	Logger   log.Logger
+}
+
+func (c *OrphanRulesCollectorConfig) defaults() error {
+	if c.Repository == nil {
+		return fmt.Errorf("repository is required")
+	}
+
+	if c.NamespaceFilter == nil {
+		c.NamespaceFilter = AllNamespaces
+	}
+
+	err := c.Shard.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid shard: %w", err)
+	}
+
+	if c.Interval == 0 {
+		c.Interval = time.Hour
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.OrphanRulesCollector"})
+
+	return nil
+}
+
+// OrphanRulesCollector knows how to delete the generated Prometheus operator rules whose PrometheusServiceLevel
+// doesn't exist anymore but Kubernetes didn't garbage collect them, e.g the owner reference was lost or the
+// rules were restored from a backup. The retained rules are not checked, these are deleted by the
+// PendingDeletionCollector after their grace period.
+type OrphanRulesCollector struct {
+	repository OrphanRulesKubernetesRepository
+	namespace  string
+	nsFilter   NamespaceFilter
+	shard      Shard
+	interval   time.Duration
+	logger     log.Logger
+}
+
+// NewOrphanRulesCollector returns a new OrphanRulesCollector.
+func NewOrphanRulesCollector(config OrphanRulesCollectorConfig) (*OrphanRulesCollector, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &OrphanRulesCollector{
+		repository: config.Repository,
+		namespace:  config.Namespace,
+		nsFilter:   config.NamespaceFilter,
+		shard:      config.Shard,
+		interval:   config.Interval,
+		logger:     config.Logger,
+	}, nil
+}
+
+// Run will collect the orphan rules in intervals until the context is cancelled.
+func (o *OrphanRulesCollector) Run(ctx context.Context) error {
+	t := time.NewTicker(o.interval)
+	defer t.Stop()
+
+	for {
+		err := o.Collect(ctx)
+		if err != nil {
+			o.logger.Errorf("Could not collect orphan Prometheus rules: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Collect will check once all the generated rules, deleting the ones without PrometheusServiceLevel.
+func (o *OrphanRulesCollector) Collect(ctx context.Context) error {
+	rules, err := o.repository.ListPrometheusRules(ctx, o.namespace, map[string]string{
+		"app.kubernetes.io/managed-by":    "sloth",
+		k8sprometheus.SourceKindLabelName: "PrometheusServiceLevel",
+	})
+	if err != nil {
+		return fmt.Errorf("could not list generated Prometheus rules: %w", err)
+	}
+
+	for _, rule := range rules.Items {
+		if rule.Labels[k8sprometheus.RetainLabelName] == "true" {
+			continue
+		}
+
+		ns, name := sourcePrometheusServiceLevel(rule)
+		if !o.nsFilter.Allows(ns) || !o.shard.Owns(ns, name) {
+			continue
+		}
+
+		_, err := o.repository.GetPrometheusServiceLevel(ctx, ns, name)
+		if err == nil {
+			continue
+		}
+		if !kubeerrors.IsNotFound(err) {
+			return fmt.Errorf("could not get %s/%s PrometheusServiceLevel: %w", ns, name, err)
+		}
+
+		err = o.repository.DeletePrometheusRule(ctx, rule.Namespace, rule.Name)
+		if err != nil {
+			return fmt.Errorf("could not delete %s/%s Prometheus rule: %w", rule.Namespace, rule.Name, err)
+		}
+		o.logger.WithValues(log.Kv{"ns": rule.Namespace, "name": rule.Name}).Warningf("PrometheusServiceLevel missing, orphan Prometheus rule deleted")
+	}
+
+	return nil
+}
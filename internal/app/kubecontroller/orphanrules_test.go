@@ -0,0 +1,121 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringfake "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+func TestOrphanRulesCollectorCollect(t *testing.T) {
+	generatedLabels := map[string]string{
+		"app.kubernetes.io/managed-by":    "sloth",
+		k8sprometheus.SourceKindLabelName: "PrometheusServiceLevel",
+	}
+	newRule := func(name string, labels, annotations map[string]string) *monitoringv1.PrometheusRule {
+		return &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "test-ns",
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+
+	tests := map[string]struct {
+		slos     []runtime.Object
+		rules    []runtime.Object
+		expRules []string
+	}{
+		"Generated rules with PrometheusServiceLevel should be kept.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}},
+			},
+			rules: []runtime.Object{
+				newRule("test", generatedLabels, nil),
+			},
+			expRules: []string{"test"},
+		},
+
+		"Generated rules without PrometheusServiceLevel should be deleted.": {
+			rules: []runtime.Object{
+				newRule("test", generatedLabels, nil),
+			},
+			expRules: []string{},
+		},
+
+		"Generated rule parts with PrometheusServiceLevel should be kept.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}},
+			},
+			rules: []runtime.Object{
+				newRule("test-part-1", generatedLabels, map[string]string{
+					k8sprometheus.SourceNamespaceAnnotationName: "test-ns",
+					k8sprometheus.SourceNameAnnotationName:      "test",
+					k8sprometheus.PartOfAnnotationName:          "test",
+				}),
+			},
+			expRules: []string{"test-part-1"},
+		},
+
+		"Retained rules without PrometheusServiceLevel should be kept.": {
+			rules: []runtime.Object{
+				newRule("test", map[string]string{
+					"app.kubernetes.io/managed-by":    "sloth",
+					k8sprometheus.SourceKindLabelName: "PrometheusServiceLevel",
+					k8sprometheus.RetainLabelName:     "true",
+				}, nil),
+			},
+			expRules: []string{"test"},
+		},
+
+		"Rules not generated from a PrometheusServiceLevel should be kept.": {
+			rules: []runtime.Object{
+				newRule("test-cli", map[string]string{"app.kubernetes.io/managed-by": "sloth"}, nil),
+				newRule("test-cm", map[string]string{
+					"app.kubernetes.io/managed-by":    "sloth",
+					k8sprometheus.SourceKindLabelName: "ConfigMap",
+				}, nil),
+			},
+			expRules: []string{"test-cli", "test-cm"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			slothCli := slothfake.NewSimpleClientset(test.slos...)
+			monitoringCli := monitoringfake.NewSimpleClientset(test.rules...)
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothCli, monitoringCli, log.Noop)
+
+			collector, err := kubecontroller.NewOrphanRulesCollector(kubecontroller.OrphanRulesCollectorConfig{
+				Repository: ksvc,
+			})
+			require.NoError(err)
+
+			err = collector.Collect(context.TODO())
+			require.NoError(err)
+
+			gotRules, err := monitoringCli.MonitoringV1().PrometheusRules("test-ns").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(err)
+			got := []string{}
+			for _, r := range gotRules.Items {
+				got = append(got, r.Name)
+			}
+			assert.Equal(test.expRules, got)
+		})
+	}
+}
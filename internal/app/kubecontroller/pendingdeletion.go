@@ -0,0 +1,233 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/metrics"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// PendingDeletionKubernetesRepository is the service to manage k8s resources by the pending deletion collector.
+type PendingDeletionKubernetesRepository interface {
+	GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error)
+	ListPrometheusRules(ctx context.Context, ns string, labelSelector map[string]string) (*monitoringv1.PrometheusRuleList, error)
+	EnsurePrometheusRule(ctx context.Context, pr *monitoringv1.PrometheusRule) error
+	DeletePrometheusRule(ctx context.Context, ns, name string) error
+}
+
+// PendingDeletionCollectorConfig is the pending deletion collector configuration.
+type PendingDeletionCollectorConfig struct {
+	Repository PendingDeletionKubernetesRepository
+	// Namespace is the namespace where the collector will check the retained rules, by default all.
+	Namespace string
+	// NamespaceFilter filters the namespaces where the collector will check the retained rules, by default all.
+	NamespaceFilter NamespaceFilter
+	// Shard is the controller shard, only the retained rules of the shard will be checked.
+	Shard Shard
+	// GracePeriod is the time a retained rule without PrometheusServiceLevel will wait before
+	// being deleted.
+	GracePeriod time.Duration
+	// Interval is the interval between the retained rules checks.
+	Interval        time.Duration
+	MetricsRecorder metrics.Recorder
+	// TimeNow is used to get the current time (used for testing purposes).
+	TimeNow func() time.Time
+	Logger  log.Logger
+}
+
+func (c *PendingDeletionCollectorConfig) defaults() error {
+	if c.Repository == nil {
+		return fmt.Errorf("repository is required")
+	}
+
+	if c.NamespaceFilter == nil {
+		c.NamespaceFilter = AllNamespaces
+	}
+
+	err := c.Shard.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid shard: %w", err)
+	}
+
+	if c.GracePeriod == 0 {
+		c.GracePeriod = 24 * time.Hour
+	}
+
+	if c.Interval == 0 {
+		c.Interval = time.Minute
+	}
+
+	if c.MetricsRecorder == nil {
+		c.MetricsRecorder = metrics.Noop
+	}
+
+	if c.TimeNow == nil {
+		c.TimeNow = time.Now
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.PendingDeletionCollector"})
+
+	return nil
+}
+
+// PendingDeletionCollector knows how to delete the retained Prometheus operator rules whose
+// PrometheusServiceLevel has been deleted. Instead of deleting them instantly, these are
+// labeled as pending deletion and deleted after a grace period, so an accidental deletion of
+// the PrometheusServiceLevels doesn't wipe all the SLO alerting instantly.
+type PendingDeletionCollector struct {
+	repository      PendingDeletionKubernetesRepository
+	namespace       string
+	nsFilter        NamespaceFilter
+	shard           Shard
+	gracePeriod     time.Duration
+	interval        time.Duration
+	metricsRecorder metrics.Recorder
+	timeNow         func() time.Time
+	logger          log.Logger
+}
+
+// NewPendingDeletionCollector returns a new PendingDeletionCollector.
+func NewPendingDeletionCollector(config PendingDeletionCollectorConfig) (*PendingDeletionCollector, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &PendingDeletionCollector{
+		repository:      config.Repository,
+		namespace:       config.Namespace,
+		nsFilter:        config.NamespaceFilter,
+		shard:           config.Shard,
+		gracePeriod:     config.GracePeriod,
+		interval:        config.Interval,
+		metricsRecorder: config.MetricsRecorder,
+		timeNow:         config.TimeNow,
+		logger:          config.Logger,
+	}, nil
+}
+
+// Run will collect the retained rules in intervals until the context is cancelled.
+func (p *PendingDeletionCollector) Run(ctx context.Context) error {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
+	for {
+		err := p.Collect(ctx)
+		if err != nil {
+			p.logger.Errorf("Could not collect retained Prometheus rules: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Collect will check once all the retained rules, marking as pending deletion the ones without
+// PrometheusServiceLevel and deleting the ones that have been pending more than the grace period.
+func (p *PendingDeletionCollector) Collect(ctx context.Context) error {
+	rules, err := p.repository.ListPrometheusRules(ctx, p.namespace, map[string]string{
+		k8sprometheus.RetainLabelName: "true",
+	})
+	if err != nil {
+		return fmt.Errorf("could not list retained Prometheus rules: %w", err)
+	}
+
+	pending := 0
+	for _, rule := range rules.Items {
+		ns, name := sourcePrometheusServiceLevel(rule)
+		if !p.nsFilter.Allows(ns) || !p.shard.Owns(ns, name) {
+			continue
+		}
+
+		ctx := p.logger.SetValuesOnCtx(ctx, log.Kv{"ns": rule.Namespace, "name": rule.Name})
+		isPending, err := p.collectRule(ctx, rule)
+		if err != nil {
+			return fmt.Errorf("could not collect %s/%s Prometheus rule: %w", rule.Namespace, rule.Name, err)
+		}
+		if isPending {
+			pending++
+		}
+	}
+
+	p.metricsRecorder.SetPrometheusRulesPendingDeletion(ctx, pending)
+
+	return nil
+}
+
+func (p *PendingDeletionCollector) collectRule(ctx context.Context, rule *monitoringv1.PrometheusRule) (pending bool, err error) {
+	logger := p.logger.WithCtxValues(ctx)
+
+	ns, name := sourcePrometheusServiceLevel(rule)
+	_, err = p.repository.GetPrometheusServiceLevel(ctx, ns, name)
+	if err == nil {
+		// The controller will overwrite the rule (and its labels) when handling the
+		// PrometheusServiceLevel, so we don't need to unmark it.
+		return false, nil
+	}
+	if !kubeerrors.IsNotFound(err) {
+		return false, fmt.Errorf("could not get PrometheusServiceLevel: %w", err)
+	}
+
+	// Mark as pending deletion if it wasn't already.
+	since, err := time.Parse(time.RFC3339, rule.Annotations[k8sprometheus.PendingDeletionSinceAnnotationName])
+	if rule.Labels[k8sprometheus.PendingDeletionLabelName] != "true" || err != nil {
+		rule = rule.DeepCopy()
+		if rule.Labels == nil {
+			rule.Labels = map[string]string{}
+		}
+		if rule.Annotations == nil {
+			rule.Annotations = map[string]string{}
+		}
+		rule.Labels[k8sprometheus.PendingDeletionLabelName] = "true"
+		rule.Annotations[k8sprometheus.PendingDeletionSinceAnnotationName] = p.timeNow().UTC().Format(time.RFC3339)
+
+		err := p.repository.EnsurePrometheusRule(ctx, rule)
+		if err != nil {
+			return false, fmt.Errorf("could not mark Prometheus rule as pending deletion: %w", err)
+		}
+		logger.Warningf("PrometheusServiceLevel missing, Prometheus rule marked as pending deletion, will be deleted in %s", p.gracePeriod)
+
+		return true, nil
+	}
+
+	// Still in grace period.
+	if p.timeNow().Sub(since) < p.gracePeriod {
+		return true, nil
+	}
+
+	err = p.repository.DeletePrometheusRule(ctx, rule.Namespace, rule.Name)
+	if err != nil {
+		return false, fmt.Errorf("could not delete Prometheus rule: %w", err)
+	}
+	logger.Infof("Prometheus rule pending deletion grace period expired, deleted")
+
+	return false, nil
+}
+
+// sourcePrometheusServiceLevel returns the namespace and name of the PrometheusServiceLevel that generated
+// the rule. The generated rules have the same name and namespace as the PrometheusServiceLevel, except the
+// ones stored on a different namespace, that have the source annotations.
+func sourcePrometheusServiceLevel(rule *monitoringv1.PrometheusRule) (ns, name string) {
+	ns, name = rule.Namespace, rule.Name
+	if v := rule.Annotations[k8sprometheus.SourceNamespaceAnnotationName]; v != "" {
+		ns = v
+	}
+	if v := rule.Annotations[k8sprometheus.SourceNameAnnotationName]; v != "" {
+		name = v
+	}
+
+	return ns, name
+}
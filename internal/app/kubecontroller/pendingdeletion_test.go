@@ -0,0 +1,166 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringfake "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+func TestPendingDeletionCollectorCollect(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	newRule := func(name string, labels, annotations map[string]string) *monitoringv1.PrometheusRule {
+		return &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "test-ns",
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+
+	tests := map[string]struct {
+		slos     []runtime.Object
+		rules    []runtime.Object
+		expRules map[string]*monitoringv1.PrometheusRule
+	}{
+		"Retained rules with PrometheusServiceLevel should be ignored.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}},
+			},
+			rules: []runtime.Object{
+				newRule("test", map[string]string{k8sprometheus.RetainLabelName: "true"}, nil),
+			},
+			expRules: map[string]*monitoringv1.PrometheusRule{
+				"test": newRule("test", map[string]string{k8sprometheus.RetainLabelName: "true"}, nil),
+			},
+		},
+
+		"Not retained rules without PrometheusServiceLevel should be ignored.": {
+			rules: []runtime.Object{
+				newRule("test", map[string]string{"app.kubernetes.io/managed-by": "sloth"}, nil),
+			},
+			expRules: map[string]*monitoringv1.PrometheusRule{
+				"test": newRule("test", map[string]string{"app.kubernetes.io/managed-by": "sloth"}, nil),
+			},
+		},
+
+		"Retained rules without PrometheusServiceLevel should be marked as pending deletion.": {
+			rules: []runtime.Object{
+				newRule("test", map[string]string{k8sprometheus.RetainLabelName: "true"}, nil),
+			},
+			expRules: map[string]*monitoringv1.PrometheusRule{
+				"test": newRule("test",
+					map[string]string{k8sprometheus.RetainLabelName: "true", k8sprometheus.PendingDeletionLabelName: "true"},
+					map[string]string{k8sprometheus.PendingDeletionSinceAnnotationName: "2021-06-01T12:00:00Z"},
+				),
+			},
+		},
+
+		"Retained rules pending deletion in grace period should be kept.": {
+			rules: []runtime.Object{
+				newRule("test",
+					map[string]string{k8sprometheus.RetainLabelName: "true", k8sprometheus.PendingDeletionLabelName: "true"},
+					map[string]string{k8sprometheus.PendingDeletionSinceAnnotationName: "2021-06-01T11:00:00Z"},
+				),
+			},
+			expRules: map[string]*monitoringv1.PrometheusRule{
+				"test": newRule("test",
+					map[string]string{k8sprometheus.RetainLabelName: "true", k8sprometheus.PendingDeletionLabelName: "true"},
+					map[string]string{k8sprometheus.PendingDeletionSinceAnnotationName: "2021-06-01T11:00:00Z"},
+				),
+			},
+		},
+
+		"Retained rules pending deletion after the grace period should be deleted.": {
+			rules: []runtime.Object{
+				newRule("test",
+					map[string]string{k8sprometheus.RetainLabelName: "true", k8sprometheus.PendingDeletionLabelName: "true"},
+					map[string]string{k8sprometheus.PendingDeletionSinceAnnotationName: "2021-05-31T11:00:00Z"},
+				),
+			},
+			expRules: map[string]*monitoringv1.PrometheusRule{},
+		},
+
+		"Retained rules from other namespace with PrometheusServiceLevel should be ignored.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "other-ns"}},
+			},
+			rules: []runtime.Object{
+				newRule("other-ns-test",
+					map[string]string{k8sprometheus.RetainLabelName: "true"},
+					map[string]string{k8sprometheus.SourceNamespaceAnnotationName: "other-ns", k8sprometheus.SourceNameAnnotationName: "test"},
+				),
+			},
+			expRules: map[string]*monitoringv1.PrometheusRule{
+				"other-ns-test": newRule("other-ns-test",
+					map[string]string{k8sprometheus.RetainLabelName: "true"},
+					map[string]string{k8sprometheus.SourceNamespaceAnnotationName: "other-ns", k8sprometheus.SourceNameAnnotationName: "test"},
+				),
+			},
+		},
+
+		"Retained rules from other namespace without PrometheusServiceLevel should be marked as pending deletion.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "other-ns-test", Namespace: "test-ns"}},
+			},
+			rules: []runtime.Object{
+				newRule("other-ns-test",
+					map[string]string{k8sprometheus.RetainLabelName: "true"},
+					map[string]string{k8sprometheus.SourceNamespaceAnnotationName: "other-ns", k8sprometheus.SourceNameAnnotationName: "test"},
+				),
+			},
+			expRules: map[string]*monitoringv1.PrometheusRule{
+				"other-ns-test": newRule("other-ns-test",
+					map[string]string{k8sprometheus.RetainLabelName: "true", k8sprometheus.PendingDeletionLabelName: "true"},
+					map[string]string{
+						k8sprometheus.SourceNamespaceAnnotationName:      "other-ns",
+						k8sprometheus.SourceNameAnnotationName:           "test",
+						k8sprometheus.PendingDeletionSinceAnnotationName: "2021-06-01T12:00:00Z",
+					},
+				),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			slothCli := slothfake.NewSimpleClientset(test.slos...)
+			monitoringCli := monitoringfake.NewSimpleClientset(test.rules...)
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothCli, monitoringCli, log.Noop)
+
+			collector, err := kubecontroller.NewPendingDeletionCollector(kubecontroller.PendingDeletionCollectorConfig{
+				Repository:  ksvc,
+				GracePeriod: 24 * time.Hour,
+				TimeNow:     func() time.Time { return now },
+			})
+			require.NoError(err)
+
+			err = collector.Collect(context.TODO())
+			require.NoError(err)
+
+			gotRules, err := monitoringCli.MonitoringV1().PrometheusRules("test-ns").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(err)
+			got := map[string]*monitoringv1.PrometheusRule{}
+			for _, r := range gotRules.Items {
+				got[r.Name] = r
+			}
+			assert.Equal(test.expRules, got)
+		})
+	}
+}
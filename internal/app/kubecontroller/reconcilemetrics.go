@@ -0,0 +1,94 @@
+package kubecontroller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/slok/sloth/internal/metrics"
+)
+
+// LocalCluster is the name of the cluster where the controller runs.
+const LocalCluster = "local"
+
+// ReconcileMetrics records the reconcile metrics of the objects of a cluster, tracking their consecutive
+// failures. The deleted objects are forgotten by the handler when it sees them being deleted, and by the
+// inventory collector when these are not listed anymore (e.g deleted without finalizer), so the tracked
+// objects and their metrics don't grow forever.
+type ReconcileMetrics struct {
+	cluster         string
+	metricsRecorder metrics.Recorder
+	mu              sync.Mutex
+	failures        map[string]int
+	tracked         map[string]objectKey
+}
+
+type objectKey struct {
+	namespace string
+	name      string
+}
+
+func (o objectKey) String() string { return o.namespace + "/" + o.name }
+
+// NewReconcileMetrics returns a new ReconcileMetrics for the objects of the cluster.
+func NewReconcileMetrics(cluster string, metricsRecorder metrics.Recorder) *ReconcileMetrics {
+	if metricsRecorder == nil {
+		metricsRecorder = metrics.Noop
+	}
+
+	return &ReconcileMetrics{
+		cluster:         cluster,
+		metricsRecorder: metricsRecorder,
+		failures:        map[string]int{},
+		tracked:         map[string]objectKey{},
+	}
+}
+
+// record records the object reconcile result and returns its consecutive failures.
+func (r *ReconcileMetrics) record(ctx context.Context, ns, name, result string, duration time.Duration) int {
+	key := objectKey{namespace: ns, name: name}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tracked[key.String()] = key
+	failures := 0
+	if result != metrics.ReconcileResultSuccess {
+		failures = r.failures[key.String()] + 1
+		r.failures[key.String()] = failures
+	} else {
+		delete(r.failures, key.String())
+	}
+	r.metricsRecorder.ObservePrometheusServiceLevelReconcile(ctx, r.cluster, ns, name, result, duration)
+	r.metricsRecorder.SetPrometheusServiceLevelConsecutiveFailures(ctx, r.cluster, ns, name, failures)
+
+	return failures
+}
+
+// forget forgets the object consecutive failures and deletes its metrics.
+func (r *ReconcileMetrics) forget(ctx context.Context, ns, name string) {
+	key := objectKey{namespace: ns, name: name}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tracked[key.String()]; !ok {
+		return
+	}
+	delete(r.tracked, key.String())
+	delete(r.failures, key.String())
+	r.metricsRecorder.DeletePrometheusServiceLevelMetrics(ctx, r.cluster, ns, name)
+}
+
+// trackedObjects returns the `<namespace>/<name>` keys of the tracked objects.
+func (r *ReconcileMetrics) trackedObjects() map[string]objectKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tracked := make(map[string]objectKey, len(r.tracked))
+	for k, v := range r.tracked {
+		tracked[k] = v
+	}
+
+	return tracked
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/spotahome/kooper/v2/controller"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
@@ -18,14 +20,95 @@ type RetrieverKubernetesRepository interface {
 	WatchPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (watch.Interface, error)
 }
 
-// NewPrometheusServiceLevelsRetriver returns the retriever for Prometheus service levels events.
-func NewPrometheusServiceLevelsRetriver(ns string, repo RetrieverKubernetesRepository) controller.Retriever {
+// ConfigMapRetrieverKubernetesRepository is the service to manage k8s ConfigMaps by the Kubernetes
+// controller retrievers, the label selector is filtered by the API server.
+type ConfigMapRetrieverKubernetesRepository interface {
+	ListConfigMaps(ctx context.Context, ns string, labelSelector string) (*corev1.ConfigMapList, error)
+	WatchConfigMaps(ctx context.Context, ns string, labelSelector string) (watch.Interface, error)
+}
+
+// NewPrometheusServiceLevelsRetriver returns the retriever for Prometheus service levels events, only
+// the Prometheus service levels of the allowed namespaces, matching the label selector and owned by
+// the controller shard will be retrieved.
+func NewPrometheusServiceLevelsRetriver(ns string, nsFilter NamespaceFilter, selector labels.Selector, shard Shard, repo RetrieverKubernetesRepository) controller.Retriever {
+	handles := func(psl *slothv1.PrometheusServiceLevel) bool {
+		return nsFilter.Allows(psl.Namespace) &&
+			selector.Matches(labels.Set(psl.Labels)) &&
+			shard.Owns(psl.Namespace, psl.Name)
+	}
+
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			l, err := repo.ListPrometheusServiceLevels(context.TODO(), ns, map[string]string{})
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]slothv1.PrometheusServiceLevel, 0, len(l.Items))
+			for _, psl := range l.Items {
+				if handles(&psl) {
+					items = append(items, psl)
+				}
+			}
+			l.Items = items
+
+			return l, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			w, err := repo.WatchPrometheusServiceLevels(context.TODO(), ns, map[string]string{})
+			if err != nil {
+				return nil, err
+			}
+
+			return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+				psl, ok := in.Object.(*slothv1.PrometheusServiceLevel)
+				if !ok {
+					return in, true
+				}
+				return in, handles(psl)
+			}), nil
+		},
+	})
+}
+
+// NewConfigMapSpecsRetriever returns the retriever for the events of the ConfigMaps that have raw Sloth
+// specs, the ConfigMaps are selected by the label selector, only the ones of the allowed namespaces and
+// owned by the controller shard will be retrieved.
+func NewConfigMapSpecsRetriever(ns string, nsFilter NamespaceFilter, selector labels.Selector, shard Shard, repo ConfigMapRetrieverKubernetesRepository) controller.Retriever {
+	handles := func(cm *corev1.ConfigMap) bool {
+		return nsFilter.Allows(cm.Namespace) && shard.Owns(cm.Namespace, cm.Name)
+	}
+
 	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			return repo.ListPrometheusServiceLevels(context.TODO(), ns, map[string]string{})
+			l, err := repo.ListConfigMaps(context.TODO(), ns, selector.String())
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]corev1.ConfigMap, 0, len(l.Items))
+			for _, cm := range l.Items {
+				if handles(&cm) {
+					items = append(items, cm)
+				}
+			}
+			l.Items = items
+
+			return l, nil
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			return repo.WatchPrometheusServiceLevels(context.TODO(), ns, map[string]string{})
+			w, err := repo.WatchConfigMaps(context.TODO(), ns, selector.String())
+			if err != nil {
+				return nil, err
+			}
+
+			return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+				cm, ok := in.Object.(*corev1.ConfigMap)
+				if !ok {
+					return in, true
+				}
+				return in, handles(cm)
+			}), nil
 		},
 	})
 }
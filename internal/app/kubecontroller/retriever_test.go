@@ -0,0 +1,154 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	monitoringfake "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+type namespaceFilterFunc func(ns string) bool
+
+func (n namespaceFilterFunc) Allows(ns string) bool { return n(ns) }
+
+func TestPrometheusServiceLevelsRetrieverList(t *testing.T) {
+	newPSL := func(ns, name string, labels map[string]string) *slothv1.PrometheusServiceLevel {
+		return &slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels}}
+	}
+	psls := []runtime.Object{
+		newPSL("ns-a", "test-1", map[string]string{"instance": "a"}),
+		newPSL("ns-a", "test-2", map[string]string{"instance": "b"}),
+		newPSL("ns-b", "test-3", map[string]string{"instance": "a"}),
+		newPSL("ns-b", "test-4", nil),
+	}
+
+	tests := map[string]struct {
+		ns       string
+		nsFilter kubecontroller.NamespaceFilter
+		selector labels.Selector
+		expNames []string
+	}{
+		"Without filters should retrieve all.": {
+			nsFilter: kubecontroller.AllNamespaces,
+			selector: labels.Everything(),
+			expNames: []string{"test-1", "test-2", "test-3", "test-4"},
+		},
+
+		"A namespace should retrieve only the namespace ones.": {
+			ns:       "ns-b",
+			nsFilter: kubecontroller.AllNamespaces,
+			selector: labels.Everything(),
+			expNames: []string{"test-3", "test-4"},
+		},
+
+		"A namespace filter should retrieve only the allowed namespaces ones.": {
+			nsFilter: namespaceFilterFunc(func(ns string) bool { return ns == "ns-a" }),
+			selector: labels.Everything(),
+			expNames: []string{"test-1", "test-2"},
+		},
+
+		"A label selector should retrieve only the matching ones.": {
+			nsFilter: kubecontroller.AllNamespaces,
+			selector: labels.SelectorFromSet(labels.Set{"instance": "a"}),
+			expNames: []string{"test-1", "test-3"},
+		},
+
+		"A namespace filter and label selector should retrieve only the allowed and matching ones.": {
+			nsFilter: namespaceFilterFunc(func(ns string) bool { return ns == "ns-b" }),
+			selector: labels.SelectorFromSet(labels.Set{"instance": "a"}),
+			expNames: []string{"test-3"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothfake.NewSimpleClientset(psls...), monitoringfake.NewSimpleClientset(), log.Noop)
+			ret := kubecontroller.NewPrometheusServiceLevelsRetriver(test.ns, test.nsFilter, test.selector, kubecontroller.Shard{}, ksvc)
+
+			obj, err := ret.List(context.TODO(), metav1.ListOptions{})
+			require.NoError(err)
+
+			gotNames := []string{}
+			for _, psl := range obj.(*slothv1.PrometheusServiceLevelList).Items {
+				gotNames = append(gotNames, psl.Name)
+			}
+			sort.Strings(gotNames)
+			assert.Equal(test.expNames, gotNames)
+		})
+	}
+}
+
+func TestConfigMapSpecsRetrieverList(t *testing.T) {
+	newCM := func(ns, name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels}}
+	}
+	cms := []runtime.Object{
+		newCM("ns-a", "test-1", map[string]string{"sloth-spec": "true"}),
+		newCM("ns-a", "test-2", nil),
+		newCM("ns-b", "test-3", map[string]string{"sloth-spec": "true"}),
+		newCM("ns-b", "test-4", map[string]string{"sloth-spec": "false"}),
+	}
+
+	tests := map[string]struct {
+		ns       string
+		nsFilter kubecontroller.NamespaceFilter
+		selector labels.Selector
+		expNames []string
+	}{
+		"A label selector should retrieve only the matching ones.": {
+			nsFilter: kubecontroller.AllNamespaces,
+			selector: labels.SelectorFromSet(labels.Set{"sloth-spec": "true"}),
+			expNames: []string{"test-1", "test-3"},
+		},
+
+		"A namespace should retrieve only the namespace matching ones.": {
+			ns:       "ns-b",
+			nsFilter: kubecontroller.AllNamespaces,
+			selector: labels.SelectorFromSet(labels.Set{"sloth-spec": "true"}),
+			expNames: []string{"test-3"},
+		},
+
+		"A namespace filter should retrieve only the allowed namespaces matching ones.": {
+			nsFilter: namespaceFilterFunc(func(ns string) bool { return ns == "ns-a" }),
+			selector: labels.SelectorFromSet(labels.Set{"sloth-spec": "true"}),
+			expNames: []string{"test-1"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(cms...), nil, slothfake.NewSimpleClientset(), monitoringfake.NewSimpleClientset(), log.Noop)
+			ret := kubecontroller.NewConfigMapSpecsRetriever(test.ns, test.nsFilter, test.selector, kubecontroller.Shard{}, ksvc)
+
+			obj, err := ret.List(context.TODO(), metav1.ListOptions{})
+			require.NoError(err)
+
+			gotNames := []string{}
+			for _, cm := range obj.(*corev1.ConfigMapList).Items {
+				gotNames = append(gotNames, cm.Name)
+			}
+			sort.Strings(gotNames)
+			assert.Equal(test.expNames, gotNames)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// RuleFilesKubernetesRepository is the service to get the k8s objects that generated the rule files.
+type RuleFilesKubernetesRepository interface {
+	GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error)
+	GetConfigMap(ctx context.Context, ns, name string) (*corev1.ConfigMap, error)
+}
+
+// RuleFilesRepository is the repository of the stored rule files.
+type RuleFilesRepository interface {
+	ListRuleFiles(ctx context.Context) ([]k8sprometheus.RuleFile, error)
+	DeleteRuleFile(ctx context.Context, ns, name string) error
+}
+
+// RuleFilesCollectorConfig is the rule files collector configuration.
+type RuleFilesCollectorConfig struct {
+	KubernetesRepository RuleFilesKubernetesRepository
+	RuleFilesRepository  RuleFilesRepository
+	// ConfigMapSource will check the rule files against the SLO spec ConfigMaps instead of the PrometheusServiceLevels.
+	ConfigMapSource bool
+	// Namespace is the namespace of the rule files objects the collector will check, by default all.
+	Namespace string
+	// NamespaceFilter filters the namespaces of the rule files objects the collector will check, by default all.
+	NamespaceFilter NamespaceFilter
+	// Shard is the controller shard, only the rule files of the shard will be checked.
+	Shard Shard
+	// Interval is the interval between the rule files checks.
+	Interval time.Duration
+	Logger   log.Logger
+}
+
+func (c *RuleFilesCollectorConfig) defaults() error {
+	if c.KubernetesRepository == nil {
+		return fmt.Errorf("kubernetes repository is required")
+	}
+
+	if c.RuleFilesRepository == nil {
+		return fmt.Errorf("rule files repository is required")
+	}
+
+	if c.NamespaceFilter == nil {
+		c.NamespaceFilter = AllNamespaces
+	}
+
+	err := c.Shard.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid shard: %w", err)
+	}
+
+	if c.Interval == 0 {
+		c.Interval = time.Minute
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.RuleFilesCollector"})
+
+	return nil
+}
+
+// RuleFilesCollector knows how to delete the rule files whose SLO spec object doesn't exist anymore. Unlike
+// the Kubernetes objects, the rule files are not garbage collected by Kubernetes using owner references.
+type RuleFilesCollector struct {
+	kubeRepo        RuleFilesKubernetesRepository
+	fileRepo        RuleFilesRepository
+	configMapSource bool
+	namespace       string
+	nsFilter        NamespaceFilter
+	shard           Shard
+	interval        time.Duration
+	logger          log.Logger
+}
+
+// NewRuleFilesCollector returns a new RuleFilesCollector.
+func NewRuleFilesCollector(config RuleFilesCollectorConfig) (*RuleFilesCollector, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &RuleFilesCollector{
+		kubeRepo:        config.KubernetesRepository,
+		fileRepo:        config.RuleFilesRepository,
+		configMapSource: config.ConfigMapSource,
+		namespace:       config.Namespace,
+		nsFilter:        config.NamespaceFilter,
+		shard:           config.Shard,
+		interval:        config.Interval,
+		logger:          config.Logger,
+	}, nil
+}
+
+// Run will collect the rule files in intervals until the context is cancelled.
+func (r *RuleFilesCollector) Run(ctx context.Context) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		err := r.Collect(ctx)
+		if err != nil {
+			r.logger.Errorf("Could not collect rule files: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Collect will check once all the rule files, deleting the ones without SLO spec object.
+func (r *RuleFilesCollector) Collect(ctx context.Context) error {
+	files, err := r.fileRepo.ListRuleFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list rule files: %w", err)
+	}
+
+	for _, f := range files {
+		if r.namespace != "" && f.Namespace != r.namespace {
+			continue
+		}
+		if !r.nsFilter.Allows(f.Namespace) || !r.shard.Owns(f.Namespace, f.Name) {
+			continue
+		}
+
+		kind := "PrometheusServiceLevel"
+		if r.configMapSource {
+			kind = "ConfigMap"
+			_, err = r.kubeRepo.GetConfigMap(ctx, f.Namespace, f.Name)
+		} else {
+			_, err = r.kubeRepo.GetPrometheusServiceLevel(ctx, f.Namespace, f.Name)
+		}
+		if err == nil {
+			continue
+		}
+		if !kubeerrors.IsNotFound(err) {
+			return fmt.Errorf("could not get %s/%s %s: %w", f.Namespace, f.Name, kind, err)
+		}
+
+		err = r.fileRepo.DeleteRuleFile(ctx, f.Namespace, f.Name)
+		if err != nil {
+			return fmt.Errorf("could not delete %s/%s rule file: %w", f.Namespace, f.Name, err)
+		}
+		r.logger.WithValues(log.Kv{"ns": f.Namespace, "name": f.Name}).Infof("%s missing, rule file deleted", kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+func TestRuleFilesCollectorCollect(t *testing.T) {
+	tests := map[string]struct {
+		configMapSource bool
+		slos            []runtime.Object
+		cms             []runtime.Object
+		files           []string
+		expFiles        []k8sprometheus.RuleFile
+	}{
+		"Rule files with PrometheusServiceLevel should be kept and the ones without deleted.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}},
+			},
+			cms: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test2", Namespace: "test-ns"}},
+			},
+			files: []string{"test-ns_test.yml", "test-ns_test2.yml"},
+			expFiles: []k8sprometheus.RuleFile{
+				{Namespace: "test-ns", Name: "test"},
+			},
+		},
+
+		"Rule files with ConfigMap should be kept and the ones without deleted on ConfigMap source.": {
+			configMapSource: true,
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}},
+			},
+			cms: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test2", Namespace: "test-ns"}},
+			},
+			files: []string{"test-ns_test.yml", "test-ns_test2.yml"},
+			expFiles: []k8sprometheus.RuleFile{
+				{Namespace: "test-ns", Name: "test2"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dir := t.TempDir()
+			for _, f := range test.files {
+				require.NoError(os.WriteFile(filepath.Join(dir, f), []byte("groups: []\n"), 0644))
+			}
+			fileRepo := k8sprometheus.NewRuleFileRepo(dir, log.Noop)
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(test.cms...), nil, slothfake.NewSimpleClientset(test.slos...), nil, log.Noop)
+
+			collector, err := kubecontroller.NewRuleFilesCollector(kubecontroller.RuleFilesCollectorConfig{
+				KubernetesRepository: ksvc,
+				RuleFilesRepository:  fileRepo,
+				ConfigMapSource:      test.configMapSource,
+			})
+			require.NoError(err)
+
+			err = collector.Collect(context.TODO())
+			require.NoError(err)
+
+			gotFiles, err := fileRepo.ListRuleFiles(context.TODO())
+			require.NoError(err)
+			assert.Equal(test.expFiles, gotFiles)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/spotahome/kooper/v2/controller"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// RulesDriftKubernetesRepository is the service to manage k8s resources by the rules drift repairer.
+type RulesDriftKubernetesRepository interface {
+	GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error)
+	ListPrometheusRules(ctx context.Context, ns string, labelSelector map[string]string) (*monitoringv1.PrometheusRuleList, error)
+	WatchPrometheusRules(ctx context.Context, ns string, labelSelector map[string]string) (watch.Interface, error)
+}
+
+// RulesDriftRepairerConfig is the rules drift repairer configuration.
+type RulesDriftRepairerConfig struct {
+	Repository RulesDriftKubernetesRepository
+	// Handler is the controller handler the PrometheusServiceLevels of the drifted rules are handled with.
+	Handler controller.Handler
+	// Namespace is the namespace where the repairer will watch the generated rules (e.g the rules namespace), by default all.
+	Namespace string
+	// NamespaceFilter filters the namespaces of the PrometheusServiceLevels whose rules are repaired, by default all.
+	NamespaceFilter NamespaceFilter
+	// Selector is the label selector of the PrometheusServiceLevels whose rules are repaired, by default all.
+	Selector labels.Selector
+	// Shard is the controller shard, only the rules of the shard PrometheusServiceLevels will be repaired.
+	Shard  Shard
+	Logger log.Logger
+}
+
+func (c *RulesDriftRepairerConfig) defaults() error {
+	if c.Repository == nil {
+		return fmt.Errorf("repository is required")
+	}
+
+	if c.Handler == nil {
+		return fmt.Errorf("handler is required")
+	}
+
+	if c.NamespaceFilter == nil {
+		c.NamespaceFilter = AllNamespaces
+	}
+
+	if c.Selector == nil {
+		c.Selector = labels.Everything()
+	}
+
+	err := c.Shard.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid shard: %w", err)
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.RulesDriftRepairer"})
+
+	return nil
+}
+
+// RulesDriftRepairer knows how to repair the generated Prometheus operator rules as soon as these are edited
+// or deleted (e.g manual hotfixes), handling again their PrometheusServiceLevel, instead of waiting for the
+// controller resync. The rules pending deletion are not repaired.
+type RulesDriftRepairer struct {
+	repository RulesDriftKubernetesRepository
+	handler    controller.Handler
+	namespace  string
+	nsFilter   NamespaceFilter
+	selector   labels.Selector
+	shard      Shard
+	logger     log.Logger
+}
+
+// NewRulesDriftRepairer returns a new RulesDriftRepairer.
+func NewRulesDriftRepairer(config RulesDriftRepairerConfig) (*RulesDriftRepairer, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &RulesDriftRepairer{
+		repository: config.Repository,
+		handler:    config.Handler,
+		namespace:  config.Namespace,
+		nsFilter:   config.NamespaceFilter,
+		selector:   config.Selector,
+		shard:      config.Shard,
+		logger:     config.Logger,
+	}, nil
+}
+
+// Run will watch the generated rules and repair the drifted ones until the context is cancelled.
+func (r *RulesDriftRepairer) Run(ctx context.Context) error {
+	generatedLabels := map[string]string{
+		"app.kubernetes.io/managed-by":    "sloth",
+		k8sprometheus.SourceKindLabelName: "PrometheusServiceLevel",
+	}
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return r.repository.ListPrometheusRules(ctx, r.namespace, generatedLabels)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return r.repository.WatchPrometheusRules(ctx, r.namespace, generatedLabels)
+		},
+	}, &monitoringv1.PrometheusRule{}, 0, cache.Indexers{})
+
+	// The PrometheusServiceLevels are queued, so the multiple rule parts events are deduplicated.
+	queue := workqueue.New()
+	defer queue.ShutDown()
+
+	enqueue := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		rule, ok := obj.(*monitoringv1.PrometheusRule)
+		if !ok || rule.Labels[k8sprometheus.PendingDeletionLabelName] == "true" {
+			return
+		}
+		ns, name := sourcePrometheusServiceLevel(rule)
+		if !r.nsFilter.Allows(ns) || !r.shard.Owns(ns, name) {
+			return
+		}
+		queue.Add(ns + "/" + name)
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldRule, ok1 := oldObj.(*monitoringv1.PrometheusRule)
+			newRule, ok2 := newObj.(*monitoringv1.PrometheusRule)
+			if ok1 && ok2 && oldRule.ResourceVersion == newRule.ResourceVersion {
+				return
+			}
+			enqueue(newObj)
+		},
+		DeleteFunc: enqueue,
+	})
+
+	go informer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return nil
+	}
+
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+
+	for {
+		key, shutdown := queue.Get()
+		if shutdown {
+			return nil
+		}
+
+		err := r.Repair(ctx, key.(string))
+		if err != nil {
+			r.logger.Errorf("Could not repair %s PrometheusServiceLevel rules: %s", key, err)
+		}
+		queue.Done(key)
+	}
+}
+
+// Repair will handle again the PrometheusServiceLevel of drifted rules, the key is in `<namespace>/<name>` format.
+func (r *RulesDriftRepairer) Repair(ctx context.Context, key string) error {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	psl, err := r.repository.GetPrometheusServiceLevel(ctx, ns, name)
+	if err != nil {
+		// Deleted PrometheusServiceLevel, the rules are deleted with it.
+		if kubeerrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("could not get PrometheusServiceLevel: %w", err)
+	}
+
+	if !r.selector.Matches(labels.Set(psl.Labels)) {
+		return nil
+	}
+
+	err = r.handler.Handle(WithForceHandle(ctx), psl)
+	if err != nil {
+		return err
+	}
+	r.logger.WithValues(log.Kv{"ns": ns, "name": name}).Infof("Drifted Prometheus rules repaired")
+
+	return nil
+}
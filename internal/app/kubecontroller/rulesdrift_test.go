@@ -0,0 +1,114 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringfake "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
+	"github.com/spotahome/kooper/v2/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+func TestRulesDriftRepairerRepair(t *testing.T) {
+	tests := map[string]struct {
+		slos       []runtime.Object
+		selector   labels.Selector
+		expHandled bool
+	}{
+		"A missing PrometheusServiceLevel should not be handled.": {
+			expHandled: false,
+		},
+
+		"A PrometheusServiceLevel not matching the selector should not be handled.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}},
+			},
+			selector:   labels.SelectorFromSet(labels.Set{"team": "a"}),
+			expHandled: false,
+		},
+
+		"A PrometheusServiceLevel should be handled forcing the handling.": {
+			slos: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}},
+			},
+			expHandled: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothfake.NewSimpleClientset(test.slos...), monitoringfake.NewSimpleClientset(), log.Noop)
+			handled := false
+			repairer, err := kubecontroller.NewRulesDriftRepairer(kubecontroller.RulesDriftRepairerConfig{
+				Repository: ksvc,
+				Handler: controller.HandlerFunc(func(ctx context.Context, obj runtime.Object) error {
+					handled = true
+					return nil
+				}),
+				Selector: test.selector,
+			})
+			require.NoError(err)
+
+			err = repairer.Repair(context.TODO(), "test-ns/test")
+			require.NoError(err)
+			assert.Equal(test.expHandled, handled)
+		})
+	}
+}
+
+func TestRulesDriftRepairerRun(t *testing.T) {
+	require := require.New(t)
+
+	generatedLabels := map[string]string{
+		"app.kubernetes.io/managed-by":    "sloth",
+		k8sprometheus.SourceKindLabelName: "PrometheusServiceLevel",
+	}
+	slothCli := slothfake.NewSimpleClientset(&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}})
+	monitoringCli := monitoringfake.NewSimpleClientset(&monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Labels: generatedLabels}})
+	ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothCli, monitoringCli, log.Noop)
+
+	handled := make(chan string, 10)
+	repairer, err := kubecontroller.NewRulesDriftRepairer(kubecontroller.RulesDriftRepairerConfig{
+		Repository: ksvc,
+		Handler: controller.HandlerFunc(func(ctx context.Context, obj runtime.Object) error {
+			psl := obj.(*slothv1.PrometheusServiceLevel)
+			handled <- psl.Namespace + "/" + psl.Name
+			return nil
+		}),
+	})
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = repairer.Run(ctx) }()
+
+	// Wait until the watch is running so the deletion is received.
+	time.Sleep(100 * time.Millisecond)
+
+	// Deleting a generated rule should handle its PrometheusServiceLevel.
+	err = monitoringCli.MonitoringV1().PrometheusRules("test-ns").Delete(ctx, "test", metav1.DeleteOptions{})
+	require.NoError(err)
+
+	select {
+	case got := <-handled:
+		require.Equal("test-ns/test", got)
+	case <-time.After(5 * time.Second):
+		require.Fail("the PrometheusServiceLevel was not handled")
+	}
+}
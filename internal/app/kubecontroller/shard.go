@@ -0,0 +1,40 @@
+package kubecontroller
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Shard is a controller shard, the objects are spread between the shards using the hash of
+// their namespace and name, so each controller replica only handles its part of the objects.
+type Shard struct {
+	// Index is the shard of the controller, in the [0, Total) range.
+	Index int
+	// Total is the number of shards, 0 or 1 disable the sharding.
+	Total int
+}
+
+// Validate validates the shard.
+func (s Shard) Validate() error {
+	if s.Total < 0 {
+		return fmt.Errorf("total shards can't be negative")
+	}
+
+	if s.Total > 1 && (s.Index < 0 || s.Index >= s.Total) {
+		return fmt.Errorf("shard index must be in the [0, %d) range", s.Total)
+	}
+
+	return nil
+}
+
+// Owns returns true if the object with the namespace and name belongs to the shard.
+func (s Shard) Owns(ns, name string) bool {
+	if s.Total <= 1 {
+		return true
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(ns + "/" + name))
+
+	return int(h.Sum32()%uint32(s.Total)) == s.Index
+}
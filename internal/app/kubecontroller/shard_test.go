@@ -0,0 +1,77 @@
+package kubecontroller_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+)
+
+func TestShardValidate(t *testing.T) {
+	tests := map[string]struct {
+		shard  kubecontroller.Shard
+		expErr bool
+	}{
+		"Disabled sharding should be valid.": {
+			shard: kubecontroller.Shard{},
+		},
+
+		"An index in the range should be valid.": {
+			shard: kubecontroller.Shard{Index: 2, Total: 3},
+		},
+
+		"An index out of the range should fail.": {
+			shard:  kubecontroller.Shard{Index: 3, Total: 3},
+			expErr: true,
+		},
+
+		"A negative index should fail.": {
+			shard:  kubecontroller.Shard{Index: -1, Total: 3},
+			expErr: true,
+		},
+
+		"A negative total should fail.": {
+			shard:  kubecontroller.Shard{Total: -1},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.shard.Validate()
+			if test.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShardOwns(t *testing.T) {
+	assert := assert.New(t)
+
+	// Disabled sharding owns everything.
+	assert.True(kubecontroller.Shard{}.Owns("test-ns", "test"))
+	assert.True(kubecontroller.Shard{Total: 1}.Owns("test-ns", "test"))
+
+	// Every object should be owned by only one shard.
+	const total = 4
+	owned := map[int]int{}
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("test-%d", i)
+		owners := 0
+		for idx := 0; idx < total; idx++ {
+			if (kubecontroller.Shard{Index: idx, Total: total}).Owns("test-ns", name) {
+				owners++
+				owned[idx]++
+			}
+		}
+		assert.Equal(1, owners, name)
+	}
+
+	// All the shards should own objects.
+	assert.Len(owned, total)
+}
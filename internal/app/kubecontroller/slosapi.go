@@ -0,0 +1,243 @@
+package kubecontroller
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// SLOsAPIKubernetesRepository is the service to manage k8s resources by the SLOs API.
+type SLOsAPIKubernetesRepository interface {
+	ListPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (*slothv1.PrometheusServiceLevelList, error)
+}
+
+// SLOsAPIHandlerConfig is the SLOs API HTTP handler configuration.
+type SLOsAPIHandlerConfig struct {
+	Repository SLOsAPIKubernetesRepository
+	// SpecLoader loads the PrometheusServiceLevels into the SLO models.
+	SpecLoader SpecLoader
+	// Token is the bearer token the requests need to be authenticated with.
+	Token string
+	// Namespace is the namespace of the listed PrometheusServiceLevels, by default all.
+	Namespace string
+	// NamespaceFilter filters the namespaces of the listed PrometheusServiceLevels, by default all.
+	NamespaceFilter NamespaceFilter
+	// Selector is the label selector of the listed PrometheusServiceLevels, by default all.
+	Selector labels.Selector
+	Logger   log.Logger
+}
+
+func (c *SLOsAPIHandlerConfig) defaults() error {
+	if c.Repository == nil {
+		return fmt.Errorf("repository is required")
+	}
+
+	if c.SpecLoader == nil {
+		c.SpecLoader = k8sprometheus.CRSpecLoader
+	}
+
+	if c.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+
+	if c.NamespaceFilter == nil {
+		c.NamespaceFilter = AllNamespaces
+	}
+
+	if c.Selector == nil {
+		c.Selector = labels.Everything()
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.SLOsAPIHandler"})
+
+	return nil
+}
+
+type slosAPIHandler struct {
+	repository SLOsAPIKubernetesRepository
+	specLoader SpecLoader
+	token      string
+	namespace  string
+	nsFilter   NamespaceFilter
+	selector   labels.Selector
+	logger     log.Logger
+}
+
+// NewSLOsAPIHandler returns an HTTP handler that responds with a JSON of all the SLOs managed by the
+// controller, with their PrometheusServiceLevel, generated rules and last handling status, e.g for
+// developer portals and audits. The sharding is ignored, so all the controller replicas respond the same.
+func NewSLOsAPIHandler(config SLOsAPIHandlerConfig) (http.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return slosAPIHandler{
+		repository: config.Repository,
+		specLoader: config.SpecLoader,
+		token:      config.Token,
+		namespace:  config.Namespace,
+		nsFilter:   config.NamespaceFilter,
+		selector:   config.Selector,
+		logger:     config.Logger,
+	}, nil
+}
+
+type slosAPIResponseJSON struct {
+	SLOs []sloAPIJSON `json:"slos"`
+}
+
+type sloAPIJSON struct {
+	ID                     string            `json:"id"`
+	Name                   string            `json:"name"`
+	Service                string            `json:"service"`
+	Namespace              string            `json:"namespace"`
+	PrometheusServiceLevel string            `json:"prometheusServiceLevel"`
+	Objective              float64           `json:"objective"`
+	TimeWindow             string            `json:"timeWindow,omitempty"`
+	Labels                 map[string]string `json:"labels,omitempty"`
+	RuleGroups             []string          `json:"ruleGroups,omitempty"`
+	Alerts                 []string          `json:"alerts,omitempty"`
+	Status                 sloAPIStatusJSON  `json:"status"`
+}
+
+type sloAPIStatusJSON struct {
+	RulesGenerated          bool       `json:"rulesGenerated"`
+	UpToDate                bool       `json:"upToDate"`
+	LastSuccessfulReconcile *time.Time `json:"lastSuccessfulReconcile,omitempty"`
+	LastError               string     `json:"lastError,omitempty"`
+}
+
+func (h slosAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	auth := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(auth, "Bearer ")
+	if token == auth || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	slos, err := h.listSLOs(r.Context())
+	if err != nil {
+		h.logger.Errorf("Could not list SLOs: %s", err)
+		http.Error(w, "could not list SLOs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(slosAPIResponseJSON{SLOs: slos})
+}
+
+func (h slosAPIHandler) listSLOs(ctx context.Context) ([]sloAPIJSON, error) {
+	l, err := h.repository.ListPrometheusServiceLevels(ctx, h.namespace, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list PrometheusServiceLevels: %w", err)
+	}
+
+	slos := []sloAPIJSON{}
+	for _, psl := range l.Items {
+		psl := psl
+		if !h.nsFilter.Allows(psl.Namespace) || !h.selector.Matches(labels.Set(psl.Labels)) {
+			continue
+		}
+
+		status := sloAPIStatusJSON{
+			RulesGenerated: psl.Status.PromOpRulesGenerated,
+			UpToDate:       psl.Generation == psl.Status.ObservedGeneration,
+			LastError:      psl.Status.LastError,
+		}
+		if psl.Status.LastPromOpRulesSuccessfulGenerated != nil {
+			t := psl.Status.LastPromOpRulesSuccessfulGenerated.Time
+			status.LastSuccessfulReconcile = &t
+		}
+
+		// The invalid specs can't be loaded, list these with the raw spec data.
+		model, err := h.specLoader.LoadSpec(ctx, &psl)
+		if err != nil {
+			for _, slo := range psl.Spec.SLOs {
+				slos = append(slos, sloAPIJSON{
+					ID:                     fmt.Sprintf("%s-%s", psl.Spec.Service, slo.Name),
+					Name:                   slo.Name,
+					Service:                psl.Spec.Service,
+					Namespace:              psl.Namespace,
+					PrometheusServiceLevel: psl.Name,
+					Objective:              slo.Objective,
+					Status:                 status,
+				})
+			}
+			continue
+		}
+
+		for _, slo := range model.SLOs {
+			slos = append(slos, sloAPIJSON{
+				ID:                     slo.ID,
+				Name:                   slo.Name,
+				Service:                slo.Service,
+				Namespace:              psl.Namespace,
+				PrometheusServiceLevel: psl.Name,
+				Objective:              slo.Objective,
+				TimeWindow:             slo.TimeWindow.String(),
+				Labels:                 slo.Labels,
+				RuleGroups:             sloRuleGroups(slo),
+				Alerts:                 sloAlerts(slo),
+				Status:                 status,
+			})
+		}
+	}
+
+	sort.SliceStable(slos, func(i, j int) bool {
+		if slos[i].Namespace != slos[j].Namespace {
+			return slos[i].Namespace < slos[j].Namespace
+		}
+		return slos[i].PrometheusServiceLevel < slos[j].PrometheusServiceLevel
+	})
+
+	return slos, nil
+}
+
+func sloRuleGroups(slo prometheus.SLO) []string {
+	groups := []string{}
+	for _, c := range []prometheus.RuleGroupCategory{
+		prometheus.SLIRecordingsRuleGroupCategory,
+		prometheus.MetadataRecordingsRuleGroupCategory,
+		prometheus.AlertsRuleGroupCategory,
+	} {
+		name, err := slo.GetRuleGroupName(c)
+		if err != nil {
+			continue
+		}
+		groups = append(groups, name)
+	}
+
+	return groups
+}
+
+func sloAlerts(slo prometheus.SLO) []string {
+	alerts := []string{}
+	for _, a := range []prometheus.AlertMeta{slo.PageAlertMeta, slo.WarningAlertMeta} {
+		if !a.Disable && a.Name != "" {
+			alerts = append(alerts, a.Name)
+		}
+	}
+
+	return alerts
+}
@@ -0,0 +1,115 @@
+package kubecontroller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+func TestSLOsAPIHandler(t *testing.T) {
+	lastSuccess := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	slos := []runtime.Object{
+		&slothv1.PrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Generation: 2},
+			Spec: slothv1.PrometheusServiceLevelSpec{
+				Service: "test-svc",
+				SLOs: []slothv1.SLO{{
+					Name:      "slo1",
+					Objective: 99.9,
+					SLI:       slothv1.SLI{Raw: &slothv1.SLIRaw{ErrorRatioQuery: "test_expr"}},
+					Alerting: slothv1.Alerting{
+						Name:        "TestAlert",
+						PageAlert:   slothv1.Alert{Labels: map[string]string{"severity": "page"}},
+						TicketAlert: slothv1.Alert{Disable: true},
+					},
+				}},
+			},
+			Status: slothv1.PrometheusServiceLevelStatus{
+				PromOpRulesGenerated:               true,
+				ObservedGeneration:                 2,
+				LastPromOpRulesSuccessfulGenerated: &metav1.Time{Time: lastSuccess},
+			},
+		},
+		&slothv1.PrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-invalid", Namespace: "test-ns", Generation: 1},
+			Spec: slothv1.PrometheusServiceLevelSpec{
+				Service: "test-svc",
+				SLOs:    []slothv1.SLO{{Name: "slo2", Objective: 100}},
+			},
+			Status: slothv1.PrometheusServiceLevelStatus{LastError: "something"},
+		},
+	}
+
+	tests := map[string]struct {
+		method  string
+		token   string
+		expCode int
+		expBody string
+	}{
+		"A request without token should be unauthorized.": {
+			method:  http.MethodGet,
+			expCode: http.StatusUnauthorized,
+		},
+
+		"A request with an invalid token should be unauthorized.": {
+			method:  http.MethodGet,
+			token:   "other",
+			expCode: http.StatusUnauthorized,
+		},
+
+		"A non GET request should not be allowed.": {
+			method:  http.MethodPost,
+			token:   "secret",
+			expCode: http.StatusMethodNotAllowed,
+		},
+
+		"An authenticated request should list the SLOs.": {
+			method:  http.MethodGet,
+			token:   "secret",
+			expCode: http.StatusOK,
+			expBody: `{"slos":[` +
+				`{"id":"test-svc-slo1","name":"slo1","service":"test-svc","namespace":"test-ns","prometheusServiceLevel":"test","objective":99.9,"timeWindow":"720h0m0s","ruleGroups":["sloth-slo-sli-recordings-test-svc-slo1","sloth-slo-meta-recordings-test-svc-slo1","sloth-slo-alerts-test-svc-slo1"],"alerts":["TestAlert"],"status":{"rulesGenerated":true,"upToDate":true,"lastSuccessfulReconcile":"2021-06-01T10:00:00Z"}},` +
+				`{"id":"test-svc-slo2","name":"slo2","service":"test-svc","namespace":"test-ns","prometheusServiceLevel":"test-invalid","objective":100,"status":{"rulesGenerated":false,"upToDate":false,"lastError":"something"}}` +
+				`]}` + "\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothfake.NewSimpleClientset(slos...), nil, log.Noop)
+			h, err := kubecontroller.NewSLOsAPIHandler(kubecontroller.SLOsAPIHandlerConfig{
+				Repository: ksvc,
+				Token:      "secret",
+			})
+			require.NoError(err)
+
+			req := httptest.NewRequest(test.method, "/api/v1/slos", nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			assert.Equal(test.expCode, w.Code)
+			if test.expBody != "" {
+				assert.Equal(test.expBody, w.Body.String())
+			}
+		})
+	}
+}
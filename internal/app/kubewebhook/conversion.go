@@ -0,0 +1,155 @@
+package kubewebhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothv2 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v2"
+)
+
+// conversionReview is the `apiextensions.k8s.io/v1` ConversionReview, we only need
+// these fields so we don't depend on the apiextensions library.
+type conversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *conversionRequest  `json:"request,omitempty"`
+	Response        *conversionResponse `json:"response,omitempty"`
+}
+
+type conversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type conversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// ConversionHandlerConfig is the conversion webhook handler configuration.
+type ConversionHandlerConfig struct {
+	Logger log.Logger
+}
+
+func (c *ConversionHandlerConfig) defaults() error {
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubewebhook.ConversionHandler"})
+
+	return nil
+}
+
+type conversionHandler struct {
+	logger log.Logger
+}
+
+// NewConversionHandler returns the HTTP handler of the PrometheusServiceLevel CRD conversion
+// webhook, it converts the objects between the `sloth.slok.dev/v1` and `sloth.slok.dev/v2` versions.
+func NewConversionHandler(config ConversionHandlerConfig) (http.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return conversionHandler{logger: config.Logger}, nil
+}
+
+func (c conversionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	review := conversionReview{}
+	err = json.Unmarshal(body, &review)
+	if err != nil || review.Request == nil {
+		http.Error(w, "invalid conversion review", http.StatusBadRequest)
+		return
+	}
+
+	resp := &conversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, obj := range review.Request.Objects {
+		converted, err := convertObject(obj.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			c.logger.Errorf("Could not convert object: %s", err)
+			resp.ConvertedObjects = nil
+			resp.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	review.Request = nil
+	review.Response = resp
+	data, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not marshal conversion review: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func convertObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	tm := metav1.TypeMeta{}
+	err := json.Unmarshal(raw, &tm)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode object type: %w", err)
+	}
+
+	v1APIVersion := slothv1.SchemeGroupVersion.String()
+	v2APIVersion := slothv2.SchemeGroupVersion.String()
+
+	var converted interface{}
+	switch {
+	case tm.APIVersion == desiredAPIVersion:
+		return raw, nil
+
+	case tm.APIVersion == v1APIVersion && desiredAPIVersion == v2APIVersion:
+		psl := &slothv1.PrometheusServiceLevel{}
+		err := json.Unmarshal(raw, psl)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %s object: %w", tm.APIVersion, err)
+		}
+		converted, err = slothv2.ConvertFromV1(psl)
+		if err != nil {
+			return nil, err
+		}
+
+	case tm.APIVersion == v2APIVersion && desiredAPIVersion == v1APIVersion:
+		psl := &slothv2.PrometheusServiceLevel{}
+		err := json.Unmarshal(raw, psl)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %s object: %w", tm.APIVersion, err)
+		}
+		converted, err = slothv2.ConvertToV1(psl)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported conversion from %q to %q", tm.APIVersion, desiredAPIVersion)
+	}
+
+	return json.Marshal(converted)
+}
@@ -0,0 +1,107 @@
+package kubewebhook_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/app/kubewebhook"
+)
+
+func TestConversionHandler(t *testing.T) {
+	tests := map[string]struct {
+		desiredAPIVersion string
+		object            string
+		expCode           int
+		expStatus         string
+		expObject         string
+	}{
+		"An invalid conversion review should fail.": {
+			expCode: http.StatusBadRequest,
+		},
+
+		"Converting to the same version should not change the object.": {
+			desiredAPIVersion: "sloth.slok.dev/v1",
+			object:            `{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel","metadata":{"name":"test"},"spec":{"service":"svc"}}`,
+			expCode:           http.StatusOK,
+			expStatus:         "Success",
+			expObject:         `{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel","metadata":{"name":"test"},"spec":{"service":"svc"}}`,
+		},
+
+		"Converting an unknown version should fail.": {
+			desiredAPIVersion: "sloth.slok.dev/v3",
+			object:            `{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel","metadata":{"name":"test"},"spec":{"service":"svc"}}`,
+			expCode:           http.StatusOK,
+			expStatus:         "Failure",
+		},
+
+		"Converting a v1 object to v2 should convert the object.": {
+			desiredAPIVersion: "sloth.slok.dev/v2",
+			object:            `{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel","metadata":{"name":"test"},"spec":{"service":"svc","slos":[{"name":"slo1","objective":99,"sli":{"raw":{"errorRatioQuery":"q"}},"alerting":{"name":"a"}}]}}`,
+			expCode:           http.StatusOK,
+			expStatus:         "Success",
+			expObject:         `{"kind":"PrometheusServiceLevel","apiVersion":"sloth.slok.dev/v2","metadata":{"name":"test","creationTimestamp":null},"spec":{"service":"svc","slos":[{"name":"slo1","objective":99,"sli":{"raw":{"errorRatioQuery":"q"}},"alerting":{"name":"a","pageAlert":{},"ticketAlert":{}}}]},"status":{"promOpRulesGeneratedSLOs":0,"processedSLOs":0,"promOpRulesGenerated":false,"observedGeneration":0}}`,
+		},
+
+		"Converting a v2 object with v2 SLIs to v1 should store the v2 SLIs on the annotation.": {
+			desiredAPIVersion: "sloth.slok.dev/v1",
+			object:            `{"apiVersion":"sloth.slok.dev/v2","kind":"PrometheusServiceLevel","metadata":{"name":"test"},"spec":{"service":"svc","slos":[{"name":"slo1","objective":99,"sli":{"weighted":{"slos":[{"id":"a","weight":1},{"id":"b","weight":2}]}},"alerting":{"name":"a"}}]}}`,
+			expCode:           http.StatusOK,
+			expStatus:         "Success",
+			expObject:         `{"kind":"PrometheusServiceLevel","apiVersion":"sloth.slok.dev/v1","metadata":{"name":"test","creationTimestamp":null,"annotations":{"sloth.slok.dev/v2-slis":"{\"slo1\":{\"weighted\":{\"slos\":[{\"id\":\"a\",\"weight\":1},{\"id\":\"b\",\"weight\":2}]}}}"}},"spec":{"service":"svc","slos":[{"name":"slo1","objective":99,"sli":{},"alerting":{"name":"a","pageAlert":{},"ticketAlert":{}}}]},"status":{"promOpRulesGeneratedSLOs":0,"processedSLOs":0,"promOpRulesGenerated":false,"observedGeneration":0}}`,
+		},
+
+		"Converting a v1 object with v2 SLIs on the annotation to v2 should restore the v2 SLIs.": {
+			desiredAPIVersion: "sloth.slok.dev/v2",
+			object:            `{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel","metadata":{"name":"test","annotations":{"sloth.slok.dev/v2-slis":"{\"slo1\":{\"weighted\":{\"slos\":[{\"id\":\"a\",\"weight\":1},{\"id\":\"b\",\"weight\":2}]}}}"}},"spec":{"service":"svc","slos":[{"name":"slo1","objective":99,"sli":{},"alerting":{"name":"a"}}]}}`,
+			expCode:           http.StatusOK,
+			expStatus:         "Success",
+			expObject:         `{"kind":"PrometheusServiceLevel","apiVersion":"sloth.slok.dev/v2","metadata":{"name":"test","creationTimestamp":null},"spec":{"service":"svc","slos":[{"name":"slo1","objective":99,"sli":{"weighted":{"slos":[{"id":"a","weight":1},{"id":"b","weight":2}]}},"alerting":{"name":"a","pageAlert":{},"ticketAlert":{}}}]},"status":{"promOpRulesGeneratedSLOs":0,"processedSLOs":0,"promOpRulesGenerated":false,"observedGeneration":0}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			body := `{}`
+			if test.object != "" {
+				body = `{"apiVersion":"apiextensions.k8s.io/v1","kind":"ConversionReview","request":{"uid":"test-uid","desiredAPIVersion":"` +
+					test.desiredAPIVersion + `","objects":[` + test.object + `]}}`
+			}
+
+			h, err := kubewebhook.NewConversionHandler(kubewebhook.ConversionHandlerConfig{})
+			require.NoError(err)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+			require.Equal(test.expCode, w.Code)
+			if test.expCode != http.StatusOK {
+				return
+			}
+
+			review := struct {
+				Response struct {
+					UID              string            `json:"uid"`
+					ConvertedObjects []json.RawMessage `json:"convertedObjects"`
+					Result           struct {
+						Status string `json:"status"`
+					} `json:"result"`
+				} `json:"response"`
+			}{}
+			require.NoError(json.Unmarshal(w.Body.Bytes(), &review))
+			assert.Equal("test-uid", review.Response.UID)
+			assert.Equal(test.expStatus, review.Response.Result.Status)
+			if test.expObject != "" && assert.Len(review.Response.ConvertedObjects, 1) {
+				assert.Equal(test.expObject, string(review.Response.ConvertedObjects[0]))
+			}
+		})
+	}
+}
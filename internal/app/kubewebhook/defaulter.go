@@ -0,0 +1,104 @@
+package kubewebhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// DefaulterConfig is the PrometheusServiceLevel defaulter configuration, these are the
+// organization defaults set on the objects that don't set them.
+type DefaulterConfig struct {
+	// TimeWindow is the SLO period set on the SLOs without time window (e.g 28d).
+	TimeWindow string
+	// Labels are the Prometheus labels merged on the spec labels, the spec ones take precedence.
+	Labels map[string]string
+	// AlertAnnotations are the annotations merged on the SLO alerting annotations, the SLO
+	// ones take precedence.
+	AlertAnnotations map[string]string
+	Logger           log.Logger
+}
+
+func (c *DefaulterConfig) defaults() error {
+	if c.TimeWindow != "" {
+		_, _, err := prometheus.ParseTimeWindow(c.TimeWindow)
+		if err != nil {
+			return fmt.Errorf("invalid time window: %w", err)
+		}
+	}
+
+	if c.Labels == nil {
+		c.Labels = map[string]string{}
+	}
+
+	if c.AlertAnnotations == nil {
+		c.AlertAnnotations = map[string]string{}
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubewebhook.Defaulter"})
+
+	return nil
+}
+
+// Defaulter knows how to set the organization defaults on PrometheusServiceLevels.
+type Defaulter struct {
+	timeWindow       string
+	labels           map[string]string
+	alertAnnotations map[string]string
+	logger           log.Logger
+}
+
+// NewDefaulter returns a new PrometheusServiceLevel defaulter.
+func NewDefaulter(config DefaulterConfig) (*Defaulter, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &Defaulter{
+		timeWindow:       config.TimeWindow,
+		labels:           config.Labels,
+		alertAnnotations: config.AlertAnnotations,
+		logger:           config.Logger,
+	}, nil
+}
+
+// Default sets the defaults on the PrometheusServiceLevel, the values already set are not changed.
+func (d Defaulter) Default(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error {
+	if len(d.labels) > 0 {
+		psl.Spec.Labels = mergeDefaults(d.labels, psl.Spec.Labels)
+	}
+
+	for i := range psl.Spec.SLOs {
+		slo := &psl.Spec.SLOs[i]
+
+		if slo.TimeWindow == "" {
+			slo.TimeWindow = d.timeWindow
+		}
+
+		if len(d.alertAnnotations) > 0 {
+			slo.Alerting.Annotations = mergeDefaults(d.alertAnnotations, slo.Alerting.Annotations)
+		}
+	}
+
+	return nil
+}
+
+// mergeDefaults returns the values merged on the defaults, the values take precedence.
+func mergeDefaults(defaults, values map[string]string) map[string]string {
+	res := make(map[string]string, len(defaults)+len(values))
+	for k, v := range defaults {
+		res[k] = v
+	}
+	for k, v := range values {
+		res[k] = v
+	}
+
+	return res
+}
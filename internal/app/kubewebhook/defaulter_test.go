@@ -0,0 +1,83 @@
+package kubewebhook_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/app/kubewebhook"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+func TestDefaulterDefault(t *testing.T) {
+	tests := map[string]struct {
+		config    kubewebhook.DefaulterConfig
+		psl       slothv1.PrometheusServiceLevel
+		expPSL    slothv1.PrometheusServiceLevel
+		expNewErr bool
+	}{
+		"An invalid time window should fail.": {
+			config:    kubewebhook.DefaulterConfig{TimeWindow: "1x"},
+			expNewErr: true,
+		},
+
+		"Without defaults the object should not change.": {
+			config: kubewebhook.DefaulterConfig{},
+			psl: slothv1.PrometheusServiceLevel{Spec: slothv1.PrometheusServiceLevelSpec{
+				SLOs: []slothv1.SLO{{Name: "slo1"}},
+			}},
+			expPSL: slothv1.PrometheusServiceLevel{Spec: slothv1.PrometheusServiceLevelSpec{
+				SLOs: []slothv1.SLO{{Name: "slo1"}},
+			}},
+		},
+
+		"The defaults should be set without changing the object values.": {
+			config: kubewebhook.DefaulterConfig{
+				TimeWindow:       "28d",
+				Labels:           map[string]string{"owner": "platform", "tier": "2"},
+				AlertAnnotations: map[string]string{"dashboard": "https://grafana.test"},
+			},
+			psl: slothv1.PrometheusServiceLevel{Spec: slothv1.PrometheusServiceLevelSpec{
+				Labels: map[string]string{"tier": "1"},
+				SLOs: []slothv1.SLO{
+					{Name: "slo1"},
+					{Name: "slo2", TimeWindow: "7d", Alerting: slothv1.Alerting{
+						Annotations: map[string]string{"dashboard": "https://grafana.test/slo2"},
+					}},
+				},
+			}},
+			expPSL: slothv1.PrometheusServiceLevel{Spec: slothv1.PrometheusServiceLevelSpec{
+				Labels: map[string]string{"owner": "platform", "tier": "1"},
+				SLOs: []slothv1.SLO{
+					{Name: "slo1", TimeWindow: "28d", Alerting: slothv1.Alerting{
+						Annotations: map[string]string{"dashboard": "https://grafana.test"},
+					}},
+					{Name: "slo2", TimeWindow: "7d", Alerting: slothv1.Alerting{
+						Annotations: map[string]string{"dashboard": "https://grafana.test/slo2"},
+					}},
+				},
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			defaulter, err := kubewebhook.NewDefaulter(test.config)
+			if test.expNewErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			err = defaulter.Default(context.TODO(), &test.psl)
+			if assert.NoError(err) {
+				assert.Equal(test.expPSL, test.psl)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package kubewebhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// Mutator knows how to mutate a PrometheusServiceLevel.
+type Mutator interface {
+	Default(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error
+}
+
+// MutatingHandlerConfig is the mutating webhook handler configuration.
+type MutatingHandlerConfig struct {
+	Mutator Mutator
+	Logger  log.Logger
+}
+
+func (c *MutatingHandlerConfig) defaults() error {
+	if c.Mutator == nil {
+		return fmt.Errorf("mutator is required")
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubewebhook.MutatingHandler"})
+
+	return nil
+}
+
+type mutatingHandler struct {
+	mutator Mutator
+	logger  log.Logger
+}
+
+// NewMutatingHandler returns the HTTP handler of a Kubernetes mutating admission webhook
+// (`admission.k8s.io/v1` AdmissionReview) that mutates the PrometheusServiceLevel objects,
+// the mutations are returned as a JSON patch of the object spec.
+func NewMutatingHandler(config MutatingHandlerConfig) (http.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return mutatingHandler{
+		mutator: config.Mutator,
+		logger:  config.Logger,
+	}, nil
+}
+
+func (m mutatingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	err = json.Unmarshal(body, &review)
+	if err != nil || review.Request == nil {
+		http.Error(w, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	ctx := m.logger.SetValuesOnCtx(r.Context(), log.Kv{"ns": review.Request.Namespace, "name": review.Request.Name})
+	resp, err := m.mutate(ctx, review.Request)
+	if err != nil {
+		m.logger.WithCtxValues(ctx).Errorf("Could not mutate object: %s", err)
+		resp = &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &metav1.Status{Status: metav1.StatusFailure, Message: err.Error()},
+		}
+	}
+	resp.UID = review.Request.UID
+
+	review.Request = nil
+	review.Response = resp
+	data, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not marshal admission review: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+func (m mutatingHandler) mutate(ctx context.Context, req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	psl := &slothv1.PrometheusServiceLevel{}
+	err := json.Unmarshal(req.Object.Raw, psl)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode PrometheusServiceLevel: %w", err)
+	}
+
+	mutated := psl.DeepCopy()
+	err = m.mutator.Default(ctx, mutated)
+	if err != nil {
+		return nil, fmt.Errorf("could not set defaults: %w", err)
+	}
+
+	// Nothing to patch.
+	if reflect.DeepEqual(psl.Spec, mutated.Spec) {
+		return &admissionv1.AdmissionResponse{Allowed: true}, nil
+	}
+
+	patch, err := json.Marshal([]jsonPatchOp{{Op: "replace", Path: "/spec", Value: mutated.Spec}})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal patch: %w", err)
+	}
+
+	m.logger.WithCtxValues(ctx).Debugf("PrometheusServiceLevel defaults set")
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}, nil
+}
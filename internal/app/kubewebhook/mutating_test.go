@@ -0,0 +1,74 @@
+package kubewebhook_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"github.com/slok/sloth/internal/app/kubewebhook"
+)
+
+func TestMutatingHandler(t *testing.T) {
+	tests := map[string]struct {
+		body       string
+		expCode    int
+		expAllowed bool
+		expPatch   string
+	}{
+		"An invalid admission review should fail.": {
+			body:    `{}`,
+			expCode: http.StatusBadRequest,
+		},
+
+		"An object without missing defaults should be allowed without patch.": {
+			body: `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"test-uid","object":
+{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel","spec":{"service":"svc","labels":{"owner":"myteam"},"slos":[{"name":"slo1","objective":99,"timeWindow":"7d","sli":{},"alerting":{}}]}}}}`,
+			expCode:    http.StatusOK,
+			expAllowed: true,
+		},
+
+		"An object with missing defaults should be allowed with the defaults patch.": {
+			body: `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"test-uid","object":
+{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel","spec":{"service":"svc","slos":[{"name":"slo1","objective":99,"sli":{},"alerting":{}}]}}}}`,
+			expCode:    http.StatusOK,
+			expAllowed: true,
+			expPatch:   `[{"op":"replace","path":"/spec","value":{"service":"svc","labels":{"owner":"platform"},"slos":[{"name":"slo1","objective":99,"timeWindow":"28d","sli":{},"alerting":{"name":"","pageAlert":{},"ticketAlert":{}}}]}}]`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			defaulter, err := kubewebhook.NewDefaulter(kubewebhook.DefaulterConfig{
+				TimeWindow: "28d",
+				Labels:     map[string]string{"owner": "platform"},
+			})
+			require.NoError(err)
+			h, err := kubewebhook.NewMutatingHandler(kubewebhook.MutatingHandlerConfig{Mutator: defaulter})
+			require.NoError(err)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body)))
+
+			require.Equal(test.expCode, w.Code)
+			if test.expCode != http.StatusOK {
+				return
+			}
+
+			review := admissionv1.AdmissionReview{}
+			require.NoError(json.Unmarshal(w.Body.Bytes(), &review))
+			require.NotNil(review.Response)
+			assert.Equal("test-uid", string(review.Response.UID))
+			assert.Equal(test.expAllowed, review.Response.Allowed)
+			assert.Equal(test.expPatch, string(review.Response.Patch))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+func NewIOWriterSLOFactsRepo(writer io.Writer, entityRef string, logger log.Logger) IOWriterSLOFactsRepo {
+	return IOWriterSLOFactsRepo{
+		writer:    writer,
+		entityRef: entityRef,
+		logger:    logger.WithValues(log.Kv{"svc": "storage.IOWriter", "format": "backstage-facts"}),
+	}
+}
+
+// IOWriterSLOFactsRepo knows to store the SLOs in an IOWriter as a Backstage SLO fact file (JSON),
+// so the developer portal can show the SLOs of a service using the specs as the source of truth.
+//
+// If the entity reference is missing, it will use the SLOs service as a component in the
+// default namespace (e.g `component:default/myservice`).
+type IOWriterSLOFactsRepo struct {
+	writer    io.Writer
+	entityRef string
+	logger    log.Logger
+}
+
+type factsFileJSON struct {
+	Entity string    `json:"entity"`
+	Facts  factsJSON `json:"facts"`
+}
+
+type factsJSON struct {
+	SLOs []sloFactJSON `json:"slos"`
+}
+
+type sloFactJSON struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Service     string            `json:"service"`
+	Description string            `json:"description,omitempty"`
+	Objective   float64           `json:"objective"`
+	TimeWindow  string            `json:"timeWindow"`
+	Calendar    string            `json:"calendar,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	PageAlert   string            `json:"pageAlert,omitempty"`
+	TicketAlert string            `json:"ticketAlert,omitempty"`
+	// PrometheusSelector are the labels that identify the SLO generated Prometheus metrics.
+	PrometheusSelector map[string]string `json:"prometheusSelector"`
+}
+
+func (i IOWriterSLOFactsRepo) StoreSLOs(ctx context.Context, slos []prometheus.SLO) error {
+	if len(slos) == 0 {
+		return fmt.Errorf("slos required")
+	}
+
+	entityRef := i.entityRef
+	if entityRef == "" {
+		entityRef = fmt.Sprintf("component:default/%s", slos[0].Service)
+	}
+
+	facts := factsFileJSON{Entity: entityRef}
+	for _, slo := range slos {
+		fact := sloFactJSON{
+			ID:                 slo.ID,
+			Name:               slo.Name,
+			Service:            slo.Service,
+			Description:        slo.Description,
+			Objective:          slo.Objective,
+			TimeWindow:         timeDurationToPromStr(slo.TimeWindow),
+			Calendar:           string(slo.Calendar),
+			Labels:             slo.Labels,
+			PrometheusSelector: slo.GetSLOIDPromLabels(),
+		}
+
+		if !slo.PageAlertMeta.Disable {
+			fact.PageAlert = slo.PageAlertMeta.Name
+		}
+
+		if !slo.WarningAlertMeta.Disable {
+			fact.TicketAlert = slo.WarningAlertMeta.Name
+		}
+
+		facts.Facts.SLOs = append(facts.Facts.SLOs, fact)
+	}
+
+	data, err := json.MarshalIndent(facts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal backstage facts: %w", err)
+	}
+
+	_, err = i.writer.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("could not write backstage facts: %w", err)
+	}
+
+	logger := i.logger.WithCtxValues(ctx)
+	logger.WithValues(log.Kv{"slos": len(facts.Facts.SLOs), "entity": entityRef}).Infof("Backstage SLO facts written")
+
+	return nil
+}
+
+func timeDurationToPromStr(t time.Duration) string {
+	return prommodel.Duration(t).String()
+}
@@ -0,0 +1,142 @@
+package backstage_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/backstage"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+func TestIOWriterSLOFactsRepo(t *testing.T) {
+	tests := map[string]struct {
+		entityRef string
+		slos      []prometheus.SLO
+		expJSON   string
+		expErr    bool
+	}{
+		"Having 0 SLOs should fail.": {
+			slos:   []prometheus.SLO{},
+			expErr: true,
+		},
+
+		"Having multiple SLOs should store the facts using the service as the entity.": {
+			slos: []prometheus.SLO{
+				{
+					ID:               "test-svc-slo1",
+					Name:             "slo1",
+					Service:          "test-svc",
+					Description:      "This is a test.",
+					Objective:        99.9,
+					TimeWindow:       30 * 24 * time.Hour,
+					Labels:           map[string]string{"owner": "myteam"},
+					PageAlertMeta:    prometheus.AlertMeta{Name: "testAlert"},
+					WarningAlertMeta: prometheus.AlertMeta{Name: "testAlert"},
+				},
+				{
+					ID:               "test-svc-slo2",
+					Name:             "slo2",
+					Service:          "test-svc",
+					Objective:        95,
+					TimeWindow:       30 * 24 * time.Hour,
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			},
+			expJSON: `{
+  "entity": "component:default/test-svc",
+  "facts": {
+    "slos": [
+      {
+        "id": "test-svc-slo1",
+        "name": "slo1",
+        "service": "test-svc",
+        "description": "This is a test.",
+        "objective": 99.9,
+        "timeWindow": "30d",
+        "labels": {
+          "owner": "myteam"
+        },
+        "pageAlert": "testAlert",
+        "ticketAlert": "testAlert",
+        "prometheusSelector": {
+          "sloth_id": "test-svc-slo1",
+          "sloth_service": "test-svc",
+          "sloth_slo": "slo1"
+        }
+      },
+      {
+        "id": "test-svc-slo2",
+        "name": "slo2",
+        "service": "test-svc",
+        "objective": 95,
+        "timeWindow": "30d",
+        "prometheusSelector": {
+          "sloth_id": "test-svc-slo2",
+          "sloth_service": "test-svc",
+          "sloth_slo": "slo2"
+        }
+      }
+    ]
+  }
+}
+`,
+		},
+
+		"Having a custom entity should store the facts using the entity.": {
+			entityRef: "component:payments/test-svc-api",
+			slos: []prometheus.SLO{
+				{
+					ID:               "test-svc-slo1",
+					Name:             "slo1",
+					Service:          "test-svc",
+					Objective:        99,
+					TimeWindow:       30 * 24 * time.Hour,
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			},
+			expJSON: `{
+  "entity": "component:payments/test-svc-api",
+  "facts": {
+    "slos": [
+      {
+        "id": "test-svc-slo1",
+        "name": "slo1",
+        "service": "test-svc",
+        "objective": 99,
+        "timeWindow": "30d",
+        "prometheusSelector": {
+          "sloth_id": "test-svc-slo1",
+          "sloth_service": "test-svc",
+          "sloth_slo": "slo1"
+        }
+      }
+    ]
+  }
+}
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var gotJSON bytes.Buffer
+			repo := backstage.NewIOWriterSLOFactsRepo(&gotJSON, test.entityRef, log.Noop)
+			err := repo.StoreSLOs(context.TODO(), test.slos)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expJSON, gotJSON.String())
+			}
+		})
+	}
+}
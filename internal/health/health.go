@@ -0,0 +1,161 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/slok/sloth/internal/log"
+)
+
+// Checker knows how to check the health of a component.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is a helper to use functions as Checkers.
+type CheckerFunc func(ctx context.Context) error
+
+// Check satisfies Checker interface.
+func (c CheckerFunc) Check(ctx context.Context) error { return c(ctx) }
+
+const (
+	statusOK     = "ok"
+	statusFailed = "failed"
+)
+
+// HandlerConfig is the health check HTTP handler configuration.
+type HandlerConfig struct {
+	// Checks are the health checks by name.
+	Checks map[string]Checker
+	// Timeout is the max time all the checks can take.
+	Timeout time.Duration
+	Logger  log.Logger
+}
+
+func (c *HandlerConfig) defaults() error {
+	if c.Checks == nil {
+		c.Checks = map[string]Checker{}
+	}
+
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "health.Handler"})
+
+	return nil
+}
+
+type handler struct {
+	checks  map[string]Checker
+	timeout time.Duration
+	logger  log.Logger
+}
+
+// NewHandler returns an HTTP handler that runs all the checks and responds with a JSON
+// of the result of each check. If any of the checks fails, it will respond with a
+// 503 status code.
+func NewHandler(config HandlerConfig) (http.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return handler{
+		checks:  config.Checks,
+		timeout: config.Timeout,
+		logger:  config.Logger,
+	}, nil
+}
+
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type result struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Run all checks concurrently.
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	res := result{Status: statusOK, Checks: map[string]checkResult{}}
+	for _, name := range names {
+		name := name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cr := checkResult{Status: statusOK}
+			err := h.checks[name].Check(ctx)
+			if err != nil {
+				cr = checkResult{Status: statusFailed, Error: err.Error()}
+				h.logger.WithValues(log.Kv{"check": name}).Warningf("Health check failed: %s", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			res.Checks[name] = cr
+			if err != nil {
+				res.Status = statusFailed
+			}
+		}()
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	if res.Status != statusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// NewTLSCertificateChecker returns a checker that checks the TLS certificate is valid at least for the
+// min validity duration. The checked certificate should be the one being served, so a liveness
+// check restarts the server when the certificate expired and has not been reloaded.
+func NewTLSCertificateChecker(cert tls.Certificate, minValidity time.Duration) (Checker, error) {
+	if len(cert.Certificate) == 0 {
+		return nil, fmt.Errorf("certificate is required")
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("could not parse certificate: %w", err)
+	}
+
+	return CheckerFunc(func(ctx context.Context) error {
+		now := time.Now()
+		switch {
+		case now.Before(leaf.NotBefore):
+			return fmt.Errorf("certificate not valid until %s", leaf.NotBefore.UTC().Format(time.RFC3339))
+		case now.Add(minValidity).After(leaf.NotAfter):
+			return fmt.Errorf("certificate expires at %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+		}
+
+		return nil
+	}), nil
+}
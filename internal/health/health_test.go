@@ -0,0 +1,130 @@
+package health_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/health"
+)
+
+func TestHandler(t *testing.T) {
+	okCheck := health.CheckerFunc(func(ctx context.Context) error { return nil })
+	failCheck := health.CheckerFunc(func(ctx context.Context) error { return fmt.Errorf("something") })
+
+	tests := map[string]struct {
+		checks  map[string]health.Checker
+		expCode int
+		expBody string
+	}{
+		"Without checks it should be healthy.": {
+			expCode: http.StatusOK,
+			expBody: `{"status":"ok","checks":{}}`,
+		},
+
+		"With all the checks ok it should be healthy.": {
+			checks:  map[string]health.Checker{"a": okCheck, "b": okCheck},
+			expCode: http.StatusOK,
+			expBody: `{"status":"ok","checks":{"a":{"status":"ok"},"b":{"status":"ok"}}}`,
+		},
+
+		"With a failed check it should be unhealthy.": {
+			checks:  map[string]health.Checker{"a": okCheck, "b": failCheck},
+			expCode: http.StatusServiceUnavailable,
+			expBody: `{"status":"failed","checks":{"a":{"status":"ok"},"b":{"status":"failed","error":"something"}}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			h, err := health.NewHandler(health.HandlerConfig{Checks: test.checks})
+			require.NoError(err)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(test.expCode, w.Code)
+			assert.Equal("application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(test.expBody, w.Body.String())
+		})
+	}
+}
+
+func newTestCertificate(t *testing.T, notBefore, notAfter time.Time) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestTLSCertificateChecker(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		cert        tls.Certificate
+		minValidity time.Duration
+		expErr      bool
+	}{
+		"A valid certificate should be healthy.": {
+			cert: newTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour)),
+		},
+
+		"An expired certificate should be unhealthy.": {
+			cert:   newTestCertificate(t, now.Add(-2*time.Hour), now.Add(-time.Hour)),
+			expErr: true,
+		},
+
+		"A not yet valid certificate should be unhealthy.": {
+			cert:   newTestCertificate(t, now.Add(time.Hour), now.Add(2*time.Hour)),
+			expErr: true,
+		},
+
+		"A certificate that expires before the min validity should be unhealthy.": {
+			cert:        newTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour)),
+			minValidity: 2 * time.Hour,
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			checker, err := health.NewTLSCertificateChecker(test.cert, test.minValidity)
+			require.NoError(err)
+
+			err = checker.Check(context.TODO())
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package k8sprometheus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/slok/sloth/internal/alert"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+	k8sprometheusv2 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v2"
+)
+
+// AlertWindowsCatalog has the multiwindow-multiburn alert windows of each SLO period (time window).
+type AlertWindowsCatalog struct {
+	periods []catalogPeriod
+}
+
+type catalogPeriod struct {
+	timeWindow time.Duration
+	calendar   prometheus.CalendarPeriod
+	windows    alert.MWMBAlertGroupOverride
+}
+
+// NewAlertWindowsCatalogFromConfigMap loads an alert windows catalog from a ConfigMap. Each data key is
+// an SLO period (e.g `30d`, `28d` or `calendar-month`) with the alert windows YAML, in the same format as
+// the PrometheusServiceLevel `alertWindows`.
+func NewAlertWindowsCatalogFromConfigMap(cm *corev1.ConfigMap) (*AlertWindowsCatalog, error) {
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	catalog := &AlertWindowsCatalog{}
+	for _, k := range keys {
+		period := strings.TrimSuffix(strings.TrimSuffix(k, ".yaml"), ".yml")
+		timeWindow, calendar, err := prometheus.ParseTimeWindow(period)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q key SLO period: %w", k, err)
+		}
+
+		ws := k8sprometheusv2.AlertWindows{}
+		err = yaml.NewYAMLOrJSONDecoder(strings.NewReader(cm.Data[k]), 4096).Decode(&ws)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %q key alert windows: %w", k, err)
+		}
+
+		p := catalogPeriod{timeWindow: timeWindow, calendar: calendar}
+		windows := []struct {
+			name     string
+			window   k8sprometheusv2.AlertWindow
+			override *alert.MWMBAlertOverride
+		}{
+			{name: "page quick", window: ws.PageQuick, override: &p.windows.PageQuick},
+			{name: "page slow", window: ws.PageSlow, override: &p.windows.PageSlow},
+			{name: "ticket quick", window: ws.TicketQuick, override: &p.windows.TicketQuick},
+			{name: "ticket slow", window: ws.TicketSlow, override: &p.windows.TicketSlow},
+		}
+		for _, w := range windows {
+			short, long, err := prometheus.ParseAlertWindows(w.window.Short, w.window.Long)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q key %s alert windows: %w", k, w.name, err)
+			}
+			w.override.ShortWindow, w.override.LongWindow = short, long
+		}
+		catalog.periods = append(catalog.periods, p)
+	}
+
+	return catalog, nil
+}
+
+// SetAlertWindows sets the catalog alert windows of the SLO period on the multiwindow-multiburn
+// alerts of the SLOs, the windows already set on the SLO alerts take precedence.
+func (a AlertWindowsCatalog) SetAlertWindows(slos []prometheus.SLO) {
+	for i, slo := range slos {
+		if slo.AlertStrategy != "" && slo.AlertStrategy != prometheus.MWMBAlertStrategy {
+			continue
+		}
+
+		for _, p := range a.periods {
+			if p.timeWindow != slo.TimeWindow || p.calendar != slo.Calendar {
+				continue
+			}
+
+			overrides := []struct {
+				catalog alert.MWMBAlertOverride
+				slo     *alert.MWMBAlertOverride
+			}{
+				{catalog: p.windows.PageQuick, slo: &slos[i].AlertOverrides.PageQuick},
+				{catalog: p.windows.PageSlow, slo: &slos[i].AlertOverrides.PageSlow},
+				{catalog: p.windows.TicketQuick, slo: &slos[i].AlertOverrides.TicketQuick},
+				{catalog: p.windows.TicketSlow, slo: &slos[i].AlertOverrides.TicketSlow},
+			}
+			for _, o := range overrides {
+				if o.slo.ShortWindow != 0 || o.slo.LongWindow != 0 {
+					continue
+				}
+				o.slo.ShortWindow, o.slo.LongWindow = o.catalog.ShortWindow, o.catalog.LongWindow
+			}
+		}
+	}
+}
+
+// ConfigMapGetter knows how to get Kubernetes ConfigMaps.
+type ConfigMapGetter interface {
+	GetConfigMap(ctx context.Context, ns, name string) (*corev1.ConfigMap, error)
+}
+
+// NewAlertWindowsCatalogRepo returns a new alert windows catalog repository that caches the
+// loaded catalogs during the cache TTL.
+func NewAlertWindowsCatalogRepo(getter ConfigMapGetter, cacheTTL time.Duration, logger log.Logger) *AlertWindowsCatalogRepo {
+	if cacheTTL == 0 {
+		cacheTTL = time.Minute
+	}
+
+	return &AlertWindowsCatalogRepo{
+		getter:   getter,
+		cacheTTL: cacheTTL,
+		timeNow:  time.Now,
+		cache:    map[string]cachedCatalog{},
+		logger:   logger.WithValues(log.Kv{"svc": "storage.AlertWindowsCatalog", "format": "configmap"}),
+	}
+}
+
+// AlertWindowsCatalogRepo knows how to get the alert windows catalogs stored on ConfigMaps,
+// the catalogs are cached so each handling doesn't get the ConfigMap.
+type AlertWindowsCatalogRepo struct {
+	getter   ConfigMapGetter
+	cacheTTL time.Duration
+	timeNow  func() time.Time
+	logger   log.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedCatalog
+}
+
+type cachedCatalog struct {
+	catalog *AlertWindowsCatalog
+	expires time.Time
+}
+
+// GetAlertWindowsCatalog returns the alert windows catalog of a ConfigMap.
+func (a *AlertWindowsCatalogRepo) GetAlertWindowsCatalog(ctx context.Context, ns, name string) (*AlertWindowsCatalog, error) {
+	key := ns + "/" + name
+
+	a.mu.Lock()
+	c, ok := a.cache[key]
+	a.mu.Unlock()
+	if ok && a.timeNow().Before(c.expires) {
+		return c.catalog, nil
+	}
+
+	cm, err := a.getter.GetConfigMap(ctx, ns, name)
+	if err != nil {
+		return nil, fmt.Errorf("could not get %q alert windows catalog ConfigMap: %w", key, err)
+	}
+
+	catalog, err := NewAlertWindowsCatalogFromConfigMap(cm)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q alert windows catalog: %w", key, err)
+	}
+
+	a.mu.Lock()
+	a.cache[key] = cachedCatalog{catalog: catalog, expires: a.timeNow().Add(a.cacheTTL)}
+	a.mu.Unlock()
+	a.logger.WithCtxValues(ctx).WithValues(log.Kv{"catalog": key}).Debugf("Alert windows catalog loaded")
+
+	return catalog, nil
+}
+
+// Invalidate drops the cached catalogs, so these are loaded again on the next get (e.g new window sets
+// rolled out without waiting the cache TTL).
+func (a *AlertWindowsCatalogRepo) Invalidate() {
+	a.mu.Lock()
+	a.cache = map[string]cachedCatalog{}
+	a.mu.Unlock()
+}
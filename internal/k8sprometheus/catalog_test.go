@@ -0,0 +1,149 @@
+package k8sprometheus_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/slok/sloth/internal/alert"
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+func TestAlertWindowsCatalogSetAlertWindows(t *testing.T) {
+	tests := map[string]struct {
+		data   map[string]string
+		slos   []prometheus.SLO
+		expErr bool
+		expSLO []prometheus.SLO
+	}{
+		"An invalid SLO period key should fail.": {
+			data:   map[string]string{"something": `pageQuick: {short: 5m, long: 1h}`},
+			expErr: true,
+		},
+
+		"Invalid alert windows should fail.": {
+			data:   map[string]string{"30d": `pageQuick: {short: 1h, long: 5m}`},
+			expErr: true,
+		},
+
+		"The catalog windows should be set on the SLOs with the same period.": {
+			data: map[string]string{
+				"30d.yaml":       `pageQuick: {short: 10m, long: 2h}`,
+				"28d":            `pageQuick: {short: 15m, long: 3h}`,
+				"calendar-month": `ticketSlow: {short: 12h, long: 4d}`,
+			},
+			slos: []prometheus.SLO{
+				{ID: "slo1", TimeWindow: 30 * 24 * time.Hour},
+				{ID: "slo2", TimeWindow: 28 * 24 * time.Hour},
+				{ID: "slo3", TimeWindow: 30 * 24 * time.Hour, Calendar: prometheus.CalendarMonthPeriod},
+				{ID: "slo4", TimeWindow: 7 * 24 * time.Hour},
+			},
+			expSLO: []prometheus.SLO{
+				{ID: "slo1", TimeWindow: 30 * 24 * time.Hour, AlertOverrides: alert.MWMBAlertGroupOverride{
+					PageQuick: alert.MWMBAlertOverride{ShortWindow: 10 * time.Minute, LongWindow: 2 * time.Hour},
+				}},
+				{ID: "slo2", TimeWindow: 28 * 24 * time.Hour, AlertOverrides: alert.MWMBAlertGroupOverride{
+					PageQuick: alert.MWMBAlertOverride{ShortWindow: 15 * time.Minute, LongWindow: 3 * time.Hour},
+				}},
+				{ID: "slo3", TimeWindow: 30 * 24 * time.Hour, Calendar: prometheus.CalendarMonthPeriod, AlertOverrides: alert.MWMBAlertGroupOverride{
+					TicketSlow: alert.MWMBAlertOverride{ShortWindow: 12 * time.Hour, LongWindow: 4 * 24 * time.Hour},
+				}},
+				{ID: "slo4", TimeWindow: 7 * 24 * time.Hour},
+			},
+		},
+
+		"The SLO windows should take precedence over the catalog windows.": {
+			data: map[string]string{"30d": `
+pageQuick: {short: 10m, long: 2h}
+pageSlow: {short: 1h, long: 12h}
+`},
+			slos: []prometheus.SLO{
+				{ID: "slo1", TimeWindow: 30 * 24 * time.Hour, AlertOverrides: alert.MWMBAlertGroupOverride{
+					PageQuick: alert.MWMBAlertOverride{ShortWindow: 5 * time.Minute, LongWindow: time.Hour, BurnRateFactor: 10},
+				}},
+			},
+			expSLO: []prometheus.SLO{
+				{ID: "slo1", TimeWindow: 30 * 24 * time.Hour, AlertOverrides: alert.MWMBAlertGroupOverride{
+					PageQuick: alert.MWMBAlertOverride{ShortWindow: 5 * time.Minute, LongWindow: time.Hour, BurnRateFactor: 10},
+					PageSlow:  alert.MWMBAlertOverride{ShortWindow: time.Hour, LongWindow: 12 * time.Hour},
+				}},
+			},
+		},
+
+		"The catalog windows should not be set on SLOs that don't use multiwindow-multiburn alerts.": {
+			data: map[string]string{"30d": `pageQuick: {short: 10m, long: 2h}`},
+			slos: []prometheus.SLO{
+				{ID: "slo1", TimeWindow: 30 * 24 * time.Hour, AlertStrategy: prometheus.SingleWindowAlertStrategy},
+			},
+			expSLO: []prometheus.SLO{
+				{ID: "slo1", TimeWindow: 30 * 24 * time.Hour, AlertStrategy: prometheus.SingleWindowAlertStrategy},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			catalog, err := k8sprometheus.NewAlertWindowsCatalogFromConfigMap(&corev1.ConfigMap{Data: test.data})
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			catalog.SetAlertWindows(test.slos)
+			assert.Equal(test.expSLO, test.slos)
+		})
+	}
+}
+
+type configMapGetterFunc func(ctx context.Context, ns, name string) (*corev1.ConfigMap, error)
+
+func (c configMapGetterFunc) GetConfigMap(ctx context.Context, ns, name string) (*corev1.ConfigMap, error) {
+	return c(ctx, ns, name)
+}
+
+func TestAlertWindowsCatalogRepoGetAlertWindowsCatalog(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gets := 0
+	repo := k8sprometheus.NewAlertWindowsCatalogRepo(configMapGetterFunc(func(ctx context.Context, ns, name string) (*corev1.ConfigMap, error) {
+		gets++
+		if name != "windows" {
+			return nil, fmt.Errorf("not found")
+		}
+		return &corev1.ConfigMap{Data: map[string]string{"30d": `pageQuick: {short: 10m, long: 2h}`}}, nil
+	}), time.Hour, log.Noop)
+
+	// The catalogs should be cached.
+	c1, err := repo.GetAlertWindowsCatalog(context.TODO(), "monitoring", "windows")
+	require.NoError(err)
+	c2, err := repo.GetAlertWindowsCatalog(context.TODO(), "monitoring", "windows")
+	require.NoError(err)
+	assert.Same(c1, c2)
+	assert.Equal(1, gets)
+
+	// The failures should not be cached.
+	_, err = repo.GetAlertWindowsCatalog(context.TODO(), "monitoring", "missing")
+	assert.Error(err)
+	_, err = repo.GetAlertWindowsCatalog(context.TODO(), "monitoring", "missing")
+	assert.Error(err)
+	assert.Equal(3, gets)
+
+	// The invalidated catalogs should be loaded again.
+	repo.Invalidate()
+	c3, err := repo.GetAlertWindowsCatalog(context.TODO(), "monitoring", "windows")
+	require.NoError(err)
+	assert.NotSame(c1, c3)
+	assert.Equal(4, gets)
+}
@@ -10,3 +10,17 @@ func mergeLabels(ms ...map[string]string) map[string]string {
 
 	return res
 }
+
+// mergeAlertTierLabels merges the common alert labels/annotations with the
+// specific alert tier (page, ticket) ones. The tier takes precedence over the
+// common block and a tier key with an empty value will unset the common key.
+func mergeAlertTierLabels(common, tier map[string]string) map[string]string {
+	res := mergeLabels(common, tier)
+	for k, v := range tier {
+		if v == "" {
+			delete(res, k)
+		}
+	}
+
+	return res
+}
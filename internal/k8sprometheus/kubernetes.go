@@ -2,14 +2,22 @@ package k8sprometheus
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringclientset "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	corev1 "k8s.io/api/core/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/slok/sloth/internal/log"
 	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
@@ -17,20 +25,39 @@ import (
 )
 
 type KubernetesService struct {
+	coreCli       kubernetes.Interface
+	dynamicCli    dynamic.Interface
 	slothCli      slothclientset.Interface
 	monitoringCli monitoringclientset.Interface
+	fieldManager  string
 	logger        log.Logger
 }
 
 // NewKubernetesService returns a new Kubernetes Service.
-func NewKubernetesService(slothCli slothclientset.Interface, monitoringCli monitoringclientset.Interface, logger log.Logger) KubernetesService {
+func NewKubernetesService(coreCli kubernetes.Interface, dynamicCli dynamic.Interface, slothCli slothclientset.Interface, monitoringCli monitoringclientset.Interface, logger log.Logger) KubernetesService {
 	return KubernetesService{
+		coreCli:       coreCli,
+		dynamicCli:    dynamicCli,
 		slothCli:      slothCli,
 		monitoringCli: monitoringCli,
 		logger:        logger.WithValues(log.Kv{"service": "k8sprometheus.Service"}),
 	}
 }
 
+// WithServerSideApply returns a copy of the service that stores the generated objects using server-side apply
+// with the field manager, instead of overwriting them, so the fields set by other controllers or admission
+// policies are kept.
+func (k KubernetesService) WithServerSideApply(fieldManager string) KubernetesService {
+	k.fieldManager = fieldManager
+	return k
+}
+
+func (k KubernetesService) applyPatchOptions() metav1.PatchOptions {
+	// Force the conflicts, the generated objects are owned by Sloth.
+	force := true
+	return metav1.PatchOptions{FieldManager: k.fieldManager, Force: &force}
+}
+
 func (k KubernetesService) ListPrometheusServiceLevels(ctx context.Context, ns string, labelSelector map[string]string) (*slothv1.PrometheusServiceLevelList, error) {
 	return k.slothCli.SlothV1().PrometheusServiceLevels(ns).List(ctx, metav1.ListOptions{
 		LabelSelector: labels.Set(labelSelector).String(),
@@ -43,8 +70,68 @@ func (k KubernetesService) WatchPrometheusServiceLevels(ctx context.Context, ns
 	})
 }
 
+func (k KubernetesService) GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error) {
+	return k.slothCli.SlothV1().PrometheusServiceLevels(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (k KubernetesService) GetConfigMap(ctx context.Context, ns, name string) (*corev1.ConfigMap, error) {
+	return k.coreCli.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (k KubernetesService) ListConfigMaps(ctx context.Context, ns string, labelSelector string) (*corev1.ConfigMapList, error) {
+	return k.coreCli.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+}
+
+func (k KubernetesService) WatchConfigMaps(ctx context.Context, ns string, labelSelector string) (watch.Interface, error) {
+	return k.coreCli.CoreV1().ConfigMaps(ns).Watch(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+}
+
+func (k KubernetesService) ListPrometheusRules(ctx context.Context, ns string, labelSelector map[string]string) (*monitoringv1.PrometheusRuleList, error) {
+	return k.monitoringCli.MonitoringV1().PrometheusRules(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(labelSelector).String(),
+	})
+}
+
+func (k KubernetesService) WatchPrometheusRules(ctx context.Context, ns string, labelSelector map[string]string) (watch.Interface, error) {
+	return k.monitoringCli.MonitoringV1().PrometheusRules(ns).Watch(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(labelSelector).String(),
+	})
+}
+
+func (k KubernetesService) GetPrometheusRule(ctx context.Context, ns, name string) (*monitoringv1.PrometheusRule, error) {
+	return k.monitoringCli.MonitoringV1().PrometheusRules(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (k KubernetesService) DeletePrometheusRule(ctx context.Context, ns, name string) error {
+	err := k.monitoringCli.MonitoringV1().PrometheusRules(ns).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !kubeerrors.IsNotFound(err) {
+		return err
+	}
+	k.logger.WithCtxValues(ctx).Debugf("monitoringv1.PrometheusRule has been deleted")
+
+	return nil
+}
+
 func (k KubernetesService) EnsurePrometheusRule(ctx context.Context, pr *monitoringv1.PrometheusRule) error {
 	logger := k.logger.WithCtxValues(ctx)
+	if k.fieldManager != "" {
+		data, err := json.Marshal(pr)
+		if err != nil {
+			return err
+		}
+		_, err = k.monitoringCli.MonitoringV1().PrometheusRules(pr.Namespace).Patch(ctx, pr.Name, types.ApplyPatchType, data, k.applyPatchOptions())
+		if err != nil {
+			return err
+		}
+		logger.Debugf("monitoringv1.PrometheusRule has been applied")
+
+		return nil
+	}
+
 	pr = pr.DeepCopy()
 	stored, err := k.monitoringCli.MonitoringV1().PrometheusRules(pr.Namespace).Get(ctx, pr.Name, metav1.GetOptions{})
 	if err != nil {
@@ -71,24 +158,234 @@ func (k KubernetesService) EnsurePrometheusRule(ctx context.Context, pr *monitor
 	return nil
 }
 
+func (k KubernetesService) EnsureConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
+	logger := k.logger.WithCtxValues(ctx)
+	if k.fieldManager != "" {
+		data, err := json.Marshal(cm)
+		if err != nil {
+			return err
+		}
+		_, err = k.coreCli.CoreV1().ConfigMaps(cm.Namespace).Patch(ctx, cm.Name, types.ApplyPatchType, data, k.applyPatchOptions())
+		if err != nil {
+			return err
+		}
+		logger.Debugf("corev1.ConfigMap has been applied")
+
+		return nil
+	}
+
+	cm = cm.DeepCopy()
+	stored, err := k.coreCli.CoreV1().ConfigMaps(cm.Namespace).Get(ctx, cm.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kubeerrors.IsNotFound(err) {
+			return err
+		}
+		_, err = k.coreCli.CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		logger.Debugf("corev1.ConfigMap has been created")
+
+		return nil
+	}
+
+	// Force overwrite.
+	cm.ObjectMeta.ResourceVersion = stored.ResourceVersion
+	_, err = k.coreCli.CoreV1().ConfigMaps(cm.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	logger.Debugf("corev1.ConfigMap has been overwritten")
+
+	return nil
+}
+
+func (k KubernetesService) EnsureVMRule(ctx context.Context, rule *unstructured.Unstructured) error {
+	logger := k.logger.WithCtxValues(ctx)
+	cli := k.dynamicCli.Resource(VMRuleGVR).Namespace(rule.GetNamespace())
+	if k.fieldManager != "" {
+		data, err := rule.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		_, err = cli.Patch(ctx, rule.GetName(), types.ApplyPatchType, data, k.applyPatchOptions())
+		if err != nil {
+			return err
+		}
+		logger.Debugf("VMRule has been applied")
+
+		return nil
+	}
+
+	rule = rule.DeepCopy()
+	stored, err := cli.Get(ctx, rule.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !kubeerrors.IsNotFound(err) {
+			return err
+		}
+		_, err = cli.Create(ctx, rule, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		logger.Debugf("VMRule has been created")
+
+		return nil
+	}
+
+	// Force overwrite.
+	rule.SetResourceVersion(stored.GetResourceVersion())
+	_, err = cli.Update(ctx, rule, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	logger.Debugf("VMRule has been overwritten")
+
+	return nil
+}
+
+// AddPrometheusServiceLevelFinalizer adds the finalizer to the PrometheusServiceLevel (if missing) and returns
+// the updated PrometheusServiceLevel.
+func (k KubernetesService) AddPrometheusServiceLevelFinalizer(ctx context.Context, slo *slothv1.PrometheusServiceLevel, finalizer string) (*slothv1.PrometheusServiceLevel, error) {
+	for _, f := range slo.Finalizers {
+		if f == finalizer {
+			return slo, nil
+		}
+	}
+
+	slo = slo.DeepCopy()
+	slo.Finalizers = append(slo.Finalizers, finalizer)
+
+	return k.slothCli.SlothV1().PrometheusServiceLevels(slo.Namespace).Update(ctx, slo, metav1.UpdateOptions{})
+}
+
+// RemovePrometheusServiceLevelFinalizer removes the finalizer from the PrometheusServiceLevel (if present).
+func (k KubernetesService) RemovePrometheusServiceLevelFinalizer(ctx context.Context, slo *slothv1.PrometheusServiceLevel, finalizer string) error {
+	finalizers := []string{}
+	for _, f := range slo.Finalizers {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == len(slo.Finalizers) {
+		return nil
+	}
+
+	slo = slo.DeepCopy()
+	slo.Finalizers = finalizers
+	_, err := k.slothCli.SlothV1().PrometheusServiceLevels(slo.Namespace).Update(ctx, slo, metav1.UpdateOptions{})
+	if err != nil && !kubeerrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
 // EnsurePrometheusServiceLevelStatus updates the status of a PrometheusServiceLeve, be aware that updating
 // an status will trigger a watch update event on a controller.
 // In case of no error we will update "last correct Prometheus operation rules generated" TS so we can be in
 // a infinite loop of handling, the handler should break this loop somehow (e.g: if ok and last generated < 5m, ignore).
-func (k KubernetesService) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, err error) error {
+func (k KubernetesService) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, result HandleResult) error {
 	slo = slo.DeepCopy()
 
 	slo.Status.PromOpRulesGenerated = false
 	slo.Status.PromOpRulesGeneratedSLOs = 0
 	slo.Status.ProcessedSLOs = len(slo.Spec.SLOs)
 	slo.Status.ObservedGeneration = slo.Generation
+	slo.Status.GeneratedRules = result.GeneratedRules
+	slo.Status.Objectives = objectivesRange(slo.Spec.SLOs)
+	slo.Status.LastError = ""
+	slo.Status.SLOErrors = nil
+	for _, e := range result.SLOErrors {
+		slo.Status.SLOErrors = append(slo.Status.SLOErrors, slothv1.SLOError{
+			SLO:        e.SLO,
+			Field:      e.Field,
+			Expression: e.Expression,
+			Message:    e.Message,
+		})
+	}
 
-	if err == nil {
+	if result.Err == nil {
 		slo.Status.PromOpRulesGenerated = true
 		slo.Status.PromOpRulesGeneratedSLOs = len(slo.Spec.SLOs)
 		slo.Status.LastPromOpRulesSuccessfulGenerated = &metav1.Time{Time: time.Now().UTC()}
+	} else {
+		slo.Status.LastError = result.Err.Error()
+	}
+
+	// The first step that didn't succeed has the error, the next ones are not known.
+	steps := []struct {
+		condition    string
+		ok           bool
+		okReason     string
+		failedReason string
+	}{
+		{condition: slothv1.ConditionValidated, ok: result.Validated, okReason: "SpecValid", failedReason: "SpecInvalid"},
+		{condition: slothv1.ConditionRulesGenerated, ok: result.RulesGenerated, okReason: "RulesGenerated", failedReason: "GenerationFailed"},
+		{condition: slothv1.ConditionRulesApplied, ok: result.RulesApplied, okReason: "RulesApplied", failedReason: "ApplyFailed"},
+	}
+	failed := false
+	for _, step := range steps {
+		cond := metav1.Condition{
+			Type:               step.condition,
+			ObservedGeneration: slo.Generation,
+		}
+		switch {
+		case step.ok:
+			cond.Status = metav1.ConditionTrue
+			cond.Reason = step.okReason
+		case !failed:
+			failed = true
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = step.failedReason
+			if result.Err != nil {
+				cond.Message = result.Err.Error()
+			}
+		default:
+			cond.Status = metav1.ConditionUnknown
+			cond.Reason = "PreviousStepFailed"
+		}
+		meta.SetStatusCondition(&slo.Status.Conditions, cond)
 	}
 
-	_, err = k.slothCli.SlothV1().PrometheusServiceLevels(slo.Namespace).UpdateStatus(ctx, slo, metav1.UpdateOptions{})
+	ready := metav1.Condition{
+		Type:               slothv1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: slo.Generation,
+		Reason:             "Ready",
+	}
+	if failed {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "NotReady"
+		if result.Err != nil {
+			ready.Message = result.Err.Error()
+		}
+	}
+	meta.SetStatusCondition(&slo.Status.Conditions, ready)
+
+	_, err := k.slothCli.SlothV1().PrometheusServiceLevels(slo.Namespace).UpdateStatus(ctx, slo, metav1.UpdateOptions{})
 	return err
 }
+
+// objectivesRange returns the range of the SLO objectives (e.g `99-99.9`), or the objective if all are the same.
+func objectivesRange(slos []slothv1.SLO) string {
+	if len(slos) == 0 {
+		return ""
+	}
+
+	min, max := slos[0].Objective, slos[0].Objective
+	for _, slo := range slos[1:] {
+		if slo.Objective < min {
+			min = slo.Objective
+		}
+		if slo.Objective > max {
+			max = slo.Objective
+		}
+	}
+
+	format := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	if min == max {
+		return format(min)
+	}
+
+	return format(min) + "-" + format(max)
+}
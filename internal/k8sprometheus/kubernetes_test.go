@@ -0,0 +1,201 @@
+package k8sprometheus_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringfake "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/slok/sloth/internal/k8sprometheus"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+func TestKubernetesServiceEnsurePrometheusServiceLevelStatus(t *testing.T) {
+	tests := map[string]struct {
+		result    k8sprometheus.HandleResult
+		expStatus slothv1.PrometheusServiceLevelStatus
+	}{
+		"A successful handling should set all the conditions as true.": {
+			result: k8sprometheus.HandleResult{
+				Validated:      true,
+				RulesGenerated: true,
+				RulesApplied:   true,
+				GeneratedRules: 42,
+			},
+			expStatus: slothv1.PrometheusServiceLevelStatus{
+				PromOpRulesGeneratedSLOs: 2,
+				ProcessedSLOs:            2,
+				PromOpRulesGenerated:     true,
+				ObservedGeneration:       3,
+				Objectives:               "99-99.9",
+				GeneratedRules:           42,
+				Conditions: []metav1.Condition{
+					{Type: "Validated", Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: "SpecValid"},
+					{Type: "RulesGenerated", Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: "RulesGenerated"},
+					{Type: "RulesApplied", Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: "RulesApplied"},
+					{Type: "Ready", Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: "Ready"},
+				},
+			},
+		},
+
+		"A failed validation should set the error on the validated condition and the next ones as unknown.": {
+			result: k8sprometheus.HandleResult{
+				Err: fmt.Errorf("something"),
+			},
+			expStatus: slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 3,
+				Objectives:         "99-99.9",
+				LastError:          "something",
+				Conditions: []metav1.Condition{
+					{Type: "Validated", Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: "SpecInvalid", Message: "something"},
+					{Type: "RulesGenerated", Status: metav1.ConditionUnknown, ObservedGeneration: 3, Reason: "PreviousStepFailed"},
+					{Type: "RulesApplied", Status: metav1.ConditionUnknown, ObservedGeneration: 3, Reason: "PreviousStepFailed"},
+					{Type: "Ready", Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: "NotReady", Message: "something"},
+				},
+			},
+		},
+
+		"A failed generation should set the SLO errors.": {
+			result: k8sprometheus.HandleResult{
+				Validated: true,
+				Err:       fmt.Errorf("something"),
+				SLOErrors: []k8sprometheus.SLOError{
+					{SLO: "slo2", Field: "SLI.Raw.ErrorRatioQuery", Expression: "sum(", Message: "invalid"},
+				},
+			},
+			expStatus: slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 3,
+				Objectives:         "99-99.9",
+				LastError:          "something",
+				SLOErrors: []slothv1.SLOError{
+					{SLO: "slo2", Field: "SLI.Raw.ErrorRatioQuery", Expression: "sum(", Message: "invalid"},
+				},
+				Conditions: []metav1.Condition{
+					{Type: "Validated", Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: "SpecValid"},
+					{Type: "RulesGenerated", Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: "GenerationFailed", Message: "something"},
+					{Type: "RulesApplied", Status: metav1.ConditionUnknown, ObservedGeneration: 3, Reason: "PreviousStepFailed"},
+					{Type: "Ready", Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: "NotReady", Message: "something"},
+				},
+			},
+		},
+
+		"A failed rules apply should set the error on the rules applied condition.": {
+			result: k8sprometheus.HandleResult{
+				Validated:      true,
+				RulesGenerated: true,
+				GeneratedRules: 42,
+				Err:            fmt.Errorf("something"),
+			},
+			expStatus: slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 3,
+				Objectives:         "99-99.9",
+				GeneratedRules:     42,
+				LastError:          "something",
+				Conditions: []metav1.Condition{
+					{Type: "Validated", Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: "SpecValid"},
+					{Type: "RulesGenerated", Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: "RulesGenerated"},
+					{Type: "RulesApplied", Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: "ApplyFailed", Message: "something"},
+					{Type: "Ready", Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: "NotReady", Message: "something"},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			psl := &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Generation: 3},
+				Spec: slothv1.PrometheusServiceLevelSpec{
+					SLOs: []slothv1.SLO{{Name: "slo1", Objective: 99.9}, {Name: "slo2", Objective: 99}},
+				},
+			}
+			slothCli := slothfake.NewSimpleClientset(psl)
+			svc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothCli, monitoringfake.NewSimpleClientset(), log.Noop)
+
+			err := svc.EnsurePrometheusServiceLevelStatus(context.TODO(), psl, test.result)
+			require.NoError(err)
+
+			got, err := slothCli.SlothV1().PrometheusServiceLevels("test-ns").Get(context.TODO(), "test", metav1.GetOptions{})
+			require.NoError(err)
+
+			// Remove variations.
+			got.Status.LastPromOpRulesSuccessfulGenerated = nil
+			for i := range got.Status.Conditions {
+				got.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+			}
+			assert.Equal(test.expStatus, got.Status)
+		})
+	}
+}
+
+func TestKubernetesServiceEnsurePrometheusRuleServerSideApply(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var gotAction kubetesting.PatchAction
+	monitoringCli := monitoringfake.NewSimpleClientset()
+	monitoringCli.PrependReactor("patch", "prometheusrules", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		gotAction = action.(kubetesting.PatchAction)
+		return true, &monitoringv1.PrometheusRule{}, nil
+	})
+	ksvc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothfake.NewSimpleClientset(), monitoringCli, log.Noop).WithServerSideApply("sloth")
+
+	err := ksvc.EnsurePrometheusRule(context.TODO(), &monitoringv1.PrometheusRule{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "monitoring.coreos.com/v1", Kind: "PrometheusRule"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+	})
+	require.NoError(err)
+
+	// The rule should be applied instead of created.
+	require.NotNil(gotAction)
+	assert.Equal(types.ApplyPatchType, gotAction.GetPatchType())
+	assert.Equal("test", gotAction.GetName())
+	assert.Contains(string(gotAction.GetPatch()), `"kind":"PrometheusRule"`)
+	_, err = monitoringCli.MonitoringV1().PrometheusRules("test-ns").Get(context.TODO(), "test", metav1.GetOptions{})
+	assert.Error(err)
+}
+
+func TestKubernetesServicePrometheusServiceLevelFinalizer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	psl := &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Finalizers: []string{"other"}},
+	}
+	slothCli := slothfake.NewSimpleClientset(psl)
+	svc := k8sprometheus.NewKubernetesService(kubefake.NewSimpleClientset(), nil, slothCli, monitoringfake.NewSimpleClientset(), log.Noop)
+
+	// Adding the finalizer multiple times should set it once.
+	got, err := svc.AddPrometheusServiceLevelFinalizer(context.TODO(), psl, "test-finalizer")
+	require.NoError(err)
+	got, err = svc.AddPrometheusServiceLevelFinalizer(context.TODO(), got, "test-finalizer")
+	require.NoError(err)
+	assert.Equal([]string{"other", "test-finalizer"}, got.Finalizers)
+	stored, err := slothCli.SlothV1().PrometheusServiceLevels("test-ns").Get(context.TODO(), "test", metav1.GetOptions{})
+	require.NoError(err)
+	assert.Equal([]string{"other", "test-finalizer"}, stored.Finalizers)
+
+	// Removing the finalizer should keep the other ones.
+	err = svc.RemovePrometheusServiceLevelFinalizer(context.TODO(), stored, "test-finalizer")
+	require.NoError(err)
+	stored, err = slothCli.SlothV1().PrometheusServiceLevels("test-ns").Get(context.TODO(), "test", metav1.GetOptions{})
+	require.NoError(err)
+	assert.Equal([]string{"other"}, stored.Finalizers)
+}
@@ -39,6 +39,50 @@ func (s SLOGroup) Validate() error {
 	return nil
 }
 
+// ConfigMapSLOGroup is the group of raw Sloth specs stored on a Kubernetes ConfigMap.
+type ConfigMapSLOGroup struct {
+	K8sMeta K8sMeta
+	// Specs are the specs of each ConfigMap data key, sorted by key.
+	Specs []ConfigMapSpec
+}
+
+// ConfigMapSpec is a raw Sloth spec of a ConfigMap data key.
+type ConfigMapSpec struct {
+	Key string
+	// Version is the loaded spec version (e.g prometheus/v1).
+	Version string
+	prometheus.SLOGroup
+}
+
 var modelSpecValidate = func() *validator.Validate {
 	return validator.New()
 }()
+
+// HandleResult is the result of handling a Kubernetes SLO group, it's used to
+// set the status of the handled Kubernetes object.
+type HandleResult struct {
+	// Validated is true when the spec has been loaded and validated.
+	Validated bool
+	// RulesGenerated is true when the Prometheus rules have been generated.
+	RulesGenerated bool
+	// RulesApplied is true when the generated rules have been stored.
+	RulesApplied bool
+	// GeneratedRules is the number of generated Prometheus rules.
+	GeneratedRules int
+	// Err is the error of the handling, if any.
+	Err error
+	// SLOErrors are the errors of the specific SLOs that made the handling fail, if known.
+	SLOErrors []SLOError
+}
+
+// SLOError is the handling error of a specific SLO.
+type SLOError struct {
+	// SLO is the SLO name.
+	SLO string
+	// Field is the failed SLO model field, if known.
+	Field string
+	// Expression is the failed expression, if known.
+	Expression string
+	// Message is the error message.
+	Message string
+}
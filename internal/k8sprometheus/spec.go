@@ -3,13 +3,18 @@ package k8sprometheus
 import (
 	"context"
 	"fmt"
-	"time"
+	"sort"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/prometheus"
 	k8sprometheusv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	k8sprometheusv2 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v2"
 	"github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/scheme"
+	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
+	prometheusv2 "github.com/slok/sloth/pkg/prometheus/api/v2"
 )
 
 type yamlSpecLoader struct {
@@ -31,9 +36,17 @@ func (y yamlSpecLoader) LoadSpec(ctx context.Context, data []byte) (*SLOGroup, e
 		return nil, fmt.Errorf("could not decode kubernetes object %w", err)
 	}
 
-	kslo, ok := obj.(*k8sprometheusv1.PrometheusServiceLevel)
-	if !ok {
-		return nil, fmt.Errorf("can't type assert runtime.Object to v1.PrometheusServiceLeve")
+	var kslo *k8sprometheusv2.PrometheusServiceLevel
+	switch v := obj.(type) {
+	case *k8sprometheusv1.PrometheusServiceLevel:
+		kslo, err = k8sprometheusv2.ConvertFromV1(v)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert v1 PrometheusServiceLevel: %w", err)
+		}
+	case *k8sprometheusv2.PrometheusServiceLevel:
+		kslo = v
+	default:
+		return nil, fmt.Errorf("can't type assert runtime.Object to v1.PrometheusServiceLevel or v2.PrometheusServiceLevel")
 	}
 
 	// Check at least we have one SLO.
@@ -55,57 +68,221 @@ type crSpecLoader bool
 const CRSpecLoader = crSpecLoader(false)
 
 func (c crSpecLoader) LoadSpec(ctx context.Context, spec *k8sprometheusv1.PrometheusServiceLevel) (*SLOGroup, error) {
-	return mapSpecToModel(spec)
+	// v1 objects can have v2 SLIs (e.g composite) stored by the conversion webhook.
+	kslo, err := k8sprometheusv2.ConvertFromV1(spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert v1 PrometheusServiceLevel: %w", err)
+	}
+
+	return mapSpecToModel(kslo)
+}
+
+type configMapSpecLoader bool
+
+// ConfigMapSpecLoader knows how to load the raw Sloth specs stored on the data keys of a
+// Kubernetes ConfigMap and converts them to a model. Every data key is a spec, any of the
+// supported spec types can be used (Prometheus v1, v2 or Kubernetes PrometheusServiceLevel).
+const ConfigMapSpecLoader = configMapSpecLoader(false)
+
+func (c configMapSpecLoader) LoadSpec(ctx context.Context, cm *corev1.ConfigMap) (*ConfigMapSLOGroup, error) {
+	if len(cm.Data) == 0 {
+		return nil, fmt.Errorf("at least one spec is required")
+	}
+
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	specs := make([]ConfigMapSpec, 0, len(keys))
+	for _, k := range keys {
+		spec, err := c.loadKeySpec(ctx, []byte(cm.Data[k]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q key spec: %w", k, err)
+		}
+		spec.Key = k
+		specs = append(specs, *spec)
+	}
+
+	return &ConfigMapSLOGroup{
+		K8sMeta: K8sMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+			UID:        string(cm.UID),
+			Name:       cm.Name,
+			Namespace:  cm.Namespace,
+		},
+		Specs: specs,
+	}, nil
 }
 
-func mapSpecToModel(kspec *k8sprometheusv1.PrometheusServiceLevel) (*SLOGroup, error) {
+func (c configMapSpecLoader) loadKeySpec(ctx context.Context, data []byte) (*ConfigMapSpec, error) {
+	slos, promErr := prometheus.YAMLSpecLoader.LoadSpec(ctx, data)
+	if promErr == nil {
+		return &ConfigMapSpec{Version: prometheusv1.Version, SLOGroup: *slos}, nil
+	}
+
+	slos, promV2Err := prometheus.YAMLSpecV2Loader.LoadSpec(ctx, data)
+	if promV2Err == nil {
+		return &ConfigMapSpec{Version: prometheusv2.Version, SLOGroup: *slos}, nil
+	}
+
+	sloGroup, k8sErr := YAMLSpecLoader.LoadSpec(ctx, data)
+	if k8sErr == nil {
+		version := fmt.Sprintf("%s/%s", k8sprometheusv1.SchemeGroupVersion.Group, k8sprometheusv1.SchemeGroupVersion.Version)
+		return &ConfigMapSpec{Version: version, SLOGroup: sloGroup.SLOGroup}, nil
+	}
+
+	return nil, fmt.Errorf("could not load with any of the supported spec types (prometheus v1: %s, prometheus v2: %s, kubernetes: %s)", promErr, promV2Err, k8sErr)
+}
+
+func mapSpecToModel(kspec *k8sprometheusv2.PrometheusServiceLevel) (*SLOGroup, error) {
 	slos := make([]prometheus.SLO, 0, len(kspec.Spec.SLOs))
 	spec := kspec.Spec
+
+	var intervals prometheus.RuleGroupIntervals
+	if spec.RuleGroupIntervals != nil {
+		var err error
+		intervals, err = prometheus.NewRuleGroupIntervals(spec.RuleGroupIntervals.SLI, spec.RuleGroupIntervals.Metadata, spec.RuleGroupIntervals.Alerts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule group intervals: %w", err)
+		}
+	}
+
+	var runbookURLs prometheus.RunbookURLs
+	if spec.RunbookURLs != nil {
+		runbookURLs = prometheus.RunbookURLs{Page: spec.RunbookURLs.Page, Ticket: spec.RunbookURLs.Ticket}
+	}
+
+	var slothLabels prometheus.SlothLabels
+	if spec.SlothLabels != nil {
+		slothLabels = prometheus.SlothLabels{Prefix: spec.SlothLabels.Prefix, Rename: spec.SlothLabels.Rename, Drop: spec.SlothLabels.Drop}
+	}
+
+	var rulerOptions prometheus.RulerOptions
+	if spec.Ruler != nil {
+		rulerOptions = prometheus.RulerOptions{DedupLabels: spec.Ruler.DedupLabels, PartialResponseStrategy: spec.Ruler.PartialResponseStrategy}
+	}
+
 	for _, specSLO := range kspec.Spec.SLOs {
+		timeWindow, calendar, err := prometheus.ParseTimeWindow(specSLO.TimeWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO time window: %w", specSLO.Name, err)
+		}
+
+		err = prometheus.ValidateObjective(specSLO.Objective)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO objective: %w", specSLO.Name, err)
+		}
+
 		slo := prometheus.SLO{
-			ID:               fmt.Sprintf("%s-%s", spec.Service, specSLO.Name),
-			Name:             specSLO.Name,
-			Description:      specSLO.Description,
-			Service:          spec.Service,
-			TimeWindow:       30 * 24 * time.Hour, // Default and for now the only one supported.
-			Objective:        specSLO.Objective,
-			Labels:           mergeLabels(spec.Labels, specSLO.Labels),
-			PageAlertMeta:    prometheus.AlertMeta{Disable: true},
-			WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+			ID:                 fmt.Sprintf("%s-%s", spec.Service, specSLO.Name),
+			Name:               specSLO.Name,
+			Description:        specSLO.Description,
+			DependsOn:          specSLO.DependsOn,
+			Service:            spec.Service,
+			MetricsPrefix:      spec.MetricsPrefix,
+			RuleGroupIntervals: intervals,
+			RuleGroupName:      spec.RuleGroupName,
+			RunbookURLs:        runbookURLs,
+			SlothLabels:        slothLabels,
+			RulerOptions:       rulerOptions,
+			ExternalLabels:     spec.ExternalLabels,
+			TimeWindow:         timeWindow,
+			Calendar:           calendar,
+			Objective:          specSLO.Objective,
+			Labels:             mergeLabels(spec.Labels, specSLO.Labels),
+			PageAlertMeta:      prometheus.AlertMeta{Disable: true},
+			WarningAlertMeta:   prometheus.AlertMeta{Disable: true},
 		}
 
 		// Set SLIs.
-		if specSLO.SLI.Events != nil {
-			slo.SLI.Events = &prometheus.SLIEvents{
-				ErrorQuery: specSLO.SLI.Events.ErrorQuery,
-				TotalQuery: specSLO.SLI.Events.TotalQuery,
-			}
+		slo.SLI, err = mapSLIToModel(specSLO.SLI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO SLI: %w", specSLO.Name, err)
 		}
 
-		if specSLO.SLI.Raw != nil {
-			slo.SLI.Raw = &prometheus.SLIRaw{
-				ErrorRatioQuery: specSLO.SLI.Raw.ErrorRatioQuery,
+		// Set time slice.
+		if specSLO.TimeSlice != nil {
+			slo.TimeSlice, err = prometheus.NewTimeSlice(specSLO.TimeSlice.Duration, specSLO.TimeSlice.Threshold)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q SLO time slice: %w", specSLO.Name, err)
 			}
 		}
 
-		// Set alerts.
-		if !specSLO.Alerting.PageAlert.Disable {
-			slo.PageAlertMeta = prometheus.AlertMeta{
-				Name:        specSLO.Alerting.Name,
-				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.PageAlert.Labels),
-				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.PageAlert.Annotations),
+		// Set exclusions.
+		for _, e := range specSLO.Exclusions {
+			exclusion, err := prometheus.NewExclusion(e.Query, e.Start, e.End, e.StartTime, e.EndTime, e.Days)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q SLO exclusion: %w", specSLO.Name, err)
 			}
+			slo.Exclusions = append(slo.Exclusions, exclusion)
 		}
 
-		if !specSLO.Alerting.TicketAlert.Disable {
-			slo.WarningAlertMeta = prometheus.AlertMeta{
-				Name:        specSLO.Alerting.Name,
-				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.TicketAlert.Labels),
-				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.TicketAlert.Annotations),
+		// Set disabled rules.
+		if specSLO.DisabledRules != nil {
+			slo.DisabledRules = prometheus.DisabledRules{
+				SLIErrorRecRules: specSLO.DisabledRules.SLIRecordings,
+				MetadataRecRules: specSLO.DisabledRules.MetadataRecordings,
+				AlertRules:       specSLO.DisabledRules.Alerts,
 			}
 		}
 
+		// Set alerts.
+		alerting := specSLO.Alerting
+		alerting.Windows = mergeAlertWindows(spec.AlertWindows, alerting.Windows)
+		err = mapAlertingToModel(&slo, alerting)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO alerting: %w", specSLO.Name, err)
+		}
+
 		slos = append(slos, slo)
+
+		// Set additional objectives as independent SLOs.
+		for _, specObj := range specSLO.AdditionalObjectives {
+			err := prometheus.ValidateObjective(specObj.Objective)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q SLO %q objective: %w", specSLO.Name, specObj.Name, err)
+			}
+
+			objSLO := slo
+			objSLO.ID = fmt.Sprintf("%s-%s", slo.ID, specObj.Name)
+			objSLO.Name = fmt.Sprintf("%s-%s", slo.Name, specObj.Name)
+			objSLO.Objective = specObj.Objective
+			objSLO.Labels = mergeLabels(slo.Labels)
+			objSLO.PageAlertMeta = prometheus.AlertMeta{Disable: true}
+			objSLO.WarningAlertMeta = prometheus.AlertMeta{Disable: true}
+			objSLO.AlertOverrides = alert.MWMBAlertGroupOverride{}
+			objSLO.ForecastAlertMeta = nil
+			objSLO.MinTraffic = nil
+			if specObj.Alerting != nil {
+				alerting := *specObj.Alerting
+				alerting.Windows = mergeAlertWindows(spec.AlertWindows, alerting.Windows)
+				err := mapAlertingToModel(&objSLO, alerting)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %q SLO %q objective alerting: %w", specSLO.Name, specObj.Name, err)
+				}
+			}
+
+			slos = append(slos, objSLO)
+		}
+	}
+
+	// The rule selector labels are the ones required by Prometheus to select
+	// the generated PrometheusRule, so they are set on the object.
+	labels := kspec.Labels
+	if len(spec.RuleSelectorLabels) > 0 {
+		labels = mergeLabels(kspec.Labels, spec.RuleSelectorLabels)
+	}
+	annotations := kspec.Annotations
+	if len(spec.RuleAnnotations) > 0 {
+		annotations = mergeLabels(kspec.Annotations, spec.RuleAnnotations)
+	}
+
+	err := prometheus.ValidateSLIReferences(slos)
+	if err != nil {
+		return nil, err
 	}
 
 	res := &SLOGroup{
@@ -115,11 +292,203 @@ func mapSpecToModel(kspec *k8sprometheusv1.PrometheusServiceLevel) (*SLOGroup, e
 			UID:         string(kspec.UID),
 			Name:        kspec.Name,
 			Namespace:   kspec.Namespace,
-			Labels:      kspec.Labels,
-			Annotations: kspec.Annotations,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		SLOGroup: prometheus.SLOGroup{SLOs: slos},
 	}
 
 	return res, nil
 }
+
+func mapSLIToModel(specSLI k8sprometheusv2.SLI) (prometheus.SLI, error) {
+	sli := prometheus.SLI{}
+
+	if specSLI.Events != nil {
+		sli.Events = &prometheus.SLIEvents{
+			ErrorQuery: specSLI.Events.ErrorQuery,
+			GoodQuery:  specSLI.Events.GoodQuery,
+			TotalQuery: specSLI.Events.TotalQuery,
+		}
+	}
+
+	if specSLI.Raw != nil {
+		errorRatioQuery, err := prometheus.ExpandRawSLIQueries(specSLI.Raw.ErrorRatioQuery, specSLI.Raw.Queries)
+		if err != nil {
+			return sli, fmt.Errorf("invalid raw SLI: %w", err)
+		}
+		sli.Raw = &prometheus.SLIRaw{
+			ErrorRatioQuery: errorRatioQuery,
+		}
+	}
+
+	if specSLI.NativeHistogram != nil {
+		sli.NativeHistogram = &prometheus.SLINativeHistogram{
+			Selector:  specSLI.NativeHistogram.Selector,
+			Threshold: specSLI.NativeHistogram.Threshold,
+		}
+	}
+
+	if specSLI.Composite != nil {
+		slis := make([]prometheus.SLI, 0, len(specSLI.Composite.All))
+		for _, s := range specSLI.Composite.All {
+			compSLI, err := mapSLIToModel(k8sprometheusv2.SLI{Raw: s.Raw, Events: s.Events, NativeHistogram: s.NativeHistogram})
+			if err != nil {
+				return sli, err
+			}
+			slis = append(slis, compSLI)
+		}
+		sli.Composite = &prometheus.SLIComposite{SLIs: slis}
+	}
+
+	if specSLI.Weighted != nil {
+		slos := make([]prometheus.WeightedSLO, 0, len(specSLI.Weighted.SLOs))
+		for _, s := range specSLI.Weighted.SLOs {
+			slos = append(slos, prometheus.WeightedSLO{ID: s.ID, Weight: s.Weight})
+		}
+		sli.Weighted = &prometheus.SLIWeighted{SLOs: slos}
+	}
+
+	if specSLI.Aggregated != nil {
+		sli.Aggregated = &prometheus.SLIAggregated{ID: specSLI.Aggregated.ID, Labels: specSLI.Aggregated.Labels}
+	}
+
+	return sli, nil
+}
+
+// mergeAlertWindows merges the SLO alert windows on the spec ones, the windows of each alert
+// set on the SLO take precedence.
+func mergeAlertWindows(specWindows, sloWindows *k8sprometheusv2.AlertWindows) *k8sprometheusv2.AlertWindows {
+	if specWindows == nil {
+		return sloWindows
+	}
+	if sloWindows == nil {
+		return specWindows
+	}
+
+	merged := *specWindows
+	if sloWindows.PageQuick != (k8sprometheusv2.AlertWindow{}) {
+		merged.PageQuick = sloWindows.PageQuick
+	}
+	if sloWindows.PageSlow != (k8sprometheusv2.AlertWindow{}) {
+		merged.PageSlow = sloWindows.PageSlow
+	}
+	if sloWindows.TicketQuick != (k8sprometheusv2.AlertWindow{}) {
+		merged.TicketQuick = sloWindows.TicketQuick
+	}
+	if sloWindows.TicketSlow != (k8sprometheusv2.AlertWindow{}) {
+		merged.TicketSlow = sloWindows.TicketSlow
+	}
+
+	return &merged
+}
+
+func mapAlertingToModel(slo *prometheus.SLO, alerting k8sprometheusv2.Alerting) error {
+	slo.AlertStrategy = prometheus.AlertStrategy(alerting.Strategy)
+	slo.PageAlertMeta, slo.WarningAlertMeta = prometheus.AlertMeta{Disable: true}, prometheus.AlertMeta{Disable: true}
+
+	if !alerting.PageAlert.Disable {
+		slo.PageAlertMeta = prometheus.AlertMeta{
+			Name:        alerting.Name,
+			Labels:      mergeAlertTierLabels(alerting.Labels, alerting.PageAlert.Labels),
+			Annotations: mergeAlertTierLabels(alerting.Annotations, alerting.PageAlert.Annotations),
+		}
+	}
+
+	if !alerting.TicketAlert.Disable {
+		slo.WarningAlertMeta = prometheus.AlertMeta{
+			Name:        alerting.Name,
+			Labels:      mergeAlertTierLabels(alerting.Labels, alerting.TicketAlert.Labels),
+			Annotations: mergeAlertTierLabels(alerting.Annotations, alerting.TicketAlert.Annotations),
+		}
+	}
+
+	pageFor, err := prometheus.ParseAlertFor(alerting.PageAlert.For)
+	if err != nil {
+		return fmt.Errorf("invalid page alert: %w", err)
+	}
+	slo.PageAlertMeta.For = pageFor
+
+	ticketFor, err := prometheus.ParseAlertFor(alerting.TicketAlert.For)
+	if err != nil {
+		return fmt.Errorf("invalid ticket alert: %w", err)
+	}
+	slo.WarningAlertMeta.For = ticketFor
+
+	err = prometheus.ApplyAlertSeverities(slo, alerting.Severities)
+	if err != nil {
+		return err
+	}
+
+	page, err := prometheus.SingleWindowAlertOverride(slo.AlertStrategy, alerting.PageAlert.Window, alerting.PageAlert.BurnRate)
+	if err != nil {
+		return fmt.Errorf("invalid page alert: %w", err)
+	}
+
+	ticket, err := prometheus.SingleWindowAlertOverride(slo.AlertStrategy, alerting.TicketAlert.Window, alerting.TicketAlert.BurnRate)
+	if err != nil {
+		return fmt.Errorf("invalid ticket alert: %w", err)
+	}
+
+	slo.AlertOverrides = alert.MWMBAlertGroupOverride{PageQuick: page, TicketQuick: ticket}
+
+	if br := alerting.BurnRates; br != nil {
+		if slo.AlertStrategy != "" && slo.AlertStrategy != prometheus.MWMBAlertStrategy {
+			return fmt.Errorf("burn rates are only supported by %q alert strategy", prometheus.MWMBAlertStrategy)
+		}
+		slo.AlertOverrides.PageQuick.BurnRateFactor = br.PageQuick
+		slo.AlertOverrides.PageSlow.BurnRateFactor = br.PageSlow
+		slo.AlertOverrides.TicketQuick.BurnRateFactor = br.TicketQuick
+		slo.AlertOverrides.TicketSlow.BurnRateFactor = br.TicketSlow
+	}
+
+	if ws := alerting.Windows; ws != nil {
+		if slo.AlertStrategy != "" && slo.AlertStrategy != prometheus.MWMBAlertStrategy {
+			return fmt.Errorf("windows are only supported by %q alert strategy", prometheus.MWMBAlertStrategy)
+		}
+
+		windows := []struct {
+			name     string
+			window   k8sprometheusv2.AlertWindow
+			override *alert.MWMBAlertOverride
+		}{
+			{name: "page quick", window: ws.PageQuick, override: &slo.AlertOverrides.PageQuick},
+			{name: "page slow", window: ws.PageSlow, override: &slo.AlertOverrides.PageSlow},
+			{name: "ticket quick", window: ws.TicketQuick, override: &slo.AlertOverrides.TicketQuick},
+			{name: "ticket slow", window: ws.TicketSlow, override: &slo.AlertOverrides.TicketSlow},
+		}
+		for _, w := range windows {
+			short, long, err := prometheus.ParseAlertWindows(w.window.Short, w.window.Long)
+			if err != nil {
+				return fmt.Errorf("invalid %s alert windows: %w", w.name, err)
+			}
+			w.override.ShortWindow, w.override.LongWindow = short, long
+		}
+	}
+
+	slo.MinTraffic = nil
+	if mt := alerting.MinTraffic; mt != nil {
+		minTraffic, err := prometheus.NewMinTraffic(slo.SLI, mt.Query, mt.Rate)
+		if err != nil {
+			return fmt.Errorf("invalid min traffic: %w", err)
+		}
+		slo.MinTraffic = minTraffic
+	}
+
+	slo.ForecastAlertMeta = nil
+	if fa := alerting.ForecastAlert; fa != nil {
+		window, err := prometheus.ParseAlertWindow(fa.Window)
+		if err != nil {
+			return fmt.Errorf("invalid forecast alert: %w", err)
+		}
+
+		slo.ForecastAlertMeta = &prometheus.ForecastAlertMeta{
+			Name:        prometheus.ForecastAlertName(alerting.Name, fa.Name),
+			Window:      window,
+			Labels:      mergeAlertTierLabels(alerting.Labels, fa.Labels),
+			Annotations: mergeAlertTierLabels(alerting.Annotations, fa.Annotations),
+		}
+	}
+
+	return nil
+}
@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/k8sprometheus"
 	"github.com/slok/sloth/internal/prometheus"
 )
@@ -212,6 +215,245 @@ spec:
 				},
 			},
 		},
+
+		"Rule selector labels should be set on the object labels with precedence over the object ones.": {
+			specYaml: `
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: k8s-test-svc
+  namespace: test-ns
+  labels:
+    lk1: lv1
+    prometheus: default
+spec:
+  service: "test-svc"
+  ruleSelectorLabels:
+    prometheus: k8s
+    role: alert-rules
+  slos:
+    - name: "slo1"
+      objective: 99.9
+      sli:
+        raw:
+          errorRatioQuery: test_expr_ratio_1
+      alerting:
+        pageAlert:
+          disable: true
+        ticketAlert:
+          disable: true
+`,
+			expModel: &k8sprometheus.SLOGroup{
+				K8sMeta: k8sprometheus.K8sMeta{
+					Kind:       "PrometheusServiceLevel",
+					APIVersion: "sloth.slok.dev/v1",
+					Name:       "k8s-test-svc",
+					Namespace:  "test-ns",
+					Labels: map[string]string{
+						"lk1":        "lv1",
+						"prometheus": "k8s",
+						"role":       "alert-rules",
+					},
+				},
+				SLOGroup: prometheus.SLOGroup{SLOs: []prometheus.SLO{
+					{
+						ID:         "test-svc-slo1",
+						Name:       "slo1",
+						Service:    "test-svc",
+						TimeWindow: 30 * 24 * time.Hour,
+						SLI: prometheus.SLI{
+							Raw: &prometheus.SLIRaw{
+								ErrorRatioQuery: "test_expr_ratio_1",
+							},
+						},
+						Objective:        99.9,
+						Labels:           map[string]string{},
+						PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+						WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+					},
+				},
+				},
+			},
+		},
+
+		"Rule annotations should be set on the object annotations with precedence over the object ones.": {
+			specYaml: `
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: k8s-test-svc
+  namespace: test-ns
+  annotations:
+    ak1: av1
+    team: a
+spec:
+  service: "test-svc"
+  ruleAnnotations:
+    team: b
+    owner: sre
+  slos:
+    - name: "slo1"
+      objective: 99.9
+      sli:
+        raw:
+          errorRatioQuery: test_expr_ratio_1
+      alerting:
+        pageAlert:
+          disable: true
+        ticketAlert:
+          disable: true
+`,
+			expModel: &k8sprometheus.SLOGroup{
+				K8sMeta: k8sprometheus.K8sMeta{
+					Kind:       "PrometheusServiceLevel",
+					APIVersion: "sloth.slok.dev/v1",
+					Name:       "k8s-test-svc",
+					Namespace:  "test-ns",
+					Annotations: map[string]string{
+						"ak1":   "av1",
+						"team":  "b",
+						"owner": "sre",
+					},
+				},
+				SLOGroup: prometheus.SLOGroup{SLOs: []prometheus.SLO{
+					{
+						ID:         "test-svc-slo1",
+						Name:       "slo1",
+						Service:    "test-svc",
+						TimeWindow: 30 * 24 * time.Hour,
+						SLI: prometheus.SLI{
+							Raw: &prometheus.SLIRaw{
+								ErrorRatioQuery: "test_expr_ratio_1",
+							},
+						},
+						Objective:        99.9,
+						Labels:           map[string]string{},
+						PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+						WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+					},
+				},
+				},
+			},
+		},
+
+		"Spec alert windows should be set on all the SLOs with the SLO ones taking precedence by alert.": {
+			specYaml: `
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: k8s-test-svc
+  namespace: test-ns
+spec:
+  service: "test-svc"
+  alertWindows:
+    pageQuick:
+      short: 10m
+      long: 2h
+    ticketSlow:
+      short: 12h
+      long: 4d
+  slos:
+    - name: "slo1"
+      objective: 99.9
+      sli:
+        raw:
+          errorRatioQuery: test_expr_ratio_1
+      alerting:
+        pageAlert:
+          disable: true
+        ticketAlert:
+          disable: true
+        windows:
+          pageQuick:
+            short: 2m
+            long: 30m
+`,
+			expModel: &k8sprometheus.SLOGroup{
+				K8sMeta: k8sprometheus.K8sMeta{
+					Kind:       "PrometheusServiceLevel",
+					APIVersion: "sloth.slok.dev/v1",
+					Name:       "k8s-test-svc",
+					Namespace:  "test-ns",
+				},
+				SLOGroup: prometheus.SLOGroup{SLOs: []prometheus.SLO{
+					{
+						ID:         "test-svc-slo1",
+						Name:       "slo1",
+						Service:    "test-svc",
+						TimeWindow: 30 * 24 * time.Hour,
+						SLI: prometheus.SLI{
+							Raw: &prometheus.SLIRaw{
+								ErrorRatioQuery: "test_expr_ratio_1",
+							},
+						},
+						Objective:        99.9,
+						Labels:           map[string]string{},
+						PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+						WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+						AlertOverrides: alert.MWMBAlertGroupOverride{
+							PageQuick:  alert.MWMBAlertOverride{ShortWindow: 2 * time.Minute, LongWindow: 30 * time.Minute},
+							TicketSlow: alert.MWMBAlertOverride{ShortWindow: 12 * time.Hour, LongWindow: 4 * 24 * time.Hour},
+						},
+					},
+				},
+				},
+			},
+		},
+
+		"A v2 spec should return the models with the v2 SLIs.": {
+			specYaml: `
+apiVersion: sloth.slok.dev/v2
+kind: PrometheusServiceLevel
+metadata:
+  name: k8s-test-svc
+  namespace: test-ns
+spec:
+  service: "test-svc"
+  slos:
+    - name: "slo1"
+      objective: 99.9
+      sli:
+        composite:
+          all:
+            - raw:
+                errorRatioQuery: test_expr_ratio_1
+            - nativeHistogram:
+                selector: test_duration_seconds
+                threshold: 0.25
+      alerting:
+        pageAlert:
+          disable: true
+        ticketAlert:
+          disable: true
+`,
+			expModel: &k8sprometheus.SLOGroup{
+				K8sMeta: k8sprometheus.K8sMeta{
+					Kind:       "PrometheusServiceLevel",
+					APIVersion: "sloth.slok.dev/v1",
+					Name:       "k8s-test-svc",
+					Namespace:  "test-ns",
+				},
+				SLOGroup: prometheus.SLOGroup{SLOs: []prometheus.SLO{
+					{
+						ID:         "test-svc-slo1",
+						Name:       "slo1",
+						Service:    "test-svc",
+						TimeWindow: 30 * 24 * time.Hour,
+						SLI: prometheus.SLI{
+							Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+								{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+								{NativeHistogram: &prometheus.SLINativeHistogram{Selector: "test_duration_seconds", Threshold: 0.25}},
+							}},
+						},
+						Objective:        99.9,
+						Labels:           map[string]string{},
+						PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+						WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+					},
+				},
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -228,3 +470,95 @@ spec:
 		})
 	}
 }
+
+func TestConfigMapLoadSpec(t *testing.T) {
+	promV1Spec := `
+version: "prometheus/v1"
+service: "svc1"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: test_expr
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`
+	k8sSpec := `
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: test
+spec:
+  service: "svc2"
+  slos:
+    - name: "slo2"
+      objective: 99.9
+      sli:
+        raw:
+          errorRatioQuery: test_expr
+      alerting:
+        pageAlert:
+          disable: true
+        ticketAlert:
+          disable: true
+`
+
+	tests := map[string]struct {
+		data        map[string]string
+		expKeys     []string
+		expVersions []string
+		expSLOIDs   []string
+		expErr      bool
+	}{
+		"A ConfigMap without specs should fail.": {
+			expErr: true,
+		},
+
+		"A ConfigMap with an invalid spec should fail.": {
+			data:   map[string]string{"a.yaml": promV1Spec, "b.yaml": "service: test"},
+			expErr: true,
+		},
+
+		"A ConfigMap with multiple spec types should load all of them sorted by key.": {
+			data:        map[string]string{"b.yaml": promV1Spec, "a.yaml": k8sSpec},
+			expKeys:     []string{"a.yaml", "b.yaml"},
+			expVersions: []string{"sloth.slok.dev/v1", "prometheus/v1"},
+			expSLOIDs:   []string{"svc2-slo2", "svc1-slo1"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "test-ns", UID: "test-uid", Labels: map[string]string{"sloth-spec": "true"}},
+				Data:       test.data,
+			}
+			gotModel, err := k8sprometheus.ConfigMapSpecLoader.LoadSpec(context.TODO(), cm)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				expKMeta := k8sprometheus.K8sMeta{Kind: "ConfigMap", APIVersion: "v1", Name: "test-cm", Namespace: "test-ns", UID: "test-uid"}
+				assert.Equal(expKMeta, gotModel.K8sMeta)
+
+				gotKeys, gotVersions, gotSLOIDs := []string{}, []string{}, []string{}
+				for _, spec := range gotModel.Specs {
+					gotKeys = append(gotKeys, spec.Key)
+					gotVersions = append(gotVersions, spec.Version)
+					for _, slo := range spec.SLOs {
+						gotSLOIDs = append(gotSLOIDs, slo.ID)
+					}
+				}
+				assert.Equal(test.expKeys, gotKeys)
+				assert.Equal(test.expVersions, gotVersions)
+				assert.Equal(test.expSLOIDs, gotSLOIDs)
+			}
+		})
+	}
+}
@@ -3,13 +3,26 @@ package k8sprometheus
 import (
 	"bytes"
 	"context"
+	stdjson "encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -67,6 +80,121 @@ func (i IOWriterPrometheusOperatorYAMLRepo) StoreSLOs(ctx context.Context, kmeta
 	return nil
 }
 
+const kustomizationFileName = "kustomization.yaml"
+
+func NewKustomizeDirPrometheusOperatorYAMLRepo(dir string, logger log.Logger) KustomizeDirPrometheusOperatorYAMLRepo {
+	return KustomizeDirPrometheusOperatorYAMLRepo{
+		dir:     dir,
+		encoder: json.NewYAMLSerializer(json.DefaultMetaFactory, nil, nil),
+		logger:  logger.WithValues(log.Kv{"svc": "storage.KustomizeDir", "format": "k8s-prometheus-operator"}),
+	}
+}
+
+// KustomizeDirPrometheusOperatorYAMLRepo knows to store all the SLO rules (recordings and alerts)
+// as a Kubernetes prometheus operator YAML file inside a directory that is also a kustomize base.
+//
+// Every PrometheusRule is stored in its own file named after the object (stable names), and
+// the directory `kustomization.yaml` will be created or updated to reference it. Any other
+// field of an existing kustomization (e.g `namePrefix`, `commonLabels`, `namespace`...) is
+// preserved so overlays and transformers can be managed by the users.
+type KustomizeDirPrometheusOperatorYAMLRepo struct {
+	dir     string
+	encoder runtime.Encoder
+	logger  log.Logger
+}
+
+func (k KustomizeDirPrometheusOperatorYAMLRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
+	rule, err := mapModelToPrometheusOperator(ctx, kmeta, slos)
+	if err != nil {
+		return fmt.Errorf("could not map model to Prometheus operator CR: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = k.encoder.Encode(rule, &b)
+	if err != nil {
+		return fmt.Errorf("could encode prometheus operator object: %w", err)
+	}
+
+	err = os.MkdirAll(k.dir, 0755)
+	if err != nil {
+		return fmt.Errorf("could not create kustomize directory: %w", err)
+	}
+
+	ruleFile := fmt.Sprintf("%s.yaml", rule.Name)
+	err = os.WriteFile(filepath.Join(k.dir, ruleFile), writeTopDisclaimer(b.Bytes()), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write prometheus operator rule file: %w", err)
+	}
+
+	err = k.ensureKustomizationResource(ruleFile)
+	if err != nil {
+		return fmt.Errorf("could not ensure kustomization: %w", err)
+	}
+
+	logger := k.logger.WithCtxValues(ctx)
+	logger.WithValues(log.Kv{"file": ruleFile}).Infof("Kustomize base written")
+
+	return nil
+}
+
+// ensureKustomizationResource will create or update the kustomization file of the directory
+// adding the resource if missing. We use a YAML map slice so we don't lose the order nor the
+// fields set by the users on the kustomization.
+func (k KustomizeDirPrometheusOperatorYAMLRepo) ensureKustomizationResource(resource string) error {
+	path := filepath.Join(k.dir, kustomizationFileName)
+
+	kustomization := yaml.MapSlice{
+		{Key: "apiVersion", Value: "kustomize.config.k8s.io/v1beta1"},
+		{Key: "kind", Value: "Kustomization"},
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		kustomization = yaml.MapSlice{}
+		err = yaml.Unmarshal(data, &kustomization)
+		if err != nil {
+			return fmt.Errorf("could not unmarshal %q: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("could not read %q: %w", path, err)
+	}
+
+	// Get the current resources and set the new one if required.
+	resourcesIdx := -1
+	resources := []string{}
+	for i, item := range kustomization {
+		if item.Key != "resources" {
+			continue
+		}
+		resourcesIdx = i
+		items, _ := item.Value.([]interface{})
+		for _, r := range items {
+			resources = append(resources, fmt.Sprintf("%v", r))
+		}
+	}
+
+	for _, r := range resources {
+		if r == resource {
+			return nil
+		}
+	}
+	resources = append(resources, resource)
+	sort.Strings(resources)
+
+	if resourcesIdx < 0 {
+		kustomization = append(kustomization, yaml.MapItem{Key: "resources", Value: resources})
+	} else {
+		kustomization[resourcesIdx].Value = resources
+	}
+
+	data, err = yaml.Marshal(kustomization)
+	if err != nil {
+		return fmt.Errorf("could not marshal kustomization: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
 func mapModelToPrometheusOperator(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) (*monitoringv1.PrometheusRule, error) {
 	// Add extra labels.
 	labels := map[string]string{
@@ -95,24 +223,31 @@ func mapModelToPrometheusOperator(ctx context.Context, kmeta K8sMeta, slos []Sto
 	}
 
 	for _, slo := range slos {
-		if len(slo.Rules.SLIErrorRecRules) > 0 {
-			rule.Spec.Groups = append(rule.Spec.Groups, monitoringv1.RuleGroup{
-				Name:  fmt.Sprintf("sloth-slo-sli-recordings-%s", slo.SLO.ID),
-				Rules: promRulesToKubeRules(slo.Rules.SLIErrorRecRules),
-			})
+		groups := []struct {
+			category prometheus.RuleGroupCategory
+			interval time.Duration
+			rules    []rulefmt.Rule
+		}{
+			{category: prometheus.SLIRecordingsRuleGroupCategory, interval: slo.SLO.RuleGroupIntervals.SLIErrorRecRules, rules: slo.Rules.SLIErrorRecRules},
+			{category: prometheus.MetadataRecordingsRuleGroupCategory, interval: slo.SLO.RuleGroupIntervals.MetadataRecRules, rules: slo.Rules.MetadataRecRules},
+			{category: prometheus.AlertsRuleGroupCategory, interval: slo.SLO.RuleGroupIntervals.AlertRules, rules: slo.Rules.AlertRules},
 		}
 
-		if len(slo.Rules.MetadataRecRules) > 0 {
-			rule.Spec.Groups = append(rule.Spec.Groups, monitoringv1.RuleGroup{
-				Name:  fmt.Sprintf("sloth-slo-meta-recordings-%s", slo.SLO.ID),
-				Rules: promRulesToKubeRules(slo.Rules.MetadataRecRules),
-			})
-		}
+		for _, group := range groups {
+			if len(group.rules) == 0 {
+				continue
+			}
+
+			name, err := slo.SLO.GetRuleGroupName(group.category)
+			if err != nil {
+				return nil, fmt.Errorf("could not get %q SLO rule group name: %w", slo.SLO.ID, err)
+			}
 
-		if len(slo.Rules.AlertRules) > 0 {
 			rule.Spec.Groups = append(rule.Spec.Groups, monitoringv1.RuleGroup{
-				Name:  fmt.Sprintf("sloth-slo-alerts-%s", slo.SLO.ID),
-				Rules: promRulesToKubeRules(slo.Rules.AlertRules),
+				Name:                    name,
+				Interval:                ruleGroupInterval(group.interval),
+				PartialResponseStrategy: slo.SLO.RulerOptions.PartialResponseStrategy,
+				Rules:                   promRulesToKubeRules(group.rules),
 			})
 		}
 	}
@@ -126,6 +261,15 @@ func mapModelToPrometheusOperator(ctx context.Context, kmeta K8sMeta, slos []Sto
 	return rule, nil
 }
 
+// ruleGroupInterval returns the Prometheus operator rule group interval, empty if not set.
+func ruleGroupInterval(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	return prommodel.Duration(d).String()
+}
+
 func promRulesToKubeRules(rules []rulefmt.Rule) []monitoringv1.Rule {
 	res := make([]monitoringv1.Rule, 0, len(rules))
 	for _, r := range rules {
@@ -157,6 +301,32 @@ var disclaimer = fmt.Sprintf(`
 
 `, info.Version)
 
+const (
+	// RetainLabelName is the label set on the PrometheusRules that are not owned by the
+	// PrometheusServiceLevel, so they are not deleted by Kubernetes when the PrometheusServiceLevel
+	// is deleted.
+	RetainLabelName  = "sloth.slok.dev/retain"
+	retainLabelValue = "true"
+	// PendingDeletionLabelName is the label set on the retained PrometheusRules whose
+	// PrometheusServiceLevel has been deleted and are waiting to be deleted.
+	PendingDeletionLabelName = "sloth.slok.dev/pending-deletion"
+	// PendingDeletionSinceAnnotationName is the annotation with the time since a PrometheusRule
+	// is pending to be deleted.
+	PendingDeletionSinceAnnotationName = "sloth.slok.dev/pending-deletion-since"
+	// SourceNamespaceAnnotationName is the annotation with the namespace of the PrometheusServiceLevel
+	// on the PrometheusRules stored on a different namespace.
+	SourceNamespaceAnnotationName = "sloth.slok.dev/source-namespace"
+	// SourceNameAnnotationName is the annotation with the name of the PrometheusServiceLevel on the
+	// PrometheusRules stored on a different namespace.
+	SourceNameAnnotationName = "sloth.slok.dev/source-name"
+	// PartOfAnnotationName is the annotation with the name of the first PrometheusRule on the
+	// PrometheusRules of a rule set split in multiple parts.
+	PartOfAnnotationName = "sloth.slok.dev/part-of"
+	// SourceKindLabelName is the label with the kind of the object (e.g PrometheusServiceLevel) that generated
+	// the PrometheusRules stored by the controller, used to find the orphan ones.
+	SourceKindLabelName = "sloth.slok.dev/source-kind"
+)
+
 func NewPrometheusOperatorCRDRepo(ensurer PrometheusRulesEnsurer, logger log.Logger) PrometheusOperatorCRDRepo {
 	return PrometheusOperatorCRDRepo{
 		ensurer: ensurer,
@@ -164,11 +334,29 @@ func NewPrometheusOperatorCRDRepo(ensurer PrometheusRulesEnsurer, logger log.Log
 	}
 }
 
+// NewRetainPrometheusOperatorCRDRepo is like NewPrometheusOperatorCRDRepo but the stored PrometheusRules
+// will not be owned by the PrometheusServiceLevel, instead of being deleted by Kubernetes when the
+// PrometheusServiceLevel is deleted, these will be retained and labeled with RetainLabelName so
+// something else can handle their deletion.
+func NewRetainPrometheusOperatorCRDRepo(ensurer PrometheusRulesEnsurer, logger log.Logger) PrometheusOperatorCRDRepo {
+	return PrometheusOperatorCRDRepo{
+		ensurer: ensurer,
+		retain:  true,
+		logger:  logger.WithValues(log.Kv{"svc": "storage.PrometheusOperatorCRDAPIServer", "format": "k8s-prometheus-operator"}),
+	}
+}
+
 // PrometheusOperatorCRDRepo knows to store all the SLO rules (recordings and alerts)
 // grouped as a Kubernetes prometheus operator CR using Kubernetes API server.
 type PrometheusOperatorCRDRepo struct {
-	logger  log.Logger
-	ensurer PrometheusRulesEnsurer
+	logger     log.Logger
+	retain     bool
+	ensurer    PrometheusRulesEnsurer
+	maxSize    int
+	partsRepo  PrometheusRulePartsRepository
+	ruleGetter PrometheusRuleGetter
+	adopt      bool
+	deleter    PrometheusRulePartsRepository
 }
 
 type PrometheusRulesEnsurer interface {
@@ -177,6 +365,52 @@ type PrometheusRulesEnsurer interface {
 
 //go:generate mockery --case underscore --output k8sprometheusmock --outpkg k8sprometheusmock --name PrometheusRulesEnsurer
 
+// PrometheusRulePartsRepository knows how to list and delete the PrometheusRules, used to garbage
+// collect the parts of the split rule sets that are not required anymore.
+type PrometheusRulePartsRepository interface {
+	ListPrometheusRules(ctx context.Context, ns string, labelSelector map[string]string) (*monitoringv1.PrometheusRuleList, error)
+	DeletePrometheusRule(ctx context.Context, ns, name string) error
+}
+
+//go:generate mockery --case underscore --output k8sprometheusmock --outpkg k8sprometheusmock --name PrometheusRulePartsRepository
+
+// WithSplit returns a copy of the repository that splits the rule groups across multiple PrometheusRules
+// when the PrometheusRule (JSON encoded) would be bigger than maxSize bytes (e.g: etcd object size limits).
+//
+// The rule groups are packed in order, so the split is deterministic, the first part keeps the
+// PrometheusRule name and the next ones are named `<name>-part-<n>`. The parts that are not required
+// anymore (e.g: the rule set shrunk) are deleted.
+func (p PrometheusOperatorCRDRepo) WithSplit(maxSize int, partsRepo PrometheusRulePartsRepository) PrometheusOperatorCRDRepo {
+	p.maxSize = maxSize
+	p.partsRepo = partsRepo
+	return p
+}
+
+// PrometheusRuleGetter knows how to get the stored PrometheusRules.
+type PrometheusRuleGetter interface {
+	GetPrometheusRule(ctx context.Context, ns, name string) (*monitoringv1.PrometheusRule, error)
+}
+
+//go:generate mockery --case underscore --output k8sprometheusmock --outpkg k8sprometheusmock --name PrometheusRuleGetter
+
+// WithOwnershipCheck returns a copy of the repository that fails instead of overwriting the already existing
+// PrometheusRules that are not managed by Sloth (e.g hand-written rules). When adopt is enabled, these are
+// adopted instead, overwriting their content and setting the PrometheusServiceLevel as the owner, to smooth
+// the migrations from hand-written rules.
+func (p PrometheusOperatorCRDRepo) WithOwnershipCheck(getter PrometheusRuleGetter, adopt bool) PrometheusOperatorCRDRepo {
+	p.ruleGetter = getter
+	p.adopt = adopt
+	return p
+}
+
+// WithDeleter returns a copy of the repository that can delete the stored PrometheusRules of an object
+// (including the split rule set parts), used when the PrometheusRules are not owned by the object
+// (e.g stored on a different namespace).
+func (p PrometheusOperatorCRDRepo) WithDeleter(deleter PrometheusRulePartsRepository) PrometheusOperatorCRDRepo {
+	p.deleter = deleter
+	return p
+}
+
 func (p PrometheusOperatorCRDRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
 	// Map to the Prometheus operator CRD.
 	rule, err := mapModelToPrometheusOperator(ctx, kmeta, slos)
@@ -184,19 +418,744 @@ func (p PrometheusOperatorCRDRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta,
 		return fmt.Errorf("could not map model to Prometheus operator CR: %w", err)
 	}
 
-	// Add object reference.
-	rule.ObjectMeta.OwnerReferences = append(rule.ObjectMeta.OwnerReferences, metav1.OwnerReference{
+	if kmeta.Kind != "" {
+		rule.ObjectMeta.Labels[SourceKindLabelName] = kmeta.Kind
+	}
+
+	// Add object reference or mark as retained.
+	if p.retain {
+		rule.ObjectMeta.Labels[RetainLabelName] = retainLabelValue
+	} else {
+		rule.ObjectMeta.OwnerReferences = append(rule.ObjectMeta.OwnerReferences, metav1.OwnerReference{
+			Kind:       kmeta.Kind,
+			APIVersion: kmeta.APIVersion,
+			Name:       kmeta.Name,
+			UID:        types.UID(kmeta.UID),
+		})
+	}
+
+	rules := []*monitoringv1.PrometheusRule{rule}
+	if p.maxSize > 0 {
+		rules, err = splitPrometheusRule(rule, kmeta, p.maxSize)
+		if err != nil {
+			return fmt.Errorf("could not split Prometheus operator rule CR: %w", err)
+		}
+	}
+
+	// Create on API server.
+	for _, rule := range rules {
+		if p.ruleGetter != nil {
+			err = p.checkOwnership(ctx, rule)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = p.ensurer.EnsurePrometheusRule(ctx, rule)
+		if err != nil {
+			return fmt.Errorf("could not ensure Prometheus operator rule CR: %w", err)
+		}
+	}
+
+	if p.partsRepo != nil {
+		err = p.deleteStaleParts(ctx, rules)
+		if err != nil {
+			return fmt.Errorf("could not delete stale Prometheus operator rule CR parts: %w", err)
+		}
+	}
+
+	if len(rules) > 1 {
+		p.logger.WithCtxValues(ctx).WithValues(log.Kv{"parts": len(rules)}).Debugf("Prometheus operator rule CR split")
+	}
+
+	return nil
+}
+
+// DeleteSLOs deletes the stored PrometheusRule of the object and its parts.
+func (p PrometheusOperatorCRDRepo) DeleteSLOs(ctx context.Context, kmeta K8sMeta) error {
+	if p.deleter == nil {
+		return fmt.Errorf("Prometheus operator rule CR deletion not supported")
+	}
+
+	stored, err := p.deleter.ListPrometheusRules(ctx, kmeta.Namespace, map[string]string{"app.kubernetes.io/managed-by": "sloth"})
+	if err != nil {
+		return fmt.Errorf("could not list Prometheus operator rule CRs: %w", err)
+	}
+
+	for _, r := range stored.Items {
+		if r.Name != kmeta.Name && r.Annotations[PartOfAnnotationName] != kmeta.Name {
+			continue
+		}
+
+		err := p.deleter.DeletePrometheusRule(ctx, r.Namespace, r.Name)
+		if err != nil {
+			return fmt.Errorf("could not delete %q Prometheus operator rule CR: %w", r.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// checkOwnership checks the already existing rule is managed by Sloth, adopting it if enabled.
+func (p PrometheusOperatorCRDRepo) checkOwnership(ctx context.Context, rule *monitoringv1.PrometheusRule) error {
+	stored, err := p.ruleGetter.GetPrometheusRule(ctx, rule.Namespace, rule.Name)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("could not get Prometheus operator rule CR: %w", err)
+	}
+
+	if stored.Labels["app.kubernetes.io/managed-by"] == "sloth" {
+		return nil
+	}
+
+	if !p.adopt {
+		return fmt.Errorf("%s/%s Prometheus operator rule CR already exists and is not managed by Sloth, adoption is disabled", rule.Namespace, rule.Name)
+	}
+	p.logger.WithCtxValues(ctx).WithValues(log.Kv{"rule-ns": rule.Namespace, "rule-name": rule.Name}).Warningf("Existing Prometheus operator rule CR not managed by Sloth adopted")
+
+	return nil
+}
+
+// deleteStaleParts deletes the parts of the rule set that have not been stored.
+func (p PrometheusOperatorCRDRepo) deleteStaleParts(ctx context.Context, rules []*monitoringv1.PrometheusRule) error {
+	base := rules[0]
+	stored, err := p.partsRepo.ListPrometheusRules(ctx, base.Namespace, map[string]string{"app.kubernetes.io/managed-by": "sloth"})
+	if err != nil {
+		return err
+	}
+
+	current := map[string]bool{}
+	for _, r := range rules {
+		current[r.Name] = true
+	}
+
+	for _, r := range stored.Items {
+		if current[r.Name] || r.Annotations[PartOfAnnotationName] != base.Name {
+			continue
+		}
+
+		err := p.partsRepo.DeletePrometheusRule(ctx, r.Namespace, r.Name)
+		if err != nil {
+			return fmt.Errorf("could not delete %q part: %w", r.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// splitPrometheusRule splits the rule groups of a PrometheusRule in multiple PrometheusRules that don't
+// exceed the max size. A rule group can't be split, so a rule group bigger than the max size will
+// be on its own PrometheusRule.
+//
+// The parts have the source PrometheusServiceLevel annotations so these are not mistaken by PrometheusRules
+// of other PrometheusServiceLevels.
+func splitPrometheusRule(rule *monitoringv1.PrometheusRule, kmeta K8sMeta, maxSize int) ([]*monitoringv1.PrometheusRule, error) {
+	newPart := func(n int) *monitoringv1.PrometheusRule {
+		part := rule.DeepCopy()
+		part.Spec.Groups = nil
+		if n == 0 {
+			return part
+		}
+
+		part.Name = fmt.Sprintf("%s-part-%d", rule.Name, n)
+		annotations := map[string]string{
+			SourceNamespaceAnnotationName: kmeta.Namespace,
+			SourceNameAnnotationName:      kmeta.Name,
+		}
+		for k, v := range part.Annotations {
+			annotations[k] = v
+		}
+		annotations[PartOfAnnotationName] = rule.Name
+		part.Annotations = annotations
+
+		return part
+	}
+
+	parts := []*monitoringv1.PrometheusRule{newPart(0)}
+	for _, group := range rule.Spec.Groups {
+		part := parts[len(parts)-1]
+		part.Spec.Groups = append(part.Spec.Groups, group)
+
+		size, err := prometheusRuleSize(part)
+		if err != nil {
+			return nil, err
+		}
+		if size <= maxSize || len(part.Spec.Groups) == 1 {
+			continue
+		}
+
+		// Doesn't fit, move the group to a new part.
+		part.Spec.Groups = part.Spec.Groups[:len(part.Spec.Groups)-1]
+		part = newPart(len(parts))
+		part.Spec.Groups = append(part.Spec.Groups, group)
+		parts = append(parts, part)
+	}
+
+	return parts, nil
+}
+
+func prometheusRuleSize(rule *monitoringv1.PrometheusRule) (int, error) {
+	data, err := stdjson.Marshal(rule)
+	if err != nil {
+		return 0, fmt.Errorf("could not marshal Prometheus operator rule CR: %w", err)
+	}
+
+	return len(data), nil
+}
+
+const (
+	// ConfigMapKeyLayoutSingle stores all the rules of the PrometheusServiceLevel on a single
+	// `<name>.yml` ConfigMap key.
+	ConfigMapKeyLayoutSingle = "single"
+	// ConfigMapKeyLayoutSLO stores the rules of each SLO on its own `<slo-id>.yml` ConfigMap key.
+	ConfigMapKeyLayoutSLO = "slo"
+)
+
+func NewIOWriterConfigMapYAMLRepo(writer io.Writer, keyLayout string, logger log.Logger) IOWriterConfigMapYAMLRepo {
+	return IOWriterConfigMapYAMLRepo{
+		writer:    writer,
+		keyLayout: keyLayout,
+		encoder:   json.NewYAMLSerializer(json.DefaultMetaFactory, nil, nil),
+		logger:    logger.WithValues(log.Kv{"svc": "storage.IOWriter", "format": "k8s-configmap"}),
+	}
+}
+
+// IOWriterConfigMapYAMLRepo knows to store all the SLO rules (recordings and alerts) as Prometheus
+// rule files inside a Kubernetes ConfigMap YAML in an IOWriter.
+type IOWriterConfigMapYAMLRepo struct {
+	writer    io.Writer
+	keyLayout string
+	encoder   runtime.Encoder
+	logger    log.Logger
+}
+
+func (i IOWriterConfigMapYAMLRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
+	cm, err := mapModelToConfigMap(ctx, kmeta, slos, i.keyLayout)
+	if err != nil {
+		return fmt.Errorf("could not map model to ConfigMap: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = i.encoder.Encode(cm, &b)
+	if err != nil {
+		return fmt.Errorf("could encode ConfigMap object: %w", err)
+	}
+
+	_, err = i.writer.Write(writeTopDisclaimer(b.Bytes()))
+	if err != nil {
+		return fmt.Errorf("could not write top disclaimer: %w", err)
+	}
+
+	return nil
+}
+
+func NewConfigMapRepo(ensurer ConfigMapEnsurer, keyLayout string, logger log.Logger) ConfigMapRepo {
+	return ConfigMapRepo{
+		ensurer:   ensurer,
+		keyLayout: keyLayout,
+		logger:    logger.WithValues(log.Kv{"svc": "storage.ConfigMapAPIServer", "format": "k8s-configmap"}),
+	}
+}
+
+// ConfigMapRepo knows to store all the SLO rules (recordings and alerts) as Prometheus rule files
+// inside a Kubernetes ConfigMap owned by the PrometheusServiceLevel using Kubernetes API server,
+// so Prometheus instances not managed by Prometheus operator can mount them.
+type ConfigMapRepo struct {
+	logger    log.Logger
+	keyLayout string
+	ensurer   ConfigMapEnsurer
+}
+
+type ConfigMapEnsurer interface {
+	EnsureConfigMap(ctx context.Context, cm *corev1.ConfigMap) error
+}
+
+//go:generate mockery --case underscore --output k8sprometheusmock --outpkg k8sprometheusmock --name ConfigMapEnsurer
+
+func (c ConfigMapRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
+	cm, err := mapModelToConfigMap(ctx, kmeta, slos, c.keyLayout)
+	if err != nil {
+		return fmt.Errorf("could not map model to ConfigMap: %w", err)
+	}
+
+	cm.ObjectMeta.OwnerReferences = append(cm.ObjectMeta.OwnerReferences, metav1.OwnerReference{
 		Kind:       kmeta.Kind,
 		APIVersion: kmeta.APIVersion,
 		Name:       kmeta.Name,
 		UID:        types.UID(kmeta.UID),
 	})
 
-	// Create on API server.
-	err = p.ensurer.EnsurePrometheusRule(ctx, rule)
+	err = c.ensurer.EnsureConfigMap(ctx, cm)
+	if err != nil {
+		return fmt.Errorf("could not ensure ConfigMap: %w", err)
+	}
+
+	return nil
+}
+
+func mapModelToConfigMap(ctx context.Context, kmeta K8sMeta, slos []StorageSLO, keyLayout string) (*corev1.ConfigMap, error) {
+	if len(slos) == 0 {
+		return nil, fmt.Errorf("slo rules required")
+	}
+
+	// Group the SLOs by ConfigMap key.
+	keys := []string{}
+	keySLOs := map[string][]prometheus.StorageSLO{}
+	for _, slo := range slos {
+		key := fmt.Sprintf("%s.yml", kmeta.Name)
+		switch keyLayout {
+		case ConfigMapKeyLayoutSingle, "":
+		case ConfigMapKeyLayoutSLO:
+			key = fmt.Sprintf("%s.yml", slo.SLO.ID)
+		default:
+			return nil, fmt.Errorf("unknown %q ConfigMap key layout", keyLayout)
+		}
+
+		if _, ok := keySLOs[key]; !ok {
+			keys = append(keys, key)
+		}
+		keySLOs[key] = append(keySLOs[key], prometheus.StorageSLO{SLO: slo.SLO, Rules: slo.Rules})
+	}
+
+	data := map[string]string{}
+	for _, key := range keys {
+		var b bytes.Buffer
+		err := prometheus.NewIOWriterGroupedRulesYAMLRepo(&b, log.Noop).StoreSLOs(ctx, keySLOs[key])
+		if err != nil {
+			if errors.Is(err, prometheus.ErrNoSLORules) {
+				continue
+			}
+			return nil, fmt.Errorf("could not map %q key rules: %w", key, err)
+		}
+		data[key] = strings.TrimLeft(b.String(), "\n")
+	}
+
+	// If we don't have anything to store, error so we can increase the reliability
+	// because maybe this was due to an unintended error (typos, misconfig, too many disable...).
+	if len(data) == 0 {
+		return nil, ErrNoSLORules
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/component":  "SLO",
+		"app.kubernetes.io/managed-by": "sloth",
+	}
+	for k, v := range kmeta.Labels {
+		labels[k] = v
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        kmeta.Name,
+			Namespace:   kmeta.Namespace,
+			Labels:      labels,
+			Annotations: kmeta.Annotations,
+		},
+		Data: data,
+	}, nil
+}
+
+// VMRuleGVR is the VictoriaMetrics operator VMRule resource.
+var VMRuleGVR = schema.GroupVersionResource{Group: "operator.victoriametrics.com", Version: "v1beta1", Resource: "vmrules"}
+
+func NewIOWriterVMRuleYAMLRepo(writer io.Writer, logger log.Logger) IOWriterVMRuleYAMLRepo {
+	return IOWriterVMRuleYAMLRepo{
+		writer:  writer,
+		encoder: json.NewYAMLSerializer(json.DefaultMetaFactory, nil, nil),
+		logger:  logger.WithValues(log.Kv{"svc": "storage.IOWriter", "format": "k8s-vmrule"}),
+	}
+}
+
+// IOWriterVMRuleYAMLRepo knows to store all the SLO rules (recordings and alerts)
+// grouped in an IOWriter in Kubernetes VictoriaMetrics operator VMRule YAML format.
+type IOWriterVMRuleYAMLRepo struct {
+	writer  io.Writer
+	encoder runtime.Encoder
+	logger  log.Logger
+}
+
+func (i IOWriterVMRuleYAMLRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
+	rule, err := mapModelToVMRule(ctx, kmeta, slos)
+	if err != nil {
+		return fmt.Errorf("could not map model to VMRule: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = i.encoder.Encode(rule, &b)
+	if err != nil {
+		return fmt.Errorf("could encode VMRule object: %w", err)
+	}
+
+	_, err = i.writer.Write(writeTopDisclaimer(b.Bytes()))
+	if err != nil {
+		return fmt.Errorf("could not write top disclaimer: %w", err)
+	}
+
+	return nil
+}
+
+func NewVMRuleRepo(ensurer VMRuleEnsurer, logger log.Logger) VMRuleRepo {
+	return VMRuleRepo{
+		ensurer: ensurer,
+		logger:  logger.WithValues(log.Kv{"svc": "storage.VMRuleAPIServer", "format": "k8s-vmrule"}),
+	}
+}
+
+// VMRuleRepo knows to store all the SLO rules (recordings and alerts) grouped as a Kubernetes
+// VictoriaMetrics operator VMRule owned by the PrometheusServiceLevel using Kubernetes API server.
+type VMRuleRepo struct {
+	logger  log.Logger
+	ensurer VMRuleEnsurer
+}
+
+type VMRuleEnsurer interface {
+	EnsureVMRule(ctx context.Context, rule *unstructured.Unstructured) error
+}
+
+//go:generate mockery --case underscore --output k8sprometheusmock --outpkg k8sprometheusmock --name VMRuleEnsurer
+
+func (v VMRuleRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
+	rule, err := mapModelToVMRule(ctx, kmeta, slos)
+	if err != nil {
+		return fmt.Errorf("could not map model to VMRule: %w", err)
+	}
+
+	rule.SetOwnerReferences([]metav1.OwnerReference{{
+		Kind:       kmeta.Kind,
+		APIVersion: kmeta.APIVersion,
+		Name:       kmeta.Name,
+		UID:        types.UID(kmeta.UID),
+	}})
+
+	err = v.ensurer.EnsureVMRule(ctx, rule)
+	if err != nil {
+		return fmt.Errorf("could not ensure VMRule: %w", err)
+	}
+
+	return nil
+}
+
+// mapModelToVMRule maps the model to a VictoriaMetrics operator VMRule. We don't depend on the
+// VictoriaMetrics operator types, the VMRule spec is the same as the Prometheus operator
+// PrometheusRule spec, so we reuse it.
+func mapModelToVMRule(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) (*unstructured.Unstructured, error) {
+	promRule, err := mapModelToPrometheusOperator(ctx, kmeta, slos)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(promRule)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert rule to unstructured: %w", err)
+	}
+	rule := &unstructured.Unstructured{Object: obj}
+	rule.SetAPIVersion(VMRuleGVR.GroupVersion().String())
+	rule.SetKind("VMRule")
+
+	// VMRules don't have Thanos partial response strategy.
+	groups, _, err := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	if err != nil {
+		return nil, fmt.Errorf("could not get rule groups: %w", err)
+	}
+	for _, g := range groups {
+		if g, ok := g.(map[string]interface{}); ok {
+			delete(g, "partial_response_strategy")
+		}
+	}
+	err = unstructured.SetNestedSlice(rule.Object, groups, "spec", "groups")
+	if err != nil {
+		return nil, fmt.Errorf("could not set rule groups: %w", err)
+	}
+
+	return rule, nil
+}
+
+func NewRulerRepo(client RulerClient, logger log.Logger) RulerRepo {
+	return RulerRepo{
+		client: client,
+		logger: logger.WithValues(log.Kv{"svc": "storage.RulerAPI", "format": "prometheus-rule-groups"}),
+	}
+}
+
+// RulerRepo knows to store all the SLO rules (recordings and alerts) as rule groups on a Mimir/Cortex
+// ruler using its HTTP API. The rule groups are stored on a `<namespace>_<name>` ruler namespace
+// and the rule groups that are no longer generated are deleted.
+type RulerRepo struct {
+	client          RulerClient
+	namespacePrefix string
+	logger          log.Logger
+}
+
+// WithNamespacePrefix returns a copy of the repository that stores the rule groups on a
+// `<prefix>_<namespace>_<name>` ruler namespace, so the rules of multiple clusters can be
+// stored on the same ruler. The prefix can't have the separator.
+func (r RulerRepo) WithNamespacePrefix(prefix string) RulerRepo {
+	r.namespacePrefix = prefix
+	return r
+}
+
+// RulerClient knows how to manage the rule groups of a ruler.
+type RulerClient interface {
+	ListRuleGroups(ctx context.Context, namespace string) ([]string, error)
+	SetRuleGroup(ctx context.Context, namespace string, group []byte) error
+	DeleteRuleGroup(ctx context.Context, namespace, group string) error
+}
+
+//go:generate mockery --case underscore --output k8sprometheusmock --outpkg k8sprometheusmock --name RulerClient
+
+func (r RulerRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
+	groups, err := mapModelToRuleGroups(ctx, slos)
+	if err != nil {
+		return fmt.Errorf("could not map model to rule groups: %w", err)
+	}
+
+	namespace := r.rulerNamespace(kmeta)
+	current, err := r.client.ListRuleGroups(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("could not list ruler rule groups: %w", err)
+	}
+
+	names := make([]string, 0, len(groups))
+	desired := map[string]bool{}
+	for _, group := range groups {
+		name := ruleGroupName(group)
+		names = append(names, name)
+		desired[name] = true
+
+		data, err := yaml.Marshal(group)
+		if err != nil {
+			return fmt.Errorf("could not format %q rule group: %w", name, err)
+		}
+
+		err = r.client.SetRuleGroup(ctx, namespace, data)
+		if err != nil {
+			return fmt.Errorf("could not set %q rule group: %w", name, err)
+		}
+	}
+
+	// Delete the rule groups that are not generated anymore (e.g: removed SLOs).
+	for _, name := range current {
+		if desired[name] {
+			continue
+		}
+
+		err := r.client.DeleteRuleGroup(ctx, namespace, name)
+		if err != nil {
+			return fmt.Errorf("could not delete %q stale rule group: %w", name, err)
+		}
+	}
+
+	logger := r.logger.WithCtxValues(ctx)
+	logger.WithValues(log.Kv{"namespace": namespace, "groups": names}).Debugf("Ruler rule groups stored")
+
+	return nil
+}
+
+// DeleteSLOs deletes all the rule groups of the object ruler namespace.
+func (r RulerRepo) DeleteSLOs(ctx context.Context, kmeta K8sMeta) error {
+	namespace := r.rulerNamespace(kmeta)
+	current, err := r.client.ListRuleGroups(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("could not list ruler rule groups: %w", err)
+	}
+
+	for _, name := range current {
+		err := r.client.DeleteRuleGroup(ctx, namespace, name)
+		if err != nil {
+			return fmt.Errorf("could not delete %q rule group: %w", name, err)
+		}
+	}
+	r.logger.WithCtxValues(ctx).WithValues(log.Kv{"namespace": namespace, "groups": current}).Debugf("Ruler rule groups deleted")
+
+	return nil
+}
+
+// RulerNamespaceSeparator separates the ruler namespace parts, Kubernetes namespaces and names can't
+// have it (unlike dots on the names), so the ruler namespaces of different objects don't collide.
+const RulerNamespaceSeparator = "_"
+
+func (r RulerRepo) rulerNamespace(kmeta K8sMeta) string {
+	parts := []string{kmeta.Namespace, kmeta.Name}
+	if r.namespacePrefix != "" {
+		parts = append([]string{r.namespacePrefix}, parts...)
+	}
+
+	return strings.Join(parts, RulerNamespaceSeparator)
+}
+
+// mapModelToRuleGroups maps the model to Prometheus rule groups, the groups are kept as
+// YAML map slices so we reuse the Prometheus rules format keeping the fields order.
+func mapModelToRuleGroups(ctx context.Context, slos []StorageSLO) ([]yaml.MapSlice, error) {
+	promSLOs := make([]prometheus.StorageSLO, 0, len(slos))
+	for _, slo := range slos {
+		promSLOs = append(promSLOs, prometheus.StorageSLO{SLO: slo.SLO, Rules: slo.Rules})
+	}
+
+	var b bytes.Buffer
+	err := prometheus.NewIOWriterGroupedRulesYAMLRepo(&b, log.Noop).StoreSLOs(ctx, promSLOs)
+	if err != nil {
+		if errors.Is(err, prometheus.ErrNoSLORules) {
+			return nil, ErrNoSLORules
+		}
+		return nil, err
+	}
+
+	ruleGroups := struct {
+		Groups []yaml.MapSlice `yaml:"groups"`
+	}{}
+	err = yaml.Unmarshal(b.Bytes(), &ruleGroups)
+	if err != nil {
+		return nil, fmt.Errorf("could not load rule groups: %w", err)
+	}
+
+	return ruleGroups.Groups, nil
+}
+
+func ruleGroupName(group yaml.MapSlice) string {
+	for _, item := range group {
+		if item.Key == "name" {
+			name, _ := item.Value.(string)
+			return name
+		}
+	}
+
+	return ""
+}
+
+// NewRuleFileRepo returns a new RuleFileRepo.
+func NewRuleFileRepo(dir string, logger log.Logger) RuleFileRepo {
+	return RuleFileRepo{
+		dir:    dir,
+		logger: logger.WithValues(log.Kv{"svc": "storage.RuleFileDir", "format": "prometheus-rule-file"}),
+	}
+}
+
+// RuleFileRepo knows to store all the SLO rules (recordings and alerts) as Prometheus rule files on a
+// directory (e.g a volume shared with a Thanos ruler or a Prometheus sidecar), with a `<namespace>_<name>.yml`
+// file by object (Kubernetes names can't have underscores). The files are written atomically, so the
+// readers never load a partially written rule file.
+type RuleFileRepo struct {
+	dir    string
+	logger log.Logger
+}
+
+// RuleFile is a stored rule file, identified by the Kubernetes object that generated it.
+type RuleFile struct {
+	Namespace string
+	Name      string
+}
+
+func (r RuleFileRepo) StoreSLOs(ctx context.Context, kmeta K8sMeta, slos []StorageSLO) error {
+	if len(slos) == 0 {
+		return ErrNoSLORules
+	}
+
+	promSLOs := make([]prometheus.StorageSLO, 0, len(slos))
+	for _, slo := range slos {
+		promSLOs = append(promSLOs, prometheus.StorageSLO{SLO: slo.SLO, Rules: slo.Rules})
+	}
+
+	var b bytes.Buffer
+	err := prometheus.NewIOWriterGroupedRulesYAMLRepo(&b, log.Noop).StoreSLOs(ctx, promSLOs)
 	if err != nil {
-		return fmt.Errorf("could not ensure Prometheus operator rule CR: %w", err)
+		if errors.Is(err, prometheus.ErrNoSLORules) {
+			return ErrNoSLORules
+		}
+		return fmt.Errorf("could not map model to rule file: %w", err)
 	}
 
+	path := filepath.Join(r.dir, ruleFileName(kmeta.Namespace, kmeta.Name))
+	err = writeFileAtomically(path, b.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not write %q rule file: %w", path, err)
+	}
+	r.logger.WithCtxValues(ctx).WithValues(log.Kv{"file": path}).Debugf("Rule file stored")
+
 	return nil
 }
+
+// DeleteSLOs deletes the rule file of the object.
+func (r RuleFileRepo) DeleteSLOs(ctx context.Context, kmeta K8sMeta) error {
+	err := r.DeleteRuleFile(ctx, kmeta.Namespace, kmeta.Name)
+	if err != nil {
+		return fmt.Errorf("could not delete rule file: %w", err)
+	}
+
+	return nil
+}
+
+// ListRuleFiles returns the rule files stored on the directory.
+func (r RuleFileRepo) ListRuleFiles(ctx context.Context) ([]RuleFile, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read rule files directory: %w", err)
+	}
+
+	files := []RuleFile{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yml") {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSuffix(e.Name(), ".yml"), "_", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		files = append(files, RuleFile{Namespace: parts[0], Name: parts[1]})
+	}
+
+	return files, nil
+}
+
+// DeleteRuleFile deletes the rule file of an object.
+func (r RuleFileRepo) DeleteRuleFile(ctx context.Context, ns, name string) error {
+	err := os.Remove(filepath.Join(r.dir, ruleFileName(ns, name)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func ruleFileName(ns, name string) string {
+	return fmt.Sprintf("%s_%s.yml", ns, name)
+}
+
+// writeFileAtomically writes the file data on a temporary file of the same directory and then renames it.
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -4,13 +4,23 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus/prometheus/pkg/rulefmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -90,6 +100,56 @@ spec:
 `,
 		},
 
+		"Having a rule group interval should render the group with the interval.": {
+			k8sMeta: k8sprometheus.K8sMeta{
+				Name:        "test-name",
+				Namespace:   "test-ns",
+				Labels:      map[string]string{"lk1": "lv1"},
+				Annotations: map[string]string{"ak1": "av1"},
+			},
+			slos: []k8sprometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1", RuleGroupIntervals: prometheus.RuleGroupIntervals{SLIErrorRecRules: 30 * time.Second}},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{
+							{
+								Record: "test:record",
+								Expr:   "test-expr",
+								Labels: map[string]string{"test-label": "one"},
+							},
+						},
+					},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  annotations:
+    ak1: av1
+  creationTimestamp: null
+  labels:
+    app.kubernetes.io/component: SLO
+    app.kubernetes.io/managed-by: sloth
+    lk1: lv1
+  name: test-name
+  namespace: test-ns
+spec:
+  groups:
+  - interval: 30s
+    name: sloth-slo-sli-recordings-test1
+    rules:
+    - expr: test-expr
+      labels:
+        test-label: one
+      record: test:record
+`,
+		},
+
 		"Having a single metadata recording rule should render correctly.": {
 			k8sMeta: k8sprometheus.K8sMeta{
 				Name:        "test-name",
@@ -369,6 +429,7 @@ func TestPrometheusOperatorCRDRepo(t *testing.T) {
 	tests := map[string]struct {
 		k8sMeta k8sprometheus.K8sMeta
 		slos    []k8sprometheus.StorageSLO
+		retain  bool
 		mock    func(m *k8sprometheusmock.PrometheusRulesEnsurer)
 		expErr  bool
 	}{
@@ -501,6 +562,7 @@ func TestPrometheusOperatorCRDRepo(t *testing.T) {
 							"lk1":                          "lv1",
 							"app.kubernetes.io/component":  "SLO",
 							"app.kubernetes.io/managed-by": "sloth",
+							"sloth.slok.dev/source-kind":   "test-kind",
 						},
 						Annotations: map[string]string{"ak1": "av1"},
 						OwnerReferences: []metav1.OwnerReference{
@@ -598,6 +660,64 @@ func TestPrometheusOperatorCRDRepo(t *testing.T) {
 				m.On("EnsurePrometheusRule", mock.Anything, exp).Once().Return(nil)
 			},
 		},
+
+		"Having retain enabled should ensure on Kubernetes without owner and with the retain label.": {
+			k8sMeta: k8sprometheus.K8sMeta{
+				Name:       "test-name",
+				Namespace:  "test-ns",
+				Kind:       "test-kind",
+				APIVersion: "test-apiversion",
+				UID:        "test-uid",
+			},
+			slos: []k8sprometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "testa"},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{
+							{
+								Record: "test:record-a1",
+								Expr:   "test-expr-a1",
+								Labels: map[string]string{"test-label": "a-1"},
+							},
+						},
+					},
+				},
+			},
+			retain: true,
+			mock: func(m *k8sprometheusmock.PrometheusRulesEnsurer) {
+				exp := &monitoringv1.PrometheusRule{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "monitoring.coreos.com/v1",
+						Kind:       "PrometheusRule",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-name",
+						Namespace: "test-ns",
+						Labels: map[string]string{
+							"app.kubernetes.io/component":  "SLO",
+							"app.kubernetes.io/managed-by": "sloth",
+							"sloth.slok.dev/retain":        "true",
+							"sloth.slok.dev/source-kind":   "test-kind",
+						},
+					},
+					Spec: monitoringv1.PrometheusRuleSpec{
+						Groups: []monitoringv1.RuleGroup{
+							{
+								Name: "sloth-slo-sli-recordings-testa",
+								Rules: []monitoringv1.Rule{
+									{
+										Record: "test:record-a1",
+										Expr:   intstr.FromString("test-expr-a1"),
+										Labels: map[string]string{"test-label": "a-1"},
+									},
+								},
+							},
+						},
+					},
+				}
+				m.On("EnsurePrometheusRule", mock.Anything, exp).Once().Return(nil)
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -609,6 +729,9 @@ func TestPrometheusOperatorCRDRepo(t *testing.T) {
 			test.mock(mpre)
 
 			repo := k8sprometheus.NewPrometheusOperatorCRDRepo(mpre, log.Noop)
+			if test.retain {
+				repo = k8sprometheus.NewRetainPrometheusOperatorCRDRepo(mpre, log.Noop)
+			}
 			err := repo.StoreSLOs(context.TODO(), test.k8sMeta, test.slos)
 
 			if test.expErr {
@@ -620,3 +743,858 @@ func TestPrometheusOperatorCRDRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusOperatorCRDRepoSplit(t *testing.T) {
+	bigExpr := strings.Repeat("a", 1000)
+	kmeta := k8sprometheus.K8sMeta{
+		Name:        "test-name",
+		Namespace:   "test-ns",
+		Kind:        "test-kind",
+		APIVersion:  "test-apiversion",
+		UID:         "test-uid",
+		Annotations: map[string]string{"test-annot": "a"},
+	}
+	slos := []k8sprometheus.StorageSLO{
+		{
+			SLO: prometheus.SLO{ID: "testa"},
+			Rules: prometheus.SLORules{
+				SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-a1", Expr: bigExpr}},
+				AlertRules:       []rulefmt.Rule{{Alert: "testAlertA1", Expr: bigExpr}},
+			},
+		},
+		{
+			SLO: prometheus.SLO{ID: "testb"},
+			Rules: prometheus.SLORules{
+				SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-b1", Expr: bigExpr}},
+			},
+		},
+	}
+	storedRules := &monitoringv1.PrometheusRuleList{Items: []*monitoringv1.PrometheusRule{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-name-part-1", Namespace: "test-ns", Annotations: map[string]string{"sloth.slok.dev/part-of": "test-name"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-name-part-2", Namespace: "test-ns", Annotations: map[string]string{"sloth.slok.dev/part-of": "test-name"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-name-part-3", Namespace: "test-ns", Annotations: map[string]string{"sloth.slok.dev/part-of": "test-name"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-part-3", Namespace: "test-ns", Annotations: map[string]string{"sloth.slok.dev/part-of": "other"}}},
+	}}
+
+	type part struct {
+		name        string
+		groups      []string
+		annotations map[string]string
+		owned       bool
+	}
+
+	tests := map[string]struct {
+		maxSize    int
+		expParts   []part
+		expDeleted []string
+	}{
+		"Having rules smaller than the max size should store a single PrometheusRule and delete the previous parts.": {
+			maxSize: 1000000,
+			expParts: []part{
+				{
+					name:        "test-name",
+					groups:      []string{"sloth-slo-sli-recordings-testa", "sloth-slo-alerts-testa", "sloth-slo-sli-recordings-testb"},
+					annotations: map[string]string{"test-annot": "a"},
+					owned:       true,
+				},
+			},
+			expDeleted: []string{"test-name-part-1", "test-name-part-2", "test-name-part-3"},
+		},
+
+		"Having rules bigger than the max size should split the rule groups in multiple PrometheusRules and delete the extra parts.": {
+			maxSize: 1500,
+			expParts: []part{
+				{
+					name:        "test-name",
+					groups:      []string{"sloth-slo-sli-recordings-testa"},
+					annotations: map[string]string{"test-annot": "a"},
+					owned:       true,
+				},
+				{
+					name:   "test-name-part-1",
+					groups: []string{"sloth-slo-alerts-testa"},
+					annotations: map[string]string{
+						"test-annot":                      "a",
+						"sloth.slok.dev/part-of":          "test-name",
+						"sloth.slok.dev/source-name":      "test-name",
+						"sloth.slok.dev/source-namespace": "test-ns",
+					},
+					owned: true,
+				},
+				{
+					name:   "test-name-part-2",
+					groups: []string{"sloth-slo-sli-recordings-testb"},
+					annotations: map[string]string{
+						"test-annot":                      "a",
+						"sloth.slok.dev/part-of":          "test-name",
+						"sloth.slok.dev/source-name":      "test-name",
+						"sloth.slok.dev/source-namespace": "test-ns",
+					},
+					owned: true,
+				},
+			},
+			expDeleted: []string{"test-name-part-3"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			gotParts := []part{}
+			mpre := &k8sprometheusmock.PrometheusRulesEnsurer{}
+			mpre.On("EnsurePrometheusRule", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				pr := args.Get(1).(*monitoringv1.PrometheusRule)
+				groups := []string{}
+				for _, g := range pr.Spec.Groups {
+					groups = append(groups, g.Name)
+				}
+				gotParts = append(gotParts, part{
+					name:        pr.Name,
+					groups:      groups,
+					annotations: pr.Annotations,
+					owned:       len(pr.OwnerReferences) == 1 && pr.OwnerReferences[0].UID == "test-uid",
+				})
+			}).Return(nil)
+
+			gotDeleted := []string{}
+			mprpr := &k8sprometheusmock.PrometheusRulePartsRepository{}
+			mprpr.On("ListPrometheusRules", mock.Anything, "test-ns", map[string]string{"app.kubernetes.io/managed-by": "sloth"}).Once().Return(storedRules, nil)
+			mprpr.On("DeletePrometheusRule", mock.Anything, "test-ns", mock.Anything).Run(func(args mock.Arguments) {
+				gotDeleted = append(gotDeleted, args.String(2))
+			}).Return(nil)
+
+			repo := k8sprometheus.NewPrometheusOperatorCRDRepo(mpre, log.Noop).WithSplit(test.maxSize, mprpr)
+			err := repo.StoreSLOs(context.TODO(), kmeta, slos)
+			require.NoError(err)
+
+			assert.Equal(test.expParts, gotParts)
+			assert.Equal(test.expDeleted, gotDeleted)
+		})
+	}
+}
+
+func TestPrometheusOperatorCRDRepoDeleteSLOs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	storedRules := &monitoringv1.PrometheusRuleList{Items: []*monitoringv1.PrometheusRule{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-name-part-1", Namespace: "test-ns", Annotations: map[string]string{"sloth.slok.dev/part-of": "test-name"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test-ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-part-1", Namespace: "test-ns", Annotations: map[string]string{"sloth.slok.dev/part-of": "other"}}},
+	}}
+
+	// Mocks.
+	gotDeleted := []string{}
+	mprpr := &k8sprometheusmock.PrometheusRulePartsRepository{}
+	mprpr.On("ListPrometheusRules", mock.Anything, "test-ns", map[string]string{"app.kubernetes.io/managed-by": "sloth"}).Once().Return(storedRules, nil)
+	mprpr.On("DeletePrometheusRule", mock.Anything, "test-ns", mock.Anything).Run(func(args mock.Arguments) {
+		gotDeleted = append(gotDeleted, args.String(2))
+	}).Return(nil)
+
+	// Without deleter the deletion should fail.
+	kmeta := k8sprometheus.K8sMeta{Name: "test-name", Namespace: "test-ns"}
+	err := k8sprometheus.NewPrometheusOperatorCRDRepo(nil, log.Noop).DeleteSLOs(context.TODO(), kmeta)
+	assert.Error(err)
+
+	// The rule and its parts should be deleted.
+	err = k8sprometheus.NewPrometheusOperatorCRDRepo(nil, log.Noop).WithDeleter(mprpr).DeleteSLOs(context.TODO(), kmeta)
+	require.NoError(err)
+	assert.Equal([]string{"test-name", "test-name-part-1"}, gotDeleted)
+	mprpr.AssertExpectations(t)
+}
+
+func TestPrometheusOperatorCRDRepoOwnershipCheck(t *testing.T) {
+	kmeta := k8sprometheus.K8sMeta{
+		Name:       "test-name",
+		Namespace:  "test-ns",
+		Kind:       "test-kind",
+		APIVersion: "test-apiversion",
+		UID:        "test-uid",
+	}
+	slos := []k8sprometheus.StorageSLO{
+		{
+			SLO: prometheus.SLO{ID: "testa"},
+			Rules: prometheus.SLORules{
+				SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-a1", Expr: "test-expr-a1"}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		stored    *monitoringv1.PrometheusRule
+		getErr    error
+		adopt     bool
+		expEnsure bool
+		expErr    bool
+	}{
+		"A missing rule should be stored.": {
+			getErr:    kubeerrors.NewNotFound(schema.GroupResource{}, "test-name"),
+			expEnsure: true,
+		},
+
+		"An existing rule managed by Sloth should be overwritten.": {
+			stored: &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "sloth"},
+			}},
+			expEnsure: true,
+		},
+
+		"An existing rule not managed by Sloth without adoption should fail.": {
+			stored:    &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"}},
+			expEnsure: false,
+			expErr:    true,
+		},
+
+		"An existing rule not managed by Sloth with adoption should be adopted.": {
+			stored:    &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"}},
+			adopt:     true,
+			expEnsure: true,
+		},
+
+		"A failure getting the existing rule should fail.": {
+			getErr:    fmt.Errorf("something"),
+			expEnsure: false,
+			expErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Mocks.
+			mprg := &k8sprometheusmock.PrometheusRuleGetter{}
+			mprg.On("GetPrometheusRule", mock.Anything, "test-ns", "test-name").Once().Return(test.stored, test.getErr)
+
+			gotEnsure := false
+			mpre := &k8sprometheusmock.PrometheusRulesEnsurer{}
+			mpre.On("EnsurePrometheusRule", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				pr := args.Get(1).(*monitoringv1.PrometheusRule)
+				gotEnsure = true
+				assert.Equal("sloth", pr.Labels["app.kubernetes.io/managed-by"])
+				assert.Equal(types.UID("test-uid"), pr.OwnerReferences[0].UID)
+			}).Return(nil)
+
+			repo := k8sprometheus.NewPrometheusOperatorCRDRepo(mpre, log.Noop).WithOwnershipCheck(mprg, test.adopt)
+			err := repo.StoreSLOs(context.TODO(), kmeta, slos)
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.Equal(test.expEnsure, gotEnsure)
+			mprg.AssertExpectations(t)
+		})
+	}
+}
+
+func TestKustomizeDirPrometheusOperatorYAMLRepo(t *testing.T) {
+	tests := map[string]struct {
+		k8sMeta          k8sprometheus.K8sMeta
+		slos             []k8sprometheus.StorageSLO
+		kustomization    string
+		expFiles         []string
+		expKustomization string
+		expErr           bool
+	}{
+		"Having 0 SLO rules should fail.": {
+			k8sMeta: k8sprometheus.K8sMeta{},
+			slos:    []k8sprometheus.StorageSLO{},
+			expErr:  true,
+		},
+
+		"Having SLO rules without kustomization it should create the base.": {
+			k8sMeta: k8sprometheus.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: []k8sprometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1"},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record", Expr: "test-expr"}},
+					},
+				},
+			},
+			expFiles: []string{"kustomization.yaml", "test-name.yaml"},
+			expKustomization: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- test-name.yaml
+`,
+		},
+
+		"Having SLO rules with an existing kustomization it should add the resource and preserve the user fields.": {
+			k8sMeta: k8sprometheus.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: []k8sprometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1"},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record", Expr: "test-expr"}},
+					},
+				},
+			},
+			kustomization: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namePrefix: team-a-
+commonLabels:
+  team: a
+resources:
+- z-other.yaml
+- a-other.yaml
+`,
+			expFiles: []string{"kustomization.yaml", "test-name.yaml"},
+			expKustomization: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namePrefix: team-a-
+commonLabels:
+  team: a
+resources:
+- a-other.yaml
+- test-name.yaml
+- z-other.yaml
+`,
+		},
+
+		"Having SLO rules with an already referenced resource it should not change the kustomization.": {
+			k8sMeta: k8sprometheus.K8sMeta{
+				Name: "test-name",
+			},
+			slos: []k8sprometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1"},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record", Expr: "test-expr"}},
+					},
+				},
+			},
+			kustomization: `resources: [test-name.yaml]
+`,
+			expFiles: []string{"kustomization.yaml", "test-name.yaml"},
+			expKustomization: `resources: [test-name.yaml]
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dir := t.TempDir()
+			if test.kustomization != "" {
+				err := os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(test.kustomization), 0644)
+				require.NoError(err)
+			}
+
+			repo := k8sprometheus.NewKustomizeDirPrometheusOperatorYAMLRepo(dir, log.Noop)
+			err := repo.StoreSLOs(context.TODO(), test.k8sMeta, test.slos)
+
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(err)
+			gotFiles := []string{}
+			for _, e := range entries {
+				gotFiles = append(gotFiles, e.Name())
+			}
+			assert.Equal(test.expFiles, gotFiles)
+
+			gotKustomization, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+			require.NoError(err)
+			assert.Equal(test.expKustomization, string(gotKustomization))
+		})
+	}
+}
+
+func TestConfigMapRepo(t *testing.T) {
+	k8sMeta := k8sprometheus.K8sMeta{
+		Name:       "test-name",
+		Namespace:  "test-ns",
+		Labels:     map[string]string{"lk1": "lv1"},
+		Kind:       "test-kind",
+		APIVersion: "test-apiversion",
+		UID:        "test-uid",
+	}
+	slos := []k8sprometheus.StorageSLO{
+		{
+			SLO: prometheus.SLO{ID: "testa"},
+			Rules: prometheus.SLORules{
+				SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-a1", Expr: "test-expr-a1"}},
+			},
+		},
+		{
+			SLO: prometheus.SLO{ID: "testb"},
+			Rules: prometheus.SLORules{
+				AlertRules: []rulefmt.Rule{{Alert: "testAlertB1", Expr: "test-expr-b1"}},
+			},
+		},
+	}
+	expObjectMeta := metav1.ObjectMeta{
+		Name:      "test-name",
+		Namespace: "test-ns",
+		Labels: map[string]string{
+			"lk1":                          "lv1",
+			"app.kubernetes.io/component":  "SLO",
+			"app.kubernetes.io/managed-by": "sloth",
+		},
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				Kind:       "test-kind",
+				APIVersion: "test-apiversion",
+				Name:       "test-name",
+				UID:        types.UID("test-uid"),
+			},
+		},
+	}
+	expRulesA := `groups:
+- name: sloth-slo-sli-recordings-testa
+  rules:
+  - record: test:record-a1
+    expr: test-expr-a1
+`
+	expRulesB := `groups:
+- name: sloth-slo-alerts-testb
+  rules:
+  - alert: testAlertB1
+    expr: test-expr-b1
+`
+
+	tests := map[string]struct {
+		keyLayout string
+		slos      []k8sprometheus.StorageSLO
+		mock      func(m *k8sprometheusmock.ConfigMapEnsurer)
+		expErr    bool
+	}{
+		"Having 0 SLO rules should fail.": {
+			slos:   []k8sprometheus.StorageSLO{},
+			mock:   func(m *k8sprometheusmock.ConfigMapEnsurer) {},
+			expErr: true,
+		},
+
+		"Having 0 SLO rules generated should fail.": {
+			slos:   []k8sprometheus.StorageSLO{{}},
+			mock:   func(m *k8sprometheusmock.ConfigMapEnsurer) {},
+			expErr: true,
+		},
+
+		"Having an unknown key layout should fail.": {
+			keyLayout: "unknown",
+			slos:      slos,
+			mock:      func(m *k8sprometheusmock.ConfigMapEnsurer) {},
+			expErr:    true,
+		},
+
+		"Having an error while storing the ConfigMap should fail.": {
+			slos: slos,
+			mock: func(m *k8sprometheusmock.ConfigMapEnsurer) {
+				m.On("EnsureConfigMap", mock.Anything, mock.Anything).Once().Return(fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Having a single key layout should store all the rules on the same key.": {
+			keyLayout: k8sprometheus.ConfigMapKeyLayoutSingle,
+			slos:      slos,
+			mock: func(m *k8sprometheusmock.ConfigMapEnsurer) {
+				m.On("EnsureConfigMap", mock.Anything, mock.Anything).Once().Return(nil).Run(func(args mock.Arguments) {
+					cm := args.Get(1).(*corev1.ConfigMap)
+					assert.Equal(t, expObjectMeta, cm.ObjectMeta)
+					assert.Equal(t, []string{"test-name.yml"}, mapKeys(cm.Data))
+					assert.Contains(t, cm.Data["test-name.yml"], expRulesA+`- name: sloth-slo-alerts-testb
+  rules:
+  - alert: testAlertB1
+    expr: test-expr-b1
+`)
+				})
+			},
+		},
+
+		"Having an SLO key layout should store the rules of each SLO on its own key.": {
+			keyLayout: k8sprometheus.ConfigMapKeyLayoutSLO,
+			slos:      slos,
+			mock: func(m *k8sprometheusmock.ConfigMapEnsurer) {
+				m.On("EnsureConfigMap", mock.Anything, mock.Anything).Once().Return(nil).Run(func(args mock.Arguments) {
+					cm := args.Get(1).(*corev1.ConfigMap)
+					assert.Equal(t, expObjectMeta, cm.ObjectMeta)
+					assert.Equal(t, []string{"testa.yml", "testb.yml"}, mapKeys(cm.Data))
+					assert.Contains(t, cm.Data["testa.yml"], expRulesA)
+					assert.Contains(t, cm.Data["testb.yml"], expRulesB)
+				})
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Mocks.
+			mcme := &k8sprometheusmock.ConfigMapEnsurer{}
+			test.mock(mcme)
+
+			repo := k8sprometheus.NewConfigMapRepo(mcme, test.keyLayout, log.Noop)
+			err := repo.StoreSLOs(context.TODO(), k8sMeta, test.slos)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			mcme.AssertExpectations(t)
+		})
+	}
+}
+
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestVMRuleRepo(t *testing.T) {
+	tests := map[string]struct {
+		k8sMeta k8sprometheus.K8sMeta
+		slos    []k8sprometheus.StorageSLO
+		mock    func(m *k8sprometheusmock.VMRuleEnsurer)
+		expErr  bool
+	}{
+		"Having 0 SLO rules should fail.": {
+			slos:   []k8sprometheus.StorageSLO{},
+			mock:   func(m *k8sprometheusmock.VMRuleEnsurer) {},
+			expErr: true,
+		},
+
+		"Having an error while storing the VMRule should fail.": {
+			slos: []k8sprometheus.StorageSLO{
+				{
+					SLO:   prometheus.SLO{ID: "testa"},
+					Rules: prometheus.SLORules{SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-a1"}}},
+				},
+			},
+			mock: func(m *k8sprometheusmock.VMRuleEnsurer) {
+				m.On("EnsureVMRule", mock.Anything, mock.Anything).Once().Return(fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Having SLO rules should ensure the VMRule on Kubernetes correctly.": {
+			k8sMeta: k8sprometheus.K8sMeta{
+				Name:       "test-name",
+				Namespace:  "test-ns",
+				Kind:       "test-kind",
+				APIVersion: "test-apiversion",
+				UID:        "test-uid",
+			},
+			slos: []k8sprometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{
+						ID:           "testa",
+						RulerOptions: prometheus.RulerOptions{PartialResponseStrategy: "warn"},
+					},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-a1", Expr: "test-expr-a1"}},
+						AlertRules: []rulefmt.Rule{{
+							Alert:       "testAlertA1",
+							Expr:        "test-expr-a2",
+							Labels:      map[string]string{"test-label": "a-1"},
+							Annotations: map[string]string{"test-annot": "a-1"},
+						}},
+					},
+				},
+			},
+			mock: func(m *k8sprometheusmock.VMRuleEnsurer) {
+				exp := &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "operator.victoriametrics.com/v1beta1",
+					"kind":       "VMRule",
+					"metadata": map[string]interface{}{
+						"name":              "test-name",
+						"namespace":         "test-ns",
+						"creationTimestamp": nil,
+						"labels": map[string]interface{}{
+							"app.kubernetes.io/component":  "SLO",
+							"app.kubernetes.io/managed-by": "sloth",
+						},
+						"ownerReferences": []interface{}{
+							map[string]interface{}{
+								"kind":       "test-kind",
+								"apiVersion": "test-apiversion",
+								"name":       "test-name",
+								"uid":        "test-uid",
+							},
+						},
+					},
+					"spec": map[string]interface{}{
+						"groups": []interface{}{
+							map[string]interface{}{
+								"name": "sloth-slo-sli-recordings-testa",
+								"rules": []interface{}{
+									map[string]interface{}{"record": "test:record-a1", "expr": "test-expr-a1"},
+								},
+							},
+							map[string]interface{}{
+								"name": "sloth-slo-alerts-testa",
+								"rules": []interface{}{
+									map[string]interface{}{
+										"alert":       "testAlertA1",
+										"expr":        "test-expr-a2",
+										"labels":      map[string]interface{}{"test-label": "a-1"},
+										"annotations": map[string]interface{}{"test-annot": "a-1"},
+									},
+								},
+							},
+						},
+					},
+				}}
+				m.On("EnsureVMRule", mock.Anything, exp).Once().Return(nil)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Mocks.
+			mvme := &k8sprometheusmock.VMRuleEnsurer{}
+			test.mock(mvme)
+
+			repo := k8sprometheus.NewVMRuleRepo(mvme, log.Noop)
+			err := repo.StoreSLOs(context.TODO(), test.k8sMeta, test.slos)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			mvme.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRulerRepo(t *testing.T) {
+	kmeta := k8sprometheus.K8sMeta{Name: "test-name", Namespace: "test-ns"}
+	slos := []k8sprometheus.StorageSLO{
+		{
+			SLO: prometheus.SLO{ID: "testa"},
+			Rules: prometheus.SLORules{
+				SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-a1", Expr: "test-expr-a1"}},
+				AlertRules:       []rulefmt.Rule{{Alert: "testAlertA1", Expr: "test-expr-a2"}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		namespacePrefix string
+		kmeta           *k8sprometheus.K8sMeta
+		slos            []k8sprometheus.StorageSLO
+		mock            func(m *k8sprometheusmock.RulerClient)
+		expErr          bool
+	}{
+		"Having 0 SLO rules should fail.": {
+			slos:   []k8sprometheus.StorageSLO{},
+			mock:   func(m *k8sprometheusmock.RulerClient) {},
+			expErr: true,
+		},
+
+		"Having an error while listing the rule groups should fail.": {
+			slos: slos,
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "test-ns_test-name").Once().Return(nil, fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Having an error while setting a rule group should fail.": {
+			slos: slos,
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "test-ns_test-name").Once().Return(nil, nil)
+				m.On("SetRuleGroup", mock.Anything, "test-ns_test-name", mock.Anything).Once().Return(fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Having SLO rules should set the rule groups on the ruler and delete the stale ones.": {
+			slos: slos,
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "test-ns_test-name").Once().Return([]string{"sloth-slo-sli-recordings-testa", "sloth-slo-alerts-testb"}, nil)
+
+				expSLIGroup := `name: sloth-slo-sli-recordings-testa
+rules:
+- record: test:record-a1
+  expr: test-expr-a1
+`
+				expAlertGroup := `name: sloth-slo-alerts-testa
+rules:
+- alert: testAlertA1
+  expr: test-expr-a2
+`
+				m.On("SetRuleGroup", mock.Anything, "test-ns_test-name", []byte(expSLIGroup)).Once().Return(nil)
+				m.On("SetRuleGroup", mock.Anything, "test-ns_test-name", []byte(expAlertGroup)).Once().Return(nil)
+				m.On("DeleteRuleGroup", mock.Anything, "test-ns_test-name", "sloth-slo-alerts-testb").Once().Return(nil)
+			},
+		},
+
+		"Having a namespace prefix should store the rule groups on the prefixed ruler namespace.": {
+			namespacePrefix: "cluster-1",
+			slos:            slos,
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "cluster-1_test-ns_test-name").Once().Return(nil, nil)
+				m.On("SetRuleGroup", mock.Anything, "cluster-1_test-ns_test-name", mock.Anything).Twice().Return(nil)
+			},
+		},
+
+		"Having a name with dots should not collide with the prefixed ruler namespaces.": {
+			kmeta: &k8sprometheus.K8sMeta{Name: "test-ns.test-name", Namespace: "cluster-1"},
+			slos:  slos,
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "cluster-1_test-ns.test-name").Once().Return(nil, nil)
+				m.On("SetRuleGroup", mock.Anything, "cluster-1_test-ns.test-name", mock.Anything).Twice().Return(nil)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Mocks.
+			mrc := &k8sprometheusmock.RulerClient{}
+			test.mock(mrc)
+
+			km := kmeta
+			if test.kmeta != nil {
+				km = *test.kmeta
+			}
+			repo := k8sprometheus.NewRulerRepo(mrc, log.Noop).WithNamespacePrefix(test.namespacePrefix)
+			err := repo.StoreSLOs(context.TODO(), km, test.slos)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			mrc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRulerRepoDeleteSLOs(t *testing.T) {
+	tests := map[string]struct {
+		mock   func(m *k8sprometheusmock.RulerClient)
+		expErr bool
+	}{
+		"Having an error while listing the rule groups should fail.": {
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "test-ns_test-name").Once().Return(nil, fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Having an error while deleting a rule group should fail.": {
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "test-ns_test-name").Once().Return([]string{"sloth-slo-alerts-testa"}, nil)
+				m.On("DeleteRuleGroup", mock.Anything, "test-ns_test-name", "sloth-slo-alerts-testa").Once().Return(fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"All the rule groups of the object ruler namespace should be deleted.": {
+			mock: func(m *k8sprometheusmock.RulerClient) {
+				m.On("ListRuleGroups", mock.Anything, "test-ns_test-name").Once().Return([]string{"sloth-slo-sli-recordings-testa", "sloth-slo-alerts-testa"}, nil)
+				m.On("DeleteRuleGroup", mock.Anything, "test-ns_test-name", "sloth-slo-sli-recordings-testa").Once().Return(nil)
+				m.On("DeleteRuleGroup", mock.Anything, "test-ns_test-name", "sloth-slo-alerts-testa").Once().Return(nil)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Mocks.
+			mrc := &k8sprometheusmock.RulerClient{}
+			test.mock(mrc)
+
+			repo := k8sprometheus.NewRulerRepo(mrc, log.Noop)
+			err := repo.DeleteSLOs(context.TODO(), k8sprometheus.K8sMeta{Name: "test-name", Namespace: "test-ns"})
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			mrc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRuleFileRepo(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir := t.TempDir()
+	repo := k8sprometheus.NewRuleFileRepo(dir, log.Noop)
+	kmeta := k8sprometheus.K8sMeta{Name: "test-name", Namespace: "test-ns"}
+
+	// Having 0 SLO rules should fail.
+	err := repo.StoreSLOs(context.TODO(), kmeta, []k8sprometheus.StorageSLO{})
+	assert.ErrorIs(err, k8sprometheus.ErrNoSLORules)
+
+	// Having SLO rules should store the rule file of the object.
+	err = repo.StoreSLOs(context.TODO(), kmeta, []k8sprometheus.StorageSLO{
+		{
+			SLO: prometheus.SLO{ID: "testa"},
+			Rules: prometheus.SLORules{
+				SLIErrorRecRules: []rulefmt.Rule{{Record: "test:record-a1", Expr: "test-expr-a1"}},
+				AlertRules:       []rulefmt.Rule{{Alert: "testAlertA1", Expr: "test-expr-a2"}},
+			},
+		},
+	})
+	require.NoError(err)
+
+	got, err := os.ReadFile(filepath.Join(dir, "test-ns_test-name.yml"))
+	require.NoError(err)
+	assert.Contains(string(got), `groups:
+- name: sloth-slo-sli-recordings-testa
+  rules:
+  - record: test:record-a1
+    expr: test-expr-a1
+- name: sloth-slo-alerts-testa
+  rules:
+  - alert: testAlertA1
+    expr: test-expr-a2
+`)
+
+	// Only the rule files should be listed, not the temporary ones nor unrelated files.
+	require.NoError(os.WriteFile(filepath.Join(dir, ".test-ns_test-other.yml.tmp-1"), []byte{}, 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "README.md"), []byte{}, 0644))
+	files, err := repo.ListRuleFiles(context.TODO())
+	require.NoError(err)
+	assert.Equal([]k8sprometheus.RuleFile{{Namespace: "test-ns", Name: "test-name"}}, files)
+
+	// Deleting the rule files should remove them, even if they are already missing.
+	require.NoError(repo.DeleteRuleFile(context.TODO(), "test-ns", "test-name"))
+	require.NoError(repo.DeleteRuleFile(context.TODO(), "test-ns", "test-name"))
+	files, err = repo.ListRuleFiles(context.TODO())
+	require.NoError(err)
+	assert.Empty(files)
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// Recorder knows how to record Sloth metrics.
+type Recorder interface {
+	SetPrometheusRulesPendingDeletion(ctx context.Context, quantity int)
+	ObserveSLOGenerationDuration(ctx context.Context, service, slo string, duration time.Duration)
+	ObservePrometheusServiceLevelReconcile(ctx context.Context, cluster, ns, name, result string, duration time.Duration)
+	SetPrometheusServiceLevelConsecutiveFailures(ctx context.Context, cluster, ns, name string, quantity int)
+	DeletePrometheusServiceLevelMetrics(ctx context.Context, cluster, ns, name string)
+	SetManagedInventory(ctx context.Context, inventory []Inventory)
+	SetLastFullSync(ctx context.Context, t time.Time)
+}
+
+// The PrometheusServiceLevel reconcile results.
+const (
+	ReconcileResultSuccess          = "success"
+	ReconcileResultInvalidSpec      = "invalid_spec"
+	ReconcileResultGenerationFailed = "generation_failed"
+	ReconcileResultApplyFailed      = "apply_failed"
+)
+
+// Inventory is the quantity of SLO spec objects, SLOs and generated rules managed by the Kubernetes
+// controller of a namespace and spec version.
+type Inventory struct {
+	Namespace   string
+	SpecVersion string
+	Objects     int
+	SLOs        int
+	Rules       int
+}
+
+// Noop is a Recorder that doesn't record anything.
+const Noop = noop(0)
+
+type noop int
+
+func (noop) SetPrometheusRulesPendingDeletion(ctx context.Context, quantity int) {}
+
+func (noop) ObserveSLOGenerationDuration(ctx context.Context, service, slo string, duration time.Duration) {
+}
+
+func (noop) ObservePrometheusServiceLevelReconcile(ctx context.Context, cluster, ns, name, result string, duration time.Duration) {
+}
+
+func (noop) SetPrometheusServiceLevelConsecutiveFailures(ctx context.Context, cluster, ns, name string, quantity int) {
+}
+
+func (noop) DeletePrometheusServiceLevelMetrics(ctx context.Context, cluster, ns, name string) {}
+
+func (noop) SetManagedInventory(ctx context.Context, inventory []Inventory) {}
+
+func (noop) SetLastFullSync(ctx context.Context, t time.Time) {}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prefix = "sloth"
+
+type prometheusRecorder struct {
+	prometheusRulesPendingDeletion prometheus.Gauge
+	sloGenerationDuration          *prometheus.HistogramVec
+	pslReconcileDuration           *prometheus.HistogramVec
+	pslConsecutiveFailures         *prometheus.GaugeVec
+	managedObjects                 *prometheus.GaugeVec
+	managedSLOs                    *prometheus.GaugeVec
+	managedRules                   *prometheus.GaugeVec
+	lastFullSync                   prometheus.Gauge
+}
+
+// NewPrometheusRecorder returns a new Recorder that knows how to measure
+// using Prometheus as the backend.
+func NewPrometheusRecorder(reg prometheus.Registerer) Recorder {
+	r := prometheusRecorder{
+		prometheusRulesPendingDeletion: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: prefix,
+			Subsystem: "kubernetes",
+			Name:      "prometheus_rules_pending_deletion",
+			Help:      "The number of retained Prometheus operator rules waiting to be deleted.",
+		}),
+		sloGenerationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: prefix,
+			Subsystem: "generate",
+			Name:      "slo_duration_seconds",
+			Help:      "The duration of the SLO rules generation.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"sloth_service", "sloth_slo"}),
+		pslReconcileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: prefix,
+			Subsystem: "kubernetes",
+			Name:      "prometheus_service_level_reconcile_duration_seconds",
+			Help:      "The duration of the PrometheusServiceLevel reconciles by result.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"cluster", "namespace", "name", "result"}),
+		pslConsecutiveFailures: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: prefix,
+			Subsystem: "kubernetes",
+			Name:      "prometheus_service_level_consecutive_failures",
+			Help:      "The number of failed PrometheusServiceLevel reconciles (including retries) since the last successful one.",
+		}, []string{"cluster", "namespace", "name"}),
+		managedObjects: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: prefix,
+			Subsystem: "kubernetes",
+			Name:      "managed_objects",
+			Help:      "The number of SLO spec objects managed by the controller.",
+		}, []string{"namespace", "spec_version"}),
+		managedSLOs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: prefix,
+			Subsystem: "kubernetes",
+			Name:      "managed_slos",
+			Help:      "The number of SLOs managed by the controller.",
+		}, []string{"namespace", "spec_version"}),
+		managedRules: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: prefix,
+			Subsystem: "kubernetes",
+			Name:      "managed_rules",
+			Help:      "The number of Prometheus rules generated by the controller.",
+		}, []string{"namespace", "spec_version"}),
+		lastFullSync: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: prefix,
+			Subsystem: "kubernetes",
+			Name:      "last_full_sync_timestamp_seconds",
+			Help:      "The timestamp of the last time all the managed SLO spec objects were in sync.",
+		}),
+	}
+
+	reg.MustRegister(
+		r.prometheusRulesPendingDeletion,
+		r.sloGenerationDuration,
+		r.pslReconcileDuration,
+		r.pslConsecutiveFailures,
+		r.managedObjects,
+		r.managedSLOs,
+		r.managedRules,
+		r.lastFullSync,
+	)
+
+	return r
+}
+
+func (p prometheusRecorder) SetPrometheusRulesPendingDeletion(ctx context.Context, quantity int) {
+	p.prometheusRulesPendingDeletion.Set(float64(quantity))
+}
+
+func (p prometheusRecorder) ObserveSLOGenerationDuration(ctx context.Context, service, slo string, duration time.Duration) {
+	p.sloGenerationDuration.WithLabelValues(service, slo).Observe(duration.Seconds())
+}
+
+func (p prometheusRecorder) ObservePrometheusServiceLevelReconcile(ctx context.Context, cluster, ns, name, result string, duration time.Duration) {
+	p.pslReconcileDuration.WithLabelValues(cluster, ns, name, result).Observe(duration.Seconds())
+}
+
+func (p prometheusRecorder) SetPrometheusServiceLevelConsecutiveFailures(ctx context.Context, cluster, ns, name string, quantity int) {
+	p.pslConsecutiveFailures.WithLabelValues(cluster, ns, name).Set(float64(quantity))
+}
+
+func (p prometheusRecorder) DeletePrometheusServiceLevelMetrics(ctx context.Context, cluster, ns, name string) {
+	p.pslConsecutiveFailures.DeleteLabelValues(cluster, ns, name)
+	for _, result := range []string{ReconcileResultSuccess, ReconcileResultInvalidSpec, ReconcileResultGenerationFailed, ReconcileResultApplyFailed} {
+		p.pslReconcileDuration.DeleteLabelValues(cluster, ns, name, result)
+	}
+}
+
+func (p prometheusRecorder) SetManagedInventory(ctx context.Context, inventory []Inventory) {
+	// Reset so the namespaces without managed objects anymore are removed.
+	p.managedObjects.Reset()
+	p.managedSLOs.Reset()
+	p.managedRules.Reset()
+	for _, i := range inventory {
+		p.managedObjects.WithLabelValues(i.Namespace, i.SpecVersion).Set(float64(i.Objects))
+		p.managedSLOs.WithLabelValues(i.Namespace, i.SpecVersion).Set(float64(i.SLOs))
+		p.managedRules.WithLabelValues(i.Namespace, i.SpecVersion).Set(float64(i.Rules))
+	}
+}
+
+func (p prometheusRecorder) SetLastFullSync(ctx context.Context, t time.Time) {
+	p.lastFullSync.Set(float64(t.Unix()))
+}
@@ -4,48 +4,155 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"text/template"
+	"time"
 
+	prommodel "github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/rulefmt"
 
 	"github.com/slok/sloth/internal/alert"
 )
 
-// genFunc knows how to generate an SLI recording rule for a specific time window.
-type alertGenFunc func(slo SLO, sloAlert AlertMeta, quick, slow alert.MWMBAlert) (*rulefmt.Rule, error)
+// SLOAlertStrategy knows how to generate the alert rule of an SLO alert severity (page or ticket).
+// It receives the quick and slow multiwindow multiburn alerts of the severity, the strategies
+// can use them as they need (e.g the windows and burn rate factors).
+type SLOAlertStrategy interface {
+	GenerateSLOAlertRule(slo SLO, sloAlert AlertMeta, quick, slow alert.MWMBAlert) (*rulefmt.Rule, error)
+}
+
+// SLOAlertStrategyFunc is a helper to use functions as SLOAlertStrategy.
+type SLOAlertStrategyFunc func(slo SLO, sloAlert AlertMeta, quick, slow alert.MWMBAlert) (*rulefmt.Rule, error)
+
+// GenerateSLOAlertRule satisfies SLOAlertStrategy interface.
+func (f SLOAlertStrategyFunc) GenerateSLOAlertRule(slo SLO, sloAlert AlertMeta, quick, slow alert.MWMBAlert) (*rulefmt.Rule, error) {
+	return f(slo, sloAlert, quick, slow)
+}
 
 type sloAlertRulesGenerator struct {
-	alertGenFunc alertGenFunc
+	strategies map[AlertStrategy]SLOAlertStrategy
 }
 
 // SLOAlertRulesGenerator knows how to generate the SLO prometheus alert rules
-// from an SLO.
-var SLOAlertRulesGenerator = sloAlertRulesGenerator{alertGenFunc: defaultSLOAlertGenerator}
+// from an SLO using the SLO alert strategy.
+var SLOAlertRulesGenerator = NewSLOAlertRulesGenerator(map[AlertStrategy]SLOAlertStrategy{
+	MWMBAlertStrategy:           SLOAlertStrategyFunc(defaultSLOAlertGenerator),
+	SingleWindowAlertStrategy:   SLOAlertStrategyFunc(singleWindowSLOAlertGenerator),
+	BudgetConsumedAlertStrategy: SLOAlertStrategyFunc(budgetConsumedSLOAlertGenerator),
+})
+
+// NewSLOAlertRulesGenerator returns a new SLO alert rules generator with custom alert strategies.
+func NewSLOAlertRulesGenerator(strategies map[AlertStrategy]SLOAlertStrategy) sloAlertRulesGenerator {
+	return sloAlertRulesGenerator{strategies: strategies}
+}
 
 func (s sloAlertRulesGenerator) GenerateSLOAlertRules(ctx context.Context, slo SLO, alerts alert.MWMBAlertGroup) ([]rulefmt.Rule, error) {
 	rules := []rulefmt.Rule{}
 
+	// Get the alert strategy.
+	strategyName := slo.AlertStrategy
+	if strategyName == "" {
+		strategyName = MWMBAlertStrategy
+	}
+	strategy, ok := s.strategies[strategyName]
+	if !ok {
+		return nil, fmt.Errorf("unknown %q alert strategy", strategyName)
+	}
+
 	// Generate Page alerts.
 	if !slo.PageAlertMeta.Disable {
-		rule, err := s.alertGenFunc(slo, slo.PageAlertMeta, alerts.PageQuick, alerts.PageSlow)
+		rule, err := strategy.GenerateSLOAlertRule(slo, slo.PageAlertMeta, alerts.PageQuick, alerts.PageSlow)
 		if err != nil {
 			return nil, fmt.Errorf("could not create page alert: %w", err)
 		}
 
+		rule = runbookURLAlertRule(rule, slo.RunbookURLs.Page)
+		rule, err = renderAlertRuleTemplates(slo, rule, alerts.PageQuick.Severity.String(), alerts.PageQuick.LongWindow)
+		if err != nil {
+			return nil, fmt.Errorf("could not render page alert templates: %w", err)
+		}
+
+		rule, err = minTrafficGuardAlertRule(slo, rule, alerts.PageQuick.LongWindow)
+		if err != nil {
+			return nil, fmt.Errorf("could not guard page alert with min traffic: %w", err)
+		}
+
 		rules = append(rules, *rule)
 	}
 
 	// Generate Ticket alerts.
 	if !slo.WarningAlertMeta.Disable {
-		rule, err := s.alertGenFunc(slo, slo.WarningAlertMeta, alerts.TicketQuick, alerts.TicketSlow)
+		rule, err := strategy.GenerateSLOAlertRule(slo, slo.WarningAlertMeta, alerts.TicketQuick, alerts.TicketSlow)
 		if err != nil {
 			return nil, fmt.Errorf("could not create ticket alert: %w", err)
 		}
 
+		rule = runbookURLAlertRule(rule, slo.RunbookURLs.Ticket)
+		rule, err = renderAlertRuleTemplates(slo, rule, alerts.TicketQuick.Severity.String(), alerts.TicketQuick.LongWindow)
+		if err != nil {
+			return nil, fmt.Errorf("could not render ticket alert templates: %w", err)
+		}
+
+		rule, err = minTrafficGuardAlertRule(slo, rule, alerts.TicketQuick.LongWindow)
+		if err != nil {
+			return nil, fmt.Errorf("could not guard ticket alert with min traffic: %w", err)
+		}
+
+		rules = append(rules, *rule)
+	}
+
+	// Generate the forecast alert.
+	if slo.ForecastAlertMeta != nil {
+		rule, err := forecastSLOAlertGenerator(slo, *slo.ForecastAlertMeta, alerts.TicketQuick)
+		if err != nil {
+			return nil, fmt.Errorf("could not create forecast alert: %w", err)
+		}
+
+		window := slo.ForecastAlertMeta.Window
+		if window == 0 {
+			window = alerts.TicketQuick.LongWindow
+		}
+		rule = runbookURLAlertRule(rule, slo.RunbookURLs.Ticket)
+		rule, err = renderAlertRuleTemplates(slo, rule, alert.TicketAlertSeverity.String(), window)
+		if err != nil {
+			return nil, fmt.Errorf("could not render forecast alert templates: %w", err)
+		}
+
 		rules = append(rules, *rule)
 	}
 
-	return rules, nil
+	if len(rules) == 0 {
+		return rules, nil
+	}
+
+	// The alerts are based on the burn rates, these are recorded with the alerts (before them) so
+	// the alerts work even if the SLI error ratios are recorded by other system (e.g disabled SLI
+	// recordings).
+	windows := getSLIWindows(slo, alerts)
+	res := make([]rulefmt.Rule, 0, len(windows)+len(rules))
+	for _, window := range windows {
+		res = append(res, burnRateRecordGenerator(slo, window))
+	}
+
+	return append(res, rules...), nil
+}
+
+// burnRateRecordGenerator generates the error budget burn rate recording rule of a window
+// based on the window SLI error ratio recording rule.
+func burnRateRecordGenerator(slo SLO, window time.Duration) rulefmt.Rule {
+	strWindow := timeDurationToPromStr(window)
+	return rulefmt.Rule{
+		Record: slo.GetBurnRateMetric(window),
+		Expr: fmt.Sprintf("%s%s\n/ (1 - %s)\n",
+			slo.GetSLIErrorMetric(window), labelsToPromFilter(slo.GetSLOIDPromLabels()), formatRatio(slo.Objective/100)),
+		Labels: mergeLabels(
+			slo.GetSLOIDPromLabels(),
+			map[string]string{
+				slo.GetLabelName(sloWindowLabelName): strWindow,
+			},
+			slo.Labels,
+		),
+	}
 }
 
 func defaultSLOAlertGenerator(slo SLO, sloAlert AlertMeta, quick, slow alert.MWMBAlert) (*rulefmt.Rule, error) {
@@ -55,7 +162,6 @@ func defaultSLOAlertGenerator(slo SLO, sloAlert AlertMeta, quick, slow alert.MWM
 	// Render the alert template.
 	tplData := struct {
 		MetricFilter         string
-		ErrorBudgetRatio     float64
 		QuickShortMetric     string
 		QuickShortBurnFactor float64
 		QuickLongMetric      string
@@ -67,16 +173,15 @@ func defaultSLOAlertGenerator(slo SLO, sloAlert AlertMeta, quick, slow alert.MWM
 		WindowLabel          string
 	}{
 		MetricFilter:         metricFilter,
-		ErrorBudgetRatio:     quick.ErrorBudget / 100, // Any(quick or slow) should work because are the same.
-		QuickShortMetric:     slo.GetSLIErrorMetric(quick.ShortWindow),
+		QuickShortMetric:     slo.GetBurnRateMetric(quick.ShortWindow),
 		QuickShortBurnFactor: quick.BurnRateFactor,
-		QuickLongMetric:      slo.GetSLIErrorMetric(quick.LongWindow),
+		QuickLongMetric:      slo.GetBurnRateMetric(quick.LongWindow),
 		QuickLongBurnFactor:  quick.BurnRateFactor,
-		SlowShortMetric:      slo.GetSLIErrorMetric(slow.ShortWindow),
+		SlowShortMetric:      slo.GetBurnRateMetric(slow.ShortWindow),
 		SlowShortBurnFactor:  slow.BurnRateFactor,
-		SlowQuickMetric:      slo.GetSLIErrorMetric(slow.LongWindow),
+		SlowQuickMetric:      slo.GetBurnRateMetric(slow.LongWindow),
 		SlowQuickBurnFactor:  slow.BurnRateFactor,
-		WindowLabel:          sloWindowLabelName,
+		WindowLabel:          slo.GetLabelName(sloWindowLabelName),
 	}
 	var expr bytes.Buffer
 	err := mwmbAlertTpl.Execute(&expr, tplData)
@@ -84,37 +189,207 @@ func defaultSLOAlertGenerator(slo SLO, sloAlert AlertMeta, quick, slow alert.MWM
 		return nil, fmt.Errorf("could not render alert expression: %w", err)
 	}
 
-	// Add specific annotations.
 	severity := quick.Severity.String() // Any(quick or slow) should work because are the same.
+	return newSLOAlertRule(slo, sloAlert, severity, expr.String(),
+		fmt.Sprintf("(%s) {{$labels.%s}} {{$labels.%s}} SLO error budget burn rate is too fast.", severity, slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName)),
+		fmt.Sprintf("{{$labels.%s}} {{$labels.%s}} SLO error budget burn rate is over expected.", slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName)),
+	), nil
+}
+
+// singleWindowSLOAlertGenerator generates an alert based on a single window burn rate, it uses the
+// quick alert long window and its burn rate factor (e.g: page 1h at 14.4x, ticket 1d at 3x). Simpler
+// to reason about but less precise than the multiwindow multiburn alerts.
+func singleWindowSLOAlertGenerator(slo SLO, sloAlert AlertMeta, quick, slow alert.MWMBAlert) (*rulefmt.Rule, error) {
+	var expr bytes.Buffer
+	err := singleWindowAlertTpl.Execute(&expr, map[string]interface{}{
+		"Metric":       slo.GetBurnRateMetric(quick.LongWindow),
+		"MetricFilter": labelsToPromFilter(slo.GetSLOIDPromLabels()),
+		"BurnFactor":   quick.BurnRateFactor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render alert expression: %w", err)
+	}
+
+	severity := quick.Severity.String()
+	return newSLOAlertRule(slo, sloAlert, severity, expr.String(),
+		fmt.Sprintf("(%s) {{$labels.%s}} {{$labels.%s}} SLO error budget burn rate is too fast.", severity, slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName)),
+		fmt.Sprintf("{{$labels.%s}} {{$labels.%s}} SLO error budget burn rate is over expected.", slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName)),
+	), nil
+}
+
+// Error budget consumed ratios on the SLO period that trigger the budget consumed alerts.
+const (
+	budgetConsumedPageRatio   = 1
+	budgetConsumedTicketRatio = 0.75
+)
+
+// budgetConsumedSLOAlertGenerator generates an alert based on the error budget consumed on the SLO period,
+// the page alert triggers when all the error budget has been consumed and the ticket alert when 75% has
+// been consumed.
+func budgetConsumedSLOAlertGenerator(slo SLO, sloAlert AlertMeta, quick, slow alert.MWMBAlert) (*rulefmt.Rule, error) {
+	consumedRatio := float64(budgetConsumedTicketRatio)
+	if quick.Severity == alert.PageAlertSeverity {
+		consumedRatio = budgetConsumedPageRatio
+	}
+
+	var expr bytes.Buffer
+	err := singleWindowAlertTpl.Execute(&expr, map[string]interface{}{
+		"Metric":       slo.GetBurnRateMetric(slo.TimeWindow),
+		"MetricFilter": labelsToPromFilter(slo.GetSLOIDPromLabels()),
+		"BurnFactor":   consumedRatio,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render alert expression: %w", err)
+	}
+
+	severity := quick.Severity.String()
+	return newSLOAlertRule(slo, sloAlert, severity, expr.String(),
+		fmt.Sprintf("(%s) {{$labels.%s}} {{$labels.%s}} SLO error budget consumed over %g%%.", severity, slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName), consumedRatio*100),
+		fmt.Sprintf("{{$labels.%s}} {{$labels.%s}} SLO error budget consumption on the period is over expected.", slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName)),
+	), nil
+}
+
+// forecastSLOAlertGenerator generates an alert that triggers when the error budget will be exhausted
+// before the SLO period ends at the current burn rate. The burn rate is measured on the forecast
+// window (by default the ticket quick alert long window). At burn rate B the remaining budget ratio R
+// is consumed in `R * period / B`, so the alert condition is `B > R` (`R = 1 - period_burn_rate`).
+func forecastSLOAlertGenerator(slo SLO, forecastAlert ForecastAlertMeta, ticket alert.MWMBAlert) (*rulefmt.Rule, error) {
+	window := forecastAlert.Window
+	if window == 0 {
+		window = ticket.LongWindow
+	}
+
+	filter := labelsToPromFilter(slo.GetSLOIDPromLabels())
+	var expr bytes.Buffer
+	err := forecastAlertTpl.Execute(&expr, map[string]interface{}{
+		"Metric":       slo.GetBurnRateMetric(window),
+		"PeriodMetric": slo.GetBurnRateMetric(slo.TimeWindow),
+		"MetricFilter": filter,
+		"WindowLabel":  slo.GetLabelName(sloWindowLabelName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render alert expression: %w", err)
+	}
+
+	severity := alert.TicketAlertSeverity.String()
+	rule := newSLOAlertRule(slo, AlertMeta{
+		Name:        forecastAlert.Name,
+		Labels:      forecastAlert.Labels,
+		Annotations: forecastAlert.Annotations,
+	}, severity, expr.String(),
+		fmt.Sprintf("(%s) {{$labels.%s}} {{$labels.%s}} SLO error budget will be exhausted before the end of the period.", severity, slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName)),
+		fmt.Sprintf("{{$labels.%s}} {{$labels.%s}} SLO error budget is forecasted to be exhausted at the current burn rate.", slo.GetLabelName(sloServiceLabelName), slo.GetLabelName(sloNameLabelName)),
+	)
+
+	return rule, nil
+}
+
+// runbookURLAlertRule sets the runbook URL annotation on the alert rule, the alert annotations
+// take precedence so the alerts that already have one are not changed.
+func runbookURLAlertRule(rule *rulefmt.Rule, runbookURL string) *rulefmt.Rule {
+	if runbookURL == "" {
+		return rule
+	}
+
+	if _, ok := rule.Annotations[runbookURLAnnotationName]; ok {
+		return rule
+	}
+
+	withRunbook := *rule
+	withRunbook.Annotations = mergeLabels(rule.Annotations, map[string]string{runbookURLAnnotationName: runbookURL})
+
+	return &withRunbook
+}
+
+// minTrafficGuardAlertRule makes the alert rule only trigger when the SLO has the minimum
+// traffic required, the traffic is measured on the alert window.
+func minTrafficGuardAlertRule(slo SLO, rule *rulefmt.Rule, window time.Duration) (*rulefmt.Rule, error) {
+	if slo.MinTraffic == nil {
+		return rule, nil
+	}
+
+	trafficTpl, err := template.New("minTraffic").Option("missingkey=error").Parse(slo.MinTraffic.Query)
+	if err != nil {
+		return nil, fmt.Errorf("could not create min traffic query template: %w", err)
+	}
+
+	var traffic bytes.Buffer
+	err = trafficTpl.Execute(&traffic, map[string]string{
+		tplKeyWindow: timeDurationToPromStr(window),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render min traffic query template: %w", err)
+	}
+
+	var expr bytes.Buffer
+	err = minTrafficAlertTpl.Execute(&expr, map[string]interface{}{
+		"Expr":         strings.TrimSuffix(rule.Expr, "\n"),
+		"TrafficQuery": strings.TrimSpace(traffic.String()),
+		"MinRate":      slo.MinTraffic.Rate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render alert expression: %w", err)
+	}
+
+	guarded := *rule
+	guarded.Expr = expr.String()
+
+	return &guarded, nil
+}
+
+// newSLOAlertRule returns an alert rule with the common Sloth alert labels and annotations.
+func newSLOAlertRule(slo SLO, sloAlert AlertMeta, severity, expr, title, summary string) *rulefmt.Rule {
+	// Add specific annotations.
 	extraAnnotations := map[string]string{
-		"title":   fmt.Sprintf("(%s) {{$labels.%s}} {{$labels.%s}} SLO error budget burn rate is too fast.", severity, sloServiceLabelName, sloNameLabelName),
-		"summary": fmt.Sprintf("{{$labels.%s}} {{$labels.%s}} SLO error budget burn rate is over expected.", sloServiceLabelName, sloNameLabelName),
+		"title":   title,
+		"summary": summary,
+	}
+	if len(slo.DependsOn) > 0 {
+		extraAnnotations[dependsOnAnnotationName] = slo.getDependsOn()
 	}
 
 	// Add specific labels. We don't add the labels from the rules because we will
 	// inherit on the alerts, this way we avoid warnings of overrided labels.
-	extraLabels := map[string]string{
-		sloSeverityLabelName: severity,
-	}
+	extraLabels := map[string]string{}
+	slo.setSlothLabel(extraLabels, sloSeverityLabelName, severity)
 
 	return &rulefmt.Rule{
 		Alert:       sloAlert.Name,
-		Expr:        expr.String(),
+		Expr:        expr,
+		For:         prommodel.Duration(sloAlert.For),
 		Annotations: mergeLabels(extraAnnotations, sloAlert.Annotations),
 		Labels:      mergeLabels(extraLabels, sloAlert.Labels),
-	}, nil
+	}
 }
 
 // Multiburn multiwindow alert template.
 var mwmbAlertTpl = template.Must(template.New("mwmbAlertTpl").Option("missingkey=error").Parse(`(
-    ({{ .QuickShortMetric }}{{ .MetricFilter}} > ({{ .QuickShortBurnFactor }} * {{ .ErrorBudgetRatio }}))
+    ({{ .QuickShortMetric }}{{ .MetricFilter}} > {{ .QuickShortBurnFactor }})
     and ignoring ({{ .WindowLabel }})
-    ({{ .QuickLongMetric }}{{ .MetricFilter}} > ({{ .QuickLongBurnFactor }} * {{ .ErrorBudgetRatio }}))
+    ({{ .QuickLongMetric }}{{ .MetricFilter}} > {{ .QuickLongBurnFactor }})
 )
 or ignoring ({{ .WindowLabel }})
 (
-    ({{ .SlowShortMetric }}{{ .MetricFilter }} > ({{ .SlowShortBurnFactor }} * {{ .ErrorBudgetRatio }}))
+    ({{ .SlowShortMetric }}{{ .MetricFilter }} > {{ .SlowShortBurnFactor }})
     and ignoring ({{ .WindowLabel }})
-    ({{ .SlowQuickMetric }}{{ .MetricFilter }} > ({{ .SlowQuickBurnFactor }} * {{ .ErrorBudgetRatio }}))
+    ({{ .SlowQuickMetric }}{{ .MetricFilter }} > {{ .SlowQuickBurnFactor }})
+)
+`))
+
+// Single window alert template.
+var singleWindowAlertTpl = template.Must(template.New("singleWindowAlertTpl").Option("missingkey=error").Parse(`{{ .Metric }}{{ .MetricFilter }} > {{ .BurnFactor }}
+`))
+
+// Forecast alert template.
+var forecastAlertTpl = template.Must(template.New("forecastAlertTpl").Option("missingkey=error").Parse(`{{ .Metric }}{{ .MetricFilter }}
+> ignoring ({{ .WindowLabel }})
+(1 - {{ .PeriodMetric }}{{ .MetricFilter }})
+`))
+
+// Min traffic guard alert template.
+var minTrafficAlertTpl = template.Must(template.New("minTrafficAlertTpl").Option("missingkey=error").Parse(`(
+{{ .Expr }}
 )
+and on()
+({{ .TrafficQuery }}) >= {{ .MinRate }}
 `))
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	prommodel "github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/rulefmt"
 	"github.com/stretchr/testify/assert"
 
@@ -77,15 +78,15 @@ func TestGenerateSLOAlertRules(t *testing.T) {
 				{
 					Alert: "something1",
 					Expr: `(
-    (slo:sli_error:ratio_rate11m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (13 * 0.01))
+    (slo:burn_rate11m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (13 * 0.01))
+    (slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
 )
 or ignoring (sloth_window)
 (
-    (slo:sli_error:ratio_rate21m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (23 * 0.01))
+    (slo:burn_rate21m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate22m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (23 * 0.01))
+    (slo:burn_rate22m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
 )
 `,
 					Labels: map[string]string{
@@ -101,15 +102,15 @@ or ignoring (sloth_window)
 				{
 					Alert: "something2",
 					Expr: `(
-    (slo:sli_error:ratio_rate31m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (33 * 0.01))
+    (slo:burn_rate31m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (33 * 0.01))
+    (slo:burn_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
 )
 or ignoring (sloth_window)
 (
-    (slo:sli_error:ratio_rate41m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (43 * 0.01))
+    (slo:burn_rate41m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate42m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (43 * 0.01))
+    (slo:burn_rate42m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
 )
 `,
 					Labels: map[string]string{
@@ -144,15 +145,15 @@ or ignoring (sloth_window)
 				{
 					Alert: "something1",
 					Expr: `(
-    (slo:sli_error:ratio_rate11m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (13 * 0.01))
+    (slo:burn_rate11m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (13 * 0.01))
+    (slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
 )
 or ignoring (sloth_window)
 (
-    (slo:sli_error:ratio_rate21m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (23 * 0.01))
+    (slo:burn_rate21m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate22m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (23 * 0.01))
+    (slo:burn_rate22m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
 )
 `,
 					Labels: map[string]string{
@@ -186,15 +187,15 @@ or ignoring (sloth_window)
 				{
 					Alert: "something2",
 					Expr: `(
-    (slo:sli_error:ratio_rate31m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (33 * 0.01))
+    (slo:burn_rate31m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (33 * 0.01))
+    (slo:burn_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
 )
 or ignoring (sloth_window)
 (
-    (slo:sli_error:ratio_rate41m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (43 * 0.01))
+    (slo:burn_rate41m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
     and ignoring (sloth_window)
-    (slo:sli_error:ratio_rate42m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > (43 * 0.01))
+    (slo:burn_rate42m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
 )
 `,
 					Labels: map[string]string{
@@ -209,6 +210,510 @@ or ignoring (sloth_window)
 				},
 			},
 		},
+
+		"Having an SLO with single window alert strategy should create the single window alert rules.": {
+			slo: prometheus.SLO{
+				ID:            "test-svc-test",
+				Name:          "test",
+				Service:       "test-svc",
+				AlertStrategy: prometheus.SingleWindowAlertStrategy,
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Name: "something2",
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13
+`,
+					Labels: map[string]string{
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":   "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+				{
+					Alert: "something2",
+					Expr: `slo:burn_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33
+`,
+					Labels: map[string]string{
+						"sloth_severity": "ticket",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":   "(ticket) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with budget consumed alert strategy should create the budget consumed alert rules.": {
+			slo: prometheus.SLO{
+				ID:            "test-svc-test",
+				Name:          "test",
+				Service:       "test-svc",
+				TimeWindow:    30 * 24 * time.Hour,
+				AlertStrategy: prometheus.BudgetConsumedAlertStrategy,
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Name: "something2",
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `slo:burn_rate30d{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 1
+`,
+					Labels: map[string]string{
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget consumption on the period is over expected.",
+						"title":   "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget consumed over 100%.",
+					},
+				},
+				{
+					Alert: "something2",
+					Expr: `slo:burn_rate30d{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 0.75
+`,
+					Labels: map[string]string{
+						"sloth_severity": "ticket",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget consumption on the period is over expected.",
+						"title":   "(ticket) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget consumed over 75%.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with page and ticket alerts for should set the for on the alert rules.": {
+			slo: prometheus.SLO{
+				ID:            "test-svc-test",
+				Name:          "test",
+				Service:       "test-svc",
+				AlertStrategy: prometheus.SingleWindowAlertStrategy,
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+					For:  5 * time.Minute,
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Name: "something2",
+					For:  1 * time.Hour,
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13
+`,
+					For: prommodel.Duration(5 * time.Minute),
+					Labels: map[string]string{
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":   "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+				{
+					Alert: "something2",
+					Expr: `slo:burn_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33
+`,
+					For: prommodel.Duration(1 * time.Hour),
+					Labels: map[string]string{
+						"sloth_severity": "ticket",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":   "(ticket) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with min traffic should guard the alert rules with the min traffic.": {
+			slo: prometheus.SLO{
+				ID:            "test-svc-test",
+				Name:          "test",
+				Service:       "test-svc",
+				AlertStrategy: prometheus.SingleWindowAlertStrategy,
+				MinTraffic: &prometheus.MinTraffic{
+					Query: "sum(rate(http_requests_total[{{.window}}]))",
+					Rate:  0.5,
+				},
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `(
+slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13
+)
+and on()
+(sum(rate(http_requests_total[12m]))) >= 0.5
+`,
+					Labels: map[string]string{
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":   "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with a forecast alert should create the forecast alert rule.": {
+			slo: prometheus.SLO{
+				ID:         "test-svc-test",
+				Name:       "test",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				PageAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+				ForecastAlertMeta: &prometheus.ForecastAlertMeta{
+					Name:        "something3",
+					Labels:      map[string]string{"custom-label": "test3"},
+					Annotations: map[string]string{"custom-annot": "test3"},
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something3",
+					Expr: `slo:burn_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"}
+> ignoring (sloth_window)
+(1 - slo:burn_rate30d{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"})
+`,
+					Labels: map[string]string{
+						"custom-label":   "test3",
+						"sloth_severity": "ticket",
+					},
+					Annotations: map[string]string{
+						"custom-annot": "test3",
+						"summary":      "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget is forecasted to be exhausted at the current burn rate.",
+						"title":        "(ticket) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget will be exhausted before the end of the period.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with a forecast alert with custom window should use the custom window.": {
+			slo: prometheus.SLO{
+				ID:         "test-svc-test",
+				Name:       "test",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				PageAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+				ForecastAlertMeta: &prometheus.ForecastAlertMeta{
+					Name:   "something3",
+					Window: 6 * time.Hour,
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something3",
+					Expr: `slo:burn_rate6h{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"}
+> ignoring (sloth_window)
+(1 - slo:burn_rate30d{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"})
+`,
+					Labels: map[string]string{
+						"sloth_severity": "ticket",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget is forecasted to be exhausted at the current burn rate.",
+						"title":   "(ticket) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget will be exhausted before the end of the period.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with alert templates should render the templates with the SLO metadata.": {
+			slo: prometheus.SLO{
+				ID:          "test-svc-test",
+				Name:        "test",
+				Service:     "test-svc",
+				Description: "checkout requests",
+				Objective:   99.9,
+				TimeWindow:  30 * 24 * time.Hour,
+				Labels:      map[string]string{"team": "payments"},
+				PageAlertMeta: prometheus.AlertMeta{
+					Name:   "something1",
+					Labels: map[string]string{"team": "[[ .labels.team | upper ]]"},
+					Annotations: map[string]string{
+						"message": "[[ .description | title ]] [[ .objective ]]% ([[ .error_budget ]]% budget) on [[ .period ]], burning on [[ .window ]] ({{ $value | humanize }}).",
+						"runbook": "https://runbooks.test/[[ .service ]]/[[ .slo | replace \"-\" \"_\" ]]?severity=[[ .severity ]]",
+					},
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `(
+    (slo:burn_rate11m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
+    and ignoring (sloth_window)
+    (slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
+)
+or ignoring (sloth_window)
+(
+    (slo:burn_rate21m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
+    and ignoring (sloth_window)
+    (slo:burn_rate22m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
+)
+`,
+					Labels: map[string]string{
+						"team":           "PAYMENTS",
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"message": "Checkout Requests 99.9% (0.1% budget) on 30d, burning on 12m ({{ $value | humanize }}).",
+						"runbook": "https://runbooks.test/test-svc/test?severity=page",
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":   "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with runbook URLs should set the rendered runbook URL annotation on the alerts that don't have it.": {
+			slo: prometheus.SLO{
+				ID:      "test-svc-test",
+				Name:    "test",
+				Service: "test-svc",
+				RunbookURLs: prometheus.RunbookURLs{
+					Page:   "https://runbooks.test/[[ .service ]]/[[ .slo ]]?severity=[[ .severity ]]",
+					Ticket: "https://runbooks.test/[[ .service ]]/[[ .slo ]]?severity=[[ .severity ]]",
+				},
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Name:        "something2",
+					Annotations: map[string]string{"runbook_url": "https://runbooks.test/custom"},
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `(
+    (slo:burn_rate11m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
+    and ignoring (sloth_window)
+    (slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
+)
+or ignoring (sloth_window)
+(
+    (slo:burn_rate21m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
+    and ignoring (sloth_window)
+    (slo:burn_rate22m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
+)
+`,
+					Labels: map[string]string{
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"runbook_url": "https://runbooks.test/test-svc/test?severity=page",
+						"summary":     "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":       "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+				{
+					Alert: "something2",
+					Expr: `(
+    (slo:burn_rate31m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
+    and ignoring (sloth_window)
+    (slo:burn_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
+)
+or ignoring (sloth_window)
+(
+    (slo:burn_rate41m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
+    and ignoring (sloth_window)
+    (slo:burn_rate42m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
+)
+`,
+					Labels: map[string]string{
+						"sloth_severity": "ticket",
+					},
+					Annotations: map[string]string{
+						"runbook_url": "https://runbooks.test/custom",
+						"summary":     "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":       "(ticket) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with dependencies should set the dependencies annotation on the alerts.": {
+			slo: prometheus.SLO{
+				ID:        "test-svc-test",
+				Name:      "test",
+				Service:   "test-svc",
+				DependsOn: []string{"payments-requests", "db-queries"},
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Name: "something2",
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `(
+    (slo:burn_rate11m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
+    and ignoring (sloth_window)
+    (slo:burn_rate12m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 13)
+)
+or ignoring (sloth_window)
+(
+    (slo:burn_rate21m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
+    and ignoring (sloth_window)
+    (slo:burn_rate22m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 23)
+)
+`,
+					Labels: map[string]string{
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"depends_on": "db-queries,payments-requests",
+						"summary":    "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":      "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+				{
+					Alert: "something2",
+					Expr: `(
+    (slo:burn_rate31m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
+    and ignoring (sloth_window)
+    (slo:burn_rate32m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 33)
+)
+or ignoring (sloth_window)
+(
+    (slo:burn_rate41m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
+    and ignoring (sloth_window)
+    (slo:burn_rate42m{sloth_id="test-svc-test", sloth_service="test-svc", sloth_slo="test"} > 43)
+)
+`,
+					Labels: map[string]string{
+						"sloth_severity": "ticket",
+					},
+					Annotations: map[string]string{
+						"depends_on": "db-queries,payments-requests",
+						"summary":    "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is over expected.",
+						"title":      "(ticket) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with customized Sloth labels should create the alert rules using the customized labels.": {
+			slo: prometheus.SLO{
+				ID:      "test-svc-test",
+				Name:    "test",
+				Service: "test-svc",
+				SlothLabels: prometheus.SlothLabels{
+					Prefix: "myorg",
+					Rename: map[string]string{"sloth_window": "window", "sloth_severity": "severity"},
+				},
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				{
+					Alert: "something1",
+					Expr: `(
+    (slo:burn_rate11m{myorg_id="test-svc-test", myorg_service="test-svc", myorg_slo="test"} > 13)
+    and ignoring (window)
+    (slo:burn_rate12m{myorg_id="test-svc-test", myorg_service="test-svc", myorg_slo="test"} > 13)
+)
+or ignoring (window)
+(
+    (slo:burn_rate21m{myorg_id="test-svc-test", myorg_service="test-svc", myorg_slo="test"} > 23)
+    and ignoring (window)
+    (slo:burn_rate22m{myorg_id="test-svc-test", myorg_service="test-svc", myorg_slo="test"} > 23)
+)
+`,
+					Labels: map[string]string{
+						"severity": "page",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.myorg_service}} {{$labels.myorg_slo}} SLO error budget burn rate is over expected.",
+						"title":   "(page) {{$labels.myorg_service}} {{$labels.myorg_slo}} SLO error budget burn rate is too fast.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with invalid alert templates should fail.": {
+			slo: prometheus.SLO{
+				ID:      "test-svc-test",
+				Name:    "test",
+				Service: "test-svc",
+				PageAlertMeta: prometheus.AlertMeta{
+					Name:        "something1",
+					Annotations: map[string]string{"message": "[[ .unknown ]]"},
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expErr:     true,
+		},
+
+		"Having an SLO with an unknown alert strategy should fail.": {
+			slo: prometheus.SLO{
+				ID:            "test-svc-test",
+				Name:          "test",
+				Service:       "test-svc",
+				AlertStrategy: "unknown",
+				PageAlertMeta: prometheus.AlertMeta{
+					Name: "something1",
+				},
+				WarningAlertMeta: prometheus.AlertMeta{
+					Disable: true,
+				},
+			},
+			alertGroup: getSLOAlertGroup,
+			expErr:     true,
+		},
 	}
 
 	for name, test := range tests {
@@ -220,6 +725,98 @@ or ignoring (sloth_window)
 			if test.expErr {
 				assert.Error(err)
 			} else if assert.NoError(err) {
+				// The burn rate recordings are checked on TestGenerateSLOAlertRulesBurnRateRecordings.
+				var gotAlertRules []rulefmt.Rule
+				for _, rule := range gotRules {
+					if rule.Alert != "" {
+						gotAlertRules = append(gotAlertRules, rule)
+					}
+				}
+				assert.Equal(test.expRules, gotAlertRules)
+			}
+		})
+	}
+}
+
+func TestGenerateSLOAlertRulesBurnRateRecordings(t *testing.T) {
+	getBurnRateRule := func(window string, labels map[string]string) rulefmt.Rule {
+		return rulefmt.Rule{
+			Record: "slo:burn_rate" + window,
+			Expr:   "slo:sli_error:ratio_rate" + window + "{sloth_id=\"test-svc-test\", sloth_service=\"test-svc\", sloth_slo=\"test\"}\n/ (1 - 0.999)\n",
+			Labels: labels,
+		}
+	}
+	getLabels := func(window string) map[string]string {
+		return map[string]string{
+			"sloth_id":      "test-svc-test",
+			"sloth_service": "test-svc",
+			"sloth_slo":     "test",
+			"sloth_window":  window,
+		}
+	}
+
+	tests := map[string]struct {
+		slo        prometheus.SLO
+		alertGroup func() alert.MWMBAlertGroup
+		expRules   []rulefmt.Rule
+	}{
+		"Having an SLO with alerts should record the burn rates of the alert windows and the period before the alerts.": {
+			slo: prometheus.SLO{
+				ID:               "test-svc-test",
+				Name:             "test",
+				Service:          "test-svc",
+				Objective:        99.9,
+				TimeWindow:       30 * 24 * time.Hour,
+				AlertStrategy:    prometheus.BudgetConsumedAlertStrategy,
+				PageAlertMeta:    prometheus.AlertMeta{Name: "something1"},
+				WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules: []rulefmt.Rule{
+				getBurnRateRule("11m", getLabels("11m")),
+				getBurnRateRule("12m", getLabels("12m")),
+				getBurnRateRule("21m", getLabels("21m")),
+				getBurnRateRule("22m", getLabels("22m")),
+				getBurnRateRule("31m", getLabels("31m")),
+				getBurnRateRule("32m", getLabels("32m")),
+				getBurnRateRule("41m", getLabels("41m")),
+				getBurnRateRule("42m", getLabels("42m")),
+				getBurnRateRule("30d", getLabels("30d")),
+				{
+					Alert: "something1",
+					Expr:  "slo:burn_rate30d{sloth_id=\"test-svc-test\", sloth_service=\"test-svc\", sloth_slo=\"test\"} > 1\n",
+					Labels: map[string]string{
+						"sloth_severity": "page",
+					},
+					Annotations: map[string]string{
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget consumption on the period is over expected.",
+						"title":   "(page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget consumed over 100%.",
+					},
+				},
+			},
+		},
+
+		"Having an SLO without alerts shouldn't record the burn rates.": {
+			slo: prometheus.SLO{
+				ID:               "test-svc-test",
+				Name:             "test",
+				Service:          "test-svc",
+				Objective:        99.9,
+				TimeWindow:       30 * 24 * time.Hour,
+				PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+				WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+			},
+			alertGroup: getSLOAlertGroup,
+			expRules:   []rulefmt.Rule{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotRules, err := prometheus.SLOAlertRulesGenerator.GenerateSLOAlertRules(context.TODO(), test.slo, test.alertGroup())
+			if assert.NoError(err) {
 				assert.Equal(test.expRules, gotRules)
 			}
 		})
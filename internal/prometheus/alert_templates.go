@@ -0,0 +1,126 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+)
+
+// Alert templates use their own delimiters so they don't collide with the Prometheus
+// alert templates (e.g `{{ $labels.instance }}`) that are rendered by Prometheus.
+const (
+	alertTplLeftDelim  = "[["
+	alertTplRightDelim = "]]"
+)
+
+const (
+	tplKeyAlertID          = "id"
+	tplKeyAlertService     = "service"
+	tplKeyAlertSLO         = "slo"
+	tplKeyAlertDescription = "description"
+	tplKeyAlertObjective   = "objective"
+	tplKeyAlertErrorBudget = "error_budget"
+	tplKeyAlertPeriod      = "period"
+	tplKeyAlertWindow      = "window"
+	tplKeyAlertSeverity    = "severity"
+	tplKeyAlertLabels      = "labels"
+)
+
+// alertTplFuncs are the functions available on the alert templates, these are a subset of
+// the common sprig functions (https://masterminds.github.io/sprig) with the same signatures.
+var alertTplFuncs = template.FuncMap{
+	// Strings.
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"title":      strings.Title,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+	"quote":      strconv.Quote,
+	"squote":     func(s string) string { return "'" + s + "'" },
+	"join":       func(sep string, l []string) string { return strings.Join(l, sep) },
+	"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+	"default": func(def interface{}, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+
+	// Numbers.
+	"add":   func(a, b float64) float64 { return a + b },
+	"sub":   func(a, b float64) float64 { return a - b },
+	"mul":   func(a, b float64) float64 { return a * b },
+	"div":   func(a, b float64) float64 { return a / b },
+	"round": func(a float64, decimals int) float64 { p := math.Pow10(decimals); return math.Round(a*p) / p },
+}
+
+// renderAlertRuleTemplates renders the alert templates of the alert rule labels and annotations
+// with the SLO metadata, the window is the alert window (e.g the quick alert long window).
+func renderAlertRuleTemplates(slo SLO, rule *rulefmt.Rule, severity string, window time.Duration) (*rulefmt.Rule, error) {
+	errorBudget, _ := strconv.ParseFloat(formatRatio(100-slo.Objective), 64)
+	data := map[string]interface{}{
+		tplKeyAlertID:          slo.ID,
+		tplKeyAlertService:     slo.Service,
+		tplKeyAlertSLO:         slo.Name,
+		tplKeyAlertDescription: slo.Description,
+		tplKeyAlertObjective:   slo.Objective,
+		tplKeyAlertErrorBudget: errorBudget,
+		tplKeyAlertPeriod:      timeDurationToPromStr(slo.TimeWindow),
+		tplKeyAlertWindow:      timeDurationToPromStr(window),
+		tplKeyAlertSeverity:    severity,
+		tplKeyAlertLabels:      slo.Labels,
+	}
+
+	labels, err := renderAlertTemplates(rule.Labels, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid labels: %w", err)
+	}
+
+	annotations, err := renderAlertTemplates(rule.Annotations, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotations: %w", err)
+	}
+
+	rendered := *rule
+	rendered.Labels = labels
+	rendered.Annotations = annotations
+
+	return &rendered, nil
+}
+
+func renderAlertTemplates(m map[string]string, data map[string]interface{}) (map[string]string, error) {
+	res := make(map[string]string, len(m))
+	for k, v := range m {
+		// Only the values with templates need to be rendered.
+		if !strings.Contains(v, alertTplLeftDelim) {
+			res[k] = v
+			continue
+		}
+
+		tpl, err := template.New(k).Delims(alertTplLeftDelim, alertTplRightDelim).Option("missingkey=error").Funcs(alertTplFuncs).Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %q template: %w", k, err)
+		}
+
+		var b bytes.Buffer
+		err = tpl.Execute(&b, data)
+		if err != nil {
+			return nil, fmt.Errorf("could not render %q template: %w", k, err)
+		}
+		res[k] = b.String()
+	}
+
+	return res, nil
+}
@@ -1,13 +1,32 @@
 package prometheus
 
 const (
-	sliErrorMetricFmt    = "slo:sli_error:ratio_rate%s"
-	sloNameLabelName     = "sloth_slo"
-	sloIDLabelName       = "sloth_id"
-	sloServiceLabelName  = "sloth_service"
-	sloWindowLabelName   = "sloth_window"
-	sloSeverityLabelName = "sloth_severity"
-	sloVersionLabelName  = "sloth_version"
-	sloModeLabelName     = "sloth_mode"
-	sloSpecLabelName     = "sloth_spec"
+	defaultRecordingMetricPrefix = "slo"
+	defaultMetricPrefix          = "sloth"
+	sliErrorMetricFmt            = "slo:sli_error:ratio_rate%s"
+	sliTimeSliceMetric           = "slo:sli_error:time_slice"
+	sliTotalMetricFmt            = "slo:sli_total:rate%s"
+	defaultRuleGroupNameTpl      = "sloth-slo-{{ .category }}-{{ .id }}"
+	burnRateMetricFmt            = "slo:burn_rate%s"
+	sloNameLabelName             = "sloth_slo"
+	sloIDLabelName               = "sloth_id"
+	sloServiceLabelName          = "sloth_service"
+	sloWindowLabelName           = "sloth_window"
+	sloSeverityLabelName         = "sloth_severity"
+	sloVersionLabelName          = "sloth_version"
+	sloModeLabelName             = "sloth_mode"
+	sloSpecLabelName             = "sloth_spec"
+	sloDependsOnLabelName        = "sloth_depends_on"
+	runbookURLAnnotationName     = "runbook_url"
+	dependsOnAnnotationName      = "depends_on"
 )
+
+// reservedLabelNames are the labels that Sloth sets on the generated rules and can't
+// be overridden by the users.
+var reservedLabelNames = map[string]struct{}{
+	sloNameLabelName:     {},
+	sloIDLabelName:       {},
+	sloServiceLabelName:  {},
+	sloWindowLabelName:   {},
+	sloSeverityLabelName: {},
+}
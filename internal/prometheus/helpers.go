@@ -20,6 +20,20 @@ func mergeLabels(ms ...map[string]string) map[string]string {
 	return res
 }
 
+// mergeAlertTierLabels merges the common alert labels/annotations with the
+// specific alert tier (page, ticket) ones. The tier takes precedence over the
+// common block and a tier key with an empty value will unset the common key.
+func mergeAlertTierLabels(common, tier map[string]string) map[string]string {
+	res := mergeLabels(common, tier)
+	for k, v := range tier {
+		if v == "" {
+			delete(res, k)
+		}
+	}
+
+	return res
+}
+
 func labelsToPromFilter(labels map[string]string) string {
 	metricFilters := prommodel.LabelSet{}
 	for k, v := range labels {
@@ -56,3 +70,24 @@ func getAlertGroupWindows(alerts alert.MWMBAlertGroup) []time.Duration {
 
 	return res
 }
+
+// getSLIWindows returns the windows that need the SLI recordings: the alert windows, the
+// forecast alert window and the SLO period time window as the last one.
+func getSLIWindows(slo SLO, alerts alert.MWMBAlertGroup) []time.Duration {
+	windows := getAlertGroupWindows(alerts)
+	if slo.ForecastAlertMeta != nil && slo.ForecastAlertMeta.Window != 0 && !containsWindow(windows, slo.ForecastAlertMeta.Window) {
+		windows = append(windows, slo.ForecastAlertMeta.Window)
+		sort.SliceStable(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	}
+
+	return append(windows, slo.TimeWindow) // Add the total time window as a handy helper.
+}
+
+func containsWindow(windows []time.Duration, window time.Duration) bool {
+	for _, w := range windows {
+		if w == window {
+			return true
+		}
+	}
+	return false
+}
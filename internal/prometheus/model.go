@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
@@ -12,43 +15,197 @@ import (
 	prommodel "github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/rulefmt"
 	promqlparser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/slok/sloth/internal/alert"
 )
 
 // SLI reprensents an SLI with custom error and total expressions.
 type SLI struct {
-	Raw    *SLIRaw
-	Events *SLIEvents
+	Raw             *SLIRaw
+	Events          *SLIEvents
+	Composite       *SLIComposite
+	Weighted        *SLIWeighted
+	Aggregated      *SLIAggregated
+	NativeHistogram *SLINativeHistogram
 }
 
 type SLIRaw struct {
-	ErrorRatioQuery string `validate:"required,prom_expr,template_vars"`
+	ErrorRatioQuery string `validate:"required,prom_expr,prom_ratio_expr,template_vars"`
 }
 
+// SLIEvents is an SLI based on error or good events, only one of them can be set.
 type SLIEvents struct {
-	ErrorQuery string `validate:"required,prom_expr,template_vars"`
+	ErrorQuery string `validate:"omitempty,prom_expr,template_vars"`
+	GoodQuery  string `validate:"omitempty,prom_expr,template_vars"`
 	TotalQuery string `validate:"required,prom_expr,template_vars"`
 }
 
+// SLIComposite is an SLI composed of multiple SLIs that need to hold at the same time.
+type SLIComposite struct {
+	SLIs []SLI `validate:"min=2,dive"`
+}
+
+// SLIWeighted is an SLI that is the weighted combination of the SLI error ratios
+// of other SLOs, these SLOs need to be on the same SLO group.
+type SLIWeighted struct {
+	SLOs []WeightedSLO `validate:"min=2,dive"`
+}
+
+// WeightedSLO is an SLO (by ID) that is part of a weighted SLI.
+type WeightedSLO struct {
+	ID     string  `validate:"required,name"`
+	Weight float64 `validate:"gt=0"`
+}
+
+// SLIAggregated is an SLI that aggregates the SLI recordings of an SLO (by ID) generated on
+// multiple places (e.g clusters or tenants), the Labels identify each of these places.
+type SLIAggregated struct {
+	ID     string   `validate:"required,name"`
+	Labels []string `validate:"min=1,dive,prom_label_key"`
+}
+
+// CalendarPeriod is the calendar boundary an SLO time window is aligned to, the error
+// budget resets on every calendar period start instead of being a rolling window.
+type CalendarPeriod string
+
+const (
+	// NoCalendarPeriod is a rolling time window.
+	NoCalendarPeriod CalendarPeriod = ""
+	// CalendarMonthPeriod resets the error budget on every calendar month.
+	CalendarMonthPeriod CalendarPeriod = "month"
+	// CalendarQuarterPeriod resets the error budget on every calendar quarter.
+	CalendarQuarterPeriod CalendarPeriod = "quarter"
+)
+
+// SLINativeHistogram is an SLI based on a native histogram, the observations above the
+// threshold are the bad events.
+type SLINativeHistogram struct {
+	Selector  string  `validate:"required,prom_selector"`
+	Threshold float64 `validate:"gt=0"`
+}
+
+// AlertStrategy is the strategy used to generate the SLO alerts.
+type AlertStrategy string
+
+const (
+	// MWMBAlertStrategy are the multiwindow multiburn alerts, the default one.
+	MWMBAlertStrategy AlertStrategy = "multiwindow-multiburn"
+	// SingleWindowAlertStrategy are the single window burn rate alerts.
+	SingleWindowAlertStrategy AlertStrategy = "single-window"
+	// BudgetConsumedAlertStrategy are the alerts based on the error budget consumed on the period.
+	BudgetConsumedAlertStrategy AlertStrategy = "budget-consumed"
+)
+
 // AlertMeta is the metadata of an alert settings.
 type AlertMeta struct {
 	Disable     bool
 	Name        string            `validate:"required_if_enabled"`
-	Labels      map[string]string `validate:"dive,keys,prom_label_key,endkeys,required,prom_label_value"`
+	Labels      map[string]string `validate:"dive,keys,prom_label_key,not_reserved_label,endkeys,required,prom_label_value"`
 	Annotations map[string]string `validate:"dive,keys,prom_annot_key,endkeys,required"`
+	For         time.Duration     `validate:"gte=0"`
+}
+
+// ForecastAlertMeta is the metadata of the error budget exhaustion forecast alert, this
+// alert is optional so the SLO will have it only when is set.
+type ForecastAlertMeta struct {
+	Name        string            `validate:"required"`
+	Window      time.Duration     `validate:"gte=0"`
+	Labels      map[string]string `validate:"dive,keys,prom_label_key,not_reserved_label,endkeys,required,prom_label_value"`
+	Annotations map[string]string `validate:"dive,keys,prom_annot_key,endkeys,required"`
+}
+
+// MinTraffic is the minimum traffic required by the SLO alerts to trigger, this avoids
+// alerting on low traffic services where a few errors make the error ratio very high.
+type MinTraffic struct {
+	Query string  `validate:"required,prom_expr,template_vars"`
+	Rate  float64 `validate:"gt=0"`
+}
+
+// TimeSlice makes the SLO error ratio be the ratio of bad time slices instead of the
+// ratio of bad events, a time slice is bad when its error ratio is above the threshold.
+type TimeSlice struct {
+	Duration  time.Duration `validate:"required"`
+	Threshold float64       `validate:"gte=0,lt=1"`
+}
+
+// Exclusion is a time range excluded from the SLO error budget, the Query is a
+// Prometheus query that returns a result while the exclusion is active.
+type Exclusion struct {
+	Query string `validate:"required,prom_expr"`
+}
+
+// RuleGroupIntervals are the evaluation intervals of the SLO rule groups, the zero
+// values use the Prometheus default evaluation interval.
+type RuleGroupIntervals struct {
+	SLIErrorRecRules time.Duration `validate:"gte=0"`
+	MetadataRecRules time.Duration `validate:"gte=0"`
+	AlertRules       time.Duration `validate:"gte=0"`
+}
+
+// DisabledRules are the SLO rule categories that will not be generated.
+type DisabledRules struct {
+	SLIErrorRecRules bool
+	MetadataRecRules bool
+	AlertRules       bool
+}
+
+// RulerOptions are the options for the Thanos or Mimir rulers that evaluate the SLO rules
+// (e.g a global ruler), the zero values generate plain Prometheus rules.
+type RulerOptions struct {
+	// DedupLabels are the HA replica labels (e.g `replica`) removed from the SLI recordings
+	// so the replicas series are deduplicated before any aggregation.
+	DedupLabels []string `validate:"dive,prom_label_key"`
+	// PartialResponseStrategy is the Thanos rule groups partial response strategy.
+	PartialResponseStrategy string `validate:"omitempty,oneof=warn abort"`
+	// SourceTenants are the Mimir tenants queried by the rule groups (federated rule groups).
+	SourceTenants []string `validate:"dive,required"`
+}
+
+// RunbookURLs are the runbook URL templates of the SLO alerts by severity.
+type RunbookURLs struct {
+	Page   string
+	Ticket string
+}
+
+// SlothLabels customizes the Sloth labels (e.g `sloth_id`) set on the generated rules.
+type SlothLabels struct {
+	// Prefix replaces the `sloth` prefix of the labels (e.g `myorg` will set `myorg_id`).
+	Prefix string `validate:"omitempty,prom_label_key"`
+	// Rename renames the labels, takes precedence over the prefix.
+	Rename map[string]string `validate:"dive,keys,oneof=sloth_id sloth_slo sloth_service sloth_window sloth_severity sloth_version sloth_mode sloth_spec sloth_depends_on,endkeys,required,prom_label_key"`
+	// Drop removes the labels, only the ones that are not required to identify the SLO series.
+	Drop []string `validate:"dive,oneof=sloth_slo sloth_service sloth_severity sloth_version sloth_mode sloth_spec sloth_depends_on"`
 }
 
 // SLO represents a service level objective configuration.
 type SLO struct {
-	ID               string `validate:"required,name"`
-	Name             string `validate:"required,name"`
-	Description      string
-	Service          string `validate:"required,name"`
-	SLI              SLI    `validate:"required"`
-	TimeWindow       time.Duration
-	Objective        float64           `validate:"gt=0,lte=100"`
-	Labels           map[string]string `validate:"dive,keys,prom_label_key,endkeys,required,prom_label_value"`
-	PageAlertMeta    AlertMeta
-	WarningAlertMeta AlertMeta
+	ID                 string `validate:"required,name"`
+	Name               string `validate:"required,name"`
+	Description        string
+	Service            string `validate:"required,name"`
+	MetricsPrefix      string `validate:"omitempty,prom_metric_prefix"`
+	SLI                SLI    `validate:"required"`
+	TimeWindow         time.Duration
+	Calendar           CalendarPeriod    `validate:"omitempty,oneof=month quarter"`
+	Objective          float64           `validate:"gt=0,lt=100"`
+	Labels             map[string]string `validate:"dive,keys,prom_label_key,endkeys,required,prom_label_value"`
+	ExternalLabels     map[string]string `validate:"dive,keys,prom_label_key,not_reserved_label,endkeys,required,prom_label_value"`
+	DependsOn          []string          `validate:"dive,name"`
+	AlertStrategy      AlertStrategy     `validate:"omitempty,oneof=multiwindow-multiburn single-window budget-consumed"`
+	AlertOverrides     alert.MWMBAlertGroupOverride
+	KeepFiringFor      time.Duration `validate:"gte=0"`
+	PageAlertMeta      AlertMeta
+	WarningAlertMeta   AlertMeta
+	ForecastAlertMeta  *ForecastAlertMeta
+	MinTraffic         *MinTraffic
+	TimeSlice          *TimeSlice
+	Exclusions         []Exclusion `validate:"dive"`
+	RuleGroupIntervals RuleGroupIntervals
+	RuleGroupName      string `validate:"omitempty,rule_group_name_tpl"`
+	DisabledRules      DisabledRules
+	RulerOptions       RulerOptions
+	RunbookURLs        RunbookURLs
+	SlothLabels        SlothLabels
 }
 
 type SLOGroup struct {
@@ -62,17 +219,156 @@ func (s SLOGroup) Validate() error {
 
 // GetSLIErrorMetric returns the SLI error metric.
 func (s SLO) GetSLIErrorMetric(window time.Duration) string {
-	return fmt.Sprintf(sliErrorMetricFmt, timeDurationToPromStr(window))
+	return s.GetMetricName(fmt.Sprintf(sliErrorMetricFmt, timeDurationToPromStr(window)))
+}
+
+// GetSLITotalMetric returns the SLI total events rate metric.
+func (s SLO) GetSLITotalMetric(window time.Duration) string {
+	return s.GetMetricName(fmt.Sprintf(sliTotalMetricFmt, timeDurationToPromStr(window)))
+}
+
+// GetBurnRateMetric returns the error budget burn rate metric.
+func (s SLO) GetBurnRateMetric(window time.Duration) string {
+	return s.GetMetricName(fmt.Sprintf(burnRateMetricFmt, timeDurationToPromStr(window)))
 }
 
-// GetSLOIDPromLabels returns the ID labels of an SLO, these can be used to identify
-// an SLO recorded metrics and alerts.
+// GetMetricName returns the name of a Sloth metric using the SLO metrics prefix, the
+// prefix replaces the default `slo` (e.g `slo:objective:ratio`) and `sloth` (e.g `sloth_slo_info`)
+// prefixes.
+func (s SLO) GetMetricName(name string) string {
+	if s.MetricsPrefix == "" {
+		return name
+	}
+
+	switch {
+	case strings.HasPrefix(name, defaultRecordingMetricPrefix+":"):
+		return s.MetricsPrefix + strings.TrimPrefix(name, defaultRecordingMetricPrefix)
+	case strings.HasPrefix(name, defaultMetricPrefix+"_"):
+		return s.MetricsPrefix + strings.TrimPrefix(name, defaultMetricPrefix)
+	}
+
+	return name
+}
+
+// RuleGroupCategory is the category of the rules of an SLO rule group.
+type RuleGroupCategory string
+
+const (
+	SLIRecordingsRuleGroupCategory      RuleGroupCategory = "sli-recordings"
+	MetadataRecordingsRuleGroupCategory RuleGroupCategory = "meta-recordings"
+	AlertsRuleGroupCategory             RuleGroupCategory = "alerts"
+)
+
+// GetRuleGroupName returns the name of the SLO rule group of a category using the SLO
+// rule group name template (by default `sloth-slo-{{ .category }}-{{ .id }}`).
+func (s SLO) GetRuleGroupName(category RuleGroupCategory) (string, error) {
+	nameTpl := s.RuleGroupName
+	if nameTpl == "" {
+		nameTpl = defaultRuleGroupNameTpl
+	}
+
+	return renderRuleGroupName(nameTpl, map[string]string{
+		tplKeyRuleGroupID:       s.ID,
+		tplKeyRuleGroupService:  s.Service,
+		tplKeyRuleGroupSLO:      s.Name,
+		tplKeyRuleGroupCategory: string(category),
+	})
+}
+
+const (
+	tplKeyRuleGroupID       = "id"
+	tplKeyRuleGroupService  = "service"
+	tplKeyRuleGroupSLO      = "slo"
+	tplKeyRuleGroupCategory = "category"
+)
+
+func renderRuleGroupName(nameTpl string, data map[string]string) (string, error) {
+	tpl, err := template.New("ruleGroupName").Option("missingkey=error").Parse(nameTpl)
+	if err != nil {
+		return "", fmt.Errorf("could not parse rule group name template: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, data)
+	if err != nil {
+		return "", fmt.Errorf("could not render rule group name template: %w", err)
+	}
+
+	name := strings.TrimSpace(b.String())
+	if name == "" {
+		return "", fmt.Errorf("rule group name can't be empty")
+	}
+
+	return name, nil
+}
+
+// GetSLOIDPromLabels returns the ID labels of an SLO (including the external labels), these
+// can be used to identify an SLO recorded metrics and alerts.
 func (s SLO) GetSLOIDPromLabels() map[string]string {
-	return map[string]string{
-		sloIDLabelName:      s.ID,
-		sloNameLabelName:    s.Name,
-		sloServiceLabelName: s.Service,
+	labels := map[string]string{}
+	s.setSlothLabel(labels, sloIDLabelName, s.ID)
+	s.setSlothLabel(labels, sloNameLabelName, s.Name)
+	s.setSlothLabel(labels, sloServiceLabelName, s.Service)
+
+	return mergeLabels(s.ExternalLabels, labels)
+}
+
+// getSLOIDPromLabelNames returns the names of the SLO ID labels, in the same order as they
+// are set on the rules.
+func (s SLO) getSLOIDPromLabelNames() []string {
+	names := []string{}
+	for _, name := range []string{sloIDLabelName, sloNameLabelName, sloServiceLabelName} {
+		if !s.isSlothLabelDropped(name) {
+			names = append(names, s.GetLabelName(name))
+		}
+	}
+
+	// External labels are part of the SLO ID, the same SLO can be on multiple places (e.g clusters).
+	external := make([]string, 0, len(s.ExternalLabels))
+	for k := range s.ExternalLabels {
+		external = append(external, k)
+	}
+	sort.Strings(external)
+
+	return append(names, external...)
+}
+
+// getDependsOn returns the SLO dependencies (sorted) as a single comma separated value.
+func (s SLO) getDependsOn() string {
+	deps := append([]string{}, s.DependsOn...)
+	sort.Strings(deps)
+	return strings.Join(deps, ",")
+}
+
+// GetLabelName returns the name of a Sloth label (e.g `sloth_id`) using the SLO
+// Sloth labels customization.
+func (s SLO) GetLabelName(name string) string {
+	if renamed, ok := s.SlothLabels.Rename[name]; ok {
+		return renamed
+	}
+
+	if s.SlothLabels.Prefix != "" && strings.HasPrefix(name, defaultMetricPrefix+"_") {
+		return s.SlothLabels.Prefix + strings.TrimPrefix(name, defaultMetricPrefix)
+	}
+
+	return name
+}
+
+// setSlothLabel sets a Sloth label on the labels using its customized name, unless dropped.
+func (s SLO) setSlothLabel(labels map[string]string, name, value string) {
+	if s.isSlothLabelDropped(name) {
+		return
+	}
+	labels[s.GetLabelName(name)] = value
+}
+
+func (s SLO) isSlothLabelDropped(name string) bool {
+	for _, d := range s.SlothLabels.Drop {
+		if d == name {
+			return true
+		}
 	}
+	return false
 }
 
 var modelSpecValidate = func() *validator.Validate {
@@ -80,14 +376,21 @@ var modelSpecValidate = func() *validator.Validate {
 
 	// More information on prometheus validators logic: https://github.com/prometheus/prometheus/blob/df80dc4d3970121f2f76cba79050983ffb3cdbb0/pkg/rulefmt/rulefmt.go#L188-L208
 	mustRegisterValidation(v, "prom_expr", validatePromExpression)
+	mustRegisterValidation(v, "prom_selector", validatePromSelector)
+	mustRegisterValidation(v, "prom_ratio_expr", validatePromRatioExpression)
 	mustRegisterValidation(v, "prom_label_key", validatePromLabelKey)
 	mustRegisterValidation(v, "prom_label_value", validatePromLabelValue)
+	mustRegisterValidation(v, "prom_metric_prefix", validatePromMetricPrefix)
+	mustRegisterValidation(v, "rule_group_name_tpl", validateRuleGroupNameTpl)
 	mustRegisterValidation(v, "prom_annot_key", validatePromAnnotKey)
+	mustRegisterValidation(v, "not_reserved_label", validateNotReservedLabel)
 	mustRegisterValidation(v, "name", validateName)
 	mustRegisterValidation(v, "required_if_enabled", validateRequiredEnabledAlertName)
 	mustRegisterValidation(v, "template_vars", validateTemplateVars)
 	v.RegisterStructValidation(validateOneSLI, SLI{})
+	v.RegisterStructValidation(validateSLIEvents, SLIEvents{})
 	v.RegisterStructValidation(validateSLOGroup, SLOGroup{})
+	v.RegisterStructValidation(validateSLOObjective, SLO{})
 	return v
 }()
 
@@ -121,6 +424,18 @@ func validatePromLabelKey(fl validator.FieldLevel) bool {
 	return prommodel.LabelName(k).IsValid() && k != prommodel.MetricNameLabel
 }
 
+// validateNotReservedLabel implements validator.CustomTypeFunc by validating
+// a label key is not one of the labels that Sloth sets on the generated rules.
+func validateNotReservedLabel(fl validator.FieldLevel) bool {
+	k, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	_, reserved := reservedLabelNames[k]
+	return !reserved
+}
+
 // validatePromLabelValue implements validator.CustomTypeFunc by validating
 // a prometheus label value.
 func validatePromLabelValue(fl validator.FieldLevel) bool {
@@ -132,10 +447,51 @@ func validatePromLabelValue(fl validator.FieldLevel) bool {
 	return prommodel.LabelValue(v).IsValid()
 }
 
+// validatePromMetricPrefix implements validator.CustomTypeFunc by validating
+// a prometheus metric name prefix.
+func validatePromMetricPrefix(fl validator.FieldLevel) bool {
+	p, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	return prommodel.LabelName(p).IsValid()
+}
+
+// validateRuleGroupNameTpl implements validator.CustomTypeFunc by validating
+// a rule group name template renders a name.
+func validateRuleGroupNameTpl(fl validator.FieldLevel) bool {
+	nameTpl, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	_, err := renderRuleGroupName(nameTpl, map[string]string{
+		tplKeyRuleGroupID:       "test-id",
+		tplKeyRuleGroupService:  "test-service",
+		tplKeyRuleGroupSLO:      "test-slo",
+		tplKeyRuleGroupCategory: string(SLIRecordingsRuleGroupCategory),
+	})
+
+	return err == nil
+}
+
 var promExprTplAllowedFakeData = map[string]string{
 	"window": "1m",
 }
 
+// validatePromSelector implements validator.CustomTypeFunc by validating
+// a prometheus metric selector.
+func validatePromSelector(fl validator.FieldLevel) bool {
+	sel, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	_, err := promqlparser.ParseMetricSelector(sel)
+	return err == nil
+}
+
 // validatePromExpression implements validator.CustomTypeFunc by validating
 // a prometheus expression.
 func validatePromExpression(fl validator.FieldLevel) bool {
@@ -144,22 +500,61 @@ func validatePromExpression(fl validator.FieldLevel) bool {
 		return false
 	}
 
-	// The expressions set by users can have some allowed templated data
-	// we are rendering the expression with fake data so prometheus can
-	// have a final expr and check if is correct.
-	tpl, err := template.New("expr").Parse(expr)
+	_, err := parseTemplatedPromExpr(expr)
+	return err == nil
+}
+
+// validatePromRatioExpression implements validator.CustomTypeFunc by validating
+// a prometheus expression can return a ratio: It needs to return an instant vector or
+// a scalar, and if it's a constant number, it needs to be in the [0, 1] range.
+func validatePromRatioExpression(fl validator.FieldLevel) bool {
+	expr, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	pexpr, err := parseTemplatedPromExpr(expr)
 	if err != nil {
 		return false
 	}
 
+	switch pexpr.Type() {
+	case promqlparser.ValueTypeVector, promqlparser.ValueTypeScalar:
+	default:
+		return false
+	}
+
+	for {
+		p, ok := pexpr.(*promqlparser.ParenExpr)
+		if !ok {
+			break
+		}
+		pexpr = p.Expr
+	}
+
+	if n, ok := pexpr.(*promqlparser.NumberLiteral); ok {
+		return n.Val >= 0 && n.Val <= 1
+	}
+
+	return true
+}
+
+// parseTemplatedPromExpr parses a prometheus expression. The expressions set by users can
+// have some allowed templated data, we are rendering the expression with fake data so
+// prometheus can have a final expr and check if is correct.
+func parseTemplatedPromExpr(expr string) (promqlparser.Expr, error) {
+	tpl, err := template.New("expr").Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
 	var tplB bytes.Buffer
 	err = tpl.Execute(&tplB, promExprTplAllowedFakeData)
 	if err != nil {
-		return false
+		return nil, err
 	}
 
-	_, err = promqlparser.ParseExpr(tplB.String())
-	return err == nil
+	return promqlparser.ParseExpr(tplB.String())
 }
 
 // Names must:
@@ -237,6 +632,23 @@ func validateOneSLI(sl validator.StructLevel) {
 	}
 }
 
+// validateSLIEvents implements validator.CustomTypeFunc by validating
+// only one of the error or good events queries is set.
+func validateSLIEvents(sl validator.StructLevel) {
+	events, ok := sl.Current().Interface().(SLIEvents)
+	if !ok {
+		sl.ReportError(events, "", "SLIEvents", "not_sli_events", "")
+		return
+	}
+
+	switch {
+	case events.ErrorQuery == "" && events.GoodQuery == "":
+		sl.ReportError(events, "", "", "error_or_good_query_required", "")
+	case events.ErrorQuery != "" && events.GoodQuery != "":
+		sl.ReportError(events, "", "", "one_error_or_good_query", "")
+	}
+}
+
 // validateSLOGroup implements validator.CustomTypeFunc by validating
 // SLO IDs are not repeated.
 func validateSLOGroup(sl validator.StructLevel) {
@@ -261,6 +673,41 @@ func validateSLOGroup(sl validator.StructLevel) {
 	}
 }
 
+// minErrorBudgetRatio is the smallest error budget ratio (1 part per million, or a 99.9999
+// objective) supported, smaller ones are lost on the floating point noise of the SLI error ratios.
+const minErrorBudgetRatio = 1e-6
+
+// validateSLOObjective implements validator.CustomTypeFunc by validating the SLO objective
+// is meaningful for the SLO settings:
+//   - The error budget is not smaller than the minimum supported.
+//   - Time slice SLOs error budget allows at least one bad time slice on the time window.
+func validateSLOObjective(sl validator.StructLevel) {
+	slo, ok := sl.Current().Interface().(SLO)
+	if !ok {
+		sl.ReportError(slo, "", "SLO", "not_slo", "")
+		return
+	}
+
+	// Let the objective field validation report the out of range objectives.
+	if slo.Objective <= 0 || slo.Objective >= 100 {
+		return
+	}
+
+	// Use the same rounding as the generated rules.
+	errorBudget, _ := strconv.ParseFloat(formatRatio((100-slo.Objective)/100), 64)
+	if errorBudget < minErrorBudgetRatio {
+		sl.ReportError(slo.Objective, "Objective", "Objective", "error_budget_precision", "")
+		return
+	}
+
+	if slo.TimeSlice != nil && slo.TimeSlice.Duration > 0 {
+		budgetTime := time.Duration(float64(slo.TimeWindow) * errorBudget)
+		if budgetTime < slo.TimeSlice.Duration {
+			sl.ReportError(slo.Objective, "Objective", "Objective", "time_slice_error_budget", "")
+		}
+	}
+}
+
 // SLORules are the prometheus rules required by an SLO.
 type SLORules struct {
 	SLIErrorRecRules []rulefmt.Rule
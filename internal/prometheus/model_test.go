@@ -2,6 +2,7 @@ package prometheus_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -232,6 +233,43 @@ func TestModelValidationSpec(t *testing.T) {
 			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Events.ErrorQuery' Error:Field validation for 'ErrorQuery' failed on the 'template_vars' tag",
 		},
 
+		"SLO SLI with good query should not fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI.Events.ErrorQuery = ""
+				s.SLOs[0].SLI.Events.GoodQuery = `sum(rate(grpc_server_handled_requests_count{job="myapp",code="OK"}[{{ .window }}]))`
+				return s
+			},
+		},
+
+		"SLO SLI good query should be valid Prometheus expr.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI.Events.ErrorQuery = ""
+				s.SLOs[0].SLI.Events.GoodQuery = "sum(rate(grpc_server_handled_requests_count{[1m]))"
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Events.GoodQuery' Error:Field validation for 'GoodQuery' failed on the 'prom_expr' tag",
+		},
+
+		"SLO SLI without error and good query should fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI.Events.ErrorQuery = ""
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Events.' Error:Field validation for '' failed on the 'error_or_good_query_required' tag",
+		},
+
+		"SLO SLI with error and good query should fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI.Events.GoodQuery = `sum(rate(grpc_server_handled_requests_count{job="myapp",code="OK"}[{{ .window }}]))`
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Events.' Error:Field validation for '' failed on the 'one_error_or_good_query' tag",
+		},
+
 		"SLO SLI total query should be valid Prometheus expr.": {
 			slo: func() prometheus.SLOGroup {
 				s := getGoodSLOGroup()
@@ -250,6 +288,145 @@ func TestModelValidationSpec(t *testing.T) {
 			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Events.TotalQuery' Error:Field validation for 'TotalQuery' failed on the 'template_vars' tag",
 		},
 
+		"SLO SLI raw query should return an instant vector.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Raw: &prometheus.SLIRaw{
+					ErrorRatioQuery: `slo:error:ratio_rate5m[{{ .window }}]`,
+				}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Raw.ErrorRatioQuery' Error:Field validation for 'ErrorRatioQuery' failed on the 'prom_ratio_expr' tag",
+		},
+
+		"SLO with composite SLI should not fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+					{Events: s.SLOs[0].SLI.Events},
+					{Raw: &prometheus.SLIRaw{ErrorRatioQuery: `slo:latency:ratio_rate{{ .window }}`}},
+				}}}
+				return s
+			},
+		},
+
+		"SLO composite SLI should have at least 2 SLIs.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+					{Events: s.SLOs[0].SLI.Events},
+				}}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Composite.SLIs' Error:Field validation for 'SLIs' failed on the 'min' tag",
+		},
+
+		"SLO composite SLI SLIs should be valid.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+					{Events: s.SLOs[0].SLI.Events},
+					{Raw: &prometheus.SLIRaw{ErrorRatioQuery: `slo:latency:ratio_rate1m`}},
+				}}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Composite.SLIs[1].Raw.ErrorRatioQuery' Error:Field validation for 'ErrorRatioQuery' failed on the 'template_vars' tag",
+		},
+
+		"SLO with weighted SLI should not fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Weighted: &prometheus.SLIWeighted{SLOs: []prometheus.WeightedSLO{
+					{ID: "api-requests", Weight: 0.6},
+					{ID: "payments-requests", Weight: 0.4},
+				}}}
+				return s
+			},
+		},
+
+		"SLO weighted SLI should have at least 2 SLOs.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Weighted: &prometheus.SLIWeighted{SLOs: []prometheus.WeightedSLO{
+					{ID: "api-requests", Weight: 0.6},
+				}}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Weighted.SLOs' Error:Field validation for 'SLOs' failed on the 'min' tag",
+		},
+
+		"SLO weighted SLI SLOs weight should be greater than 0.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Weighted: &prometheus.SLIWeighted{SLOs: []prometheus.WeightedSLO{
+					{ID: "api-requests", Weight: 0.6},
+					{ID: "payments-requests", Weight: 0},
+				}}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Weighted.SLOs[1].Weight' Error:Field validation for 'Weight' failed on the 'gt' tag",
+		},
+
+		"SLO with aggregated SLI should not fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Aggregated: &prometheus.SLIAggregated{ID: "api-requests", Labels: []string{"cluster"}}}
+				return s
+			},
+		},
+
+		"SLO aggregated SLI should have at least 1 label.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Aggregated: &prometheus.SLIAggregated{ID: "api-requests"}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Aggregated.Labels' Error:Field validation for 'Labels' failed on the 'min' tag",
+		},
+
+		"SLO aggregated SLI labels should be valid Prometheus labels.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{Aggregated: &prometheus.SLIAggregated{ID: "api-requests", Labels: []string{"cluster-name"}}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.Aggregated.Labels[0]' Error:Field validation for 'Labels[0]' failed on the 'prom_label_key' tag",
+		},
+
+		"SLO with native histogram SLI should not fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{NativeHistogram: &prometheus.SLINativeHistogram{
+					Selector:  `http_request_duration_seconds{job="myapp"}`,
+					Threshold: 0.25,
+				}}
+				return s
+			},
+		},
+
+		"SLO native histogram SLI selector should be a valid Prometheus selector.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{NativeHistogram: &prometheus.SLINativeHistogram{
+					Selector:  `sum(http_request_duration_seconds{job="myapp"})`,
+					Threshold: 0.25,
+				}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.NativeHistogram.Selector' Error:Field validation for 'Selector' failed on the 'prom_selector' tag",
+		},
+
+		"SLO native histogram SLI threshold should be greater than 0.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SLI = prometheus.SLI{NativeHistogram: &prometheus.SLINativeHistogram{
+					Selector: `http_request_duration_seconds{job="myapp"}`,
+				}}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SLI.NativeHistogram.Threshold' Error:Field validation for 'Threshold' failed on the 'gt' tag",
+		},
+
 		"SLO Objective shouldn't be less than 0.": {
 			slo: func() prometheus.SLOGroup {
 				s := getGoodSLOGroup()
@@ -274,7 +451,54 @@ func TestModelValidationSpec(t *testing.T) {
 				s.SLOs[0].Objective = 100.0001
 				return s
 			},
-			expErrMessage: "Key: 'SLOGroup.SLOs[0].Objective' Error:Field validation for 'Objective' failed on the 'lte' tag",
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].Objective' Error:Field validation for 'Objective' failed on the 'lt' tag",
+		},
+
+		"SLO Objective with high precision should not fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].Objective = 99.9999
+				return s
+			},
+		},
+
+		"SLO Objective error budget shouldn't be smaller than 1 part per million.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].Objective = 99.99999
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].Objective' Error:Field validation for 'Objective' failed on the 'error_budget_precision' tag",
+		},
+
+		"SLO Objective shouldn't be 100.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].Objective = 100
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].Objective' Error:Field validation for 'Objective' failed on the 'lt' tag",
+		},
+
+		"SLO Objective with time slices should allow at least one bad time slice on the time window.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].TimeWindow = 30 * 24 * time.Hour
+				s.SLOs[0].Objective = 99.999
+				s.SLOs[0].TimeSlice = &prometheus.TimeSlice{Duration: 5 * time.Minute, Threshold: 0.01}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].Objective' Error:Field validation for 'Objective' failed on the 'time_slice_error_budget' tag",
+		},
+
+		"SLO Objective with time slices that allow bad time slices on the time window should not fail.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].TimeWindow = 30 * 24 * time.Hour
+				s.SLOs[0].Objective = 99.99
+				s.SLOs[0].TimeSlice = &prometheus.TimeSlice{Duration: 1 * time.Minute, Threshold: 0.01}
+				return s
+			},
 		},
 
 		"SLO Labels should be valid prometheus keys.": {
@@ -353,6 +577,24 @@ func TestModelValidationSpec(t *testing.T) {
 			expErrMessage: "Key: 'SLOGroup.SLOs[0].PageAlertMeta.Labels[.something]' Error:Field validation for 'Labels[.something]' failed on the 'prom_label_key' tag",
 		},
 
+		"SLO page alert labels can't use the reserved Sloth labels.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].PageAlertMeta.Labels["sloth_severity"] = "critical"
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].PageAlertMeta.Labels[sloth_severity]' Error:Field validation for 'Labels[sloth_severity]' failed on the 'not_reserved_label' tag",
+		},
+
+		"SLO warning alert labels can't use the reserved Sloth labels.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].WarningAlertMeta.Labels["sloth_id"] = "something"
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].WarningAlertMeta.Labels[sloth_id]' Error:Field validation for 'Labels[sloth_id]' failed on the 'not_reserved_label' tag",
+		},
+
 		"SLO page alert labels should have prometheus values.": {
 			slo: func() prometheus.SLOGroup {
 				s := getGoodSLOGroup()
@@ -433,6 +675,105 @@ func TestModelValidationSpec(t *testing.T) {
 			},
 			expErrMessage: "Key: 'SLOGroup.SLOs[0].WarningAlertMeta.Annotations[something]' Error:Field validation for 'Annotations[something]' failed on the 'required' tag",
 		},
+
+		"SLO min traffic rate should be greater than 0.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].MinTraffic = &prometheus.MinTraffic{Query: "sum(rate(test_total[{{.window}}]))"}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].MinTraffic.Rate' Error:Field validation for 'Rate' failed on the 'gt' tag",
+		},
+
+		"SLO metrics prefix should be a valid Prometheus metric name prefix.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].MetricsPrefix = "my:org"
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].MetricsPrefix' Error:Field validation for 'MetricsPrefix' failed on the 'prom_metric_prefix' tag",
+		},
+
+		"SLO rule group name should be a valid template.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].RuleGroupName = "{{ .service }}-{{ .unknown }}"
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].RuleGroupName' Error:Field validation for 'RuleGroupName' failed on the 'rule_group_name_tpl' tag",
+		},
+
+		"SLO ruler partial response strategy should be a valid strategy.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].RulerOptions.PartialResponseStrategy = "ignore"
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].RulerOptions.PartialResponseStrategy' Error:Field validation for 'PartialResponseStrategy' failed on the 'oneof' tag",
+		},
+
+		"SLO ruler dedup labels should be valid label names.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].RulerOptions.DedupLabels = []string{"replica", "prometheus-replica"}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].RulerOptions.DedupLabels[1]' Error:Field validation for 'DedupLabels[1]' failed on the 'prom_label_key' tag",
+		},
+
+		"SLO Sloth labels should only rename Sloth labels.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SlothLabels.Rename = map[string]string{"instance": "svc_instance"}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SlothLabels.Rename[instance]' Error:Field validation for 'Rename[instance]' failed on the 'oneof' tag",
+		},
+
+		"SLO Sloth labels should rename to valid label names.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SlothLabels.Rename = map[string]string{"sloth_service": "my-service"}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SlothLabels.Rename[sloth_service]' Error:Field validation for 'Rename[sloth_service]' failed on the 'prom_label_key' tag",
+		},
+
+		"SLO Sloth labels shouldn't drop the SLO ID label.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].SlothLabels.Drop = []string{"sloth_slo", "sloth_id"}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].SlothLabels.Drop[1]' Error:Field validation for 'Drop[1]' failed on the 'oneof' tag",
+		},
+
+		"SLO external labels shouldn't use Sloth reserved labels.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].ExternalLabels = map[string]string{"sloth_id": "other"}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].ExternalLabels[sloth_id]' Error:Field validation for 'ExternalLabels[sloth_id]' failed on the 'not_reserved_label' tag",
+		},
+
+		"SLO dependencies should be valid SLO IDs.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].DependsOn = []string{"payments-requests", "db queries"}
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].DependsOn[1]' Error:Field validation for 'DependsOn[1]' failed on the 'name' tag",
+		},
+
+		"SLO alert strategy should be a valid strategy.": {
+			slo: func() prometheus.SLOGroup {
+				s := getGoodSLOGroup()
+				s.SLOs[0].AlertStrategy = "something"
+				return s
+			},
+			expErrMessage: "Key: 'SLOGroup.SLOs[0].AlertStrategy' Error:Field validation for 'AlertStrategy' failed on the 'oneof' tag",
+		},
 	}
 
 	for name, test := range tests {
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
@@ -26,11 +28,20 @@ var SLIRecordingRulesGenerator = sliRecordingRulesGenerator{genFunc: factorySLIR
 
 func (s sliRecordingRulesGenerator) GenerateSLIRecordingRules(ctx context.Context, slo SLO, alerts alert.MWMBAlertGroup) ([]rulefmt.Rule, error) {
 	// Get the windows we need the recording rules.
-	windows := getAlertGroupWindows(alerts)
-	windows = append(windows, slo.TimeWindow) // Add the total time window as a handy helper.
+	windows := getSLIWindows(slo, alerts)
 
 	// Generate the rules
-	rules := make([]rulefmt.Rule, 0, len(windows))
+	rules := make([]rulefmt.Rule, 0, len(windows)+1)
+
+	// Time slice SLOs need the bad time slices recorded first.
+	if slo.TimeSlice != nil {
+		rule, err := timeSliceSLIRecordGenerator(slo, alerts)
+		if err != nil {
+			return nil, fmt.Errorf("could not create %q SLO time slice rule: %w", slo.ID, err)
+		}
+		rules = append(rules, *rule)
+	}
+
 	for _, window := range windows {
 		rule, err := s.genFunc(slo, window, alerts)
 		if err != nil {
@@ -39,6 +50,15 @@ func (s sliRecordingRulesGenerator) GenerateSLIRecordingRules(ctx context.Contex
 		rules = append(rules, *rule)
 	}
 
+	// Record the total events rate, the metadata uses it to get the period total events.
+	if recordsSLITotalEvents(slo) {
+		rule, err := sliTotalRecordGenerator(slo, alerts.PageQuick.ShortWindow)
+		if err != nil {
+			return nil, fmt.Errorf("could not create %q SLO total events rule: %w", slo.ID, err)
+		}
+		rules = append(rules, *rule)
+	}
+
 	return rules, nil
 }
 
@@ -48,20 +68,161 @@ const (
 
 func factorySLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
 	switch {
+	// Calendar aligned total period time window.
+	case window == slo.TimeWindow && slo.Calendar != NoCalendarPeriod:
+		return calendarSLIRecordGenerator(slo, window, alerts.PageQuick.ShortWindow)
 	// Optimize the rules that are for the total period time window.
 	case window == slo.TimeWindow:
 		return optimizedSLIRecordGenerator(slo, window, alerts.PageQuick.ShortWindow)
+	// Time slice based SLO, exclusions are already applied on the time slices.
+	case slo.TimeSlice != nil:
+		return timeSliceRatioSLIRecordGenerator(slo, window)
+	}
+
+	rule, err := sliTypeRecordGenerator(slo, window, alerts)
+	if err != nil {
+		return nil, err
+	}
+
+	return excludeSLIRecord(slo, dedupSLIRecord(slo, rule)), nil
+}
+
+// dedupSLIRecord removes the HA replica labels from the SLI error ratio, so the SLI recording
+// has a single series when the SLI queries are evaluated with the data of all the replicas
+// (e.g a Thanos or Mimir global ruler).
+func dedupSLIRecord(slo SLO, rule *rulefmt.Rule) *rulefmt.Rule {
+	if len(slo.RulerOptions.DedupLabels) == 0 {
+		return rule
+	}
+
+	deduped := *rule
+	deduped.Expr = dedupExpr(slo, rule.Expr) + "\n"
+
+	return &deduped
+}
+
+// dedupExpr wraps the expression with a dedup-safe aggregation that removes the replica labels.
+func dedupExpr(slo SLO, expr string) string {
+	return fmt.Sprintf("max without (%s) (\n%s\n)", strings.Join(slo.RulerOptions.DedupLabels, ", "), strings.TrimSpace(expr))
+}
+
+// excludeSLIRecord removes the SLI error ratio samples while any of the SLO exclusions is active,
+// the period SLI error ratio is calculated using these samples so the excluded time ranges
+// don't consume error budget.
+func excludeSLIRecord(slo SLO, rule *rulefmt.Rule) *rulefmt.Rule {
+	if len(slo.Exclusions) == 0 {
+		return rule
+	}
+
+	queries := make([]string, 0, len(slo.Exclusions))
+	for _, e := range slo.Exclusions {
+		queries = append(queries, fmt.Sprintf("(%s)", strings.TrimSpace(e.Query)))
+	}
+
+	excluded := *rule
+	excluded.Expr = fmt.Sprintf("(%s)\nunless on()\n(%s)\n", strings.TrimSpace(rule.Expr), strings.Join(queries, " or "))
+
+	return &excluded
+}
+
+// sliTypeRecordGenerator generates the SLI error ratio recording rule based on the SLI type.
+func sliTypeRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
+	switch {
 	// Event based SLI.
 	case slo.SLI.Events != nil:
 		return eventsSLIRecordGenerator(slo, window, alerts)
 	// Raw based SLI.
 	case slo.SLI.Raw != nil:
 		return rawSLIRecordGenerator(slo, window, alerts)
+	// Composite based SLI.
+	case slo.SLI.Composite != nil:
+		return compositeSLIRecordGenerator(slo, window, alerts)
+	// Weighted SLOs based SLI.
+	case slo.SLI.Weighted != nil:
+		return weightedSLIRecordGenerator(slo, window, alerts)
+	// Multiple places aggregated SLO based SLI.
+	case slo.SLI.Aggregated != nil:
+		return aggregatedSLIRecordGenerator(slo, window, alerts)
+	// Native histogram based SLI.
+	case slo.SLI.NativeHistogram != nil:
+		return nativeHistogramSLIRecordGenerator(slo, window, alerts)
 	}
 
 	return nil, fmt.Errorf("invalid SLI type")
 }
 
+// timeSliceSLIRecordGenerator generates the recording rule that tells if the current time
+// slice is bad (1) or good (0), a time slice is bad when its SLI error ratio is above the
+// threshold.
+func timeSliceSLIRecordGenerator(slo SLO, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
+	sliRule, err := sliTypeRecordGenerator(slo, slo.TimeSlice.Duration, alerts)
+	if err != nil {
+		return nil, err
+	}
+	sliRule = dedupSLIRecord(slo, sliRule)
+
+	return excludeSLIRecord(slo, &rulefmt.Rule{
+		Record: slo.GetMetricName(sliTimeSliceMetric),
+		Expr:   fmt.Sprintf("(%s) > bool %g\n", strings.TrimSpace(sliRule.Expr), slo.TimeSlice.Threshold),
+		Labels: sliRule.Labels,
+	}), nil
+}
+
+// timeSliceRatioSLIRecordGenerator generates the SLI error ratio of a time slice SLO, the error
+// ratio is the ratio of the time the time slices have been bad on the window.
+func timeSliceRatioSLIRecordGenerator(slo SLO, window time.Duration) (*rulefmt.Rule, error) {
+	strWindow := timeDurationToPromStr(window)
+	return &rulefmt.Rule{
+		Record: slo.GetSLIErrorMetric(window),
+		Expr:   fmt.Sprintf("avg_over_time(%s%s[%s])\n", slo.GetMetricName(sliTimeSliceMetric), labelsToPromFilter(slo.GetSLOIDPromLabels()), strWindow),
+		Labels: mergeLabels(
+			slo.Labels,
+			slo.GetSLOIDPromLabels(),
+			map[string]string{
+				slo.GetLabelName(sloWindowLabelName): strWindow,
+			},
+		),
+	}, nil
+}
+
+// recordsSLITotalEvents returns true if the SLO records the SLI total events rate, only event
+// based (or aggregated) SLIs with rolling periods know their total events on the period.
+func recordsSLITotalEvents(slo SLO) bool {
+	return (slo.SLI.Events != nil || slo.SLI.Aggregated != nil) && slo.TimeSlice == nil && slo.Calendar == NoCalendarPeriod
+}
+
+// sliTotalRecordGenerator generates the SLI total events rate recording rule of a window.
+func sliTotalRecordGenerator(slo SLO, window time.Duration) (*rulefmt.Rule, error) {
+	var totalQuery string
+	switch {
+	// Aggregated SLIs total events are the ones of all the places.
+	case slo.SLI.Aggregated != nil:
+		totalQuery = slo.GetSLITotalMetric(window) + labelsToPromFilter(map[string]string{slo.GetLabelName(sloIDLabelName): slo.SLI.Aggregated.ID})
+	default:
+		q, err := renderSLIQuery(slo.SLI.Events.TotalQuery, window)
+		if err != nil {
+			return nil, fmt.Errorf("could not render total events query: %w", err)
+		}
+		totalQuery = strings.TrimSpace(q)
+		if len(slo.RulerOptions.DedupLabels) > 0 {
+			totalQuery = dedupExpr(slo, totalQuery)
+		}
+	}
+
+	strWindow := timeDurationToPromStr(window)
+	return &rulefmt.Rule{
+		Record: slo.GetSLITotalMetric(window),
+		Expr:   fmt.Sprintf("sum(%s)\n", totalQuery),
+		Labels: mergeLabels(
+			slo.Labels,
+			slo.GetSLOIDPromLabels(),
+			map[string]string{
+				slo.GetLabelName(sloWindowLabelName): strWindow,
+			},
+		),
+	}, nil
+}
+
 func rawSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
 	// Render with our templated data.
 	sliExprTpl := fmt.Sprintf(`(%s)`, slo.SLI.Raw.ErrorRatioQuery)
@@ -85,7 +246,7 @@ func rawSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlert
 		Labels: mergeLabels(
 			slo.GetSLOIDPromLabels(),
 			map[string]string{
-				sloWindowLabelName: strWindow,
+				slo.GetLabelName(sloWindowLabelName): strWindow,
 			},
 			slo.Labels,
 		),
@@ -97,8 +258,18 @@ func eventsSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAl
 /
 (%s)
 `
-	// Generate our first level of template by assembling the error and total expressions.
+	// The error ratio of good events SLIs is the inverted good events ratio.
+	const sliGoodExprTplFmt = `1 - (
+(%s)
+/
+(%s)
+)
+`
+	// Generate our first level of template by assembling the error (or good) and total expressions.
 	sliExprTpl := fmt.Sprintf(sliExprTplFmt, slo.SLI.Events.ErrorQuery, slo.SLI.Events.TotalQuery)
+	if slo.SLI.Events.GoodQuery != "" {
+		sliExprTpl = fmt.Sprintf(sliGoodExprTplFmt, slo.SLI.Events.GoodQuery, slo.SLI.Events.TotalQuery)
+	}
 
 	// Render with our templated data.
 	tpl, err := template.New("sliExpr").Option("missingkey=error").Parse(sliExprTpl)
@@ -121,13 +292,233 @@ func eventsSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAl
 		Labels: mergeLabels(
 			slo.GetSLOIDPromLabels(),
 			map[string]string{
-				sloWindowLabelName: strWindow,
+				slo.GetLabelName(sloWindowLabelName): strWindow,
 			},
 			slo.Labels,
 		),
 	}, nil
 }
 
+func nativeHistogramSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
+	strWindow := timeDurationToPromStr(window)
+	return &rulefmt.Rule{
+		Record: slo.GetSLIErrorMetric(window),
+		Expr:   nativeHistogramErrorRatioExpr(*slo.SLI.NativeHistogram, strWindow),
+		Labels: mergeLabels(
+			slo.GetSLOIDPromLabels(),
+			map[string]string{
+				slo.GetLabelName(sloWindowLabelName): strWindow,
+			},
+			slo.Labels,
+		),
+	}, nil
+}
+
+// nativeHistogramErrorRatioExpr returns the error ratio expression of a native histogram SLI, the good
+// events are the fraction of observations between 0 and the threshold.
+func nativeHistogramErrorRatioExpr(h SLINativeHistogram, window string) string {
+	return fmt.Sprintf("1 - histogram_fraction(0, %g, sum(rate(%s[%s])))\n", h.Threshold, h.Selector, window)
+}
+
+func compositeSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
+	sliExprTpl, err := compositeSLIExprTpl(*slo.SLI.Composite)
+	if err != nil {
+		return nil, fmt.Errorf("could not create composite SLI expression: %w", err)
+	}
+
+	// Render with our templated data.
+	tpl, err := template.New("sliExpr").Option("missingkey=error").Parse(sliExprTpl)
+	if err != nil {
+		return nil, fmt.Errorf("could not create SLI expression template data: %w", err)
+	}
+
+	strWindow := timeDurationToPromStr(window)
+	var b bytes.Buffer
+	err = tpl.Execute(&b, map[string]string{
+		tplKeyWindow: strWindow,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render SLI expression template: %w", err)
+	}
+
+	return &rulefmt.Rule{
+		Record: slo.GetSLIErrorMetric(window),
+		Expr:   b.String(),
+		Labels: mergeLabels(
+			slo.GetSLOIDPromLabels(),
+			map[string]string{
+				slo.GetLabelName(sloWindowLabelName): strWindow,
+			},
+			slo.Labels,
+		),
+	}, nil
+}
+
+// compositeSLIExprTpl assembles the error ratio expression template of a composite SLI. An event
+// is good only if is good on all the SLIs, so assuming the SLIs are independent, the good ratio is the
+// product of all the SLIs good ratios:
+//
+//	1 - ((1 - error_ratio_1) * (1 - error_ratio_2) * ...)
+func compositeSLIExprTpl(c SLIComposite) (string, error) {
+	goodRatios := make([]string, 0, len(c.SLIs))
+	for _, sli := range c.SLIs {
+		var errorRatio string
+		switch {
+		case sli.Events != nil && sli.Events.GoodQuery != "":
+			errorRatio = fmt.Sprintf("(1 - ((%s) / (%s)))", sli.Events.GoodQuery, sli.Events.TotalQuery)
+		case sli.Events != nil:
+			errorRatio = fmt.Sprintf("((%s) / (%s))", sli.Events.ErrorQuery, sli.Events.TotalQuery)
+		case sli.Raw != nil:
+			errorRatio = fmt.Sprintf("(%s)", sli.Raw.ErrorRatioQuery)
+		case sli.NativeHistogram != nil:
+			expr := nativeHistogramErrorRatioExpr(*sli.NativeHistogram, "{{.window}}")
+			errorRatio = fmt.Sprintf("(%s)", strings.TrimSuffix(expr, "\n"))
+		case sli.Composite != nil:
+			expr, err := compositeSLIExprTpl(*sli.Composite)
+			if err != nil {
+				return "", err
+			}
+			errorRatio = fmt.Sprintf("(%s)", expr)
+		default:
+			return "", fmt.Errorf("invalid SLI type")
+		}
+
+		goodRatios = append(goodRatios, fmt.Sprintf("(1 - %s)", errorRatio))
+	}
+
+	return fmt.Sprintf("1 - (\n%s\n)\n", strings.Join(goodRatios, "\n*\n")), nil
+}
+
+// weightedSLIRecordGenerator generates the SLI error ratio of a weighted SLI using the already
+// recorded SLI error ratios of the weighted SLOs on the same window, the weights are normalized
+// so the result is still a ratio:
+//
+//	(w1 * error_ratio_1 + w2 * error_ratio_2 + ...) / (w1 + w2 + ...)
+func weightedSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
+	total := 0.0
+	for _, s := range slo.SLI.Weighted.SLOs {
+		total += s.Weight
+	}
+
+	// Aggregate the SLO labels so we can operate on the different SLOs.
+	metric := slo.GetSLIErrorMetric(window)
+	weighted := make([]string, 0, len(slo.SLI.Weighted.SLOs))
+	for _, s := range slo.SLI.Weighted.SLOs {
+		filter := labelsToPromFilter(mergeLabels(slo.ExternalLabels, map[string]string{slo.GetLabelName(sloIDLabelName): s.ID}))
+		weighted = append(weighted, fmt.Sprintf("%g * max(%s%s)", s.Weight/total, metric, filter))
+	}
+
+	strWindow := timeDurationToPromStr(window)
+	return &rulefmt.Rule{
+		Record: metric,
+		Expr:   fmt.Sprintf("(\n%s\n)\n", strings.Join(weighted, "\n+\n")),
+		Labels: mergeLabels(
+			slo.GetSLOIDPromLabels(),
+			map[string]string{
+				slo.GetLabelName(sloWindowLabelName): strWindow,
+			},
+			slo.Labels,
+		),
+	}, nil
+}
+
+// aggregatedSLIRecordGenerator generates the SLI error ratio of an aggregated SLI using the already
+// recorded SLI error ratios of the SLO on every place (e.g cluster), weighted by the SLO total events
+// rate of each place, so the places with more traffic have more impact on the error ratio:
+//
+//	sum(error_ratio_place_1 * total_place_1 + ...) / sum(total_place_1 + ...)
+//
+// The total events rate is only recorded by events SLIs, and the shortest window one is used
+// as the current traffic of each place.
+func aggregatedSLIRecordGenerator(slo SLO, window time.Duration, alerts alert.MWMBAlertGroup) (*rulefmt.Rule, error) {
+	idLabel := slo.GetLabelName(sloIDLabelName)
+	filter := labelsToPromFilter(map[string]string{idLabel: slo.SLI.Aggregated.ID})
+	totalMetric := slo.GetSLITotalMetric(alerts.PageQuick.ShortWindow)
+	on := strings.Join(append([]string{idLabel}, slo.SLI.Aggregated.Labels...), ", ")
+
+	strWindow := timeDurationToPromStr(window)
+	return &rulefmt.Rule{
+		Record: slo.GetSLIErrorMetric(window),
+		Expr: fmt.Sprintf("sum(\n  %s%s\n  * on(%s)\n  %s%s\n)\n/\nsum(%s%s)\n",
+			slo.GetSLIErrorMetric(window), filter,
+			on,
+			totalMetric, filter,
+			totalMetric, filter),
+		Labels: mergeLabels(
+			slo.GetSLOIDPromLabels(),
+			map[string]string{
+				slo.GetLabelName(sloWindowLabelName): strWindow,
+			},
+			slo.Labels,
+		),
+	}, nil
+}
+
+// calendarSLIRecordGenerator gets the period to date SLI recording rule of calendar aligned SLOs,
+// like the optimized one, it uses the shortest window SLI recording rule, but only the samples
+// since the current calendar period start (e.g: first day of the month) are used.
+//
+// To know if a sample is from the current calendar period, we compare the calendar period of each
+// subquery step with the one of the rule evaluation time (`@ end()`), this requires Prometheus
+// `@` modifier support (enabled by default since v2.33).
+func calendarSLIRecordGenerator(slo SLO, window, shortWindow time.Duration) (*rulefmt.Rule, error) {
+	const sliExprTplFmt = `sum_over_time(
+  (
+    {{.metric}}{{.filter}}
+    and on()
+    ({{.period}} == on() last_over_time({{.period}}[{{.step}}:{{.step}}] @ end()))
+  )[{{.window}}:{{.step}}]
+)
+/ ignoring ({{.windowKey}})
+count_over_time(
+  (
+    {{.metric}}{{.filter}}
+    and on()
+    ({{.period}} == on() last_over_time({{.period}}[{{.step}}:{{.step}}] @ end()))
+  )[{{.window}}:{{.step}}]
+)
+`
+
+	// The range needs to contain the longest possible calendar period.
+	var period, rangeWindow string
+	switch slo.Calendar {
+	case CalendarMonthPeriod:
+		period, rangeWindow = "month()", "31d"
+	case CalendarQuarterPeriod:
+		period, rangeWindow = "floor((month() - 1) / 3)", "92d"
+	default:
+		return nil, fmt.Errorf("unknown %q calendar period", slo.Calendar)
+	}
+
+	tpl, err := template.New("sliExpr").Option("missingkey=error").Parse(sliExprTplFmt)
+	if err != nil {
+		return nil, fmt.Errorf("could not create SLI expression template data: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, map[string]string{
+		"metric":    slo.GetSLIErrorMetric(shortWindow),
+		"filter":    labelsToPromFilter(slo.GetSLOIDPromLabels()),
+		"period":    period,
+		"window":    rangeWindow,
+		"step":      timeDurationToPromStr(shortWindow),
+		"windowKey": slo.GetLabelName(sloWindowLabelName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render SLI expression template: %w", err)
+	}
+
+	return &rulefmt.Rule{
+		Record: slo.GetSLIErrorMetric(window),
+		Expr:   b.String(),
+		// The SLO labels will be obtained from the source SLI recording rule.
+		// We only need to set the window.
+		Labels: map[string]string{
+			slo.GetLabelName(sloWindowLabelName): timeDurationToPromStr(window),
+		},
+	}, nil
+}
+
 // optimizedSLIRecordGenerator gets a SLI recording rule from other SLI recording rules. This optimization
 // will make Prometheus consume less CPU and memory, however the result will be less accurate. Used wisely
 // is a good tradeoff. For example on calculating informative metrics like total period window (30d).
@@ -164,7 +555,7 @@ count_over_time({{.metric}}{{.filter}}[{{.window}}])
 		"metric":    shortWindowSLIRec,
 		"filter":    filter,
 		"window":    strWindow,
-		"windowKey": sloWindowLabelName,
+		"windowKey": slo.GetLabelName(sloWindowLabelName),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not render SLI expression template: %w", err)
@@ -176,7 +567,7 @@ count_over_time({{.metric}}{{.filter}}[{{.window}}])
 		// The SLO labels will be obtained from the source SLI recording rule.
 		// We only need to set the window.
 		Labels: map[string]string{
-			sloWindowLabelName: strWindow,
+			slo.GetLabelName(sloWindowLabelName): strWindow,
 		},
 	}, nil
 }
@@ -191,27 +582,28 @@ func (m metadataRecordingRulesGenerator) GenerateMetadataRecordingRules(ctx cont
 	labels := mergeLabels(slo.GetSLOIDPromLabels(), slo.Labels)
 
 	// Metatada Recordings.
-	const (
-		metricSLOObjectiveRatio                  = "slo:objective:ratio"
-		metricSLOErrorBudgetRatio                = "slo:error_budget:ratio"
-		metricSLOTimePeriodDays                  = "slo:time_period:days"
-		metricSLOCurrentBurnRateRatio            = "slo:current_burn_rate:ratio"
-		metricSLOPeriodBurnRateRatio             = "slo:period_burn_rate:ratio"
-		metricSLOPeriodErrorBudgetRemainingRatio = "slo:period_error_budget_remaining:ratio"
-		metricSLOInfo                            = "sloth_slo_info"
+	var (
+		metricSLOObjectiveRatio                  = slo.GetMetricName("slo:objective:ratio")
+		metricSLOErrorBudgetRatio                = slo.GetMetricName("slo:error_budget:ratio")
+		metricSLOTimePeriodDays                  = slo.GetMetricName("slo:time_period:days")
+		metricSLOCurrentBurnRateRatio            = slo.GetMetricName("slo:current_burn_rate:ratio")
+		metricSLOPeriodBurnRateRatio             = slo.GetMetricName("slo:period_burn_rate:ratio")
+		metricSLOPeriodErrorBudgetRemainingRatio = slo.GetMetricName("slo:period_error_budget_remaining:ratio")
+		metricSLOPeriodErrorBudgetRemainingMins  = slo.GetMetricName("slo:period_error_budget_remaining:minutes")
+		metricSLOPeriodErrorBudgetRemainingEvent = slo.GetMetricName("slo:period_error_budget_remaining:events")
+		metricSLOInfo                            = slo.GetMetricName("sloth_slo_info")
 	)
 
 	sloObjectiveRatio := slo.Objective / 100
 
 	sloFilter := labelsToPromFilter(slo.GetSLOIDPromLabels())
+	sloIDLabels := strings.Join(slo.getSLOIDPromLabelNames(), ", ")
 
 	var currentBurnRateExpr bytes.Buffer
 	err := burnRateRecordingExprTpl.Execute(&currentBurnRateExpr, map[string]string{
 		"SLIErrorMetric":         slo.GetSLIErrorMetric(alerts.PageQuick.ShortWindow),
 		"MetricFilter":           sloFilter,
-		"SLOIDName":              sloIDLabelName,
-		"SLOLabelName":           sloNameLabelName,
-		"SLOServiceName":         sloServiceLabelName,
+		"SLOIDLabels":            sloIDLabels,
 		"ErrorBudgetRatioMetric": metricSLOErrorBudgetRatio,
 	})
 	if err != nil {
@@ -222,9 +614,7 @@ func (m metadataRecordingRulesGenerator) GenerateMetadataRecordingRules(ctx cont
 	err = burnRateRecordingExprTpl.Execute(&periodBurnRateExpr, map[string]string{
 		"SLIErrorMetric":         slo.GetSLIErrorMetric(slo.TimeWindow),
 		"MetricFilter":           sloFilter,
-		"SLOIDName":              sloIDLabelName,
-		"SLOLabelName":           sloNameLabelName,
-		"SLOServiceName":         sloServiceLabelName,
+		"SLOIDLabels":            sloIDLabels,
 		"ErrorBudgetRatioMetric": metricSLOErrorBudgetRatio,
 	})
 	if err != nil {
@@ -235,21 +625,21 @@ func (m metadataRecordingRulesGenerator) GenerateMetadataRecordingRules(ctx cont
 		// SLO Objective.
 		{
 			Record: metricSLOObjectiveRatio,
-			Expr:   fmt.Sprintf(`vector(%g)`, sloObjectiveRatio),
+			Expr:   fmt.Sprintf(`vector(%s)`, formatRatio(sloObjectiveRatio)),
 			Labels: labels,
 		},
 
 		// Error budget.
 		{
 			Record: metricSLOErrorBudgetRatio,
-			Expr:   fmt.Sprintf(`vector(1-%g)`, sloObjectiveRatio),
+			Expr:   fmt.Sprintf(`vector(1-%s)`, formatRatio(sloObjectiveRatio)),
 			Labels: labels,
 		},
 
 		// Total period.
 		{
 			Record: metricSLOTimePeriodDays,
-			Expr:   fmt.Sprintf(`vector(%g)`, slo.TimeWindow.Hours()/24),
+			Expr:   timePeriodDaysExpr(slo),
 			Labels: labels,
 		},
 
@@ -274,22 +664,91 @@ func (m metadataRecordingRulesGenerator) GenerateMetadataRecordingRules(ctx cont
 			Labels: labels,
 		},
 
-		// Info.
+		// Total error budget remaining period in minutes (allowed bad minutes left).
 		{
-			Record: metricSLOInfo,
-			Expr:   `vector(1)`,
-			Labels: mergeLabels(labels, map[string]string{
-				sloVersionLabelName: info.Version,
-				sloModeLabelName:    string(info.Mode),
-				sloSpecLabelName:    info.Spec,
-			}),
+			Record: metricSLOPeriodErrorBudgetRemainingMins,
+			Expr: fmt.Sprintf("%s%s\n* on(%s) group_left()\n(%s%s * %s%s * 1440)\n",
+				metricSLOPeriodErrorBudgetRemainingRatio, sloFilter,
+				sloIDLabels,
+				metricSLOErrorBudgetRatio, sloFilter, metricSLOTimePeriodDays, sloFilter),
+			Labels: labels,
 		},
 	}
 
+	// Total error budget remaining period in events (allowed bad events left). The period
+	// total events are the average of the recorded total events rate instead of querying
+	// the SLI total events on the whole period.
+	if recordsSLITotalEvents(slo) {
+		rules = append(rules, rulefmt.Rule{
+			Record: metricSLOPeriodErrorBudgetRemainingEvent,
+			Expr: fmt.Sprintf("%s%s\n* on(%s) group_left\n%s%s\n* on(%s) group_left()\n(avg_over_time(%s%s[%s]) * %d)\n",
+				metricSLOPeriodErrorBudgetRemainingRatio, sloFilter,
+				sloIDLabels,
+				metricSLOErrorBudgetRatio, sloFilter,
+				sloIDLabels,
+				slo.GetSLITotalMetric(alerts.PageQuick.ShortWindow), sloFilter, timeDurationToPromStr(slo.TimeWindow),
+				int64(slo.TimeWindow.Seconds())),
+			Labels: labels,
+		})
+	}
+
+	// Info.
+	infoLabels := map[string]string{}
+	slo.setSlothLabel(infoLabels, sloVersionLabelName, info.Version)
+	slo.setSlothLabel(infoLabels, sloModeLabelName, string(info.Mode))
+	slo.setSlothLabel(infoLabels, sloSpecLabelName, info.Spec)
+	if len(slo.DependsOn) > 0 {
+		slo.setSlothLabel(infoLabels, sloDependsOnLabelName, slo.getDependsOn())
+	}
+	rules = append(rules, rulefmt.Rule{
+		Record: metricSLOInfo,
+		Expr:   `vector(1)`,
+		Labels: mergeLabels(labels, infoLabels),
+	})
+
 	return rules, nil
 }
 
+// renderSLIQuery renders an SLI query template for a window.
+func renderSLIQuery(query string, window time.Duration) (string, error) {
+	tpl, err := template.New("sliQuery").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("could not create SLI query template: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, map[string]string{
+		tplKeyWindow: timeDurationToPromStr(window),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not render SLI query template: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// timePeriodDaysExpr returns the expression of the SLO total period days, calendar
+// periods use the days of the current month or quarter.
+func timePeriodDaysExpr(slo SLO) string {
+	switch slo.Calendar {
+	case CalendarMonthPeriod:
+		return `days_in_month()`
+	case CalendarQuarterPeriod:
+		// The quarters have 90 (91 on leap years), 91, 92 and 92 days.
+		return `90 + (month() > bool 3) + (month() > bool 6) + (month() <= bool 3) * (year() % 4 == bool 0) * ((year() % 100 != bool 0) + (year() % 400 == bool 0))`
+	}
+
+	return fmt.Sprintf(`vector(%g)`, slo.TimeWindow.Hours()/24)
+}
+
+// formatRatio formats a ratio rounded to 12 significant digits, enough for high precision
+// objectives (e.g 99.99999) while removing the floating point noise of the calculations
+// (e.g 0.9990000000000001).
+func formatRatio(ratio float64) string {
+	return strconv.FormatFloat(ratio, 'g', 12, 64)
+}
+
 var burnRateRecordingExprTpl = template.Must(template.New("burnRateExpr").Option("missingkey=error").Parse(`{{ .SLIErrorMetric }}{{ .MetricFilter }}
-/ on({{ .SLOIDName }}, {{ .SLOLabelName }}, {{ .SLOServiceName }}) group_left
+/ on({{ .SLOIDLabels }}) group_left
 {{ .ErrorBudgetRatioMetric }}{{ .MetricFilter }}
 `))
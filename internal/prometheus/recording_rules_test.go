@@ -6,7 +6,11 @@ import (
 	"time"
 
 	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/prometheus/prometheus/promql"
+	promqlparser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/util/teststorage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/info"
@@ -183,6 +187,17 @@ func TestGenerateSLIRecordingRules(t *testing.T) {
 						"sloth_window": "30d",
 					},
 				},
+				{
+					Record: "slo:sli_total:rate5m",
+					Expr:   "sum(rate(my_metric[5m]))\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "5m",
+					},
+				},
 			},
 		},
 
@@ -353,145 +368,1324 @@ func TestGenerateSLIRecordingRules(t *testing.T) {
 						"sloth_window": "30d",
 					},
 				},
+				{
+					Record: "slo:sli_total:rate3h",
+					Expr:   "sum(rate(my_metric[3h]))\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "3h",
+					},
+				},
 			},
 		},
-	}
-
-	for name, test := range tests {
-		t.Run(name, func(t *testing.T) {
-			assert := assert.New(t)
-
-			gotRules, err := prometheus.SLIRecordingRulesGenerator.GenerateSLIRecordingRules(context.TODO(), test.slo, test.alertGroup)
-
-			if test.expErr {
-				assert.Error(err)
-			} else if assert.NoError(err) {
-				assert.Equal(test.expRules, gotRules)
-			}
-		})
-	}
-}
 
-func TestGenerateMetaRecordingRules(t *testing.T) {
-	tests := map[string]struct {
-		info       info.Info
-		slo        prometheus.SLO
-		alertGroup alert.MWMBAlertGroup
-		expRules   []rulefmt.Rule
-		expErr     bool
-	}{
-		"Having and SLO an its mwmb alerts should create the metadata recording rules.": {
-			info: info.Info{
-				Version: "test-ver",
-				Mode:    info.ModeTest,
-				Spec:    "test/v1",
-			},
+		"Having an SLO with SLI(composite) and its mwmb alerts should create the recording rules combining the SLIs.": {
 			slo: prometheus.SLO{
 				ID:         "test",
 				Name:       "test-name",
 				Service:    "test-svc",
-				Objective:  99.9,
 				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+						{Events: &prometheus.SLIEvents{
+							ErrorQuery: `rate(my_metric[{{.window}}]{error="true"})`,
+							TotalQuery: `rate(my_metric[{{.window}}])`,
+						}},
+						{Raw: &prometheus.SLIRaw{
+							ErrorRatioQuery: `rate(my_latency_metric[{{.window}}])`,
+						}},
+					}},
+				},
 				Labels: map[string]string{
 					"kind": "test",
 				},
 			},
-			alertGroup: getAlertGroup(),
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
 			expRules: []rulefmt.Rule{
 				{
-					Record: "slo:objective:ratio",
-					Expr:   "vector(0.9990000000000001)",
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "1 - (\n(1 - ((rate(my_metric[1h]{error=\"true\"})) / (rate(my_metric[1h]))))\n*\n(1 - (rate(my_latency_metric[1h])))\n)\n",
 					Labels: map[string]string{
 						"kind":          "test",
 						"sloth_service": "test-svc",
 						"sloth_slo":     "test-name",
 						"sloth_id":      "test",
+						"sloth_window":  "1h",
 					},
 				},
 				{
-					Record: "slo:error_budget:ratio",
-					Expr:   "vector(1-0.9990000000000001)",
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with SLI(events) using good events should create the recording rules inverting the good events ratio.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Events: &prometheus.SLIEvents{
+						GoodQuery:  `rate(my_metric[{{.window}}]{error="false"})`,
+						TotalQuery: `rate(my_metric[{{.window}}])`,
+					},
+				},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "1 - (\n(rate(my_metric[1h]{error=\"false\"}))\n/\n(rate(my_metric[1h]))\n)\n",
 					Labels: map[string]string{
-						"kind":          "test",
 						"sloth_service": "test-svc",
 						"sloth_slo":     "test-name",
 						"sloth_id":      "test",
+						"sloth_window":  "1h",
 					},
 				},
 				{
-					Record: "slo:time_period:days",
-					Expr:   "vector(30)",
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+				{
+					Record: "slo:sli_total:rate1h",
+					Expr:   "sum(rate(my_metric[1h]))\n",
 					Labels: map[string]string{
-						"kind":          "test",
 						"sloth_service": "test-svc",
 						"sloth_slo":     "test-name",
 						"sloth_id":      "test",
+						"sloth_window":  "1h",
+					},
+				},
+			},
+		},
+
+		"Having a time slice SLO should create the time slice recording rule and the bad time slices ratio recording rules.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Events: &prometheus.SLIEvents{
+						ErrorQuery: `rate(my_metric[{{.window}}]{error="true"})`,
+						TotalQuery: `rate(my_metric[{{.window}}])`,
 					},
 				},
+				TimeSlice: &prometheus.TimeSlice{Duration: 1 * time.Minute, Threshold: 0.05},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
 				{
-					Record: "slo:current_burn_rate:ratio",
-					Expr: `slo:sli_error:ratio_rate5m{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
-/ on(sloth_id, sloth_slo, sloth_service) group_left
-slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
-`,
+					Record: "slo:sli_error:time_slice",
+					Expr:   "((rate(my_metric[1m]{error=\"true\"}))\n/\n(rate(my_metric[1m]))) > bool 0.05\n",
 					Labels: map[string]string{
-						"kind":          "test",
 						"sloth_service": "test-svc",
 						"sloth_slo":     "test-name",
 						"sloth_id":      "test",
+						"sloth_window":  "1m",
 					},
 				},
 				{
-					Record: "slo:period_burn_rate:ratio",
-					Expr: `slo:sli_error:ratio_rate30d{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
-/ on(sloth_id, sloth_slo, sloth_service) group_left
-slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
-`,
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "avg_over_time(slo:sli_error:time_slice{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[1h])\n",
 					Labels: map[string]string{
-						"kind":          "test",
 						"sloth_service": "test-svc",
 						"sloth_slo":     "test-name",
 						"sloth_id":      "test",
+						"sloth_window":  "1h",
 					},
 				},
 				{
-					Record: "slo:period_error_budget_remaining:ratio",
-					Expr:   `1 - slo:period_burn_rate:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}`,
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+			},
+		},
+
+		"Having exclusions should remove the SLI error ratio samples while any exclusion is active.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Raw: &prometheus.SLIRaw{ErrorRatioQuery: `rate(my_metric[{{.window}}])`},
+				},
+				Exclusions: []prometheus.Exclusion{
+					{Query: `maintenance_mode == 1`},
+					{Query: `vector(time()) >= 1622541600 < 1622548800`},
+				},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "((rate(my_metric[1h])))\nunless on()\n((maintenance_mode == 1) or (vector(time()) >= 1622541600 < 1622548800))\n",
 					Labels: map[string]string{
-						"kind":          "test",
 						"sloth_service": "test-svc",
 						"sloth_slo":     "test-name",
 						"sloth_id":      "test",
+						"sloth_window":  "1h",
 					},
 				},
 				{
-					Record: "sloth_slo_info",
-					Expr:   `vector(1)`,
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+			},
+		},
+
+		"Having dedup labels should remove the replica labels from the SLI recordings.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Events: &prometheus.SLIEvents{
+						ErrorQuery: `sum by (replica) (rate(my_errors[{{.window}}]))`,
+						TotalQuery: `sum by (replica) (rate(my_total[{{.window}}]))`,
+					},
+				},
+				RulerOptions: prometheus.RulerOptions{DedupLabels: []string{"replica", "prometheus_replica"}},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "max without (replica, prometheus_replica) (\n(sum by (replica) (rate(my_errors[1h])))\n/\n(sum by (replica) (rate(my_total[1h])))\n)\n",
 					Labels: map[string]string{
-						"kind":          "test",
 						"sloth_service": "test-svc",
 						"sloth_slo":     "test-name",
 						"sloth_id":      "test",
-						"sloth_version": "test-ver",
-						"sloth_mode":    "test",
-						"sloth_spec":    "test/v1",
+						"sloth_window":  "1h",
+					},
+				},
+				{
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+				{
+					Record: "slo:sli_total:rate1h",
+					Expr:   "sum(max without (replica, prometheus_replica) (\nsum by (replica) (rate(my_total[1h]))\n))\n",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "1h",
 					},
 				},
 			},
 		},
-	}
-
-	for name, test := range tests {
-		t.Run(name, func(t *testing.T) {
-			assert := assert.New(t)
 
-			gotRules, err := prometheus.MetadataRecordingRulesGenerator.GenerateMetadataRecordingRules(context.TODO(), test.info, test.slo, test.alertGroup)
+		"Having an SLO with SLI(native histogram) should create the recording rules using the histogram fraction.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+						{Raw: &prometheus.SLIRaw{ErrorRatioQuery: `rate(my_metric[{{.window}}])`}},
+						{NativeHistogram: &prometheus.SLINativeHistogram{Selector: `my_latency{job="test"}`, Threshold: 0.5}},
+					}},
+				},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "1 - (\n(1 - (rate(my_metric[1h])))\n*\n(1 - (1 - histogram_fraction(0, 0.5, sum(rate(my_latency{job=\"test\"}[1h])))))\n)\n",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "1h",
+					},
+				},
+				{
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+			},
+		},
 
-			if test.expErr {
-				assert.Error(err)
-			} else if assert.NoError(err) {
-				assert.Equal(test.expRules, gotRules)
-			}
+		"Having an SLO with SLI(native histogram) should create the recording rules without bucket selectors.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					NativeHistogram: &prometheus.SLINativeHistogram{Selector: `my_latency{job="test"}`, Threshold: 0.25},
+				},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "1 - histogram_fraction(0, 0.25, sum(rate(my_latency{job=\"test\"}[1h])))\n",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "1h",
+					},
+				},
+				{
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with SLI(weighted) should create the recording rules using the weighted SLOs SLI recordings.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Weighted: &prometheus.SLIWeighted{SLOs: []prometheus.WeightedSLO{
+						{ID: "api-requests", Weight: 3},
+						{ID: "payments-requests", Weight: 1},
+					}},
+				},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 1 * time.Hour, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "(\n0.75 * max(slo:sli_error:ratio_rate1h{sloth_id=\"api-requests\"})\n+\n0.25 * max(slo:sli_error:ratio_rate1h{sloth_id=\"payments-requests\"})\n)\n",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "1h",
+					},
+				},
+				{
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate1h{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with SLI(aggregated) should create the recording rules using the aggregated SLO SLI recordings weighted by traffic.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Aggregated: &prometheus.SLIAggregated{ID: "test-svc-requests", Labels: []string{"cluster", "tenant"}},
+				},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate5m",
+					Expr:   "sum(\n  slo:sli_error:ratio_rate5m{sloth_id=\"test-svc-requests\"}\n  * on(sloth_id, cluster, tenant)\n  slo:sli_total:rate5m{sloth_id=\"test-svc-requests\"}\n)\n/\nsum(slo:sli_total:rate5m{sloth_id=\"test-svc-requests\"})\n",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "5m",
+					},
+				},
+				{
+					Record: "slo:sli_error:ratio_rate1h",
+					Expr:   "sum(\n  slo:sli_error:ratio_rate1h{sloth_id=\"test-svc-requests\"}\n  * on(sloth_id, cluster, tenant)\n  slo:sli_total:rate5m{sloth_id=\"test-svc-requests\"}\n)\n/\nsum(slo:sli_total:rate5m{sloth_id=\"test-svc-requests\"})\n",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "1h",
+					},
+				},
+				{
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: map[string]string{
+						"sloth_window": "30d",
+					},
+				},
+				{
+					Record: "slo:sli_total:rate5m",
+					Expr:   "sum(slo:sli_total:rate5m{sloth_id=\"test-svc-requests\"})\n",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "5m",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with a calendar quarter period should create the period recording rule using only the current quarter samples.": {
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 90 * 24 * time.Hour,
+				Calendar:   prometheus.CalendarQuarterPeriod,
+				SLI: prometheus.SLI{
+					Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: `rate(my_metric[{{.window}}])`,
+					},
+				},
+			},
+			alertGroup: alert.MWMBAlertGroup{
+				PageQuick:   alert.MWMBAlert{ShortWindow: 15 * time.Minute, LongWindow: 15 * time.Minute},
+				PageSlow:    alert.MWMBAlert{ShortWindow: 15 * time.Minute, LongWindow: 15 * time.Minute},
+				TicketQuick: alert.MWMBAlert{ShortWindow: 15 * time.Minute, LongWindow: 15 * time.Minute},
+				TicketSlow:  alert.MWMBAlert{ShortWindow: 15 * time.Minute, LongWindow: 15 * time.Minute},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error:ratio_rate15m",
+					Expr:   "(rate(my_metric[15m]))",
+					Labels: map[string]string{
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_window":  "15m",
+					},
+				},
+				{
+					Record: "slo:sli_error:ratio_rate90d",
+					Expr: `sum_over_time(
+  (
+    slo:sli_error:ratio_rate15m{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+    and on()
+    (floor((month() - 1) / 3) == on() last_over_time(floor((month() - 1) / 3)[15m:15m] @ end()))
+  )[92d:15m]
+)
+/ ignoring (sloth_window)
+count_over_time(
+  (
+    slo:sli_error:ratio_rate15m{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+    and on()
+    (floor((month() - 1) / 3) == on() last_over_time(floor((month() - 1) / 3)[15m:15m] @ end()))
+  )[92d:15m]
+)
+`,
+					Labels: map[string]string{
+						"sloth_window": "90d",
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotRules, err := prometheus.SLIRecordingRulesGenerator.GenerateSLIRecordingRules(context.TODO(), test.slo, test.alertGroup)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expRules, gotRules)
+			}
+		})
+	}
+}
+
+func TestGenerateMetaRecordingRules(t *testing.T) {
+	tests := map[string]struct {
+		info       info.Info
+		slo        prometheus.SLO
+		alertGroup alert.MWMBAlertGroup
+		expRules   []rulefmt.Rule
+		expErr     bool
+	}{
+		"Having and SLO an its mwmb alerts should create the metadata recording rules.": {
+			info: info.Info{
+				Version: "test-ver",
+				Mode:    info.ModeTest,
+				Spec:    "test/v1",
+			},
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				Objective:  99.9,
+				TimeWindow: 30 * 24 * time.Hour,
+				Labels: map[string]string{
+					"kind": "test",
+				},
+			},
+			alertGroup: getAlertGroup(),
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:objective:ratio",
+					Expr:   "vector(0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:error_budget:ratio",
+					Expr:   "vector(1-0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:time_period:days",
+					Expr:   "vector(30)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:current_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate5m{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate30d{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:ratio",
+					Expr:   `1 - slo:period_burn_rate:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:minutes",
+					Expr:   "slo:period_error_budget_remaining:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(slo:error_budget:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * slo:time_period:days{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * 1440)\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "sloth_slo_info",
+					Expr:   `vector(1)`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_version": "test-ver",
+						"sloth_mode":    "test",
+						"sloth_spec":    "test/v1",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with a metrics prefix should create the metadata recording rules using the prefix.": {
+			info: info.Info{
+				Version: "test-ver",
+				Mode:    info.ModeTest,
+				Spec:    "test/v1",
+			},
+			slo: prometheus.SLO{
+				ID:            "test",
+				Name:          "test-name",
+				Service:       "test-svc",
+				MetricsPrefix: "myorg",
+				Objective:     99.9,
+				TimeWindow:    30 * 24 * time.Hour,
+				Labels: map[string]string{
+					"kind": "test",
+				},
+			},
+			alertGroup: getAlertGroup(),
+			expRules: []rulefmt.Rule{
+				{
+					Record: "myorg:objective:ratio",
+					Expr:   "vector(0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "myorg:error_budget:ratio",
+					Expr:   "vector(1-0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "myorg:time_period:days",
+					Expr:   "vector(30)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "myorg:current_burn_rate:ratio",
+					Expr: `myorg:sli_error:ratio_rate5m{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+myorg:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "myorg:period_burn_rate:ratio",
+					Expr: `myorg:sli_error:ratio_rate30d{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+myorg:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "myorg:period_error_budget_remaining:ratio",
+					Expr:   `1 - myorg:period_burn_rate:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "myorg:period_error_budget_remaining:minutes",
+					Expr:   "myorg:period_error_budget_remaining:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(myorg:error_budget:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * myorg:time_period:days{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * 1440)\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "myorg_slo_info",
+					Expr:   `vector(1)`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_version": "test-ver",
+						"sloth_mode":    "test",
+						"sloth_spec":    "test/v1",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with external labels should create the metadata recording rules identifying the SLO with the external labels.": {
+			info: info.Info{
+				Version: "test-ver",
+				Mode:    info.ModeTest,
+				Spec:    "test/v1",
+			},
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				Objective:  99.9,
+				TimeWindow: 30 * 24 * time.Hour,
+				ExternalLabels: map[string]string{
+					"cluster": "eu-1",
+				},
+				Labels: map[string]string{
+					"kind": "test",
+				},
+			},
+			alertGroup: getAlertGroup(),
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:objective:ratio",
+					Expr:   "vector(0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:error_budget:ratio",
+					Expr:   "vector(1-0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:time_period:days",
+					Expr:   "vector(30)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:current_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate5m{cluster="eu-1", sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service, cluster) group_left
+slo:error_budget:ratio{cluster="eu-1", sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate30d{cluster="eu-1", sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service, cluster) group_left
+slo:error_budget:ratio{cluster="eu-1", sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:ratio",
+					Expr:   `1 - slo:period_burn_rate:ratio{cluster="eu-1", sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:minutes",
+					Expr:   "slo:period_error_budget_remaining:ratio{cluster=\"eu-1\", sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service, cluster) group_left()\n(slo:error_budget:ratio{cluster=\"eu-1\", sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * slo:time_period:days{cluster=\"eu-1\", sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * 1440)\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "sloth_slo_info",
+					Expr:   `vector(1)`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"cluster":       "eu-1",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_version": "test-ver",
+						"sloth_mode":    "test",
+						"sloth_spec":    "test/v1",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with customized Sloth labels should create the metadata recording rules using the customized labels.": {
+			info: info.Info{
+				Version: "test-ver",
+				Mode:    info.ModeTest,
+				Spec:    "test/v1",
+			},
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				Objective:  99.9,
+				TimeWindow: 30 * 24 * time.Hour,
+				SlothLabels: prometheus.SlothLabels{
+					Prefix: "myorg",
+					Rename: map[string]string{"sloth_service": "service"},
+					Drop:   []string{"sloth_slo", "sloth_mode"},
+				},
+				Labels: map[string]string{
+					"kind": "test",
+				},
+			},
+			alertGroup: getAlertGroup(),
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:objective:ratio",
+					Expr:   "vector(0.999)",
+					Labels: map[string]string{
+						"kind":     "test",
+						"service":  "test-svc",
+						"myorg_id": "test",
+					},
+				},
+				{
+					Record: "slo:error_budget:ratio",
+					Expr:   "vector(1-0.999)",
+					Labels: map[string]string{
+						"kind":     "test",
+						"service":  "test-svc",
+						"myorg_id": "test",
+					},
+				},
+				{
+					Record: "slo:time_period:days",
+					Expr:   "vector(30)",
+					Labels: map[string]string{
+						"kind":     "test",
+						"service":  "test-svc",
+						"myorg_id": "test",
+					},
+				},
+				{
+					Record: "slo:current_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate5m{myorg_id="test", service="test-svc"}
+/ on(myorg_id, service) group_left
+slo:error_budget:ratio{myorg_id="test", service="test-svc"}
+`,
+					Labels: map[string]string{
+						"kind":     "test",
+						"service":  "test-svc",
+						"myorg_id": "test",
+					},
+				},
+				{
+					Record: "slo:period_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate30d{myorg_id="test", service="test-svc"}
+/ on(myorg_id, service) group_left
+slo:error_budget:ratio{myorg_id="test", service="test-svc"}
+`,
+					Labels: map[string]string{
+						"kind":     "test",
+						"service":  "test-svc",
+						"myorg_id": "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:ratio",
+					Expr:   `1 - slo:period_burn_rate:ratio{myorg_id="test", service="test-svc"}`,
+					Labels: map[string]string{
+						"kind":     "test",
+						"service":  "test-svc",
+						"myorg_id": "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:minutes",
+					Expr:   "slo:period_error_budget_remaining:ratio{myorg_id=\"test\", service=\"test-svc\"}\n* on(myorg_id, service) group_left()\n(slo:error_budget:ratio{myorg_id=\"test\", service=\"test-svc\"} * slo:time_period:days{myorg_id=\"test\", service=\"test-svc\"} * 1440)\n",
+					Labels: map[string]string{
+						"kind":     "test",
+						"service":  "test-svc",
+						"myorg_id": "test",
+					},
+				},
+				{
+					Record: "sloth_slo_info",
+					Expr:   `vector(1)`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"service":       "test-svc",
+						"myorg_id":      "test",
+						"myorg_version": "test-ver",
+						"myorg_spec":    "test/v1",
+					},
+				},
+			},
+		},
+
+		"Having an SLO with dependencies should create the metadata recording rules with the dependencies on the info.": {
+			info: info.Info{
+				Version: "test-ver",
+				Mode:    info.ModeTest,
+				Spec:    "test/v1",
+			},
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				Objective:  99.9,
+				TimeWindow: 30 * 24 * time.Hour,
+				DependsOn:  []string{"payments-requests", "db-queries"},
+				Labels: map[string]string{
+					"kind": "test",
+				},
+			},
+			alertGroup: getAlertGroup(),
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:objective:ratio",
+					Expr:   "vector(0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:error_budget:ratio",
+					Expr:   "vector(1-0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:time_period:days",
+					Expr:   "vector(30)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:current_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate5m{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate30d{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:ratio",
+					Expr:   `1 - slo:period_burn_rate:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:minutes",
+					Expr:   "slo:period_error_budget_remaining:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(slo:error_budget:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * slo:time_period:days{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * 1440)\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "sloth_slo_info",
+					Expr:   `vector(1)`,
+					Labels: map[string]string{
+						"kind":             "test",
+						"sloth_service":    "test-svc",
+						"sloth_slo":        "test-name",
+						"sloth_id":         "test",
+						"sloth_version":    "test-ver",
+						"sloth_mode":       "test",
+						"sloth_spec":       "test/v1",
+						"sloth_depends_on": "db-queries,payments-requests",
+					},
+				},
+			},
+		},
+
+		"Having an events SLO should create the metadata recording rules with the remaining error budget events.": {
+			info: info.Info{
+				Version: "test-ver",
+				Mode:    info.ModeTest,
+				Spec:    "test/v1",
+			},
+			slo: prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				Objective:  99.9,
+				TimeWindow: 30 * 24 * time.Hour,
+				SLI: prometheus.SLI{
+					Events: &prometheus.SLIEvents{
+						ErrorQuery: `rate(my_metric[{{.window}}]{error="true"})`,
+						TotalQuery: `rate(my_metric[{{.window}}])`,
+					},
+				},
+				Labels: map[string]string{
+					"kind": "test",
+				},
+			},
+			alertGroup: getAlertGroup(),
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:objective:ratio",
+					Expr:   "vector(0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:error_budget:ratio",
+					Expr:   "vector(1-0.999)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:time_period:days",
+					Expr:   "vector(30)",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:current_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate5m{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_burn_rate:ratio",
+					Expr: `slo:sli_error:ratio_rate30d{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+/ on(sloth_id, sloth_slo, sloth_service) group_left
+slo:error_budget:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}
+`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:ratio",
+					Expr:   `1 - slo:period_burn_rate:ratio{sloth_id="test", sloth_service="test-svc", sloth_slo="test-name"}`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:minutes",
+					Expr:   "slo:period_error_budget_remaining:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(slo:error_budget:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * slo:time_period:days{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"} * 1440)\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "slo:period_error_budget_remaining:events",
+					Expr:   "slo:period_error_budget_remaining:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left\nslo:error_budget:ratio{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(avg_over_time(slo:sli_total:rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d]) * 2592000)\n",
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+					},
+				},
+				{
+					Record: "sloth_slo_info",
+					Expr:   `vector(1)`,
+					Labels: map[string]string{
+						"kind":          "test",
+						"sloth_service": "test-svc",
+						"sloth_slo":     "test-name",
+						"sloth_id":      "test",
+						"sloth_version": "test-ver",
+						"sloth_mode":    "test",
+						"sloth_spec":    "test/v1",
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotRules, err := prometheus.MetadataRecordingRulesGenerator.GenerateMetadataRecordingRules(context.TODO(), test.info, test.slo, test.alertGroup)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expRules, gotRules)
+
+				// The generated expressions should be valid PromQL.
+				for _, rule := range gotRules {
+					_, err := promqlparser.ParseExpr(rule.Expr)
+					assert.NoError(err, rule.Record)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMetaRecordingRulesTimePeriodDays(t *testing.T) {
+	tests := map[string]struct {
+		timeWindow time.Duration
+		calendar   prometheus.CalendarPeriod
+		at         string
+		expDays    float64
+	}{
+		"A rolling time window should use the time window days.": {
+			timeWindow: 28 * 24 * time.Hour,
+			at:         "2024-02-10",
+			expDays:    28,
+		},
+
+		"A calendar month should use the current month days.": {
+			timeWindow: 30 * 24 * time.Hour,
+			calendar:   prometheus.CalendarMonthPeriod,
+			at:         "2024-02-10",
+			expDays:    29,
+		},
+
+		"A calendar first quarter should use the current quarter days.": {
+			timeWindow: 90 * 24 * time.Hour,
+			calendar:   prometheus.CalendarQuarterPeriod,
+			at:         "2023-03-31",
+			expDays:    90,
+		},
+
+		"A calendar first quarter of a leap year should use the current quarter days.": {
+			timeWindow: 90 * 24 * time.Hour,
+			calendar:   prometheus.CalendarQuarterPeriod,
+			at:         "2024-01-01",
+			expDays:    91,
+		},
+
+		"A calendar first quarter of a century not leap year should use the current quarter days.": {
+			timeWindow: 90 * 24 * time.Hour,
+			calendar:   prometheus.CalendarQuarterPeriod,
+			at:         "2100-02-01",
+			expDays:    90,
+		},
+
+		"A calendar second quarter should use the current quarter days.": {
+			timeWindow: 90 * 24 * time.Hour,
+			calendar:   prometheus.CalendarQuarterPeriod,
+			at:         "2024-04-01",
+			expDays:    91,
+		},
+
+		"A calendar third quarter should use the current quarter days.": {
+			timeWindow: 90 * 24 * time.Hour,
+			calendar:   prometheus.CalendarQuarterPeriod,
+			at:         "2024-09-30",
+			expDays:    92,
+		},
+
+		"A calendar fourth quarter should use the current quarter days.": {
+			timeWindow: 90 * 24 * time.Hour,
+			calendar:   prometheus.CalendarQuarterPeriod,
+			at:         "2024-12-31",
+			expDays:    92,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			slo := prometheus.SLO{
+				ID:         "test",
+				Name:       "test-name",
+				Service:    "test-svc",
+				Objective:  99.9,
+				TimeWindow: test.timeWindow,
+				Calendar:   test.calendar,
+			}
+			gotRules, err := prometheus.MetadataRecordingRulesGenerator.GenerateMetadataRecordingRules(context.TODO(), info.Info{}, slo, getAlertGroup())
+			require.NoError(err)
+
+			var expr string
+			for _, rule := range gotRules {
+				if rule.Record == "slo:time_period:days" {
+					expr = rule.Expr
+				}
+			}
+			require.NotEmpty(expr)
+
+			// Evaluate the expression at the time, it doesn't depend on any series.
+			storage := teststorage.New(t)
+			defer storage.Close()
+			engine := promql.NewEngine(promql.EngineOpts{MaxSamples: 10, Timeout: time.Minute})
+			at, err := time.Parse("2006-01-02", test.at)
+			require.NoError(err)
+			query, err := engine.NewInstantQuery(storage, expr, at)
+			require.NoError(err)
+			res := query.Exec(context.TODO())
+			require.NoError(res.Err)
+			vector, err := res.Vector()
+			require.NoError(err)
+			require.Len(vector, 1)
+			assert.Equal(test.expDays, vector[0].V)
 		})
 	}
 }
@@ -1,13 +1,20 @@
 package prometheus
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
 	"time"
 
+	prommodel "github.com/prometheus/common/model"
 	"gopkg.in/yaml.v2"
 
+	"github.com/slok/sloth/internal/alert"
 	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
+	prometheusv2 "github.com/slok/sloth/pkg/prometheus/api/v2"
 )
 
 type yamlSpecLoader bool
@@ -44,54 +51,731 @@ func (y yamlSpecLoader) LoadSpec(ctx context.Context, data []byte) (*SLOGroup, e
 	return m, nil
 }
 
+// mapSpecToModel maps the v1 spec using the v2 spec mapping, the v2 spec is a superset
+// of the v1 spec with the same YAML format, so the v1 spec is converted to the v2 spec.
+// The conversion is strict, so a v1 field missing on v2 fails instead of being dropped.
 func (yamlSpecLoader) mapSpecToModel(spec prometheusv1.Spec) (*SLOGroup, error) {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal v1 spec: %w", err)
+	}
+
+	v2Spec := prometheusv2.Spec{}
+	err = yaml.UnmarshalStrict(data, &v2Spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert v1 spec to v2 spec: %w", err)
+	}
+
+	return YAMLSpecV2Loader.mapSpecToModel(v2Spec)
+}
+
+type yamlSpecV2Loader bool
+
+// YAMLSpecV2Loader knows how to load YAML v2 specs and converts them to a model.
+const YAMLSpecV2Loader = yamlSpecV2Loader(false)
+
+func (y yamlSpecV2Loader) LoadSpec(ctx context.Context, data []byte) (*SLOGroup, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("spec is required")
+	}
+
+	s := prometheusv2.Spec{}
+	err := yaml.Unmarshal(data, &s)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshall YAML spec correctly: %w", err)
+	}
+
+	// Check version.
+	if s.Version != prometheusv2.Version {
+		return nil, fmt.Errorf("invalid spec version, should be %q", prometheusv2.Version)
+	}
+
+	// Check at least we have one SLO.
+	if len(s.SLOs) == 0 {
+		return nil, fmt.Errorf("at least one SLO is required")
+	}
+
+	m, err := y.mapSpecToModel(s)
+	if err != nil {
+		return nil, fmt.Errorf("could not map to model: %w", err)
+	}
+
+	return m, nil
+}
+
+func (y yamlSpecV2Loader) mapSpecToModel(spec prometheusv2.Spec) (*SLOGroup, error) {
+	var intervals RuleGroupIntervals
+	if spec.RuleGroupIntervals != nil {
+		var err error
+		intervals, err = NewRuleGroupIntervals(spec.RuleGroupIntervals.SLI, spec.RuleGroupIntervals.Metadata, spec.RuleGroupIntervals.Alerts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule group intervals: %w", err)
+		}
+	}
+
+	var runbookURLs RunbookURLs
+	if spec.RunbookURLs != nil {
+		runbookURLs = RunbookURLs{Page: spec.RunbookURLs.Page, Ticket: spec.RunbookURLs.Ticket}
+	}
+
+	var slothLabels SlothLabels
+	if spec.SlothLabels != nil {
+		slothLabels = SlothLabels{Prefix: spec.SlothLabels.Prefix, Rename: spec.SlothLabels.Rename, Drop: spec.SlothLabels.Drop}
+	}
+
+	var rulerOptions RulerOptions
+	if spec.Ruler != nil {
+		rulerOptions = RulerOptions{
+			DedupLabels:             spec.Ruler.DedupLabels,
+			PartialResponseStrategy: spec.Ruler.PartialResponseStrategy,
+			SourceTenants:           spec.Ruler.SourceTenants,
+		}
+	}
+
 	models := make([]SLO, 0, len(spec.SLOs))
 	for _, specSLO := range spec.SLOs {
+		timeWindow, calendar, err := ParseTimeWindow(specSLO.TimeWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO time window: %w", specSLO.Name, err)
+		}
+
+		err = ValidateObjective(specSLO.Objective)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO objective: %w", specSLO.Name, err)
+		}
+
 		slo := SLO{
-			ID:               fmt.Sprintf("%s-%s", spec.Service, specSLO.Name),
-			Name:             specSLO.Name,
-			Description:      specSLO.Description,
-			Service:          spec.Service,
-			TimeWindow:       30 * 24 * time.Hour, // Default and for now the only one supported.
-			Objective:        specSLO.Objective,
-			Labels:           mergeLabels(spec.Labels, specSLO.Labels),
-			PageAlertMeta:    AlertMeta{Disable: true},
-			WarningAlertMeta: AlertMeta{Disable: true},
+			ID:                 fmt.Sprintf("%s-%s", spec.Service, specSLO.Name),
+			Name:               specSLO.Name,
+			Description:        specSLO.Description,
+			DependsOn:          specSLO.DependsOn,
+			Service:            spec.Service,
+			MetricsPrefix:      spec.MetricsPrefix,
+			RuleGroupIntervals: intervals,
+			RuleGroupName:      spec.RuleGroupName,
+			RunbookURLs:        runbookURLs,
+			SlothLabels:        slothLabels,
+			RulerOptions:       rulerOptions,
+			ExternalLabels:     spec.ExternalLabels,
+			TimeWindow:         timeWindow,
+			Calendar:           calendar,
+			Objective:          specSLO.Objective,
+			Labels:             mergeLabels(spec.Labels, specSLO.Labels),
+			PageAlertMeta:      AlertMeta{Disable: true},
+			WarningAlertMeta:   AlertMeta{Disable: true},
 		}
 
 		// Set SLIs.
-		if specSLO.SLI.Events != nil {
-			slo.SLI.Events = &SLIEvents{
-				ErrorQuery: specSLO.SLI.Events.ErrorQuery,
-				TotalQuery: specSLO.SLI.Events.TotalQuery,
-			}
+		slo.SLI, err = y.mapSLIToModel(specSLO.SLI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO SLI: %w", specSLO.Name, err)
 		}
 
-		if specSLO.SLI.Raw != nil {
-			slo.SLI.Raw = &SLIRaw{
-				ErrorRatioQuery: specSLO.SLI.Raw.ErrorRatioQuery,
+		// Set time slice.
+		if specSLO.TimeSlice != nil {
+			slo.TimeSlice, err = NewTimeSlice(specSLO.TimeSlice.Duration, specSLO.TimeSlice.Threshold)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q SLO time slice: %w", specSLO.Name, err)
 			}
 		}
 
-		// Set alerts.
-		if !specSLO.Alerting.PageAlert.Disable {
-			slo.PageAlertMeta = AlertMeta{
-				Name:        specSLO.Alerting.Name,
-				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.PageAlert.Labels),
-				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.PageAlert.Annotations),
+		// Set exclusions.
+		for _, e := range specSLO.Exclusions {
+			exclusion, err := NewExclusion(e.Query, e.Start, e.End, e.StartTime, e.EndTime, e.Days)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q SLO exclusion: %w", specSLO.Name, err)
 			}
+			slo.Exclusions = append(slo.Exclusions, exclusion)
 		}
 
-		if !specSLO.Alerting.TicketAlert.Disable {
-			slo.WarningAlertMeta = AlertMeta{
-				Name:        specSLO.Alerting.Name,
-				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.TicketAlert.Labels),
-				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.TicketAlert.Annotations),
+		// Set disabled rules.
+		if specSLO.DisabledRules != nil {
+			slo.DisabledRules = DisabledRules{
+				SLIErrorRecRules: specSLO.DisabledRules.SLIRecordings,
+				MetadataRecRules: specSLO.DisabledRules.MetadataRecordings,
+				AlertRules:       specSLO.DisabledRules.Alerts,
 			}
 		}
 
+		// Set alerts.
+		err = y.mapAlertingToModel(&slo, specSLO.Alerting)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q SLO alerting: %w", specSLO.Name, err)
+		}
+
 		models = append(models, slo)
+
+		// Set additional objectives as independent SLOs.
+		for _, specObj := range specSLO.AdditionalObjectives {
+			err := ValidateObjective(specObj.Objective)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q SLO %q objective: %w", specSLO.Name, specObj.Name, err)
+			}
+
+			objSLO := slo
+			objSLO.ID = fmt.Sprintf("%s-%s", slo.ID, specObj.Name)
+			objSLO.Name = fmt.Sprintf("%s-%s", slo.Name, specObj.Name)
+			objSLO.Objective = specObj.Objective
+			objSLO.Labels = mergeLabels(slo.Labels)
+			objSLO.PageAlertMeta = AlertMeta{Disable: true}
+			objSLO.WarningAlertMeta = AlertMeta{Disable: true}
+			objSLO.AlertOverrides = alert.MWMBAlertGroupOverride{}
+			objSLO.ForecastAlertMeta = nil
+			objSLO.MinTraffic = nil
+			objSLO.KeepFiringFor = 0
+			if specObj.Alerting != nil {
+				err := y.mapAlertingToModel(&objSLO, *specObj.Alerting)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %q SLO %q objective alerting: %w", specSLO.Name, specObj.Name, err)
+				}
+			}
+
+			models = append(models, objSLO)
+		}
+	}
+
+	err := ValidateSLIReferences(models)
+	if err != nil {
+		return nil, err
 	}
 
 	return &SLOGroup{SLOs: models}, nil
 }
+
+func (yamlSpecV2Loader) mapAlertingToModel(slo *SLO, alerting prometheusv2.Alerting) error {
+	slo.AlertStrategy = AlertStrategy(alerting.Strategy)
+	slo.PageAlertMeta, slo.WarningAlertMeta = AlertMeta{Disable: true}, AlertMeta{Disable: true}
+
+	if !alerting.PageAlert.Disable {
+		slo.PageAlertMeta = AlertMeta{
+			Name:        alerting.Name,
+			Labels:      mergeAlertTierLabels(alerting.Labels, alerting.PageAlert.Labels),
+			Annotations: mergeAlertTierLabels(alerting.Annotations, alerting.PageAlert.Annotations),
+		}
+	}
+
+	if !alerting.TicketAlert.Disable {
+		slo.WarningAlertMeta = AlertMeta{
+			Name:        alerting.Name,
+			Labels:      mergeAlertTierLabels(alerting.Labels, alerting.TicketAlert.Labels),
+			Annotations: mergeAlertTierLabels(alerting.Annotations, alerting.TicketAlert.Annotations),
+		}
+	}
+
+	pageFor, err := ParseAlertFor(alerting.PageAlert.For)
+	if err != nil {
+		return fmt.Errorf("invalid page alert: %w", err)
+	}
+	slo.PageAlertMeta.For = pageFor
+
+	ticketFor, err := ParseAlertFor(alerting.TicketAlert.For)
+	if err != nil {
+		return fmt.Errorf("invalid ticket alert: %w", err)
+	}
+	slo.WarningAlertMeta.For = ticketFor
+
+	err = ApplyAlertSeverities(slo, alerting.Severities)
+	if err != nil {
+		return err
+	}
+
+	page, err := SingleWindowAlertOverride(slo.AlertStrategy, alerting.PageAlert.Window, alerting.PageAlert.BurnRate)
+	if err != nil {
+		return fmt.Errorf("invalid page alert: %w", err)
+	}
+
+	ticket, err := SingleWindowAlertOverride(slo.AlertStrategy, alerting.TicketAlert.Window, alerting.TicketAlert.BurnRate)
+	if err != nil {
+		return fmt.Errorf("invalid ticket alert: %w", err)
+	}
+
+	slo.AlertOverrides = alert.MWMBAlertGroupOverride{PageQuick: page, TicketQuick: ticket}
+
+	if br := alerting.BurnRates; br != nil {
+		if slo.AlertStrategy != "" && slo.AlertStrategy != MWMBAlertStrategy {
+			return fmt.Errorf("burn rates are only supported by %q alert strategy", MWMBAlertStrategy)
+		}
+		slo.AlertOverrides.PageQuick.BurnRateFactor = br.PageQuick
+		slo.AlertOverrides.PageSlow.BurnRateFactor = br.PageSlow
+		slo.AlertOverrides.TicketQuick.BurnRateFactor = br.TicketQuick
+		slo.AlertOverrides.TicketSlow.BurnRateFactor = br.TicketSlow
+	}
+
+	if ws := alerting.Windows; ws != nil {
+		if slo.AlertStrategy != "" && slo.AlertStrategy != MWMBAlertStrategy {
+			return fmt.Errorf("windows are only supported by %q alert strategy", MWMBAlertStrategy)
+		}
+
+		windows := []struct {
+			name     string
+			window   prometheusv2.AlertWindow
+			override *alert.MWMBAlertOverride
+		}{
+			{name: "page quick", window: ws.PageQuick, override: &slo.AlertOverrides.PageQuick},
+			{name: "page slow", window: ws.PageSlow, override: &slo.AlertOverrides.PageSlow},
+			{name: "ticket quick", window: ws.TicketQuick, override: &slo.AlertOverrides.TicketQuick},
+			{name: "ticket slow", window: ws.TicketSlow, override: &slo.AlertOverrides.TicketSlow},
+		}
+		for _, w := range windows {
+			short, long, err := ParseAlertWindows(w.window.Short, w.window.Long)
+			if err != nil {
+				return fmt.Errorf("invalid %s alert windows: %w", w.name, err)
+			}
+			w.override.ShortWindow, w.override.LongWindow = short, long
+		}
+	}
+
+	slo.KeepFiringFor = 0
+	if alerting.KeepFiringFor != "" {
+		d, err := prommodel.ParseDuration(alerting.KeepFiringFor)
+		if err != nil {
+			return fmt.Errorf("could not parse %q keep firing for: %w", alerting.KeepFiringFor, err)
+		}
+		slo.KeepFiringFor = time.Duration(d)
+	}
+
+	slo.MinTraffic = nil
+	if mt := alerting.MinTraffic; mt != nil {
+		minTraffic, err := NewMinTraffic(slo.SLI, mt.Query, mt.Rate)
+		if err != nil {
+			return fmt.Errorf("invalid min traffic: %w", err)
+		}
+		slo.MinTraffic = minTraffic
+	}
+
+	slo.ForecastAlertMeta = nil
+	if fa := alerting.ForecastAlert; fa != nil {
+		window, err := ParseAlertWindow(fa.Window)
+		if err != nil {
+			return fmt.Errorf("invalid forecast alert: %w", err)
+		}
+
+		slo.ForecastAlertMeta = &ForecastAlertMeta{
+			Name:        ForecastAlertName(alerting.Name, fa.Name),
+			Window:      window,
+			Labels:      mergeAlertTierLabels(alerting.Labels, fa.Labels),
+			Annotations: mergeAlertTierLabels(alerting.Annotations, fa.Annotations),
+		}
+	}
+
+	return nil
+}
+
+func (y yamlSpecV2Loader) mapSLIToModel(specSLI prometheusv2.SLI) (SLI, error) {
+	sli := SLI{}
+
+	if specSLI.Events != nil {
+		sli.Events = &SLIEvents{
+			ErrorQuery: specSLI.Events.ErrorQuery,
+			GoodQuery:  specSLI.Events.GoodQuery,
+			TotalQuery: specSLI.Events.TotalQuery,
+		}
+	}
+
+	if specSLI.Raw != nil {
+		errorRatioQuery, err := ExpandRawSLIQueries(specSLI.Raw.ErrorRatioQuery, specSLI.Raw.Queries)
+		if err != nil {
+			return sli, fmt.Errorf("invalid raw SLI: %w", err)
+		}
+		sli.Raw = &SLIRaw{
+			ErrorRatioQuery: errorRatioQuery,
+		}
+	}
+
+	if specSLI.NativeHistogram != nil {
+		sli.NativeHistogram = &SLINativeHistogram{
+			Selector:  specSLI.NativeHistogram.Selector,
+			Threshold: specSLI.NativeHistogram.Threshold,
+		}
+	}
+
+	if specSLI.Composite != nil {
+		slis := make([]SLI, 0, len(specSLI.Composite.All))
+		for _, s := range specSLI.Composite.All {
+			compSLI, err := y.mapSLIToModel(s)
+			if err != nil {
+				return sli, err
+			}
+			slis = append(slis, compSLI)
+		}
+		sli.Composite = &SLIComposite{SLIs: slis}
+	}
+
+	if specSLI.Weighted != nil {
+		slos := make([]WeightedSLO, 0, len(specSLI.Weighted.SLOs))
+		for _, s := range specSLI.Weighted.SLOs {
+			slos = append(slos, WeightedSLO{ID: s.ID, Weight: s.Weight})
+		}
+		sli.Weighted = &SLIWeighted{SLOs: slos}
+	}
+
+	if specSLI.Aggregated != nil {
+		sli.Aggregated = &SLIAggregated{ID: specSLI.Aggregated.ID, Labels: specSLI.Aggregated.Labels}
+	}
+
+	return sli, nil
+}
+
+// ValidateSLIReferences validates the SLOs referenced by the SLIs of the SLOs. The weighted SLIs
+// use the SLI error ratio recordings of the referenced SLOs, so these need to be on the same
+// group and have the same time window (the same SLI windows).
+//
+// The aggregated SLIs also use the SLI total events recordings of the referenced SLO, this is
+// normally generated on other places (e.g clusters), so is only validated when it's on the group.
+func ValidateSLIReferences(slos []SLO) error {
+	sloIndex := make(map[string]SLO, len(slos))
+	for _, slo := range slos {
+		sloIndex[slo.ID] = slo
+	}
+
+	for _, slo := range slos {
+		if agg := slo.SLI.Aggregated; agg != nil {
+			if agg.ID == slo.ID {
+				return fmt.Errorf("invalid %q SLO aggregated SLI: the SLO can't aggregate itself", slo.ID)
+			}
+			ref, ok := sloIndex[agg.ID]
+			if ok && !recordsSLITotalEvents(ref) {
+				return fmt.Errorf("invalid %q SLO aggregated SLI: %q SLO should record the SLI total events (an events SLI without time slices or calendar time windows)", slo.ID, agg.ID)
+			}
+			if ok && (ref.TimeWindow != slo.TimeWindow || ref.Calendar != slo.Calendar) {
+				return fmt.Errorf("invalid %q SLO aggregated SLI: %q SLO should have the same time window", slo.ID, agg.ID)
+			}
+		}
+
+		if slo.SLI.Weighted == nil {
+			continue
+		}
+
+		for _, w := range slo.SLI.Weighted.SLOs {
+			ref, ok := sloIndex[w.ID]
+			if !ok {
+				return fmt.Errorf("invalid %q SLO weighted SLI: %q SLO is missing on the SLO group", slo.ID, w.ID)
+			}
+			if ref.TimeWindow != slo.TimeWindow || ref.Calendar != slo.Calendar {
+				return fmt.Errorf("invalid %q SLO weighted SLI: %q SLO should have the same time window", slo.ID, w.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DefaultTimeWindow is the SLO time window used when the spec doesn't set one.
+const DefaultTimeWindow = 30 * 24 * time.Hour
+
+// ParseTimeWindow parses an spec SLO time window, these can be Prometheus durations
+// (e.g 7d, 90d), the `quarterly` alias or the `calendar-month` and `calendar-quarter`
+// calendar aligned periods. Empty time windows return the default one.
+//
+// Calendar periods return their nominal time window (30d and 90d) as the time window.
+func ParseTimeWindow(tw string) (time.Duration, CalendarPeriod, error) {
+	switch tw {
+	case "":
+		return DefaultTimeWindow, NoCalendarPeriod, nil
+	case "quarterly":
+		return 90 * 24 * time.Hour, NoCalendarPeriod, nil
+	case "calendar-month":
+		return 30 * 24 * time.Hour, CalendarMonthPeriod, nil
+	case "calendar-quarter":
+		return 90 * 24 * time.Hour, CalendarQuarterPeriod, nil
+	}
+
+	d, err := prommodel.ParseDuration(tw)
+	if err != nil {
+		return 0, NoCalendarPeriod, fmt.Errorf("could not parse %q time window: %w", tw, err)
+	}
+
+	return time.Duration(d), NoCalendarPeriod, nil
+}
+
+// SingleWindowAlertOverride returns the alert override for the window and burn rate
+// settings of a single window alert, these are only valid with the single window strategy.
+func SingleWindowAlertOverride(strategy AlertStrategy, window string, burnRate float64) (alert.MWMBAlertOverride, error) {
+	if window == "" && burnRate == 0 {
+		return alert.MWMBAlertOverride{}, nil
+	}
+
+	if strategy != SingleWindowAlertStrategy {
+		return alert.MWMBAlertOverride{}, fmt.Errorf("window and burn rate are only supported by %q alert strategy", SingleWindowAlertStrategy)
+	}
+
+	if burnRate < 0 {
+		return alert.MWMBAlertOverride{}, fmt.Errorf("burn rate can't be negative")
+	}
+
+	w, err := ParseAlertWindow(window)
+	if err != nil {
+		return alert.MWMBAlertOverride{}, err
+	}
+
+	return alert.MWMBAlertOverride{LongWindow: w, BurnRateFactor: burnRate}, nil
+}
+
+// ParseAlertWindow parses an alert window in Prometheus duration format, an empty
+// window is returned as zero so the default window is used.
+func ParseAlertWindow(window string) (time.Duration, error) {
+	if window == "" {
+		return 0, nil
+	}
+
+	d, err := prommodel.ParseDuration(window)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse %q window: %w", window, err)
+	}
+	if d == 0 {
+		return 0, fmt.Errorf("window can't be zero")
+	}
+
+	return time.Duration(d), nil
+}
+
+// Alert severities that can be generated for an SLO.
+const (
+	allAlertSeverities      = "all"
+	pageOnlyAlertSeverity   = "page-only"
+	ticketOnlyAlertSeverity = "ticket-only"
+	noAlertSeverities       = "none"
+)
+
+// ApplyAlertSeverities disables the SLO alerts of the severities that should not be
+// generated, by default all the severities are generated.
+func ApplyAlertSeverities(slo *SLO, severities string) error {
+	switch severities {
+	case "", allAlertSeverities:
+	case pageOnlyAlertSeverity:
+		slo.WarningAlertMeta = AlertMeta{Disable: true}
+	case ticketOnlyAlertSeverity:
+		slo.PageAlertMeta = AlertMeta{Disable: true}
+	case noAlertSeverities:
+		slo.PageAlertMeta = AlertMeta{Disable: true}
+		slo.WarningAlertMeta = AlertMeta{Disable: true}
+	default:
+		return fmt.Errorf("unknown %q alert severities", severities)
+	}
+
+	return nil
+}
+
+// ValidateObjective validates an spec SLO objective, the objectives of 100% and 0% are
+// rejected early with the reason, instead of failing later on the SLO validation (or
+// generating burn rate thresholds without error budget).
+func ValidateObjective(objective float64) error {
+	switch {
+	case objective >= 100:
+		return fmt.Errorf("objective %g has no error budget, any error would break the SLO and the alert burn rates would divide by zero, use a lower objective (e.g 99.99) or alert on the SLI directly", objective)
+	case objective <= 0:
+		return fmt.Errorf("objective %g allows all the events to fail so the SLO can't be broken, use a higher objective (e.g 90) or remove the SLO", objective)
+	}
+
+	return nil
+}
+
+// NewTimeSlice returns the time slice of a time slice based SLO.
+func NewTimeSlice(duration string, threshold float64) (*TimeSlice, error) {
+	d, err := prommodel.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %q duration: %w", duration, err)
+	}
+
+	return &TimeSlice{Duration: time.Duration(d), Threshold: threshold}, nil
+}
+
+// NewRuleGroupIntervals returns the rule group intervals from Prometheus durations, the
+// empty ones are not set.
+func NewRuleGroupIntervals(sli, metadata, alerts string) (RuleGroupIntervals, error) {
+	var intervals RuleGroupIntervals
+	for _, i := range []struct {
+		name     string
+		value    string
+		interval *time.Duration
+	}{
+		{name: "sli", value: sli, interval: &intervals.SLIErrorRecRules},
+		{name: "metadata", value: metadata, interval: &intervals.MetadataRecRules},
+		{name: "alerts", value: alerts, interval: &intervals.AlertRules},
+	} {
+		if i.value == "" {
+			continue
+		}
+
+		d, err := prommodel.ParseDuration(i.value)
+		if err != nil {
+			return RuleGroupIntervals{}, fmt.Errorf("could not parse %s %q interval: %w", i.name, i.value, err)
+		}
+		*i.interval = time.Duration(d)
+	}
+
+	return intervals, nil
+}
+
+// NewExclusion returns an SLO exclusion, it can be a Prometheus query, a one-off time range
+// (RFC3339 start and end) or a recurring UTC time of day range (`HH:MM` start and end times)
+// optionally limited to some week days.
+func NewExclusion(query, start, end, startTime, endTime string, days []string) (Exclusion, error) {
+	oneOff := start != "" || end != ""
+	recurring := startTime != "" || endTime != "" || len(days) > 0
+
+	switch {
+	case query != "" && !oneOff && !recurring:
+		return Exclusion{Query: query}, nil
+
+	case oneOff && query == "" && !recurring:
+		s, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return Exclusion{}, fmt.Errorf("could not parse %q start: %w", start, err)
+		}
+		e, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return Exclusion{}, fmt.Errorf("could not parse %q end: %w", end, err)
+		}
+		if !e.After(s) {
+			return Exclusion{}, fmt.Errorf("end must be after start")
+		}
+
+		return Exclusion{Query: fmt.Sprintf("vector(time()) >= %d < %d", s.Unix(), e.Unix())}, nil
+
+	case recurring && query == "" && !oneOff:
+		s, err := parseTimeOfDay(startTime)
+		if err != nil {
+			return Exclusion{}, fmt.Errorf("could not parse %q start time: %w", startTime, err)
+		}
+		e, err := parseTimeOfDay(endTime)
+		if err != nil {
+			return Exclusion{}, fmt.Errorf("could not parse %q end time: %w", endTime, err)
+		}
+		if s == e {
+			return Exclusion{}, fmt.Errorf("start and end times can't be the same")
+		}
+
+		const timeOfDay = "vector(time()) % 86400"
+		q := fmt.Sprintf("%s >= %d < %d", timeOfDay, s, e)
+		if e < s {
+			q = fmt.Sprintf("(%s >= %d) or (%s < %d)", timeOfDay, s, timeOfDay, e)
+		}
+
+		if len(days) > 0 {
+			dayQueries := make([]string, 0, len(days))
+			for _, d := range days {
+				wd, ok := weekDays[strings.ToLower(d)]
+				if !ok {
+					return Exclusion{}, fmt.Errorf("unknown %q week day", d)
+				}
+				dayQueries = append(dayQueries, fmt.Sprintf("day_of_week() == %d", wd))
+			}
+			q = fmt.Sprintf("(%s) and on() (%s)", q, strings.Join(dayQueries, " or "))
+		}
+
+		return Exclusion{Query: q}, nil
+	}
+
+	return Exclusion{}, fmt.Errorf("exclusion requires only one of query, start and end or start and end times")
+}
+
+var weekDays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// parseTimeOfDay parses a `HH:MM` time of day and returns the seconds since midnight.
+func parseTimeOfDay(t string) (int, error) {
+	tt, err := time.Parse("15:04", t)
+	if err != nil {
+		return 0, err
+	}
+
+	return tt.Hour()*3600 + tt.Minute()*60, nil
+}
+
+// NewMinTraffic returns the min traffic of the SLO alerts, if the query is missing
+// the events SLI total query will be used.
+func NewMinTraffic(sli SLI, query string, rate float64) (*MinTraffic, error) {
+	if query == "" {
+		if sli.Events == nil {
+			return nil, fmt.Errorf("query is required on non events SLIs")
+		}
+		query = sli.Events.TotalQuery
+	}
+
+	return &MinTraffic{Query: query, Rate: rate}, nil
+}
+
+// ParseAlertFor parses the `for` of an alert in Prometheus duration format, an
+// empty value is returned as zero so the alert fires immediately.
+func ParseAlertFor(f string) (time.Duration, error) {
+	if f == "" {
+		return 0, nil
+	}
+
+	d, err := prommodel.ParseDuration(f)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse %q for: %w", f, err)
+	}
+
+	return time.Duration(d), nil
+}
+
+// ParseAlertWindows parses the short and long windows of an alert in Prometheus duration
+// format, the empty windows are returned as zero so the default windows are used.
+func ParseAlertWindows(short, long string) (shortWindow, longWindow time.Duration, err error) {
+	shortWindow, err = ParseAlertWindow(short)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid short window: %w", err)
+	}
+
+	longWindow, err = ParseAlertWindow(long)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid long window: %w", err)
+	}
+
+	if shortWindow != 0 && longWindow != 0 && shortWindow >= longWindow {
+		return 0, 0, fmt.Errorf("short window should be less than the long window")
+	}
+
+	return shortWindow, longWindow, nil
+}
+
+// ForecastAlertName returns the name of the forecast alert, by default based on the SLO alerts name.
+func ForecastAlertName(alertingName, name string) string {
+	if name != "" || alertingName == "" {
+		return name
+	}
+
+	return alertingName + "ErrorBudgetExhaustion"
+}
+
+var rawSLIQueryNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ExpandRawSLIQueries composes a raw SLI error ratio query using the named sub-queries, the
+// error ratio query references them as template variables (e.g `{{.errors}} / {{.total}}`)
+// and these are replaced by the sub-queries wrapped in parentheses. The `{{.window}}` template
+// variable is kept so it can be rendered later for each SLI window.
+func ExpandRawSLIQueries(errorRatioQuery string, queries map[string]string) (string, error) {
+	if len(queries) == 0 {
+		return errorRatioQuery, nil
+	}
+
+	data := map[string]string{tplKeyWindow: "{{." + tplKeyWindow + "}}"}
+	for name, query := range queries {
+		if name == tplKeyWindow || !rawSLIQueryNameRegexp.MatchString(name) {
+			return "", fmt.Errorf("invalid %q query name", name)
+		}
+		data[name] = fmt.Sprintf("(%s)", query)
+	}
+
+	tpl, err := template.New("errorRatioQuery").Option("missingkey=error").Parse(errorRatioQuery)
+	if err != nil {
+		return "", fmt.Errorf("could not parse error ratio query template: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, data)
+	if err != nil {
+		return "", fmt.Errorf("could not render error ratio query sub-queries: %w", err)
+	}
+
+	return b.String(), nil
+}
@@ -2,12 +2,18 @@ package prometheus_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
+	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/prometheus"
+	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
+	prometheusv2 "github.com/slok/sloth/pkg/prometheus/api/v2"
 )
 
 func TestYAMLoadSpec(t *testing.T) {
@@ -52,6 +58,103 @@ slos: []
 `,
 			expErr: true,
 		},
+		"Spec with invalid SLO time window should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo1"
+    objective: 99.9
+    time_window: 1month
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with SLO time windows should return the models with the time windows.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo1"
+    objective: 99.9
+    time_window: 7d
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "slo2"
+    objective: 99.9
+    time_window: quarterly
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "slo3"
+    objective: 99.9
+    time_window: calendar-month
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:               "test-svc-slo1",
+					Name:             "slo1",
+					Service:          "test-svc",
+					TimeWindow:       7 * 24 * time.Hour,
+					SLI:              prometheus.SLI{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+					Objective:        99.9,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+				{
+					ID:               "test-svc-slo2",
+					Name:             "slo2",
+					Service:          "test-svc",
+					TimeWindow:       90 * 24 * time.Hour,
+					SLI:              prometheus.SLI{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+					Objective:        99.9,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+				{
+					ID:               "test-svc-slo3",
+					Name:             "slo3",
+					Service:          "test-svc",
+					TimeWindow:       30 * 24 * time.Hour,
+					Calendar:         prometheus.CalendarMonthPeriod,
+					SLI:              prometheus.SLI{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+					Objective:        99.9,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
 		"Correct spec should return the models correctly.": {
 			specYaml: `
 version: "prometheus/v1"
@@ -165,13 +268,1700 @@ slos:
 			},
 			},
 		},
-	}
 
-	for name, test := range tests {
-		t.Run(name, func(t *testing.T) {
-			assert := assert.New(t)
+		"Spec with raw SLI queries referencing missing sub-queries should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: "{{.errors}} / {{.total}}"
+        queries:
+          errors: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
 
-			gotModel, err := prometheus.YAMLSpecLoader.LoadSpec(context.TODO(), []byte(test.specYaml))
+		"Spec with raw SLI invalid sub-query names should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: "{{.window}}"
+        queries:
+          window: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with raw SLI sub-queries should compose the error ratio query.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: "({{.errors}} + {{.throttled}}) / {{.total}}"
+        queries:
+          errors: sum(rate(test_errors[{{.window}}]))
+          throttled: sum(rate(test_throttled[{{.window}}]))
+          total: sum(rate(test_total[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "((sum(rate(test_errors[{{.window}}]))) + (sum(rate(test_throttled[{{.window}}])))) / (sum(rate(test_total[{{.window}}])))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with single window alert settings without the single window strategy should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      page_alert:
+        window: 2h
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with single window alert settings should return the alert overrides.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      strategy: single-window
+      page_alert:
+        window: 2h
+        burn_rate: 10
+      ticket_alert:
+        burn_rate: 2
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:     99,
+					Labels:        map[string]string{},
+					AlertStrategy: prometheus.SingleWindowAlertStrategy,
+					AlertOverrides: alert.MWMBAlertGroupOverride{
+						PageQuick:   alert.MWMBAlertOverride{LongWindow: 2 * time.Hour, BurnRateFactor: 10},
+						TicketQuick: alert.MWMBAlertOverride{BurnRateFactor: 2},
+					},
+					PageAlertMeta: prometheus.AlertMeta{
+						Name:        "testAlert",
+						Labels:      map[string]string{},
+						Annotations: map[string]string{},
+					},
+					WarningAlertMeta: prometheus.AlertMeta{
+						Name:        "testAlert",
+						Labels:      map[string]string{},
+						Annotations: map[string]string{},
+					},
+				},
+			}},
+		},
+
+		"Spec with burn rates on a non multiwindow-multiburn strategy should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      strategy: single-window
+      burn_rates:
+        page_quick: 20
+`,
+			expErr: true,
+		},
+
+		"Spec with burn rates should return the alert burn rate overrides.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      burn_rates:
+        page_quick: 20
+        page_slow: 8
+        ticket_slow: 1.5
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective: 99,
+					Labels:    map[string]string{},
+					AlertOverrides: alert.MWMBAlertGroupOverride{
+						PageQuick:  alert.MWMBAlertOverride{BurnRateFactor: 20},
+						PageSlow:   alert.MWMBAlertOverride{BurnRateFactor: 8},
+						TicketSlow: alert.MWMBAlertOverride{BurnRateFactor: 1.5},
+					},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with alert windows with a short window greater than the long window should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      windows:
+        page_quick:
+          short: 2h
+          long: 1h
+`,
+			expErr: true,
+		},
+
+		"Spec with alert windows should return the alert window overrides.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      burn_rates:
+        page_quick: 20
+      windows:
+        page_quick:
+          short: 2m
+          long: 30m
+        ticket_slow:
+          long: 4d
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective: 99,
+					Labels:    map[string]string{},
+					AlertOverrides: alert.MWMBAlertGroupOverride{
+						PageQuick:  alert.MWMBAlertOverride{ShortWindow: 2 * time.Minute, LongWindow: 30 * time.Minute, BurnRateFactor: 20},
+						TicketSlow: alert.MWMBAlertOverride{LongWindow: 4 * 24 * time.Hour},
+					},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with page and ticket alerts for should return the alerts with the for.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      page_alert:
+        for: 5m
+      ticket_alert:
+        for: 1h
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective: 99,
+					Labels:    map[string]string{},
+					PageAlertMeta: prometheus.AlertMeta{
+						Name:        "testAlert",
+						Labels:      map[string]string{},
+						Annotations: map[string]string{},
+						For:         5 * time.Minute,
+					},
+					WarningAlertMeta: prometheus.AlertMeta{
+						Name:        "testAlert",
+						Labels:      map[string]string{},
+						Annotations: map[string]string{},
+						For:         1 * time.Hour,
+					},
+				},
+			}},
+		},
+
+		"Spec with unknown alert severities should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      severities: critical
+`,
+			expErr: true,
+		},
+
+		"Spec with page only alert severities should disable the ticket alerts.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      severities: page-only
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective: 99,
+					Labels:    map[string]string{},
+					PageAlertMeta: prometheus.AlertMeta{
+						Name:        "testAlert",
+						Labels:      map[string]string{},
+						Annotations: map[string]string{},
+					},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with min traffic without query on a non events SLI should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      min_traffic:
+        rate: 1
+`,
+			expErr: true,
+		},
+
+		"Spec with min traffic on an events SLI should use the total query by default.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      events:
+        error_query: sum(rate(test_errors[{{.window}}]))
+        total_query: sum(rate(test_total[{{.window}}]))
+    alerting:
+      name: testAlert
+      min_traffic:
+        rate: 0.5
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Events: &prometheus.SLIEvents{
+						ErrorQuery: "sum(rate(test_errors[{{.window}}]))",
+						TotalQuery: "sum(rate(test_total[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+					MinTraffic: &prometheus.MinTraffic{
+						Query: "sum(rate(test_total[{{.window}}]))",
+						Rate:  0.5,
+					},
+				},
+			}},
+		},
+
+		"Spec with metrics prefix should set the metrics prefix on the SLOs.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+metrics_prefix: myorg
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:            "test-svc-slo1",
+					Name:          "slo1",
+					Service:       "test-svc",
+					MetricsPrefix: "myorg",
+					TimeWindow:    30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with invalid rule group intervals should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+rule_group_intervals:
+  sli: 30x
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with ruler options should set the ruler options on the SLOs.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+ruler:
+  dedup_labels: [replica]
+  partial_response_strategy: warn
+  source_tenants: [tenant-a, tenant-b]
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective: 99,
+					Labels:    map[string]string{},
+					RulerOptions: prometheus.RulerOptions{
+						DedupLabels:             []string{"replica"},
+						PartialResponseStrategy: "warn",
+						SourceTenants:           []string{"tenant-a", "tenant-b"},
+					},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with rule group intervals should set the rule group intervals on the SLOs.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+rule_group_intervals:
+  sli: 30s
+  metadata: 5m
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective: 99,
+					Labels:    map[string]string{},
+					RuleGroupIntervals: prometheus.RuleGroupIntervals{
+						SLIErrorRecRules: 30 * time.Second,
+						MetadataRecRules: 5 * time.Minute,
+					},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with rule group name should set the rule group name template on the SLOs.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+rule_group_name: "{{ .service }}-{{ .category }}-{{ .slo }}"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:            "test-svc-slo1",
+					Name:          "slo1",
+					Service:       "test-svc",
+					RuleGroupName: "{{ .service }}-{{ .category }}-{{ .slo }}",
+					TimeWindow:    30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with runbook URLs should set the runbook URL templates on the SLOs.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+runbook_urls:
+  page: "https://runbooks.test/[[ .service ]]/[[ .slo ]]"
+  ticket: "https://runbooks.test/[[ .service ]]/[[ .slo ]]?severity=[[ .severity ]]"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:      "test-svc-slo1",
+					Name:    "slo1",
+					Service: "test-svc",
+					RunbookURLs: prometheus.RunbookURLs{
+						Page:   "https://runbooks.test/[[ .service ]]/[[ .slo ]]",
+						Ticket: "https://runbooks.test/[[ .service ]]/[[ .slo ]]?severity=[[ .severity ]]",
+					},
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with Sloth labels should set the Sloth labels customization on the SLOs.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+sloth_labels:
+  prefix: myorg
+  rename:
+    sloth_service: service
+  drop: ["sloth_slo"]
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:      "test-svc-slo1",
+					Name:    "slo1",
+					Service: "test-svc",
+					SlothLabels: prometheus.SlothLabels{
+						Prefix: "myorg",
+						Rename: map[string]string{"sloth_service": "service"},
+						Drop:   []string{"sloth_slo"},
+					},
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with external labels should set the external labels on the SLOs.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+external_labels:
+  cluster: eu-1
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:             "test-svc-slo1",
+					Name:           "slo1",
+					Service:        "test-svc",
+					ExternalLabels: map[string]string{"cluster": "eu-1"},
+					TimeWindow:     30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with disabled rules should set the disabled rule categories on the SLO.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    disabled_rules:
+      sli_recordings: true
+      alerts: true
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					DisabledRules:    prometheus.DisabledRules{SLIErrorRecRules: true, AlertRules: true},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with SLO dependencies should set the dependencies on the SLO.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    depends_on: ["payments-requests", "db-queries"]
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					DependsOn:  []string{"payments-requests", "db-queries"},
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with a 100% objective should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 100
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with a 0% objective should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 0
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with time slice should return the time slice SLO.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    time_slice:
+      duration: 1m
+      threshold: 0.05
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					TimeSlice:        &prometheus.TimeSlice{Duration: 1 * time.Minute, Threshold: 0.05},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with an exclusion with query and time range should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    exclusions:
+      - query: maintenance_mode == 1
+        start: 2021-06-01T10:00:00Z
+        end: 2021-06-01T12:00:00Z
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with an exclusion with an unknown week day should fail.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    exclusions:
+      - start_time: "02:00"
+        end_time: "04:00"
+        days: [funday]
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with exclusions should return the exclusion queries.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    exclusions:
+      - query: maintenance_mode{service="test-svc"} == 1
+      - start: 2021-06-01T10:00:00Z
+        end: 2021-06-01T12:00:00Z
+      - start_time: "02:00"
+        end_time: "04:00"
+        days: [saturday, Sunday]
+      - start_time: "23:00"
+        end_time: "01:30"
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective: 99,
+					Labels:    map[string]string{},
+					Exclusions: []prometheus.Exclusion{
+						{Query: `maintenance_mode{service="test-svc"} == 1`},
+						{Query: `vector(time()) >= 1622541600 < 1622548800`},
+						{Query: `(vector(time()) % 86400 >= 7200 < 14400) and on() (day_of_week() == 6 or day_of_week() == 0)`},
+						{Query: `(vector(time()) % 86400 >= 82800) or (vector(time()) % 86400 < 5400)`},
+					},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with forecast alert should return the forecast alert with the default name.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: sum(rate(test_errors[{{.window}}]))
+    alerting:
+      name: testAlert
+      labels:
+        team: a
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+      forecast_alert:
+        window: 6h
+        labels:
+          severity: info
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{Raw: &prometheus.SLIRaw{
+						ErrorRatioQuery: "sum(rate(test_errors[{{.window}}]))",
+					}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+					ForecastAlertMeta: &prometheus.ForecastAlertMeta{
+						Name:        "testAlertErrorBudgetExhaustion",
+						Window:      6 * time.Hour,
+						Labels:      map[string]string{"team": "a", "severity": "info"},
+						Annotations: map[string]string{},
+					},
+				},
+			}},
+		},
+
+		"Spec with additional objectives should return an SLO per objective.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio_1
+    alerting:
+      name: testAlert
+      page_alert:
+        labels:
+          severity: critical
+      ticket_alert:
+        disable: true
+    additional_objectives:
+      - name: tracked
+        objective: 99.9
+      - name: internal
+        objective: 99.5
+        alerting:
+          name: testInternalAlert
+          page_alert:
+            disable: true
+          ticket_alert:
+            labels:
+              severity: slack
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI:        prometheus.SLI{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+					Objective:  99,
+					Labels:     map[string]string{},
+					PageAlertMeta: prometheus.AlertMeta{
+						Name:        "testAlert",
+						Labels:      map[string]string{"severity": "critical"},
+						Annotations: map[string]string{},
+					},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+				{
+					ID:               "test-svc-slo1-tracked",
+					Name:             "slo1-tracked",
+					Service:          "test-svc",
+					TimeWindow:       30 * 24 * time.Hour,
+					SLI:              prometheus.SLI{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+					Objective:        99.9,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+				{
+					ID:            "test-svc-slo1-internal",
+					Name:          "slo1-internal",
+					Service:       "test-svc",
+					TimeWindow:    30 * 24 * time.Hour,
+					SLI:           prometheus.SLI{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+					Objective:     99.5,
+					Labels:        map[string]string{},
+					PageAlertMeta: prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{
+						Name:        "testInternalAlert",
+						Labels:      map[string]string{"severity": "slack"},
+						Annotations: map[string]string{},
+					},
+				},
+			}},
+		},
+
+		"Spec alert tiers should inherit the common alerting labels and annotations, override them and unset them.": {
+			specYaml: `
+version: "prometheus/v1"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99.9
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio_1
+    alerting:
+      name: testAlert
+      labels:
+        team: "myteam"
+        tier: "1"
+      annotations:
+        runbook: http://whatever.com
+        dashboard: http://dashboard.com
+      page_alert:
+        labels:
+          tier: "0"
+        annotations:
+          dashboard: ""
+      ticket_alert:
+        labels:
+          team: ""
+          severity: slack
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Raw: &prometheus.SLIRaw{
+							ErrorRatioQuery: "test_expr_ratio_1",
+						},
+					},
+					Objective: 99.9,
+					Labels:    map[string]string{},
+					PageAlertMeta: prometheus.AlertMeta{
+						Name: "testAlert",
+						Labels: map[string]string{
+							"team": "myteam",
+							"tier": "0",
+						},
+						Annotations: map[string]string{
+							"runbook": "http://whatever.com",
+						},
+					},
+					WarningAlertMeta: prometheus.AlertMeta{
+						Name: "testAlert",
+						Labels: map[string]string{
+							"tier":     "1",
+							"severity": "slack",
+						},
+						Annotations: map[string]string{
+							"runbook":   "http://whatever.com",
+							"dashboard": "http://dashboard.com",
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotModel, err := prometheus.YAMLSpecLoader.LoadSpec(context.TODO(), []byte(test.specYaml))
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expModel, gotModel)
+			}
+		})
+	}
+}
+
+// fillFields sets a non zero value on all the fields, so the specs with all the fields set can be
+// checked without listing them.
+func fillFields(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fillFields(v.Field(i))
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillFields(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillFields(v.Index(0))
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fillFields(key)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillFields(value)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString("test")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	}
+}
+
+func TestYAMLSpecV1FieldsOnV2(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// The v1 specs are loaded converting them to v2 specs, so all the v1 fields should survive
+	// the conversion to v2 and back to v1.
+	v1Spec := prometheusv1.Spec{}
+	fillFields(reflect.ValueOf(&v1Spec).Elem())
+
+	data, err := yaml.Marshal(v1Spec)
+	require.NoError(err)
+	v2Spec := prometheusv2.Spec{}
+	require.NoError(yaml.UnmarshalStrict(data, &v2Spec))
+
+	data, err = yaml.Marshal(v2Spec)
+	require.NoError(err)
+	gotV1Spec := prometheusv1.Spec{}
+	require.NoError(yaml.UnmarshalStrict(data, &gotV1Spec))
+
+	assert.Equal(v1Spec, gotV1Spec)
+}
+
+func TestYAMLoadSpecV2(t *testing.T) {
+	tests := map[string]struct {
+		specYaml string
+		expModel *prometheus.SLOGroup
+		expErr   bool
+	}{
+		"Empty spec should fail.": {
+			specYaml: ``,
+			expErr:   true,
+		},
+
+		"Spec with invalid version should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+- name: something
+`,
+			expErr: true,
+		},
+
+		"Spec without SLOs should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v2"
+slos: []
+`,
+			expErr: true,
+		},
+
+		"Correct spec with composite SLIs should return the models correctly.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "test-svc"
+labels:
+  owner: "myteam"
+slos:
+  - name: "slo1"
+    objective: 99.9
+    sli:
+      composite:
+        all:
+          - events:
+              error_query: test_expr_error_1
+              total_query: test_expr_total_1
+          - raw:
+              error_ratio_query: test_expr_ratio_1
+          - composite:
+              all:
+                - raw:
+                    error_ratio_query: test_expr_ratio_2
+                - raw:
+                    error_ratio_query: test_expr_ratio_3
+    alerting:
+      name: testAlert
+      page_alert:
+        labels:
+          severity: critical
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+							{Events: &prometheus.SLIEvents{ErrorQuery: "test_expr_error_1", TotalQuery: "test_expr_total_1"}},
+							{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+							{Composite: &prometheus.SLIComposite{SLIs: []prometheus.SLI{
+								{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_2"}},
+								{Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio_3"}},
+							}}},
+						}},
+					},
+					Objective: 99.9,
+					Labels: map[string]string{
+						"owner": "myteam",
+					},
+					PageAlertMeta: prometheus.AlertMeta{
+						Name:        "testAlert",
+						Labels:      map[string]string{"severity": "critical"},
+						Annotations: map[string]string{},
+					},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Correct spec with native histogram SLIs should return the models correctly.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "test-svc"
+slos:
+  - name: "slo1"
+    objective: 99
+    sli:
+      native_histogram:
+        selector: http_request_duration_seconds{job="test"}
+        threshold: 0.25
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo1",
+					Name:       "slo1",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						NativeHistogram: &prometheus.SLINativeHistogram{
+							Selector:  `http_request_duration_seconds{job="test"}`,
+							Threshold: 0.25,
+						},
+					},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Correct spec with weighted SLIs should return the models correctly.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "checkout"
+slos:
+  - name: "journey"
+    objective: 99
+    sli:
+      weighted:
+        slos:
+          - id: checkout-api
+            weight: 0.6
+          - id: checkout-payments
+            weight: 0.4
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "api"
+    objective: 99.9
+    sli:
+      events:
+        error_query: test_expr_error_1
+        total_query: test_expr_total_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "payments"
+    objective: 99.9
+    sli:
+      events:
+        error_query: test_expr_error_2
+        total_query: test_expr_total_2
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "checkout-journey",
+					Name:       "journey",
+					Service:    "checkout",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Weighted: &prometheus.SLIWeighted{SLOs: []prometheus.WeightedSLO{
+							{ID: "checkout-api", Weight: 0.6},
+							{ID: "checkout-payments", Weight: 0.4},
+						}},
+					},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+				{
+					ID:               "checkout-api",
+					Name:             "api",
+					Service:          "checkout",
+					TimeWindow:       30 * 24 * time.Hour,
+					SLI:              prometheus.SLI{Events: &prometheus.SLIEvents{ErrorQuery: "test_expr_error_1", TotalQuery: "test_expr_total_1"}},
+					Objective:        99.9,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+				{
+					ID:               "checkout-payments",
+					Name:             "payments",
+					Service:          "checkout",
+					TimeWindow:       30 * 24 * time.Hour,
+					SLI:              prometheus.SLI{Events: &prometheus.SLIEvents{ErrorQuery: "test_expr_error_2", TotalQuery: "test_expr_total_2"}},
+					Objective:        99.9,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with weighted SLIs referencing SLOs missing on the spec should fail.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "checkout"
+slos:
+  - name: "journey"
+    objective: 99
+    sli:
+      weighted:
+        slos:
+          - id: checkout-api
+            weight: 0.6
+          - id: payments-requests
+            weight: 0.4
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "api"
+    objective: 99.9
+    sli:
+      events:
+        error_query: test_expr_error_1
+        total_query: test_expr_total_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with weighted SLIs referencing SLOs with a different time window should fail.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "checkout"
+slos:
+  - name: "journey"
+    objective: 99
+    sli:
+      weighted:
+        slos:
+          - id: checkout-api
+            weight: 0.6
+          - id: checkout-payments
+            weight: 0.4
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "api"
+    objective: 99.9
+    sli:
+      events:
+        error_query: test_expr_error_1
+        total_query: test_expr_total_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "payments"
+    objective: 99.9
+    time_window: 7d
+    sli:
+      events:
+        error_query: test_expr_error_2
+        total_query: test_expr_total_2
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Correct spec with aggregated SLIs should return the models correctly.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "myservice"
+slos:
+  - name: "global-requests"
+    objective: 99
+    sli:
+      aggregated:
+        id: myservice-requests
+        labels: [cluster]
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "myservice-global-requests",
+					Name:       "global-requests",
+					Service:    "myservice",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Aggregated: &prometheus.SLIAggregated{ID: "myservice-requests", Labels: []string{"cluster"}},
+					},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+		"Correct spec with aggregated SLIs of an events SLO on the spec should return the models correctly.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "myservice"
+slos:
+  - name: "global-requests"
+    objective: 99
+    sli:
+      aggregated:
+        id: myservice-requests
+        labels: [cluster]
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "requests"
+    objective: 99
+    sli:
+      events:
+        error_query: test_expr_error_1
+        total_query: test_expr_total_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "myservice-global-requests",
+					Name:       "global-requests",
+					Service:    "myservice",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Aggregated: &prometheus.SLIAggregated{ID: "myservice-requests", Labels: []string{"cluster"}},
+					},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+				{
+					ID:               "myservice-requests",
+					Name:             "requests",
+					Service:          "myservice",
+					TimeWindow:       30 * 24 * time.Hour,
+					SLI:              prometheus.SLI{Events: &prometheus.SLIEvents{ErrorQuery: "test_expr_error_1", TotalQuery: "test_expr_total_1"}},
+					Objective:        99,
+					Labels:           map[string]string{},
+					PageAlertMeta:    prometheus.AlertMeta{Disable: true},
+					WarningAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
+		"Spec with aggregated SLIs of an SLO on the spec that doesn't record the total events should fail.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "myservice"
+slos:
+  - name: "global-requests"
+    objective: 99
+    sli:
+      aggregated:
+        id: myservice-requests
+        labels: [cluster]
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "requests"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with aggregated SLIs of a time slice SLO on the spec should fail.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "myservice"
+slos:
+  - name: "global-requests"
+    objective: 99
+    sli:
+      aggregated:
+        id: myservice-requests
+        labels: [cluster]
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+  - name: "requests"
+    objective: 99
+    time_slice:
+      duration: 1m
+      threshold: 0.05
+    sli:
+      events:
+        error_query: test_expr_error_1
+        total_query: test_expr_total_1
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with aggregated SLIs of the same SLO should fail.": {
+			specYaml: `
+version: "prometheus/v2"
+service: "myservice"
+slos:
+  - name: "requests"
+    objective: 99
+    sli:
+      aggregated:
+        id: myservice-requests
+        labels: [cluster]
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotModel, err := prometheus.YAMLSpecV2Loader.LoadSpec(context.TODO(), []byte(test.specYaml))
 
 			if test.expErr {
 				assert.Error(err)
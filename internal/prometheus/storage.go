@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	prommodel "github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/rulefmt"
@@ -48,24 +49,32 @@ func (i IOWriterGroupedRulesYAMLRepo) StoreSLOs(ctx context.Context, slos []Stor
 
 	ruleGroups := ruleGroupsYAMLv2{}
 	for _, slo := range slos {
-		if len(slo.Rules.SLIErrorRecRules) > 0 {
-			ruleGroups.Groups = append(ruleGroups.Groups, ruleGroupYAMLv2{
-				Name:  fmt.Sprintf("sloth-slo-sli-recordings-%s", slo.SLO.ID),
-				Rules: slo.Rules.SLIErrorRecRules,
-			})
+		groups := []struct {
+			category RuleGroupCategory
+			interval time.Duration
+			rules    []rulefmt.Rule
+		}{
+			{category: SLIRecordingsRuleGroupCategory, interval: slo.SLO.RuleGroupIntervals.SLIErrorRecRules, rules: slo.Rules.SLIErrorRecRules},
+			{category: MetadataRecordingsRuleGroupCategory, interval: slo.SLO.RuleGroupIntervals.MetadataRecRules, rules: slo.Rules.MetadataRecRules},
+			{category: AlertsRuleGroupCategory, interval: slo.SLO.RuleGroupIntervals.AlertRules, rules: slo.Rules.AlertRules},
 		}
 
-		if len(slo.Rules.MetadataRecRules) > 0 {
-			ruleGroups.Groups = append(ruleGroups.Groups, ruleGroupYAMLv2{
-				Name:  fmt.Sprintf("sloth-slo-meta-recordings-%s", slo.SLO.ID),
-				Rules: slo.Rules.MetadataRecRules,
-			})
-		}
+		for _, group := range groups {
+			if len(group.rules) == 0 {
+				continue
+			}
+
+			name, err := slo.SLO.GetRuleGroupName(group.category)
+			if err != nil {
+				return fmt.Errorf("could not get %q SLO rule group name: %w", slo.SLO.ID, err)
+			}
 
-		if len(slo.Rules.AlertRules) > 0 {
 			ruleGroups.Groups = append(ruleGroups.Groups, ruleGroupYAMLv2{
-				Name:  fmt.Sprintf("sloth-slo-alerts-%s", slo.SLO.ID),
-				Rules: slo.Rules.AlertRules,
+				Name:                    name,
+				Interval:                prommodel.Duration(group.interval),
+				PartialResponseStrategy: slo.SLO.RulerOptions.PartialResponseStrategy,
+				SourceTenants:           slo.SLO.RulerOptions.SourceTenants,
+				Rules:                   mapRulesToYAMLv2(slo.SLO, group.rules),
 			})
 		}
 	}
@@ -114,5 +123,44 @@ type ruleGroupsYAMLv2 struct {
 type ruleGroupYAMLv2 struct {
 	Name     string             `yaml:"name"`
 	Interval prommodel.Duration `yaml:"interval,omitempty"`
-	Rules    []rulefmt.Rule     `yaml:"rules"`
+	// PartialResponseStrategy is the Thanos ruler rule group partial response strategy.
+	PartialResponseStrategy string `yaml:"partial_response_strategy,omitempty"`
+	// SourceTenants are the Mimir ruler rule group tenants (federated rule groups).
+	SourceTenants []string     `yaml:"source_tenants,omitempty"`
+	Rules         []ruleYAMLv2 `yaml:"rules"`
+}
+
+// ruleYAMLv2 is the Prometheus rule with the fields missing on the Prometheus
+// rulefmt version we depend on.
+type ruleYAMLv2 struct {
+	Record        string             `yaml:"record,omitempty"`
+	Alert         string             `yaml:"alert,omitempty"`
+	Expr          string             `yaml:"expr"`
+	For           prommodel.Duration `yaml:"for,omitempty"`
+	KeepFiringFor prommodel.Duration `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string  `yaml:"labels,omitempty"`
+	Annotations   map[string]string  `yaml:"annotations,omitempty"`
+}
+
+func mapRulesToYAMLv2(slo SLO, rules []rulefmt.Rule) []ruleYAMLv2 {
+	res := make([]ruleYAMLv2, 0, len(rules))
+	for _, r := range rules {
+		rule := ruleYAMLv2{
+			Record:      r.Record,
+			Alert:       r.Alert,
+			Expr:        r.Expr,
+			For:         r.For,
+			Labels:      r.Labels,
+			Annotations: r.Annotations,
+		}
+
+		// Keep firing for only applies to alerts.
+		if r.Alert != "" {
+			rule.KeepFiringFor = prommodel.Duration(slo.KeepFiringFor)
+		}
+
+		res = append(res, rule)
+	}
+
+	return res
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"testing"
+	"time"
 
 	"github.com/prometheus/prometheus/pkg/rulefmt"
 	"github.com/stretchr/testify/assert"
@@ -121,6 +122,167 @@ groups:
 `,
 		},
 
+		"Having an SLO with keep firing for should render the alert rules with keep firing for.": {
+			slos: []prometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1", KeepFiringFor: 10 * time.Minute},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{
+							{
+								Record: "test:record",
+								Expr:   "test-expr",
+							},
+						},
+						AlertRules: []rulefmt.Rule{
+							{
+								Alert: "testAlert",
+								Expr:  "test-expr",
+							},
+						},
+					},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-sli-recordings-test1
+  rules:
+  - record: test:record
+    expr: test-expr
+- name: sloth-slo-alerts-test1
+  rules:
+  - alert: testAlert
+    expr: test-expr
+    keep_firing_for: 10m
+`,
+		},
+
+		"Having an SLO with rule group intervals should render the groups with the intervals.": {
+			slos: []prometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1", RuleGroupIntervals: prometheus.RuleGroupIntervals{
+						SLIErrorRecRules: 30 * time.Second,
+						AlertRules:       time.Minute,
+					}},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{
+							{
+								Record: "test:record",
+								Expr:   "test-expr",
+							},
+						},
+						MetadataRecRules: []rulefmt.Rule{
+							{
+								Record: "test:record2",
+								Expr:   "test-expr2",
+							},
+						},
+						AlertRules: []rulefmt.Rule{
+							{
+								Alert: "testAlert",
+								Expr:  "test-expr",
+							},
+						},
+					},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-sli-recordings-test1
+  interval: 30s
+  rules:
+  - record: test:record
+    expr: test-expr
+- name: sloth-slo-meta-recordings-test1
+  rules:
+  - record: test:record2
+    expr: test-expr2
+- name: sloth-slo-alerts-test1
+  interval: 1m
+  rules:
+  - alert: testAlert
+    expr: test-expr
+`,
+		},
+
+		"Having an SLO with ruler options should render the groups with the ruler fields.": {
+			slos: []prometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "test1", RulerOptions: prometheus.RulerOptions{
+						PartialResponseStrategy: "warn",
+						SourceTenants:           []string{"tenant-a", "tenant-b"},
+					}},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{
+							{
+								Record: "test:record",
+								Expr:   "test-expr",
+							},
+						},
+					},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-sli-recordings-test1
+  partial_response_strategy: warn
+  source_tenants:
+  - tenant-a
+  - tenant-b
+  rules:
+  - record: test:record
+    expr: test-expr
+`,
+		},
+
+		"Having an SLO with a rule group name template should render the groups with the templated names.": {
+			slos: []prometheus.StorageSLO{
+				{
+					SLO: prometheus.SLO{ID: "svc-slo1", Service: "svc", Name: "slo1", RuleGroupName: "{{ .service }}-{{ .category }}-{{ .slo }}"},
+					Rules: prometheus.SLORules{
+						SLIErrorRecRules: []rulefmt.Rule{
+							{
+								Record: "test:record",
+								Expr:   "test-expr",
+							},
+						},
+						AlertRules: []rulefmt.Rule{
+							{
+								Alert: "testAlert",
+								Expr:  "test-expr",
+							},
+						},
+					},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: svc-sli-recordings-slo1
+  rules:
+  - record: test:record
+    expr: test-expr
+- name: svc-alerts-slo1
+  rules:
+  - alert: testAlert
+    expr: test-expr
+`,
+		},
+
 		"Having a multiple SLO alert and recording rules should render correctly.": {
 			slos: []prometheus.StorageSLO{
 				{
@@ -0,0 +1,186 @@
+package ruler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/slok/sloth/internal/log"
+)
+
+const (
+	// DefaultAPIPrefix is the ruler configuration API path prefix shared by Mimir and Cortex.
+	DefaultAPIPrefix = "/prometheus/config/v1/rules"
+
+	tenantHeader = "X-Scope-OrgID"
+)
+
+// ClientConfig is the ruler HTTP API client configuration.
+type ClientConfig struct {
+	// URL is the ruler (or the Mimir/Cortex gateway) base URL, basic auth credentials can be
+	// set on the URL user info.
+	URL string
+	// APIPrefix is the path prefix of the ruler configuration API.
+	APIPrefix string
+	// Tenant is the tenant the rule groups are stored on, if empty the tenant header is not sent.
+	Tenant     string
+	HTTPClient *http.Client
+	Logger     log.Logger
+}
+
+func (c *ClientConfig) defaults() error {
+	if c.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	_, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	c.URL = strings.TrimSuffix(c.URL, "/")
+
+	if c.APIPrefix == "" {
+		c.APIPrefix = DefaultAPIPrefix
+	}
+	c.APIPrefix = "/" + strings.Trim(c.APIPrefix, "/")
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "ruler.Client"})
+
+	return nil
+}
+
+// Client knows how to manage the rule groups of a Mimir/Cortex ruler using its configuration HTTP API.
+type Client struct {
+	url        string
+	apiPrefix  string
+	tenant     string
+	httpClient *http.Client
+	logger     log.Logger
+}
+
+// NewClient returns a new ruler HTTP API client.
+func NewClient(config ClientConfig) (*Client, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &Client{
+		url:        config.URL,
+		apiPrefix:  config.APIPrefix,
+		tenant:     config.Tenant,
+		httpClient: config.HTTPClient,
+		logger:     config.Logger,
+	}, nil
+}
+
+// ListRuleGroups returns the names of the rule groups stored on a ruler namespace.
+func (c *Client) ListRuleGroups(ctx context.Context, namespace string) ([]string, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.path(namespace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Missing namespaces don't have rule groups.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	err = checkResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	nsGroups := map[string][]struct {
+		Name string `yaml:"name"`
+	}{}
+	err = yaml.NewDecoder(resp.Body).Decode(&nsGroups)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("could not decode rule groups: %w", err)
+	}
+
+	groups := []string{}
+	for _, g := range nsGroups[namespace] {
+		groups = append(groups, g.Name)
+	}
+
+	return groups, nil
+}
+
+// SetRuleGroup creates or replaces a rule group (in Prometheus rule group YAML format) on a ruler namespace.
+func (c *Client) SetRuleGroup(ctx context.Context, namespace string, group []byte) error {
+	resp, err := c.do(ctx, http.MethodPost, c.path(namespace), group)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkResponse(resp)
+}
+
+// DeleteRuleGroup deletes a rule group from a ruler namespace.
+func (c *Client) DeleteRuleGroup(ctx context.Context, namespace, group string) error {
+	resp, err := c.do(ctx, http.MethodDelete, c.path(namespace, group), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Already deleted.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return checkResponse(resp)
+}
+
+func (c *Client) path(elems ...string) string {
+	p := c.apiPrefix
+	for _, e := range elems {
+		p += "/" + url.PathEscape(e)
+	}
+	return p
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+	if c.tenant != "" {
+		req.Header.Set(tenantHeader, c.tenant)
+	}
+
+	c.logger.WithValues(log.Kv{"method": method, "path": path}).Debugf("Ruler API request")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ruler API %s %s request failed: %w", method, path, err)
+	}
+
+	return resp, nil
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("ruler API %s %s request failed with %d status code: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,126 @@
+package ruler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/ruler"
+)
+
+type request struct {
+	method string
+	path   string
+	tenant string
+	body   string
+}
+
+func TestClient(t *testing.T) {
+	tests := map[string]struct {
+		tenant     string
+		apiPrefix  string
+		respCode   int
+		respBody   string
+		call       func(c *ruler.Client) (interface{}, error)
+		expRequest request
+		expResult  interface{}
+		expErr     bool
+	}{
+		"Listing the rule groups of a namespace should return the group names.": {
+			tenant:   "tenant1",
+			respCode: http.StatusOK,
+			respBody: "test-ns.test:\n- name: group1\n  rules: []\n- name: group2\n  rules: []\n",
+			call: func(c *ruler.Client) (interface{}, error) {
+				return c.ListRuleGroups(context.TODO(), "test-ns.test")
+			},
+			expRequest: request{method: http.MethodGet, path: "/prometheus/config/v1/rules/test-ns.test", tenant: "tenant1"},
+			expResult:  []string{"group1", "group2"},
+		},
+
+		"Listing the rule groups of a missing namespace should not return groups.": {
+			respCode: http.StatusNotFound,
+			call: func(c *ruler.Client) (interface{}, error) {
+				return c.ListRuleGroups(context.TODO(), "test-ns.test")
+			},
+			expRequest: request{method: http.MethodGet, path: "/prometheus/config/v1/rules/test-ns.test"},
+			expResult:  []string(nil),
+		},
+
+		"Listing the rule groups with a server error should fail.": {
+			respCode: http.StatusInternalServerError,
+			call: func(c *ruler.Client) (interface{}, error) {
+				return c.ListRuleGroups(context.TODO(), "test-ns.test")
+			},
+			expRequest: request{method: http.MethodGet, path: "/prometheus/config/v1/rules/test-ns.test"},
+			expErr:     true,
+		},
+
+		"Setting a rule group should post the group on the namespace.": {
+			tenant:    "tenant1",
+			apiPrefix: "/api/v1/rules/",
+			respCode:  http.StatusAccepted,
+			call: func(c *ruler.Client) (interface{}, error) {
+				return nil, c.SetRuleGroup(context.TODO(), "test-ns.test", []byte("name: group1\nrules: []\n"))
+			},
+			expRequest: request{method: http.MethodPost, path: "/api/v1/rules/test-ns.test", tenant: "tenant1", body: "name: group1\nrules: []\n"},
+		},
+
+		"Setting a rule group with a client error should fail.": {
+			respCode: http.StatusBadRequest,
+			call: func(c *ruler.Client) (interface{}, error) {
+				return nil, c.SetRuleGroup(context.TODO(), "test-ns.test", []byte("name: group1\n"))
+			},
+			expRequest: request{method: http.MethodPost, path: "/prometheus/config/v1/rules/test-ns.test", body: "name: group1\n"},
+			expErr:     true,
+		},
+
+		"Deleting a rule group should delete the group of the namespace.": {
+			respCode: http.StatusAccepted,
+			call: func(c *ruler.Client) (interface{}, error) {
+				return nil, c.DeleteRuleGroup(context.TODO(), "test-ns.test", "group1")
+			},
+			expRequest: request{method: http.MethodDelete, path: "/prometheus/config/v1/rules/test-ns.test/group1"},
+		},
+
+		"Deleting a missing rule group should not fail.": {
+			respCode: http.StatusNotFound,
+			call: func(c *ruler.Client) (interface{}, error) {
+				return nil, c.DeleteRuleGroup(context.TODO(), "test-ns.test", "group1")
+			},
+			expRequest: request{method: http.MethodDelete, path: "/prometheus/config/v1/rules/test-ns.test/group1"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotRequest request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotRequest = request{method: r.Method, path: r.URL.Path, tenant: r.Header.Get("X-Scope-OrgID"), body: string(body)}
+				w.WriteHeader(test.respCode)
+				_, _ = w.Write([]byte(test.respBody))
+			}))
+			defer srv.Close()
+
+			c, err := ruler.NewClient(ruler.ClientConfig{URL: srv.URL, APIPrefix: test.apiPrefix, Tenant: test.tenant})
+			require.NoError(err)
+
+			gotResult, err := test.call(c)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expResult, gotResult)
+			}
+			assert.Equal(test.expRequest, gotRequest)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package spec
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultsApplier knows how to apply a defaults document on raw specs before being loaded,
+// so multiple specs can share the common boilerplate. The defaults document has two blocks
+// written in the same format as the specs that will inherit them:
+//
+//	# Merged on the spec root (or `spec` on Kubernetes specs).
+//	spec:
+//	  labels:
+//	    team: "myteam"
+//	# Merged on every SLO of the spec.
+//	slo:
+//	  objective: 99.9
+//	  alerting:
+//	    annotations:
+//	      runbook: "https://runbooks.mycompany.com/slos"
+//
+// The spec fields take precedence over the defaults, maps are merged recursively and any other
+// value (e.g lists, strings, numbers) of the spec replaces the default one.
+type DefaultsApplier struct {
+	// Defaults is the defaults document.
+	Defaults []byte
+}
+
+type defaults struct {
+	Spec map[interface{}]interface{} `yaml:"spec,omitempty"`
+	SLO  map[interface{}]interface{} `yaml:"slo,omitempty"`
+}
+
+// Apply will apply the defaults on the spec data.
+func (d DefaultsApplier) Apply(data []byte) ([]byte, error) {
+	df := defaults{}
+	err := yaml.UnmarshalStrict(d.Defaults, &df)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal defaults: %w", err)
+	}
+
+	root := map[interface{}]interface{}{}
+	err = yaml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal spec: %w", err)
+	}
+
+	// Kubernetes specs have the spec body under `spec`.
+	body := root
+	_, hasAPIVersion := root["apiVersion"]
+	_, hasKind := root["kind"]
+	isKubernetes := hasAPIVersion && hasKind
+	if isKubernetes {
+		body, _ = root["spec"].(map[interface{}]interface{})
+	}
+
+	// Set the defaults on every SLO.
+	if slos, ok := body["slos"].([]interface{}); ok && len(df.SLO) > 0 {
+		for i, slo := range slos {
+			slos[i] = mergeYAML(df.SLO, slo)
+		}
+	}
+
+	var merged interface{} = mergeYAML(df.Spec, body)
+	if isKubernetes {
+		root["spec"] = merged
+		merged = root
+	}
+
+	res, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal spec: %w", err)
+	}
+
+	return res, nil
+}
+
+// mergeYAML merges the YAML values recursively, the value takes precedence over the default.
+func mergeYAML(def interface{}, value interface{}) interface{} {
+	defMap, defOK := def.(map[interface{}]interface{})
+	valueMap, valueOK := value.(map[interface{}]interface{})
+
+	switch {
+	case value == nil:
+		return def
+	case !defOK || !valueOK:
+		return value
+	}
+
+	res := map[interface{}]interface{}{}
+	for k, v := range defMap {
+		res[k] = v
+	}
+	for k, v := range valueMap {
+		res[k] = mergeYAML(defMap[k], v)
+	}
+
+	return res
+}
@@ -0,0 +1,177 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/slok/sloth/internal/spec"
+)
+
+func TestDefaultsApplierApply(t *testing.T) {
+	tests := map[string]struct {
+		defaults string
+		data     string
+		expData  string
+		expErr   bool
+	}{
+		"Invalid defaults should fail.": {
+			defaults: `:`,
+			data:     `service: test`,
+			expErr:   true,
+		},
+
+		"Unknown defaults blocks should fail.": {
+			defaults: `something: {}`,
+			data:     `service: test`,
+			expErr:   true,
+		},
+
+		"Invalid spec should fail.": {
+			defaults: `spec: {}`,
+			data:     `:`,
+			expErr:   true,
+		},
+
+		"Empty defaults should not change the spec.": {
+			defaults: ``,
+			data: `
+version: prometheus/v1
+service: test
+slos:
+- name: slo1
+  objective: 99
+`,
+			expData: `
+version: prometheus/v1
+service: test
+slos:
+- name: slo1
+  objective: 99
+`,
+		},
+
+		"Defaults should be applied on the spec and SLOs with the spec taking precedence.": {
+			defaults: `
+spec:
+  labels:
+    team: team1
+    tier: "1"
+slo:
+  objective: 99.9
+  labels:
+    category: availability
+  alerting:
+    annotations:
+      runbook: https://runbooks.test
+    page_alert:
+      labels:
+        severity: critical
+`,
+			data: `
+version: prometheus/v1
+service: test
+labels:
+  tier: "2"
+slos:
+- name: slo1
+  objective: 99
+  alerting:
+    name: Alert1
+- name: slo2
+  labels:
+    owner: me
+  alerting:
+    annotations:
+      summary: test
+    page_alert:
+      disable: true
+`,
+			expData: `
+version: prometheus/v1
+service: test
+labels:
+  team: team1
+  tier: "2"
+slos:
+- name: slo1
+  objective: 99
+  labels:
+    category: availability
+  alerting:
+    name: Alert1
+    annotations:
+      runbook: https://runbooks.test
+    page_alert:
+      labels:
+        severity: critical
+- name: slo2
+  objective: 99.9
+  labels:
+    category: availability
+    owner: me
+  alerting:
+    annotations:
+      runbook: https://runbooks.test
+      summary: test
+    page_alert:
+      disable: true
+      labels:
+        severity: critical
+`,
+		},
+
+		"Defaults should be applied on the Kubernetes spec body.": {
+			defaults: `
+spec:
+  labels:
+    team: team1
+slo:
+  objective: 99.9
+`,
+			data: `
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: test
+spec:
+  service: test
+  slos:
+  - name: slo1
+`,
+			expData: `
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: test
+spec:
+  service: test
+  labels:
+    team: team1
+  slos:
+  - name: slo1
+    objective: 99.9
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			applier := spec.DefaultsApplier{Defaults: []byte(test.defaults)}
+			gotData, err := applier.Apply([]byte(test.data))
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				// Compare the YAML objects, the key order is not important.
+				var exp, got interface{}
+				assert.NoError(yaml.Unmarshal([]byte(test.expData), &exp))
+				assert.NoError(yaml.Unmarshal(gotData, &got))
+				assert.Equal(exp, got)
+			}
+		})
+	}
+}
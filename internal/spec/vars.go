@@ -0,0 +1,106 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// VarsExpander knows how to expand `${VAR}` variables on raw specs before being loaded.
+type VarsExpander struct {
+	// Vars are the variables values that will be used on the expansion.
+	Vars map[string]string
+	// Env will use the environment variables as a fallback when a variable is missing.
+	Env bool
+	// Template will also render the spec as a Go template with the variables as data
+	// (e.g `[[ if eq .ENV "prod" ]]`), after the `${VAR}` expansion.
+	Template bool
+}
+
+// Go template delimiters of the specs, `{{ }}` are already used by the SLI query window
+// (`{{.window}}`) and the Prometheus alert templates.
+const (
+	templateLeftDelim  = "[["
+	templateRightDelim = "]]"
+)
+
+// varRegexp matches `${VAR}` and `${VAR:-default}` forms. We don't support `$VAR`
+// form because `$` is used on Prometheus alert templates (e.g `{{ $labels.x }}`).
+var varRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Expand will expand the variables on the spec data. It will fail if any of the
+// variables is missing and doesn't have a default value.
+func (v VarsExpander) Expand(data []byte) ([]byte, error) {
+	missing := map[string]struct{}{}
+	res := varRegexp.ReplaceAllFunc(data, func(match []byte) []byte {
+		groups := varRegexp.FindSubmatch(match)
+		name := string(groups[1])
+
+		if value, ok := v.Vars[name]; ok {
+			return []byte(value)
+		}
+
+		if v.Env {
+			if value, ok := os.LookupEnv(name); ok {
+				return []byte(value)
+			}
+		}
+
+		// Has default.
+		if len(groups[2]) > 0 {
+			return groups[3]
+		}
+
+		missing[name] = struct{}{}
+		return match
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("missing spec variables: %s", strings.Join(names, ", "))
+	}
+
+	if v.Template {
+		return v.render(res)
+	}
+
+	return res, nil
+}
+
+// render renders the spec data as a Go template, using the variables (and the environment
+// variables if enabled) as the template data.
+func (v VarsExpander) render(data []byte) ([]byte, error) {
+	tpl, err := template.New("spec").Delims(templateLeftDelim, templateRightDelim).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse spec template: %w", err)
+	}
+
+	vars := map[string]string{}
+	if v.Env {
+		for _, env := range os.Environ() {
+			parts := strings.SplitN(env, "=", 2)
+			if len(parts) == 2 {
+				vars[parts[0]] = parts[1]
+			}
+		}
+	}
+	for k, value := range v.Vars {
+		vars[k] = value
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, vars)
+	if err != nil {
+		return nil, fmt.Errorf("could not render spec template: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
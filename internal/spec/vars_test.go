@@ -0,0 +1,114 @@
+package spec_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/spec"
+)
+
+func TestVarsExpanderExpand(t *testing.T) {
+	tests := map[string]struct {
+		expander spec.VarsExpander
+		env      map[string]string
+		data     string
+		expData  string
+		expErr   bool
+	}{
+		"Without variables the data should be the same.": {
+			expander: spec.VarsExpander{},
+			data:     `service: "test"`,
+			expData:  `service: "test"`,
+		},
+
+		"Prometheus templates and non braced variables should be ignored.": {
+			expander: spec.VarsExpander{},
+			data:     `summary: "{{ $labels.job }} $NOT_A_VAR {{.window}}"`,
+			expData:  `summary: "{{ $labels.job }} $NOT_A_VAR {{.window}}"`,
+		},
+
+		"Variables should be expanded.": {
+			expander: spec.VarsExpander{Vars: map[string]string{"CLUSTER": "c1", "REGION": "eu-west-1"}},
+			data:     `cluster: "${CLUSTER}", region: "${REGION}", other: "${CLUSTER}"`,
+			expData:  `cluster: "c1", region: "eu-west-1", other: "c1"`,
+		},
+
+		"Missing variables should fail.": {
+			expander: spec.VarsExpander{Vars: map[string]string{"CLUSTER": "c1"}},
+			data:     `cluster: "${CLUSTER}", region: "${REGION}"`,
+			expErr:   true,
+		},
+
+		"Missing variables with default values should use the default value.": {
+			expander: spec.VarsExpander{Vars: map[string]string{"CLUSTER": "c1"}},
+			data:     `cluster: "${CLUSTER:-c0}", region: "${REGION:-eu-west-1}", team: "${TEAM:-}"`,
+			expData:  `cluster: "c1", region: "eu-west-1", team: ""`,
+		},
+
+		"Env variables should not be used if not enabled.": {
+			expander: spec.VarsExpander{},
+			env:      map[string]string{"SLOTH_TEST_VAR_REGION": "eu-west-1"},
+			data:     `region: "${SLOTH_TEST_VAR_REGION}"`,
+			expErr:   true,
+		},
+
+		"Env variables should be used if enabled, with less priority than the variables.": {
+			expander: spec.VarsExpander{Env: true, Vars: map[string]string{"SLOTH_TEST_VAR_CLUSTER": "c1"}},
+			env:      map[string]string{"SLOTH_TEST_VAR_REGION": "eu-west-1", "SLOTH_TEST_VAR_CLUSTER": "c2"},
+			data:     `cluster: "${SLOTH_TEST_VAR_CLUSTER}", region: "${SLOTH_TEST_VAR_REGION}"`,
+			expData:  `cluster: "c1", region: "eu-west-1"`,
+		},
+
+		"Go templates should not be rendered if not enabled.": {
+			expander: spec.VarsExpander{Vars: map[string]string{"ENV": "prod"}},
+			data:     `objective: [[ if eq .ENV "prod" ]]99.9[[ else ]]99[[ end ]]`,
+			expData:  `objective: [[ if eq .ENV "prod" ]]99.9[[ else ]]99[[ end ]]`,
+		},
+
+		"Go templates should be rendered with the variables if enabled, keeping the Prometheus templates.": {
+			expander: spec.VarsExpander{Template: true, Vars: map[string]string{"ENV": "prod", "CLUSTER": "c1"}},
+			data:     `cluster: "${CLUSTER}", objective: [[ if eq .ENV "prod" ]]99.9[[ else ]]99[[ end ]], summary: "{{ $labels.job }} {{.window}}"`,
+			expData:  `cluster: "c1", objective: 99.9, summary: "{{ $labels.job }} {{.window}}"`,
+		},
+
+		"Go templates should use the env variables if enabled, with less priority than the variables.": {
+			expander: spec.VarsExpander{Template: true, Env: true, Vars: map[string]string{"SLOTH_TEST_VAR_CLUSTER": "c1"}},
+			env:      map[string]string{"SLOTH_TEST_VAR_REGION": "eu-west-1", "SLOTH_TEST_VAR_CLUSTER": "c2"},
+			data:     `cluster: "[[ .SLOTH_TEST_VAR_CLUSTER ]]", region: "[[ .SLOTH_TEST_VAR_REGION ]]"`,
+			expData:  `cluster: "c1", region: "eu-west-1"`,
+		},
+
+		"Go templates with missing variables should fail.": {
+			expander: spec.VarsExpander{Template: true},
+			data:     `cluster: "[[ .CLUSTER ]]"`,
+			expErr:   true,
+		},
+
+		"Invalid Go templates should fail.": {
+			expander: spec.VarsExpander{Template: true},
+			data:     `cluster: "[[ .CLUSTER "`,
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			for k, v := range test.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			gotData, err := test.expander.Expand([]byte(test.data))
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expData, string(gotData))
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -54,6 +55,42 @@ func (in *Alert) DeepCopy() *Alert {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertWindow) DeepCopyInto(out *AlertWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertWindow.
+func (in *AlertWindow) DeepCopy() *AlertWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertWindows) DeepCopyInto(out *AlertWindows) {
+	*out = *in
+	out.PageQuick = in.PageQuick
+	out.PageSlow = in.PageSlow
+	out.TicketQuick = in.TicketQuick
+	out.TicketSlow = in.TicketSlow
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertWindows.
+func (in *AlertWindows) DeepCopy() *AlertWindows {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertWindows)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Alerting) DeepCopyInto(out *Alerting) {
 	*out = *in
@@ -73,6 +110,26 @@ func (in *Alerting) DeepCopyInto(out *Alerting) {
 	}
 	in.PageAlert.DeepCopyInto(&out.PageAlert)
 	in.TicketAlert.DeepCopyInto(&out.TicketAlert)
+	if in.ForecastAlert != nil {
+		in, out := &in.ForecastAlert, &out.ForecastAlert
+		*out = new(ForecastAlert)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BurnRates != nil {
+		in, out := &in.BurnRates, &out.BurnRates
+		*out = new(BurnRates)
+		**out = **in
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = new(AlertWindows)
+		**out = **in
+	}
+	if in.MinTraffic != nil {
+		in, out := &in.MinTraffic, &out.MinTraffic
+		*out = new(MinTraffic)
+		**out = **in
+	}
 	return
 }
 
@@ -86,6 +143,126 @@ func (in *Alerting) DeepCopy() *Alerting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BurnRates) DeepCopyInto(out *BurnRates) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BurnRates.
+func (in *BurnRates) DeepCopy() *BurnRates {
+	if in == nil {
+		return nil
+	}
+	out := new(BurnRates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisabledRules) DeepCopyInto(out *DisabledRules) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisabledRules.
+func (in *DisabledRules) DeepCopy() *DisabledRules {
+	if in == nil {
+		return nil
+	}
+	out := new(DisabledRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Exclusion) DeepCopyInto(out *Exclusion) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Exclusion.
+func (in *Exclusion) DeepCopy() *Exclusion {
+	if in == nil {
+		return nil
+	}
+	out := new(Exclusion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForecastAlert) DeepCopyInto(out *ForecastAlert) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForecastAlert.
+func (in *ForecastAlert) DeepCopy() *ForecastAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(ForecastAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinTraffic) DeepCopyInto(out *MinTraffic) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinTraffic.
+func (in *MinTraffic) DeepCopy() *MinTraffic {
+	if in == nil {
+		return nil
+	}
+	out := new(MinTraffic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Objective) DeepCopyInto(out *Objective) {
+	*out = *in
+	if in.Alerting != nil {
+		in, out := &in.Alerting, &out.Alerting
+		*out = new(Alerting)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Objective.
+func (in *Objective) DeepCopy() *Objective {
+	if in == nil {
+		return nil
+	}
+	out := new(Objective)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusServiceLevel) DeepCopyInto(out *PrometheusServiceLevel) {
 	*out = *in
@@ -150,6 +327,26 @@ func (in *PrometheusServiceLevelList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusServiceLevelSpec) DeepCopyInto(out *PrometheusServiceLevelSpec) {
 	*out = *in
+	if in.RuleGroupIntervals != nil {
+		in, out := &in.RuleGroupIntervals, &out.RuleGroupIntervals
+		*out = new(RuleGroupIntervals)
+		**out = **in
+	}
+	if in.RunbookURLs != nil {
+		in, out := &in.RunbookURLs, &out.RunbookURLs
+		*out = new(RunbookURLs)
+		**out = **in
+	}
+	if in.SlothLabels != nil {
+		in, out := &in.SlothLabels, &out.SlothLabels
+		*out = new(SlothLabels)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ruler != nil {
+		in, out := &in.Ruler, &out.Ruler
+		*out = new(Ruler)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -157,6 +354,32 @@ func (in *PrometheusServiceLevelSpec) DeepCopyInto(out *PrometheusServiceLevelSp
 			(*out)[key] = val
 		}
 	}
+	if in.ExternalLabels != nil {
+		in, out := &in.ExternalLabels, &out.ExternalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RuleSelectorLabels != nil {
+		in, out := &in.RuleSelectorLabels, &out.RuleSelectorLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RuleAnnotations != nil {
+		in, out := &in.RuleAnnotations, &out.RuleAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AlertWindows != nil {
+		in, out := &in.AlertWindows, &out.AlertWindows
+		*out = new(AlertWindows)
+		**out = **in
+	}
 	if in.SLOs != nil {
 		in, out := &in.SLOs, &out.SLOs
 		*out = make([]SLO, len(*in))
@@ -184,6 +407,18 @@ func (in *PrometheusServiceLevelStatus) DeepCopyInto(out *PrometheusServiceLevel
 		in, out := &in.LastPromOpRulesSuccessfulGenerated, &out.LastPromOpRulesSuccessfulGenerated
 		*out = (*in).DeepCopy()
 	}
+	if in.SLOErrors != nil {
+		in, out := &in.SLOErrors, &out.SLOErrors
+		*out = make([]SLOError, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -197,13 +432,66 @@ func (in *PrometheusServiceLevelStatus) DeepCopy() *PrometheusServiceLevelStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleGroupIntervals) DeepCopyInto(out *RuleGroupIntervals) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleGroupIntervals.
+func (in *RuleGroupIntervals) DeepCopy() *RuleGroupIntervals {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleGroupIntervals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ruler) DeepCopyInto(out *Ruler) {
+	*out = *in
+	if in.DedupLabels != nil {
+		in, out := &in.DedupLabels, &out.DedupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ruler.
+func (in *Ruler) DeepCopy() *Ruler {
+	if in == nil {
+		return nil
+	}
+	out := new(Ruler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunbookURLs) DeepCopyInto(out *RunbookURLs) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunbookURLs.
+func (in *RunbookURLs) DeepCopy() *RunbookURLs {
+	if in == nil {
+		return nil
+	}
+	out := new(RunbookURLs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLI) DeepCopyInto(out *SLI) {
 	*out = *in
 	if in.Raw != nil {
 		in, out := &in.Raw, &out.Raw
 		*out = new(SLIRaw)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Events != nil {
 		in, out := &in.Events, &out.Events
@@ -242,6 +530,13 @@ func (in *SLIEvents) DeepCopy() *SLIEvents {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLIRaw) DeepCopyInto(out *SLIRaw) {
 	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -258,6 +553,23 @@ func (in *SLIRaw) DeepCopy() *SLIRaw {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLO) DeepCopyInto(out *SLO) {
 	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeSlice != nil {
+		in, out := &in.TimeSlice, &out.TimeSlice
+		*out = new(TimeSlice)
+		**out = **in
+	}
+	if in.Exclusions != nil {
+		in, out := &in.Exclusions, &out.Exclusions
+		*out = make([]Exclusion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -267,6 +579,18 @@ func (in *SLO) DeepCopyInto(out *SLO) {
 	}
 	in.SLI.DeepCopyInto(&out.SLI)
 	in.Alerting.DeepCopyInto(&out.Alerting)
+	if in.AdditionalObjectives != nil {
+		in, out := &in.AdditionalObjectives, &out.AdditionalObjectives
+		*out = make([]Objective, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DisabledRules != nil {
+		in, out := &in.DisabledRules, &out.DisabledRules
+		*out = new(DisabledRules)
+		**out = **in
+	}
 	return
 }
 
@@ -279,3 +603,63 @@ func (in *SLO) DeepCopy() *SLO {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOError) DeepCopyInto(out *SLOError) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOError.
+func (in *SLOError) DeepCopy() *SLOError {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlothLabels) DeepCopyInto(out *SlothLabels) {
+	*out = *in
+	if in.Rename != nil {
+		in, out := &in.Rename, &out.Rename
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Drop != nil {
+		in, out := &in.Drop, &out.Drop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlothLabels.
+func (in *SlothLabels) DeepCopy() *SlothLabels {
+	if in == nil {
+		return nil
+	}
+	out := new(SlothLabels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeSlice) DeepCopyInto(out *TimeSlice) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeSlice.
+func (in *TimeSlice) DeepCopy() *TimeSlice {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeSlice)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,89 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// SLIsAnnotation is the v1 object annotation that has the v2 SLIs that can't be represented
+// on v1 (e.g composite) by SLO name, so these objects can be converted back to v2 without losing them.
+const SLIsAnnotation = "sloth.slok.dev/v2-slis"
+
+// ConvertFromV1 converts a v1 PrometheusServiceLevel to v2, the v2 SLIs stored by the v2 to v1
+// conversion are restored.
+func ConvertFromV1(in *slothv1.PrometheusServiceLevel) (*PrometheusServiceLevel, error) {
+	// v2 is a superset of v1, so both have the same representation.
+	out := &PrometheusServiceLevel{}
+	err := convertJSON(in, out)
+	if err != nil {
+		return nil, err
+	}
+	out.APIVersion = SchemeGroupVersion.String()
+
+	slis := map[string]SLI{}
+	if data, ok := out.Annotations[SLIsAnnotation]; ok {
+		err := json.Unmarshal([]byte(data), &slis)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q annotation: %w", SLIsAnnotation, err)
+		}
+		delete(out.Annotations, SLIsAnnotation)
+		if len(out.Annotations) == 0 {
+			out.Annotations = nil
+		}
+	}
+
+	for i, slo := range out.Spec.SLOs {
+		if sli, ok := slis[slo.Name]; ok {
+			out.Spec.SLOs[i].SLI = sli
+		}
+	}
+
+	return out, nil
+}
+
+// ConvertToV1 converts a v2 PrometheusServiceLevel to v1, the SLIs that can't be represented on v1
+// are stored on the SLIsAnnotation.
+func ConvertToV1(in *PrometheusServiceLevel) (*slothv1.PrometheusServiceLevel, error) {
+	out := &slothv1.PrometheusServiceLevel{}
+	err := convertJSON(in, out)
+	if err != nil {
+		return nil, err
+	}
+	out.APIVersion = slothv1.SchemeGroupVersion.String()
+
+	slis := map[string]SLI{}
+	for _, slo := range in.Spec.SLOs {
+		if slo.SLI.Raw == nil && slo.SLI.Events == nil {
+			slis[slo.Name] = slo.SLI
+		}
+	}
+
+	if len(slis) > 0 {
+		data, err := json.Marshal(slis)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal v2 SLIs: %w", err)
+		}
+		if out.Annotations == nil {
+			out.Annotations = map[string]string{}
+		}
+		out.Annotations[SLIsAnnotation] = string(data)
+	}
+
+	return out, nil
+}
+
+func convertJSON(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("could not marshal object: %w", err)
+	}
+
+	err = json.Unmarshal(data, out)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal object: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+// +k8s:deepcopy-gen=package
+// +groupName=sloth.slok.dev
+// +versionName=v2
+
+package v2
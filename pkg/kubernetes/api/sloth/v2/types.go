@@ -0,0 +1,720 @@
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//go:generate gomarkdoc -o ./README.md ./
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SERVICE",type="string",JSONPath=".spec.service"
+// +kubebuilder:printcolumn:name="DESIRED SLOs",type="integer",JSONPath=".status.processedSLOs"
+// +kubebuilder:printcolumn:name="READY SLOs",type="integer",JSONPath=".status.promOpRulesGeneratedSLOs"
+// +kubebuilder:printcolumn:name="OBJECTIVES",type="string",JSONPath=".status.objectives"
+// +kubebuilder:printcolumn:name="GEN OK",type="boolean",JSONPath=".status.promOpRulesGenerated"
+// +kubebuilder:printcolumn:name="RULES",type="integer",JSONPath=".status.generatedRules"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="GEN AGE",type="date",JSONPath=".status.lastPromOpRulesSuccessfulGenerated"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=prometheusservicelevel,path=prometheusservicelevels,shortName=psl;pslo,scope=Namespaced,categories=slo;slos;sli;slis
+//
+// PrometheusServiceLevel is the expected service quality level using Prometheus
+// as the backend used by Sloth.
+type PrometheusServiceLevel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PrometheusServiceLevelSpec   `json:"spec,omitempty"`
+	Status PrometheusServiceLevelStatus `json:"status,omitempty"`
+}
+
+// ServiceLevelSpec is the spec for a PrometheusServiceLevel.
+type PrometheusServiceLevelSpec struct {
+	// +kubebuilder:validation:Required
+	//
+	// Service is the application of the SLOs.
+	Service string `json:"service"`
+
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	//
+	// MetricsPrefix replaces the `slo` and `sloth` prefixes of the generated metrics
+	// (e.g `myorg` will generate `myorg:sli_error:ratio_rate5m` and `myorg_slo_info`).
+	// +optional
+	MetricsPrefix string `json:"metricsPrefix,omitempty"`
+
+	// RuleGroupIntervals are the evaluation intervals of the generated rule groups, if
+	// missing the Prometheus default evaluation interval will be used.
+	// +optional
+	RuleGroupIntervals *RuleGroupIntervals `json:"ruleGroupIntervals,omitempty"`
+
+	// RuleGroupName is the template of the generated rule group names, it can use the
+	// `{{ .service }}`, `{{ .slo }}`, `{{ .id }}` and `{{ .category }}` (`sli-recordings`,
+	// `meta-recordings` or `alerts`) variables. By default `sloth-slo-{{ .category }}-{{ .id }}`.
+	// +optional
+	RuleGroupName string `json:"ruleGroupName,omitempty"`
+
+	// RunbookURLs are the runbook URL templates of the generated alerts by severity, these
+	// are set on the alerts `runbook_url` annotation (unless the alert already sets it).
+	// +optional
+	RunbookURLs *RunbookURLs `json:"runbookURLs,omitempty"`
+
+	// SlothLabels customizes the Sloth labels (e.g `sloth_id`) set on the generated rules.
+	// +optional
+	SlothLabels *SlothLabels `json:"slothLabels,omitempty"`
+
+	// Ruler are the options for the Thanos ruler that evaluates the generated rules
+	// (e.g a global ruler), by default plain Prometheus rules are generated.
+	// +optional
+	Ruler *Ruler `json:"ruler,omitempty"`
+
+	// Labels are the Prometheus labels that will have all the recording
+	// and alerting rules generated for the service SLOs.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ExternalLabels are static labels (e.g cluster, region) that identify where the SLOs are,
+	// these are set on all the generated rules and used on the SLO series queries, so the same
+	// SLOs can be aggregated from multiple places (e.g Thanos) without mixing them.
+	// +optional
+	ExternalLabels map[string]string `json:"externalLabels,omitempty"`
+
+	// RuleSelectorLabels are the Kubernetes labels that will be set on the generated
+	// Prometheus operator PrometheusRule object (not on the Prometheus rules). Use them to
+	// match the `ruleSelector` of the Prometheus instance that should evaluate the rules.
+	// These take precedence over the PrometheusServiceLevel object labels, and both take
+	// precedence over the controller `--rule-selector-labels`.
+	// +optional
+	RuleSelectorLabels map[string]string `json:"ruleSelectorLabels,omitempty"`
+
+	// RuleAnnotations are the Kubernetes annotations that will be set on the generated
+	// Prometheus operator PrometheusRule object (not on the Prometheus rules).
+	// These take precedence over the PrometheusServiceLevel object annotations.
+	// +optional
+	RuleAnnotations map[string]string `json:"ruleAnnotations,omitempty"`
+
+	// AlertWindows are the windows of the multiwindow-multiburn alerts of all the SLOs, so
+	// the windows can be customized for all the service SLOs. The SLOs `alerting.windows`
+	// take precedence over these (by alert).
+	// +optional
+	AlertWindows *AlertWindows `json:"alertWindows,omitempty"`
+
+	// AlertWindowsCatalog is the name of a ConfigMap on the same namespace with the
+	// multiwindow-multiburn alert windows catalog of each SLO period (e.g `30d`, `28d`).
+	// The SLOs use the windows of their period, the `alertWindows` and SLO `alerting.windows`
+	// take precedence over these (by alert).
+	// +optional
+	AlertWindowsCatalog string `json:"alertWindowsCatalog,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	//
+	// SLOs are the SLOs of the service.
+	SLOs []SLO `json:"slos,omitempty"`
+}
+
+// RuleGroupIntervals are the evaluation intervals of the generated rule groups in
+// Prometheus duration format (e.g 30s).
+type RuleGroupIntervals struct {
+	// SLI is the evaluation interval of the SLI error ratio recording rules group.
+	// +optional
+	SLI string `json:"sli,omitempty"`
+
+	// Metadata is the evaluation interval of the SLO metadata recording rules group.
+	// +optional
+	Metadata string `json:"metadata,omitempty"`
+
+	// Alerts is the evaluation interval of the SLO alert rules group.
+	// +optional
+	Alerts string `json:"alerts,omitempty"`
+}
+
+// RunbookURLs are the runbook URL templates of the generated alerts by severity. These are
+// alert templates, so they can use the SLO variables with `[[` and `]]` delimiters
+// (e.g `https://runbooks.myorg.com/[[ .service ]]/[[ .slo ]]?severity=[[ .severity ]]`).
+type RunbookURLs struct {
+	// Page is the runbook URL template of the page alerts.
+	// +optional
+	Page string `json:"page,omitempty"`
+
+	// Ticket is the runbook URL template of the ticket alerts (including the forecast alert).
+	// +optional
+	Ticket string `json:"ticket,omitempty"`
+}
+
+// Ruler are the Thanos ruler specific options of the generated rules.
+type Ruler struct {
+	// DedupLabels are the HA replica labels (e.g `replica`, `prometheus_replica`) removed from
+	// the SLI recordings with a `max without` aggregation, so the SLI queries evaluated with the
+	// data of all the replicas record a single series.
+	// +optional
+	DedupLabels []string `json:"dedupLabels,omitempty"`
+
+	// +kubebuilder:validation:Enum=warn;abort
+	//
+	// PartialResponseStrategy is the Thanos rule groups partial response strategy, `warn` or
+	// `abort` (Thanos default).
+	// +optional
+	PartialResponseStrategy string `json:"partialResponseStrategy,omitempty"`
+}
+
+// SlothLabels customizes the Sloth labels (`sloth_id`, `sloth_slo`, `sloth_service`, `sloth_window`,
+// `sloth_severity`, `sloth_version`, `sloth_mode`, `sloth_spec` and `sloth_depends_on`) set on the
+// generated rules.
+type SlothLabels struct {
+	// Prefix replaces the `sloth` prefix of the labels (e.g `myorg` will set `myorg_id`).
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Rename renames the labels (e.g `sloth_service: service`), takes precedence over the prefix.
+	// +optional
+	Rename map[string]string `json:"rename,omitempty"`
+
+	// Drop are the labels that will not be set (e.g `sloth_slo`). `sloth_id` and `sloth_window`
+	// can't be dropped, these are required to identify the SLO series.
+	// +optional
+	Drop []string `json:"drop,omitempty"`
+}
+
+// SLO is the configuration/declaration of the service level objective of
+// a service.
+type SLO struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=128
+	//
+	// Name is the name of the SLO.
+	Name string `json:"name"`
+
+	// Description is the description of the SLO.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// DependsOn are the IDs of the SLOs this SLO depends on (e.g `payments-requests-availability`),
+	// these are set on the `sloth_slo_info` metric and the alerts, for dependency aware triage.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// +kubebuilder:validation:Required
+	//
+	// Objective is target of the SLO the percentage (0, 100) (e.g 99.9).
+	Objective float64 `json:"objective"`
+
+	// TimeWindow is the SLO period in Prometheus duration format (e.g 7d, 28d, 90d)
+	// or `quarterly` (90d). The alert windows are derived from it. By default 30d.
+	// Use `calendar-month` or `calendar-quarter` to reset the error budget on every
+	// calendar month or quarter instead of using a rolling window.
+	// +optional
+	TimeWindow string `json:"timeWindow,omitempty"`
+
+	// TimeSlice makes the SLO use bad time slices (e.g bad minutes) instead of bad events,
+	// normally used by low traffic services where a few errors would burn the error budget.
+	// +optional
+	TimeSlice *TimeSlice `json:"timeSlice,omitempty"`
+
+	// Exclusions are the time ranges (e.g maintenance windows) that don't consume error
+	// budget, the SLI errors that happen during an exclusion are ignored.
+	// +optional
+	Exclusions []Exclusion `json:"exclusions,omitempty"`
+
+	// Labels are the Prometheus labels that will have all the recording and
+	// alerting rules for this specific SLO. These labels are merged with the
+	// previous level labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +kubebuilder:validation:Required
+	//
+	// SLI is the indicator (service level indicator) for this specific SLO.
+	SLI SLI `json:"sli"`
+
+	// +kubebuilder:validation:Required
+	//
+	// Alerting is the configuration with all the things related with the SLO
+	// alerts.
+	Alerting Alerting `json:"alerting"`
+
+	// AdditionalObjectives are extra targets for the same SLI (e.g a stricter
+	// internal target), each one will be generated as an SLO named
+	// `{SLO name}-{objective name}` with its own metadata and alerts.
+	// +optional
+	AdditionalObjectives []Objective `json:"additionalObjectives,omitempty"`
+
+	// DisabledRules disables the generation of rule categories for the SLO, e.g when
+	// the SLI is already recorded by another system or only the metadata is wanted.
+	// +optional
+	DisabledRules *DisabledRules `json:"disabledRules,omitempty"`
+}
+
+// DisabledRules are the rule categories that will not be generated for an SLO.
+type DisabledRules struct {
+	// SLIRecordings disables the SLI error ratio recording rules, the SLI recordings
+	// (e.g `slo:sli_error:ratio_rate5m`) must be recorded by another system with the
+	// same metric names and labels. The burn rates used by the alerts are recorded
+	// with the alerts, so these are still generated.
+	// +optional
+	SLIRecordings bool `json:"sliRecordings,omitempty"`
+
+	// MetadataRecordings disables the SLO metadata recording rules.
+	// +optional
+	MetadataRecordings bool `json:"metadataRecordings,omitempty"`
+
+	// Alerts disables the SLO alert rules.
+	// +optional
+	Alerts bool `json:"alerts,omitempty"`
+}
+
+// TimeSlice configures a time slice based SLO, the error budget is the ratio of time
+// slices that are bad, a time slice is bad when its SLI error ratio is above the threshold.
+type TimeSlice struct {
+	// +kubebuilder:validation:Required
+	//
+	// Duration is the duration of the time slices in Prometheus duration format (e.g 1m).
+	Duration string `json:"duration"`
+
+	// Threshold is the SLI error ratio (0-1) above which a time slice is bad (e.g 0.05).
+	Threshold float64 `json:"threshold"`
+}
+
+// Exclusion is a time range excluded from the SLO error budget, only one of
+// `query`, `start`/`end` or `startTime`/`endTime` can be set.
+type Exclusion struct {
+	// Query is a Prometheus query that returns a result while the exclusion is active
+	// (e.g `maintenance_mode{service="myservice"} == 1`).
+	// +optional
+	Query string `json:"query,omitempty"`
+
+	// Start is the start of a one-off exclusion in RFC3339 format (e.g 2021-06-01T10:00:00Z).
+	// +optional
+	Start string `json:"start,omitempty"`
+
+	// End is the end of a one-off exclusion in RFC3339 format (e.g 2021-06-01T12:00:00Z).
+	// +optional
+	End string `json:"end,omitempty"`
+
+	// StartTime is the UTC start time of day of a recurring exclusion in `HH:MM` format (e.g 02:00).
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// EndTime is the UTC end time of day of a recurring exclusion in `HH:MM` format (e.g 04:00),
+	// if it's before the start time the exclusion ends on the next day.
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+
+	// Days are the week days (e.g sunday) of a recurring exclusion, if missing the exclusion
+	// happens every day.
+	// +optional
+	Days []string `json:"days,omitempty"`
+}
+
+// Objective is an additional target of an SLO.
+type Objective struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=64
+	//
+	// Name is the name of the objective (e.g tracked).
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	//
+	// Objective is target of the objective the percentage (0, 100) (e.g 99.9).
+	Objective float64 `json:"objective"`
+
+	// Alerting is the alerting configuration of the objective, if missing
+	// the objective will not have alerts.
+	// +optional
+	Alerting *Alerting `json:"alerting,omitempty"`
+}
+
+// SLI will tell what is good or bad for the SLO.
+// All SLIs will be get based on time windows, that's why Sloth needs the queries to
+// use `{{.window}}` template variable.
+//
+// Only one of the SLI types can be used.
+type SLI struct {
+	// SLIRaw is the raw SLI type.
+	// +optional
+	Raw *SLIRaw `json:"raw,omitempty"`
+
+	// SLIEvents is the events SLI type.
+	// +optional
+	Events *SLIEvents `json:"events,omitempty"`
+
+	// SLINativeHistogram is the native histogram SLI type.
+	// +optional
+	NativeHistogram *SLINativeHistogram `json:"nativeHistogram,omitempty"`
+
+	// SLIComposite is the composite SLI type.
+	// +optional
+	Composite *SLIComposite `json:"composite,omitempty"`
+
+	// SLIWeighted is the weighted SLOs SLI type.
+	// +optional
+	Weighted *SLIWeighted `json:"weighted,omitempty"`
+
+	// SLIAggregated is the multiple places (e.g clusters) aggregated SLO SLI type.
+	// +optional
+	Aggregated *SLIAggregated `json:"aggregated,omitempty"`
+}
+
+// CompositeSLI is an SLI that is part of a composite SLI, only one of the SLI types can be used.
+type CompositeSLI struct {
+	// SLIRaw is the raw SLI type.
+	// +optional
+	Raw *SLIRaw `json:"raw,omitempty"`
+
+	// SLIEvents is the events SLI type.
+	// +optional
+	Events *SLIEvents `json:"events,omitempty"`
+
+	// SLINativeHistogram is the native histogram SLI type.
+	// +optional
+	NativeHistogram *SLINativeHistogram `json:"nativeHistogram,omitempty"`
+}
+
+// SLIComposite is an SLI composed of multiple SLIs, this can be used when the SLO
+// has multiple conditions that need to hold at the same time (e.g availability and latency).
+type SLIComposite struct {
+	// +kubebuilder:validation:MinItems=2
+	//
+	// All are the SLIs that need to hold at the same time for an event to be good.
+	// The error ratio is calculated as `1 - ((1 - error_ratio_1) * (1 - error_ratio_2) * ...)`,
+	// in other words, the ratio of events that are bad on at least one of the SLIs (treating
+	// the SLIs as independent).
+	All []CompositeSLI `json:"all"`
+}
+
+// SLIWeighted is an SLI that is the weighted combination of other SLOs SLIs (e.g a business
+// level "checkout" SLO based on the API and payments SLOs). Sloth will use the SLI recording
+// rules of the SLOs, so these need to be on the same spec and have the same time window.
+type SLIWeighted struct {
+	// +kubebuilder:validation:MinItems=2
+	//
+	// SLOs are the weighted SLOs. The error ratio is calculated as
+	// `(w1 * error_ratio_1 + w2 * error_ratio_2 + ...) / (w1 + w2 + ...)`.
+	SLOs []WeightedSLO `json:"slos"`
+}
+
+// WeightedSLO is an SLO that is part of a weighted SLI.
+type WeightedSLO struct {
+	// ID is the ID of the SLO, this is the `sloth_id` label of the SLO
+	// (by default `{service}-{slo}`).
+	ID string `json:"id"`
+
+	// Weight is the weight of the SLO error ratio (e.g 0.6), must be greater than 0.
+	Weight float64 `json:"weight"`
+}
+
+// SLIAggregated is an SLI that aggregates an SLO generated on multiple places (e.g clusters
+// or tenants), normally used by the global SLOs generated for a global view (e.g Thanos, Mimir).
+// Sloth will use the SLI recording rules of the SLO on every place, so the SLO needs to be an
+// `events` SLI (the only one that records the total events), have the same SLI windows and
+// identify each place with labels (e.g `externalLabels`).
+type SLIAggregated struct {
+	// ID is the ID of the aggregated SLO, this is the `sloth_id` label of the SLO
+	// (by default `{service}-{slo}`).
+	ID string `json:"id"`
+
+	// +kubebuilder:validation:MinItems=1
+	//
+	// Labels are the labels that identify each place of the SLO (e.g `cluster`). The error
+	// ratio is the error ratio of every place weighted by its total events rate.
+	Labels []string `json:"labels"`
+}
+
+// SLINativeHistogram is an SLI based on a Prometheus native histogram, normally used for
+// latency SLIs. The observations above the threshold are the bad events, Sloth will generate
+// the error ratio using `histogram_fraction`, so Prometheus v2.40 or newer is required.
+type SLINativeHistogram struct {
+	// Selector is the Prometheus metric selector of the native histogram
+	// (e.g `http_request_duration_seconds{job="myservice"}`).
+	Selector string `json:"selector"`
+
+	// Threshold is the upper bound of the good observations (e.g 0.25 for 250ms).
+	Threshold float64 `json:"threshold"`
+}
+
+// SLIRaw is a error ratio SLI already calculated. Normally this will be used when the SLI
+// is already calculated by other recording rule, system...
+type SLIRaw struct {
+	// ErrorRatioQuery is a Prometheus query that will get the raw error ratio (0-1) for the SLO.
+	// It can use the Queries by name as template variables (e.g `{{.errors}} / {{.total}}`).
+	ErrorRatioQuery string `json:"errorRatioQuery"`
+
+	// Queries are named Prometheus sub-queries that can be composed on the ErrorRatioQuery,
+	// these will be wrapped in parentheses and can use the `{{.window}}` template variable.
+	// +optional
+	Queries map[string]string `json:"queries,omitempty"`
+}
+
+// SLIEvents is an SLI that is calculated as the division of bad (or good) events and total events,
+// giving a ratio SLI. Normally this is the most common ratio type.
+type SLIEvents struct {
+	// ErrorQuery is a Prometheus query that will get the number/count of events
+	// that we consider that are bad for the SLO (e.g "http 5xx", "latency > 250ms"...).
+	// Requires the usage of `{{.window}}` template variable.
+	// Only one of ErrorQuery or GoodQuery can be used.
+	// +optional
+	ErrorQuery string `json:"errorQuery,omitempty"`
+
+	// GoodQuery is a Prometheus query that will get the number/count of events
+	// that we consider that are good for the SLO (e.g "http 2xx"), the error
+	// ratio will be calculated inverting the good events ratio.
+	// Requires the usage of `{{.window}}` template variable.
+	// Only one of ErrorQuery or GoodQuery can be used.
+	// +optional
+	GoodQuery string `json:"goodQuery,omitempty"`
+
+	// TotalQuery is a Prometheus query that will get the total number/count of events
+	// for the SLO (e.g "all http requests"...).
+	// Requires the usage of `{{.window}}` template variable.
+	TotalQuery string `json:"totalQuery"`
+}
+
+// Alerting wraps all the configuration required by the SLO alerts.
+type Alerting struct {
+	// Name is the name used by the alerts generated for this SLO.
+	Name string `json:"name" validate:"required"`
+
+	// +kubebuilder:validation:Enum=multiwindow-multiburn;single-window;budget-consumed
+	//
+	// Strategy is the strategy used to generate the alerts: `multiwindow-multiburn` (default),
+	// `single-window` or `budget-consumed`.
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
+
+	// Labels are the Prometheus labels that will have all the alerts generated by this SLO.
+	// These are the common labels of the page and ticket alerts, the specific alert labels
+	// are merged on top of these.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the Prometheus annotations that will have all the alerts generated by
+	// this SLO.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Page alert refers to the critical alert (check multiwindow-multiburn alerts).
+	PageAlert Alert `json:"pageAlert,omitempty"`
+
+	// TicketAlert alert refers to the warning alert (check multiwindow-multiburn alerts).
+	TicketAlert Alert `json:"ticketAlert,omitempty"`
+
+	// ForecastAlert enables an extra alert that triggers when the error budget is forecasted
+	// to be exhausted before the end of the SLO period at the current burn rate.
+	// +optional
+	ForecastAlert *ForecastAlert `json:"forecastAlert,omitempty"`
+
+	// BurnRates override the burn rate factors of the multiwindow-multiburn alerts.
+	// +optional
+	BurnRates *BurnRates `json:"burnRates,omitempty"`
+
+	// Windows override the windows of the multiwindow-multiburn alerts, by default these
+	// are derived from the SLO time window.
+	// +optional
+	Windows *AlertWindows `json:"windows,omitempty"`
+
+	// +kubebuilder:validation:Enum=all;page-only;ticket-only;none
+	//
+	// Severities are the alert severities that will be generated: `all` (default),
+	// `page-only`, `ticket-only` or `none` (e.g for informational SLOs).
+	// +optional
+	Severities string `json:"severities,omitempty"`
+
+	// MinTraffic makes the page and ticket alerts only trigger when the SLO has a minimum
+	// traffic, this avoids paging on low traffic services where a few errors make the
+	// error ratio very high.
+	// +optional
+	MinTraffic *MinTraffic `json:"minTraffic,omitempty"`
+}
+
+// MinTraffic is the minimum traffic required by the alerts to trigger.
+type MinTraffic struct {
+	// +kubebuilder:validation:Required
+	//
+	// Rate is the minimum traffic events per second (e.g 0.5).
+	Rate float64 `json:"rate"`
+
+	// Query is a Prometheus query that gets the traffic events per second. Requires the usage
+	// of `{{.window}}` template variable, the window will be the alert long window. By default
+	// the events SLI total query.
+	// +optional
+	Query string `json:"query,omitempty"`
+}
+
+// AlertWindows are the windows of each multiwindow-multiburn alert, the missing ones
+// use the defaults.
+type AlertWindows struct {
+	// PageQuick are the windows of the page quick alert (by default 5m and 1h on a 30d SLO).
+	// +optional
+	PageQuick AlertWindow `json:"pageQuick,omitempty"`
+
+	// PageSlow are the windows of the page slow alert (by default 30m and 6h on a 30d SLO).
+	// +optional
+	PageSlow AlertWindow `json:"pageSlow,omitempty"`
+
+	// TicketQuick are the windows of the ticket quick alert (by default 2h and 1d on a 30d SLO).
+	// +optional
+	TicketQuick AlertWindow `json:"ticketQuick,omitempty"`
+
+	// TicketSlow are the windows of the ticket slow alert (by default 6h and 3d on a 30d SLO).
+	// +optional
+	TicketSlow AlertWindow `json:"ticketSlow,omitempty"`
+}
+
+// AlertWindow are the short and long windows of a multiwindow-multiburn alert
+// in Prometheus duration format.
+type AlertWindow struct {
+	// Short is the short window of the alert (e.g 5m).
+	// +optional
+	Short string `json:"short,omitempty"`
+
+	// Long is the long window of the alert (e.g 1h).
+	// +optional
+	Long string `json:"long,omitempty"`
+}
+
+// BurnRates are the burn rate factors of each multiwindow-multiburn alert, the
+// missing ones use the defaults (14.4, 6, 3 and 1 on a 30d SLO).
+type BurnRates struct {
+	// PageQuick is the burn rate factor of the page quick alert.
+	// +optional
+	PageQuick float64 `json:"pageQuick,omitempty"`
+
+	// PageSlow is the burn rate factor of the page slow alert.
+	// +optional
+	PageSlow float64 `json:"pageSlow,omitempty"`
+
+	// TicketQuick is the burn rate factor of the ticket quick alert.
+	// +optional
+	TicketQuick float64 `json:"ticketQuick,omitempty"`
+
+	// TicketSlow is the burn rate factor of the ticket slow alert.
+	// +optional
+	TicketSlow float64 `json:"ticketSlow,omitempty"`
+}
+
+// ForecastAlert configures the error budget exhaustion forecast alert.
+type ForecastAlert struct {
+	// Name is the name of the alert, by default `{alerting name}ErrorBudgetExhaustion`.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Window is the window used to measure the current burn rate in Prometheus duration
+	// format (e.g 6h). By default the ticket alert long window (1d on a 30d SLO).
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// Labels are the Prometheus labels for the alert, these are merged on top of the
+	// common alerting labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the Prometheus annotations for the alert, these are merged on top
+	// of the common alerting annotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Alert configures specific SLO alert.
+type Alert struct {
+	// Disable disables the alert and makes Sloth not generating this alert. This
+	// can be helpful for example to disable ticket(warning) alerts.
+	Disable bool `json:"disable,omitempty"`
+
+	// Labels are the Prometheus labels for the specific alert. For example can be
+	// useful to route the Page alert to specific Slack channel.
+	// These labels take precedence over the common alerting labels, and a label
+	// with an empty value will remove the inherited common label.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the Prometheus annotations for the specific alert.
+	// Same as the labels, these take precedence over the common alerting annotations
+	// and an annotation with an empty value will remove the inherited one.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Window is the alert window in Prometheus duration format (e.g 1h), only used by
+	// the `single-window` strategy. By default 1h for page and 1d for ticket on a 30d SLO.
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// BurnRate is the burn rate factor threshold of the alert (e.g 14.4), only used by the
+	// `single-window` strategy. By default 14.4 for page and 3 for ticket.
+	// +optional
+	BurnRate float64 `json:"burnRate,omitempty"`
+
+	// For is the time the alert condition needs to hold before the alert fires in
+	// Prometheus duration format (e.g 5m). By default the alert fires immediately.
+	// +optional
+	For string `json:"for,omitempty"`
+}
+
+type PrometheusServiceLevelStatus struct {
+	// PromOpRulesGeneratedSLOs tells how many SLOs have been processed and generated for Prometheus operator successfully.
+	PromOpRulesGeneratedSLOs int `json:"promOpRulesGeneratedSLOs"`
+	// ProcessedSLOs tells how many SLOs haven been processed for Prometheus operator.
+	ProcessedSLOs int `json:"processedSLOs"`
+	// PromOpRulesGenerated tells if the rules for prometheus operator CRD have been generated.
+	PromOpRulesGenerated bool `json:"promOpRulesGenerated"`
+	// LastPromOpRulesGeneration tells the last atemp made for a successful SLO rules generate.
+	// +optional
+	LastPromOpRulesSuccessfulGenerated *metav1.Time `json:"lastPromOpRulesSuccessfulGenerated,omitempty"`
+	// ObservedGeneration tells the generation was acted on, normally this is required to stop an
+	// infinite loop when the status is updated because it sends a watch updated event to the watchers
+	// of the K8s object.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// GeneratedRules tells how many Prometheus rules (recording and alerting) have been generated.
+	// +optional
+	GeneratedRules int `json:"generatedRules,omitempty"`
+	// Objectives is the range of the SLO objectives (e.g `99-99.9`).
+	// +optional
+	Objectives string `json:"objectives,omitempty"`
+	// LastError is the error of the last failed handling, empty if the last handling succeeded.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// SLOErrors are the errors of the specific SLOs that failed the last handling validation
+	// or generation (e.g an invalid PromQL expression).
+	// +optional
+	SLOErrors []SLOError `json:"sloErrors,omitempty"`
+	// Conditions are the `Validated`, `RulesGenerated`, `RulesApplied` and `Ready` conditions
+	// of the last handling.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// SLOError is the error of a specific SLO.
+type SLOError struct {
+	// SLO is the name of the failed SLO.
+	SLO string `json:"slo"`
+	// Field is the failed SLO field (e.g `SLI.Raw.ErrorRatioQuery`), if known.
+	// +optional
+	Field string `json:"field,omitempty"`
+	// Expression is the failed expression (e.g a PromQL query), if known.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+	// Message is the error message.
+	Message string `json:"message"`
+}
+
+const (
+	// ConditionValidated tells if the spec has been loaded and validated.
+	ConditionValidated = "Validated"
+	// ConditionRulesGenerated tells if the Prometheus rules have been generated.
+	ConditionRulesGenerated = "RulesGenerated"
+	// ConditionRulesApplied tells if the Prometheus operator rules have been stored.
+	ConditionRulesApplied = "RulesApplied"
+	// ConditionReady tells if all the handling steps succeeded.
+	ConditionReady = "Ready"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// PrometheusServiceLevelList is a list of PrometheusServiceLevel resources.
+type PrometheusServiceLevelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PrometheusServiceLevel `json:"items"`
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	slothv1 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v1"
+	slothv2 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v2"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -30,6 +31,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	SlothV1() slothv1.SlothV1Interface
+	SlothV2() slothv2.SlothV2Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -37,6 +39,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	slothV1 *slothv1.SlothV1Client
+	slothV2 *slothv2.SlothV2Client
 }
 
 // SlothV1 retrieves the SlothV1Client
@@ -44,6 +47,11 @@ func (c *Clientset) SlothV1() slothv1.SlothV1Interface {
 	return c.slothV1
 }
 
+// SlothV2 retrieves the SlothV2Client
+func (c *Clientset) SlothV2() slothv2.SlothV2Interface {
+	return c.slothV2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -69,6 +77,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.slothV2, err = slothv2.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -82,6 +94,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.slothV1 = slothv1.NewForConfigOrDie(c)
+	cs.slothV2 = slothv2.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -91,6 +104,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.slothV1 = slothv1.New(c)
+	cs.slothV2 = slothv2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -22,6 +22,8 @@ import (
 	clientset "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned"
 	slothv1 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v1"
 	fakeslothv1 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v1/fake"
+	slothv2 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v2"
+	fakeslothv2 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -80,3 +82,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) SlothV1() slothv1.SlothV1Interface {
 	return &fakeslothv1.FakeSlothV1{Fake: &c.Fake}
 }
+
+// SlothV2 retrieves the SlothV2Client
+func (c *Clientset) SlothV2() slothv2.SlothV2Interface {
+	return &fakeslothv2.FakeSlothV2{Fake: &c.Fake}
+}
@@ -20,6 +20,7 @@ package scheme
 
 import (
 	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothv2 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,6 +33,7 @@ var Codecs = serializer.NewCodecFactory(Scheme)
 var ParameterCodec = runtime.NewParameterCodec(Scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	slothv1.AddToScheme,
+	slothv2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
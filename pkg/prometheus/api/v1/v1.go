@@ -64,13 +64,82 @@ type Spec struct {
 	Version string `yaml:"version"`
 	// Service is the application of the SLOs.
 	Service string `yaml:"service"`
+	// MetricsPrefix replaces the `slo` and `sloth` prefixes of the generated metrics
+	// (e.g `myorg` will generate `myorg:sli_error:ratio_rate5m` and `myorg_slo_info`).
+	MetricsPrefix string `yaml:"metrics_prefix,omitempty"`
+	// RuleGroupIntervals are the evaluation intervals of the generated rule groups, if
+	// missing the Prometheus default evaluation interval will be used.
+	RuleGroupIntervals *RuleGroupIntervals `yaml:"rule_group_intervals,omitempty"`
+	// RuleGroupName is the template of the generated rule group names, it can use the
+	// `{{ .service }}`, `{{ .slo }}`, `{{ .id }}` and `{{ .category }}` (`sli-recordings`,
+	// `meta-recordings` or `alerts`) variables. By default `sloth-slo-{{ .category }}-{{ .id }}`.
+	RuleGroupName string `yaml:"rule_group_name,omitempty"`
+	// RunbookURLs are the runbook URL templates of the generated alerts by severity, these
+	// are set on the alerts `runbook_url` annotation (unless the alert already sets it).
+	RunbookURLs *RunbookURLs `yaml:"runbook_urls,omitempty"`
+	// SlothLabels customizes the Sloth labels (e.g `sloth_id`) set on the generated rules.
+	SlothLabels *SlothLabels `yaml:"sloth_labels,omitempty"`
+	// Ruler are the options for the Thanos or Mimir rulers that evaluate the generated rules
+	// (e.g a global ruler), by default plain Prometheus rules are generated.
+	Ruler *Ruler `yaml:"ruler,omitempty"`
 	// Labels are the Prometheus labels that will have all the recording
 	// and alerting rules generated for the service SLOs.
 	Labels map[string]string `yaml:"labels,omitempty"`
+	// ExternalLabels are static labels (e.g cluster, region) that identify where the SLOs are,
+	// these are set on all the generated rules and used on the SLO series queries, so the same
+	// SLOs can be aggregated from multiple places (e.g Thanos) without mixing them.
+	ExternalLabels map[string]string `yaml:"external_labels,omitempty"`
 	// SLOs are the SLOs of the service.
 	SLOs []SLO `yaml:"slos,omitempty"`
 }
 
+// RuleGroupIntervals are the evaluation intervals of the generated rule groups in
+// Prometheus duration format (e.g 30s).
+type RuleGroupIntervals struct {
+	// SLI is the evaluation interval of the SLI error ratio recording rules group.
+	SLI string `yaml:"sli,omitempty"`
+	// Metadata is the evaluation interval of the SLO metadata recording rules group.
+	Metadata string `yaml:"metadata,omitempty"`
+	// Alerts is the evaluation interval of the SLO alert rules group.
+	Alerts string `yaml:"alerts,omitempty"`
+}
+
+// RunbookURLs are the runbook URL templates of the generated alerts by severity. These are
+// alert templates, so they can use the SLO variables with `[[` and `]]` delimiters
+// (e.g `https://runbooks.myorg.com/[[ .service ]]/[[ .slo ]]?severity=[[ .severity ]]`).
+type RunbookURLs struct {
+	// Page is the runbook URL template of the page alerts.
+	Page string `yaml:"page,omitempty"`
+	// Ticket is the runbook URL template of the ticket alerts (including the forecast alert).
+	Ticket string `yaml:"ticket,omitempty"`
+}
+
+// Ruler are the Thanos or Mimir ruler specific options of the generated rules.
+type Ruler struct {
+	// DedupLabels are the HA replica labels (e.g `replica`, `prometheus_replica`) removed from
+	// the SLI recordings with a `max without` aggregation, so the SLI queries evaluated with the
+	// data of all the replicas record a single series.
+	DedupLabels []string `yaml:"dedup_labels,omitempty"`
+	// PartialResponseStrategy is the Thanos rule groups partial response strategy, `warn` or
+	// `abort` (Thanos default).
+	PartialResponseStrategy string `yaml:"partial_response_strategy,omitempty"`
+	// SourceTenants are the Mimir tenants queried by the rule groups (federated rule groups).
+	SourceTenants []string `yaml:"source_tenants,omitempty"`
+}
+
+// SlothLabels customizes the Sloth labels (`sloth_id`, `sloth_slo`, `sloth_service`, `sloth_window`,
+// `sloth_severity`, `sloth_version`, `sloth_mode`, `sloth_spec` and `sloth_depends_on`) set on the
+// generated rules.
+type SlothLabels struct {
+	// Prefix replaces the `sloth` prefix of the labels (e.g `myorg` will set `myorg_id`).
+	Prefix string `yaml:"prefix,omitempty"`
+	// Rename renames the labels (e.g `sloth_service: service`), takes precedence over the prefix.
+	Rename map[string]string `yaml:"rename,omitempty"`
+	// Drop are the labels that will not be set (e.g `sloth_slo`). `sloth_id` and `sloth_window`
+	// can't be dropped, these are required to identify the SLO series.
+	Drop []string `yaml:"drop,omitempty"`
+}
+
 // SLO is the configuration/declaration of the service level objective of
 // a service.
 type SLO struct {
@@ -78,8 +147,22 @@ type SLO struct {
 	Name string `yaml:"name"`
 	// Description is the description of the SLO.
 	Description string `yaml:"description,omitempty"`
-	// Objective is target of the SLO the percentage (0, 100] (e.g 99.9).
+	// DependsOn are the IDs of the SLOs this SLO depends on (e.g `payments-requests-availability`),
+	// these are set on the `sloth_slo_info` metric and the alerts, for dependency aware triage.
+	DependsOn []string `yaml:"depends_on,omitempty"`
+	// Objective is target of the SLO the percentage (0, 100) (e.g 99.9).
 	Objective float64 `yaml:"objective"`
+	// TimeWindow is the SLO period in Prometheus duration format (e.g 7d, 28d, 90d)
+	// or `quarterly` (90d). The alert windows are derived from it. By default 30d.
+	// Use `calendar-month` or `calendar-quarter` to reset the error budget on every
+	// calendar month or quarter instead of using a rolling window.
+	TimeWindow string `yaml:"time_window,omitempty"`
+	// TimeSlice makes the SLO use bad time slices (e.g bad minutes) instead of bad events,
+	// normally used by low traffic services where a few errors would burn the error budget.
+	TimeSlice *TimeSlice `yaml:"time_slice,omitempty"`
+	// Exclusions are the time ranges (e.g maintenance windows) that don't consume error
+	// budget, the SLI errors that happen during an exclusion are ignored.
+	Exclusions []Exclusion `yaml:"exclusions,omitempty"`
 	// Labels are the Prometheus labels that will have all the recording and
 	// alerting rules for this specific SLO. These labels are merged with the
 	// previous level labels.
@@ -89,6 +172,66 @@ type SLO struct {
 	// Alerting is the configuration with all the things related with the SLO
 	// alerts.
 	Alerting Alerting `yaml:"alerting"`
+	// AdditionalObjectives are extra targets for the same SLI (e.g a stricter
+	// internal target), each one will be generated as an SLO named
+	// `{SLO name}-{objective name}` with its own metadata and alerts.
+	AdditionalObjectives []Objective `yaml:"additional_objectives,omitempty"`
+	// DisabledRules disables the generation of rule categories for the SLO, e.g when
+	// the SLI is already recorded by another system or only the metadata is wanted.
+	DisabledRules *DisabledRules `yaml:"disabled_rules,omitempty"`
+}
+
+// DisabledRules are the rule categories that will not be generated for an SLO.
+type DisabledRules struct {
+	// SLIRecordings disables the SLI error ratio recording rules, the SLI recordings
+	// (e.g `slo:sli_error:ratio_rate5m`) must be recorded by another system with the
+	// same metric names and labels. The burn rates used by the alerts are recorded
+	// with the alerts, so these are still generated.
+	SLIRecordings bool `yaml:"sli_recordings,omitempty"`
+	// MetadataRecordings disables the SLO metadata recording rules.
+	MetadataRecordings bool `yaml:"metadata_recordings,omitempty"`
+	// Alerts disables the SLO alert rules.
+	Alerts bool `yaml:"alerts,omitempty"`
+}
+
+// TimeSlice configures a time slice based SLO, the error budget is the ratio of time
+// slices that are bad, a time slice is bad when its SLI error ratio is above the threshold.
+type TimeSlice struct {
+	// Duration is the duration of the time slices in Prometheus duration format (e.g 1m).
+	Duration string `yaml:"duration"`
+	// Threshold is the SLI error ratio (0-1) above which a time slice is bad (e.g 0.05).
+	Threshold float64 `yaml:"threshold"`
+}
+
+// Exclusion is a time range excluded from the SLO error budget, only one of
+// `query`, `start`/`end` or `start_time`/`end_time` can be set.
+type Exclusion struct {
+	// Query is a Prometheus query that returns a result while the exclusion is active
+	// (e.g `maintenance_mode{service="myservice"} == 1`).
+	Query string `yaml:"query,omitempty"`
+	// Start is the start of a one-off exclusion in RFC3339 format (e.g 2021-06-01T10:00:00Z).
+	Start string `yaml:"start,omitempty"`
+	// End is the end of a one-off exclusion in RFC3339 format (e.g 2021-06-01T12:00:00Z).
+	End string `yaml:"end,omitempty"`
+	// StartTime is the UTC start time of day of a recurring exclusion in `HH:MM` format (e.g 02:00).
+	StartTime string `yaml:"start_time,omitempty"`
+	// EndTime is the UTC end time of day of a recurring exclusion in `HH:MM` format (e.g 04:00),
+	// if it's before the start time the exclusion ends on the next day.
+	EndTime string `yaml:"end_time,omitempty"`
+	// Days are the week days (e.g sunday) of a recurring exclusion, if missing the exclusion
+	// happens every day.
+	Days []string `yaml:"days,omitempty"`
+}
+
+// Objective is an additional target of an SLO.
+type Objective struct {
+	// Name is the name of the objective (e.g tracked).
+	Name string `yaml:"name"`
+	// Objective is target of the objective the percentage (0, 100) (e.g 99.9).
+	Objective float64 `yaml:"objective"`
+	// Alerting is the alerting configuration of the objective, if missing
+	// the objective will not have alerts.
+	Alerting *Alerting `yaml:"alerting,omitempty"`
 }
 
 // SLI will tell what is good or bad for the SLO.
@@ -107,16 +250,27 @@ type SLI struct {
 // is already calculated by other recording rule, system...
 type SLIRaw struct {
 	// ErrorRatioQuery is a Prometheus query that will get the raw error ratio (0-1) for the SLO.
+	// It can use the Queries by name as template variables (e.g `{{.errors}} / {{.total}}`).
 	ErrorRatioQuery string `yaml:"error_ratio_query"`
+	// Queries are named Prometheus sub-queries that can be composed on the ErrorRatioQuery,
+	// these will be wrapped in parentheses and can use the `{{.window}}` template variable.
+	Queries map[string]string `yaml:"queries,omitempty"`
 }
 
-// SLIEvents is an SLI that is calculated as the division of bad events and total events, giving
-// a ratio SLI. Normally this is the most common ratio type.
+// SLIEvents is an SLI that is calculated as the division of bad (or good) events and total events,
+// giving a ratio SLI. Normally this is the most common ratio type.
 type SLIEvents struct {
 	// ErrorQuery is a Prometheus query that will get the number/count of events
 	// that we consider that are bad for the SLO (e.g "http 5xx", "latency > 250ms"...).
 	// Requires the usage of `{{.window}}` template variable.
-	ErrorQuery string `yaml:"error_query"`
+	// Only one of ErrorQuery or GoodQuery can be used.
+	ErrorQuery string `yaml:"error_query,omitempty"`
+	// GoodQuery is a Prometheus query that will get the number/count of events
+	// that we consider that are good for the SLO (e.g "http 2xx"), the error
+	// ratio will be calculated inverting the good events ratio.
+	// Requires the usage of `{{.window}}` template variable.
+	// Only one of ErrorQuery or GoodQuery can be used.
+	GoodQuery string `yaml:"good_query,omitempty"`
 	// TotalQuery is a Prometheus query that will get the total number/count of events
 	// for the SLO (e.g "all http requests"...).
 	// Requires the usage of `{{.window}}` template variable.
@@ -127,7 +281,12 @@ type SLIEvents struct {
 type Alerting struct {
 	// Name is the name used by the alerts generated for this SLO.
 	Name string `yaml:"name" validate:"required"`
+	// Strategy is the strategy used to generate the alerts: `multiwindow-multiburn` (default),
+	// `single-window` or `budget-consumed`.
+	Strategy string `yaml:"strategy,omitempty"`
 	// Labels are the Prometheus labels that will have all the alerts generated by this SLO.
+	// These are the common labels of the page and ticket alerts, the specific alert labels
+	// are merged on top of these.
 	Labels map[string]string `yaml:"labels,omitempty"`
 	// Annotations are the Prometheus annotations that will have all the alerts generated by
 	// this SLO.
@@ -136,6 +295,85 @@ type Alerting struct {
 	PageAlert Alert `yaml:"page_alert,omitempty"`
 	// TicketAlert alert refers to the warning alert (check multiwindow-multiburn alerts).
 	TicketAlert Alert `yaml:"ticket_alert,omitempty"`
+	// ForecastAlert enables an extra alert that triggers when the error budget is forecasted
+	// to be exhausted before the end of the SLO period at the current burn rate.
+	ForecastAlert *ForecastAlert `yaml:"forecast_alert,omitempty"`
+	// BurnRates override the burn rate factors of the multiwindow-multiburn alerts.
+	BurnRates *BurnRates `yaml:"burn_rates,omitempty"`
+	// Windows override the windows of the multiwindow-multiburn alerts, by default these
+	// are derived from the SLO time window.
+	Windows *AlertWindows `yaml:"windows,omitempty"`
+	// KeepFiringFor is the time the alerts will keep firing after the condition has
+	// resolved in Prometheus duration format (e.g 10m), this avoids flapping alerts.
+	// Requires Prometheus v2.42 or newer.
+	KeepFiringFor string `yaml:"keep_firing_for,omitempty"`
+	// Severities are the alert severities that will be generated: `all` (default),
+	// `page-only`, `ticket-only` or `none` (e.g for informational SLOs).
+	Severities string `yaml:"severities,omitempty"`
+	// MinTraffic makes the page and ticket alerts only trigger when the SLO has a minimum
+	// traffic, this avoids paging on low traffic services where a few errors make the
+	// error ratio very high.
+	MinTraffic *MinTraffic `yaml:"min_traffic,omitempty"`
+}
+
+// MinTraffic is the minimum traffic required by the alerts to trigger.
+type MinTraffic struct {
+	// Rate is the minimum traffic events per second (e.g 0.5).
+	Rate float64 `yaml:"rate"`
+	// Query is a Prometheus query that gets the traffic events per second. Requires the usage
+	// of `{{.window}}` template variable, the window will be the alert long window. By default
+	// the events SLI total query.
+	Query string `yaml:"query,omitempty"`
+}
+
+// AlertWindows are the windows of each multiwindow-multiburn alert, the missing ones
+// use the defaults.
+type AlertWindows struct {
+	// PageQuick are the windows of the page quick alert (by default 5m and 1h on a 30d SLO).
+	PageQuick AlertWindow `yaml:"page_quick,omitempty"`
+	// PageSlow are the windows of the page slow alert (by default 30m and 6h on a 30d SLO).
+	PageSlow AlertWindow `yaml:"page_slow,omitempty"`
+	// TicketQuick are the windows of the ticket quick alert (by default 2h and 1d on a 30d SLO).
+	TicketQuick AlertWindow `yaml:"ticket_quick,omitempty"`
+	// TicketSlow are the windows of the ticket slow alert (by default 6h and 3d on a 30d SLO).
+	TicketSlow AlertWindow `yaml:"ticket_slow,omitempty"`
+}
+
+// AlertWindow are the short and long windows of a multiwindow-multiburn alert
+// in Prometheus duration format.
+type AlertWindow struct {
+	// Short is the short window of the alert (e.g 5m).
+	Short string `yaml:"short,omitempty"`
+	// Long is the long window of the alert (e.g 1h).
+	Long string `yaml:"long,omitempty"`
+}
+
+// BurnRates are the burn rate factors of each multiwindow-multiburn alert, the
+// missing ones use the defaults (14.4, 6, 3 and 1 on a 30d SLO).
+type BurnRates struct {
+	// PageQuick is the burn rate factor of the page quick alert.
+	PageQuick float64 `yaml:"page_quick,omitempty"`
+	// PageSlow is the burn rate factor of the page slow alert.
+	PageSlow float64 `yaml:"page_slow,omitempty"`
+	// TicketQuick is the burn rate factor of the ticket quick alert.
+	TicketQuick float64 `yaml:"ticket_quick,omitempty"`
+	// TicketSlow is the burn rate factor of the ticket slow alert.
+	TicketSlow float64 `yaml:"ticket_slow,omitempty"`
+}
+
+// ForecastAlert configures the error budget exhaustion forecast alert.
+type ForecastAlert struct {
+	// Name is the name of the alert, by default `{alerting name}ErrorBudgetExhaustion`.
+	Name string `yaml:"name,omitempty"`
+	// Window is the window used to measure the current burn rate in Prometheus duration
+	// format (e.g 6h). By default the ticket alert long window (1d on a 30d SLO).
+	Window string `yaml:"window,omitempty"`
+	// Labels are the Prometheus labels for the alert, these are merged on top of the
+	// common alerting labels.
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Annotations are the Prometheus annotations for the alert, these are merged on top
+	// of the common alerting annotations.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
 }
 
 // Alert configures specific SLO alert.
@@ -145,7 +383,20 @@ type Alert struct {
 	Disable bool `yaml:"disable,omitempty"`
 	// Labels are the Prometheus labels for the specific alert. For example can be
 	// useful to route the Page alert to specific Slack channel.
+	// These labels take precedence over the common alerting labels, and a label
+	// with an empty value will remove the inherited common label.
 	Labels map[string]string `yaml:"labels,omitempty"`
 	// Annotations are the Prometheus annotations for the specific alert.
+	// Same as the labels, these take precedence over the common alerting annotations
+	// and an annotation with an empty value will remove the inherited one.
 	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// Window is the alert window in Prometheus duration format (e.g 1h), only used by
+	// the `single-window` strategy. By default 1h for page and 1d for ticket on a 30d SLO.
+	Window string `yaml:"window,omitempty"`
+	// BurnRate is the burn rate factor threshold of the alert (e.g 14.4), only used by the
+	// `single-window` strategy. By default 14.4 for page and 3 for ticket.
+	BurnRate float64 `yaml:"burn_rate,omitempty"`
+	// For is the time the alert condition needs to hold before the alert fires in
+	// Prometheus duration format (e.g 5m). By default the alert fires immediately.
+	For string `yaml:"for,omitempty"`
 }
@@ -18,7 +18,7 @@ docker run -it --rm \
 	-e PROJECT_PACKAGE=${PROJECT_PACKAGE} \
 	-e CLIENT_GENERATOR_OUT=${PROJECT_PACKAGE}/pkg/kubernetes/gen \
 	-e APIS_ROOT=${PROJECT_PACKAGE}/pkg/kubernetes/api \
-	-e GROUPS_VERSION="sloth:v1" \
+	-e GROUPS_VERSION="sloth:v1,v2" \
 	-e GENERATION_TARGETS="deepcopy,client" \
 	${IMAGE_CLI_GEN}
 
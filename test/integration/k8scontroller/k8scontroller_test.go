@@ -84,13 +84,28 @@ func TestKubernetesControllerPromOperatorGenerate(t *testing.T) {
 				gotSLOs, err := kClis.Sloth.SlothV1().PrometheusServiceLevels(ns).Get(ctx, SLOs.Name, metav1.GetOptions{})
 				require.NoError(t, err)
 
+				expRules := 0
+				for _, g := range getBasePromOpPrometheusRule(version).Spec.Groups {
+					expRules += len(g.Rules)
+				}
 				expStatus := slothv1.PrometheusServiceLevelStatus{
 					ProcessedSLOs:            2,
 					PromOpRulesGeneratedSLOs: 2,
 					PromOpRulesGenerated:     true,
 					ObservedGeneration:       newSLOs.Generation,
+					GeneratedRules:           expRules,
+					Objectives:               "99.9-99.99",
+					Conditions: []metav1.Condition{
+						{Type: "Validated", Status: metav1.ConditionTrue, ObservedGeneration: newSLOs.Generation, Reason: "SpecValid"},
+						{Type: "RulesGenerated", Status: metav1.ConditionTrue, ObservedGeneration: newSLOs.Generation, Reason: "RulesGenerated"},
+						{Type: "RulesApplied", Status: metav1.ConditionTrue, ObservedGeneration: newSLOs.Generation, Reason: "RulesApplied"},
+						{Type: "Ready", Status: metav1.ConditionTrue, ObservedGeneration: newSLOs.Generation, Reason: "Ready"},
+					},
 				}
 				gotSLOs.Status.LastPromOpRulesSuccessfulGenerated = nil // Remove variations.
+				for i := range gotSLOs.Status.Conditions {
+					gotSLOs.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+				}
 
 				assert.Equal(t, expStatus, gotSLOs.Status)
 			},
@@ -116,8 +131,21 @@ func TestKubernetesControllerPromOperatorGenerate(t *testing.T) {
 					PromOpRulesGeneratedSLOs: 0,
 					PromOpRulesGenerated:     false,
 					ObservedGeneration:       newSLOs.Generation,
+					Objectives:               "99.99-101",
+					Conditions: []metav1.Condition{
+						{Type: "Validated", Status: metav1.ConditionFalse, ObservedGeneration: newSLOs.Generation, Reason: "SpecInvalid"},
+						{Type: "RulesGenerated", Status: metav1.ConditionUnknown, ObservedGeneration: newSLOs.Generation, Reason: "PreviousStepFailed"},
+						{Type: "RulesApplied", Status: metav1.ConditionUnknown, ObservedGeneration: newSLOs.Generation, Reason: "PreviousStepFailed"},
+						{Type: "Ready", Status: metav1.ConditionFalse, ObservedGeneration: newSLOs.Generation, Reason: "NotReady"},
+					},
 				}
+				assert.NotEmpty(t, gotSLOs.Status.LastError)
 				gotSLOs.Status.LastPromOpRulesSuccessfulGenerated = nil // Remove variations.
+				gotSLOs.Status.LastError = ""
+				for i := range gotSLOs.Status.Conditions {
+					gotSLOs.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+					gotSLOs.Status.Conditions[i].Message = ""
+				}
 
 				assert.Equal(t, expStatus, gotSLOs.Status)
 			},
@@ -319,6 +347,114 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 								"sloth_window": "30d",
 							},
 						},
+						{
+							Record: "slo:sli_total:rate5m",
+							Expr:   intstr.FromString("sum(sum(rate(http_request_duration_seconds_count{job=\"myservice\"}[5m])))\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "5m",
+							},
+						},
+						{
+							Record: "slo:burn_rate5m",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate5m{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "5m",
+							},
+						},
+						{
+							Record: "slo:burn_rate30m",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate30m{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "30m",
+							},
+						},
+						{
+							Record: "slo:burn_rate1h",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate1h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "1h",
+							},
+						},
+						{
+							Record: "slo:burn_rate2h",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate2h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "2h",
+							},
+						},
+						{
+							Record: "slo:burn_rate6h",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate6h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "6h",
+							},
+						},
+						{
+							Record: "slo:burn_rate1d",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate1d{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "1d",
+							},
+						},
+						{
+							Record: "slo:burn_rate3d",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate3d{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "3d",
+							},
+						},
+						{
+							Record: "slo:burn_rate30d",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate30d{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n/ (1 - 0.999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+								"sloth_window":  "30d",
+							},
+						},
 					},
 				},
 				{
@@ -326,7 +462,7 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 					Rules: []monitoringv1.Rule{
 						{
 							Record: "slo:objective:ratio",
-							Expr:   intstr.FromString("vector(0.9990000000000001)"),
+							Expr:   intstr.FromString("vector(0.999)"),
 							Labels: map[string]string{
 								"globalk1":      "globalv1",
 								"slo01k1":       "slo01v1",
@@ -337,7 +473,7 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 						},
 						{
 							Record: "slo:error_budget:ratio",
-							Expr:   intstr.FromString("vector(1-0.9990000000000001)"),
+							Expr:   intstr.FromString("vector(1-0.999)"),
 							Labels: map[string]string{
 								"globalk1":      "globalv1",
 								"slo01k1":       "slo01v1",
@@ -390,6 +526,28 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 								"sloth_slo":     "slo01",
 							},
 						},
+						{
+							Record: "slo:period_error_budget_remaining:minutes",
+							Expr:   intstr.FromString("slo:period_error_budget_remaining:ratio{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(slo:error_budget:ratio{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} * slo:time_period:days{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} * 1440)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+							},
+						},
+						{
+							Record: "slo:period_error_budget_remaining:events",
+							Expr:   intstr.FromString("slo:period_error_budget_remaining:ratio{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left\nslo:error_budget:ratio{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(avg_over_time(slo:sli_total:rate5m{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"}[30d]) * 2592000)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"slo01k1":       "slo01v1",
+								"sloth_id":      "svc01-slo01",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo01",
+							},
+						},
 						{
 							Record: "sloth_slo_info",
 							Expr:   intstr.FromString("vector(1)"),
@@ -412,7 +570,7 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 					Rules: []monitoringv1.Rule{
 						{
 							Alert: "myServiceAlert",
-							Expr:  intstr.FromString("(\n    (slo:sli_error:ratio_rate5m{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (14.4 * 0.0009999999999999432))\n    and ignoring (sloth_window)\n    (slo:sli_error:ratio_rate1h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (14.4 * 0.0009999999999999432))\n)\nor ignoring (sloth_window)\n(\n    (slo:sli_error:ratio_rate30m{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (6 * 0.0009999999999999432))\n    and ignoring (sloth_window)\n    (slo:sli_error:ratio_rate6h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (6 * 0.0009999999999999432))\n)\n"),
+							Expr:  intstr.FromString("(\n    (slo:burn_rate5m{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 14.4)\n    and ignoring (sloth_window)\n    (slo:burn_rate1h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 14.4)\n)\nor ignoring (sloth_window)\n(\n    (slo:burn_rate30m{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 6)\n    and ignoring (sloth_window)\n    (slo:burn_rate6h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 6)\n)\n"),
 							Labels: map[string]string{
 								"alert01k1":      "alert01v1",
 								"sloth_severity": "page",
@@ -425,7 +583,7 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 						},
 						{
 							Alert: "myServiceAlert",
-							Expr:  intstr.FromString("(\n    (slo:sli_error:ratio_rate2h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (3 * 0.0009999999999999432))\n    and ignoring (sloth_window)\n    (slo:sli_error:ratio_rate1d{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (3 * 0.0009999999999999432))\n)\nor ignoring (sloth_window)\n(\n    (slo:sli_error:ratio_rate6h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (1 * 0.0009999999999999432))\n    and ignoring (sloth_window)\n    (slo:sli_error:ratio_rate3d{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > (1 * 0.0009999999999999432))\n)\n"),
+							Expr:  intstr.FromString("(\n    (slo:burn_rate2h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 3)\n    and ignoring (sloth_window)\n    (slo:burn_rate1d{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 3)\n)\nor ignoring (sloth_window)\n(\n    (slo:burn_rate6h{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 1)\n    and ignoring (sloth_window)\n    (slo:burn_rate3d{sloth_id=\"svc01-slo01\", sloth_service=\"svc01\", sloth_slo=\"slo01\"} > 1)\n)\n"),
 							Labels: map[string]string{
 								"alert01k1":      "alert01v1",
 								"sloth_severity": "ticket",
@@ -525,6 +683,94 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 								"sloth_window": "30d",
 							},
 						},
+						{
+							Record: "slo:burn_rate5m",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate5m{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "5m",
+							},
+						},
+						{
+							Record: "slo:burn_rate30m",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate30m{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "30m",
+							},
+						},
+						{
+							Record: "slo:burn_rate1h",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate1h{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "1h",
+							},
+						},
+						{
+							Record: "slo:burn_rate2h",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate2h{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "2h",
+							},
+						},
+						{
+							Record: "slo:burn_rate6h",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate6h{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "6h",
+							},
+						},
+						{
+							Record: "slo:burn_rate1d",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate1d{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "1d",
+							},
+						},
+						{
+							Record: "slo:burn_rate3d",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate3d{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "3d",
+							},
+						},
+						{
+							Record: "slo:burn_rate30d",
+							Expr:   intstr.FromString("slo:sli_error:ratio_rate30d{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n/ (1 - 0.9999)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+								"sloth_window":  "30d",
+							},
+						},
 					},
 				},
 				{
@@ -532,7 +778,7 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 					Rules: []monitoringv1.Rule{
 						{
 							Record: "slo:objective:ratio",
-							Expr:   intstr.FromString("vector(0.9998999999999999)"),
+							Expr:   intstr.FromString("vector(0.9999)"),
 							Labels: map[string]string{
 								"globalk1":      "globalv1",
 								"sloth_id":      "svc01-slo02",
@@ -542,7 +788,7 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 						},
 						{
 							Record: "slo:error_budget:ratio",
-							Expr:   intstr.FromString("vector(1-0.9998999999999999)"),
+							Expr:   intstr.FromString("vector(1-0.9999)"),
 							Labels: map[string]string{
 								"globalk1":      "globalv1",
 								"sloth_id":      "svc01-slo02",
@@ -590,6 +836,16 @@ func getBasePromOpPrometheusRule(slothVersion string) *monitoringv1.PrometheusRu
 								"sloth_slo":     "slo02",
 							},
 						},
+						{
+							Record: "slo:period_error_budget_remaining:minutes",
+							Expr:   intstr.FromString("slo:period_error_budget_remaining:ratio{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"}\n* on(sloth_id, sloth_slo, sloth_service) group_left()\n(slo:error_budget:ratio{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"} * slo:time_period:days{sloth_id=\"svc01-slo02\", sloth_service=\"svc01\", sloth_slo=\"slo02\"} * 1440)\n"),
+							Labels: map[string]string{
+								"globalk1":      "globalv1",
+								"sloth_id":      "svc01-slo02",
+								"sloth_service": "svc01",
+								"sloth_slo":     "slo02",
+							},
+						},
 						{
 							Record: "sloth_slo_info",
 							Expr:   intstr.FromString("vector(1)"),